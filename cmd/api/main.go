@@ -1,16 +1,30 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/UliVargas/blog-go/internal/application/service"
 	"github.com/UliVargas/blog-go/internal/domain/model"
+	domainRepository "github.com/UliVargas/blog-go/internal/domain/repository"
 	"github.com/UliVargas/blog-go/internal/infrastructure/config"
+	"github.com/UliVargas/blog-go/internal/infrastructure/mail"
+	"github.com/UliVargas/blog-go/internal/infrastructure/notifier"
 	"github.com/UliVargas/blog-go/internal/infrastructure/repository"
+	"github.com/UliVargas/blog-go/internal/infrastructure/scheduler"
 	"github.com/UliVargas/blog-go/internal/presentation/handler"
 	"github.com/UliVargas/blog-go/internal/presentation/middleware"
+	"github.com/UliVargas/blog-go/pkg/oidc"
+	"github.com/UliVargas/blog-go/pkg/password"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -20,40 +34,293 @@ func main() {
 		log.Println("No se pudo cargar el archivo .env", err)
 	}
 
+	// Carga de configuración
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Contexto que se cancela al recibir una señal de terminación
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Inicialización de la base de datos
 	db := config.DBConnect()
-	db.AutoMigrate(&model.User{})
+	db.AutoMigrate(&model.User{}, &model.Category{}, &model.Tag{}, &model.TagAlias{}, &model.Post{}, &model.PostSlug{}, &model.PostRevision{}, &model.Comment{}, &model.RefreshToken{}, &model.RevokedToken{}, &model.PasswordResetToken{}, &model.LoginAttempt{}, &model.RecoveryCode{}, &model.Identity{}, &model.PersonalAccessToken{}, &model.SigningKey{}, &model.Session{})
+
+	// Envío de emails
+	mailer, err := newMailer(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	mailRenderer, err := mail.NewRenderer()
+	if err != nil {
+		log.Fatal("No se pudieron cargar las plantillas de email: ", err)
+	}
+	accountNotifier := notifier.NewMailNotifier(mailer, mailRenderer, cfg.APPURL)
+
+	// Cifrado de contraseñas
+	passwordHasher, err := password.New(cfg.PASSWORDALGORITHM, password.Argon2id{
+		Memory:      cfg.ARGON2MEMORY,
+		Iterations:  cfg.ARGON2ITERATIONS,
+		Parallelism: cfg.ARGON2PARALLELISM,
+	}, password.Bcrypt{Cost: cfg.BCRYPTCOST})
+	if err != nil {
+		log.Fatal("Configuración de contraseñas no válida: ", err)
+	}
 
 	// Inicialización de servicios
-	userRepository := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepository)
+	userRepository := repository.NewCachedUserRepository(repository.NewUserRepository(db), cfg.CACHEREFRESHINTERVAL)
+	emailVerificationService := service.NewEmailVerificationService(userRepository, accountNotifier, cfg.JWTSECRET, cfg.EMAILVERIFICATIONTTL, cfg.VERIFICATIONRESENDINTERVAL)
+	emailVerificationHandler := handler.NewEmailVerificationHandler(emailVerificationService)
+
+	userService := service.NewUserService(userRepository, emailVerificationService, passwordHasher)
 	userHandler := handler.NewUserHandler(userService)
 
-	authService := service.NewAuthService(userRepository)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(db)
+	revokedTokenRepository := repository.NewCachedRevokedTokenRepository(repository.NewRevokedTokenRepository(db))
+	if err := revokedTokenRepository.Warm(time.Now()); err != nil {
+		log.Fatal("No se pudieron cargar los tokens revocados: ", err)
+	}
+	// Las sesiones revocadas antes de la vigencia de un token de acceso ya no
+	// tienen tokens que rechazar
+	sessionRepository := repository.NewCachedSessionRepository(repository.NewSessionRepository(db))
+	warmedAt := time.Now()
+	if err := sessionRepository.Warm(warmedAt.Add(-cfg.ACCESSTOKENTTL), warmedAt); err != nil {
+		log.Fatal("No se pudieron cargar las sesiones revocadas: ", err)
+	}
+	sessionService := service.NewSessionService(sessionRepository, cfg.ACCESSTOKENTTL, cfg.REFRESHTOKENTTL)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	signingKeyRepository := repository.NewSigningKeyRepository(db)
+	signingKeyService := service.NewSigningKeyService(signingKeyRepository, cfg.JWTSECRET, cfg.JWTALGORITHM, cfg.JWTKEYROTATION, cfg.ACCESSTOKENTTL)
+	if _, err := signingKeyService.Rotate(time.Now()); err != nil {
+		log.Fatal("No se pudieron cargar las claves de firma: ", err)
+	}
+	signingKeyHandler := handler.NewSigningKeyHandler(signingKeyService)
+
+	personalAccessTokenRepository := repository.NewPersonalAccessTokenRepository(db)
+	personalAccessTokenService := service.NewPersonalAccessTokenService(personalAccessTokenRepository)
+	personalAccessTokenHandler := handler.NewPersonalAccessTokenHandler(personalAccessTokenService)
+	tokenChecks := middleware.TokenChecks{Keys: signingKeyService, Revocations: revokedTokenRepository, Sessions: sessionRepository, Credentials: userRepository, AccessTokens: personalAccessTokenService}
+	loginAttemptRepository, err := newLoginAttemptRepository(cfg, db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	loginThrottle := service.NewLoginThrottle(loginAttemptRepository, userRepository, service.LoginThrottlePolicy{
+		MaxFailures:   cfg.LOGINMAXFAILURES,
+		MaxIPFailures: cfg.LOGINMAXIPFAILURES,
+		Backoff:       cfg.LOGINBACKOFF,
+		Lockout:       cfg.LOGINLOCKOUT,
+		Window:        cfg.LOGINFAILUREWINDOW,
+	})
+	loginThrottleHandler := handler.NewLoginThrottleHandler(loginThrottle)
+
+	recoveryCodeRepository := repository.NewRecoveryCodeRepository(db)
+	twoFactorService := service.NewTwoFactorService(userRepository, recoveryCodeRepository, passwordHasher, cfg.JWTSECRET, cfg.TOTPISSUER)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
+
+	authService := service.NewAuthService(userRepository, refreshTokenRepository, revokedTokenRepository, sessionRepository, emailVerificationService, loginThrottle, twoFactorService, signingKeyService, passwordHasher, cfg.JWTSECRET, cfg.ACCESSTOKENTTL, cfg.REFRESHTOKENTTL, cfg.REQUIREEMAILVERIFICATION)
 	authHandler := handler.NewAuthHandler(authService)
 
+	oidcProviders, err := newOIDCProviders(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	identityRepository := repository.NewIdentityRepository(db)
+	oidcService := service.NewOIDCService(oidcProviders, userRepository, identityRepository, authService, passwordHasher, cfg.JWTSECRET)
+	// Las cookies del inicio de sesión externo solo viajan por HTTPS si la API se sirve por HTTPS
+	oidcHandler := handler.NewOIDCHandler(oidcService, strings.HasPrefix(cfg.APPURL, "https://"))
+
+	passwordResetTokenRepository := repository.NewPasswordResetTokenRepository(db)
+	passwordResetService := service.NewPasswordResetService(userRepository, passwordResetTokenRepository, refreshTokenRepository, sessionRepository, accountNotifier, passwordHasher, cfg.PASSWORDRESETTTL, cfg.PASSWORDRESETINTERVAL)
+	passwordResetHandler := handler.NewPasswordResetHandler(passwordResetService)
+
+	postRepository := repository.NewPostRepository(db)
+	postRevisionRepository := repository.NewPostRevisionRepository(db)
+	tagRepository := repository.NewTagRepository(db)
+	categoryRepository := repository.NewCategoryRepository(db)
+	authorizer := service.NewAuthorizer(cfg.COMMENTEDITWINDOW)
+	postService := service.NewPostService(postRepository, postRevisionRepository, tagRepository, categoryRepository, authorizer)
+	postHandler := handler.NewPostHandler(postService)
+
+	tagService := service.NewTagService(tagRepository)
+	tagHandler := handler.NewTagHandler(tagService)
+
+	categoryService := service.NewCategoryService(categoryRepository)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+
+	commentRepository := repository.NewCommentRepository(db)
+	commentService := service.NewCommentService(commentRepository, postRepository, authorizer)
+	commentHandler := handler.NewCommentHandler(commentService)
+
+	// Tareas en segundo plano
+	go scheduler.Every(ctx, "publicar programadas", cfg.PUBLISHINTERVAL, func(ctx context.Context) error {
+		published, err := postService.PublishScheduled(time.Now())
+		if published > 0 {
+			log.Printf("Se publicaron %d publicaciones programadas", published)
+		}
+		return err
+	})
+	go scheduler.Every(ctx, "purgar tokens revocados", cfg.REVOCATIONPURGEINTERVAL, func(ctx context.Context) error {
+		purged, err := authService.PurgeRevokedTokens(time.Now())
+		if purged > 0 {
+			log.Printf("Se eliminaron %d tokens revocados expirados", purged)
+		}
+		return err
+	})
+	go scheduler.Every(ctx, "recargar tokens revocados", cfg.CACHEREFRESHINTERVAL, func(ctx context.Context) error {
+		return revokedTokenRepository.Refresh(time.Now())
+	})
+	go scheduler.Every(ctx, "recargar sesiones revocadas", cfg.CACHEREFRESHINTERVAL, func(ctx context.Context) error {
+		return sessionRepository.Refresh(time.Now())
+	})
+
+	go scheduler.Every(ctx, "purgar sesiones", cfg.REVOCATIONPURGEINTERVAL, func(ctx context.Context) error {
+		purged, err := sessionService.Purge(time.Now())
+		if purged > 0 {
+			log.Printf("Se eliminaron %d sesiones inactivas", purged)
+		}
+		return err
+	})
+
+	go scheduler.Every(ctx, "rotar claves de firma", cfg.JWTKEYREFRESHINTERVAL, func(ctx context.Context) error {
+		rotated, err := signingKeyService.Rotate(time.Now())
+		if rotated {
+			log.Printf("Se generó una nueva clave de firma %s", cfg.JWTALGORITHM)
+		}
+		return err
+	})
+
+	go scheduler.Every(ctx, "purgar intentos de inicio de sesión", cfg.LOGINFAILUREWINDOW, func(ctx context.Context) error {
+		purged, err := loginThrottle.PurgeExpired(time.Now())
+		if purged > 0 {
+			log.Printf("Se eliminaron %d contadores de intentos de inicio de sesión", purged)
+		}
+		return err
+	})
+
 	// Inicialización de router
-	router := gin.Default()
+	router, err := handler.NewRouter(cfg.TRUSTEDPROXIES)
+	if err != nil {
+		log.Fatal("TRUSTEDPROXIES no válido: ", err)
+	}
 
 	// Rutas de usuarios
 	api := router.Group("/api/v1")
 	{
-		// Rutas protegidas de usuarios
+		// Rutas protegidas de usuarios. Los tokens de acceso personal necesitan
+		// el scope de cada ruta y no pueden gestionar las credenciales de la cuenta
 		protectedUsers := api.Group("/users")
-		protectedUsers.Use(middleware.AuthMiddleware())
+		protectedUsers.Use(middleware.AuthMiddleware(tokenChecks))
+		{
+			protectedUsers.GET("/", middleware.RequireScope(model.ScopeUsersRead), userHandler.GetAll)
+			protectedUsers.GET("/me", middleware.RequireScope(model.ScopeUsersRead), userHandler.GetMe)
+			protectedUsers.PATCH("/me", middleware.RequireScope(model.ScopeUsersWrite), userHandler.UpdateMe)
+			protectedUsers.DELETE("/me", middleware.RequireSession(), userHandler.DeleteMe)
+			protectedUsers.POST("/me/password", middleware.RequireSession(), authHandler.ChangePassword)
+			protectedUsers.POST("/me/2fa", middleware.RequireSession(), twoFactorHandler.Setup)
+			protectedUsers.POST("/me/2fa/confirm", middleware.RequireSession(), twoFactorHandler.Confirm)
+			protectedUsers.DELETE("/me/2fa", middleware.RequireSession(), twoFactorHandler.Disable)
+			protectedUsers.POST("/me/tokens", middleware.RequireSession(), personalAccessTokenHandler.Create)
+			protectedUsers.GET("/me/tokens", middleware.RequireSession(), personalAccessTokenHandler.List)
+			protectedUsers.DELETE("/me/tokens/:id", middleware.RequireSession(), personalAccessTokenHandler.Revoke)
+			protectedUsers.GET("/me/sessions", middleware.RequireSession(), sessionHandler.List)
+			protectedUsers.DELETE("/me/sessions", middleware.RequireSession(), sessionHandler.RevokeOthers)
+			protectedUsers.DELETE("/me/sessions/:id", middleware.RequireSession(), sessionHandler.Revoke)
+			protectedUsers.GET("/:id", middleware.RequireScope(model.ScopeUsersRead), userHandler.GetByID)
+			protectedUsers.PUT("/:id/role", middleware.RequireScope(model.ScopeUsersWrite), middleware.RequirePermission(model.PermUsersManage), userHandler.UpdateRole)
+			protectedUsers.POST("/:id/unlock", middleware.RequireScope(model.ScopeUsersWrite), middleware.RequirePermission(model.PermUsersManage), loginThrottleHandler.Unlock)
+		}
+
+		// Rutas públicas de publicaciones
+		posts := api.Group("/posts")
+		posts.Use(middleware.OptionalAuthMiddleware(tokenChecks), middleware.RequireScope(model.ScopePostsRead))
+		{
+			posts.GET("/", postHandler.GetAll)
+			posts.GET("/:id", postHandler.GetByID)
+			posts.GET("/by-slug/:slug", postHandler.GetBySlug)
+			posts.GET("/:id/comments", commentHandler.GetByPost)
+		}
+
+		// Rutas protegidas de publicaciones
+		protectedPosts := api.Group("/posts")
+		protectedPosts.Use(middleware.AuthMiddleware(tokenChecks))
 		{
-			protectedUsers.GET("/", userHandler.GetAll)
-			protectedUsers.GET("/:id", userHandler.GetByID)
+			protectedPosts.GET("/mine", middleware.RequireScope(model.ScopePostsRead), postHandler.GetMine)
+			protectedPosts.POST("/", middleware.RequireScope(model.ScopePostsWrite), middleware.RequirePermission(model.PermPostsPublish), postHandler.Create)
+			protectedPosts.PUT("/:id", middleware.RequireScope(model.ScopePostsWrite), middleware.RequirePermission(model.PermPostsPublish), postHandler.Update)
+			protectedPosts.DELETE("/:id", middleware.RequireScope(model.ScopePostsWrite), middleware.RequirePermission(model.PermPostsPublish), postHandler.Delete)
+			protectedPosts.GET("/:id/revisions", middleware.RequireScope(model.ScopePostsRead), postHandler.GetRevisions)
+			protectedPosts.GET("/:id/revisions/diff", middleware.RequireScope(model.ScopePostsRead), postHandler.DiffRevisions)
+			protectedPosts.POST("/:id/revisions/:rev/restore", middleware.RequireScope(model.ScopePostsWrite), middleware.RequirePermission(model.PermPostsPublish), postHandler.RestoreRevision)
+			protectedPosts.POST("/:id/comments", middleware.RequireScope(model.ScopeCommentsWrite), middleware.RequirePermission(model.PermCommentsCreate), commentHandler.Create)
+		}
+
+		// Rutas de etiquetas
+		tags := api.Group("/tags")
+		{
+			tags.GET("/", tagHandler.GetCloud)
+			tags.GET("/:slug/posts", postHandler.GetByTag)
+		}
+
+		// Rutas de administración de etiquetas
+		protectedTags := api.Group("/tags")
+		protectedTags.Use(middleware.AuthMiddleware(tokenChecks), middleware.RequireScope(model.ScopeTaxonomyWrite), middleware.RequirePermission(model.PermTaxonomyManage))
+		{
+			protectedTags.PUT("/:id", tagHandler.Rename)
+			protectedTags.POST("/:id/merge", tagHandler.Merge)
+			protectedTags.POST("/:id/aliases", tagHandler.AddAlias)
+		}
+
+		// Rutas de categorías
+		categories := api.Group("/categories")
+		{
+			categories.GET("/", categoryHandler.GetTree)
+			categories.GET("/:slug/posts", postHandler.GetByCategory)
+		}
+
+		protectedCategories := api.Group("/categories")
+		protectedCategories.Use(middleware.AuthMiddleware(tokenChecks), middleware.RequireScope(model.ScopeTaxonomyWrite), middleware.RequirePermission(model.PermTaxonomyManage))
+		{
+			protectedCategories.POST("/", categoryHandler.Create)
+		}
+
+		// Rutas protegidas de comentarios
+		comments := api.Group("/comments")
+		comments.Use(middleware.AuthMiddleware(tokenChecks))
+		{
+			comments.GET("/pending", middleware.RequireScope(model.ScopeCommentsModerate), middleware.RequirePermission(model.PermCommentsModerate), commentHandler.GetPending)
+			comments.POST("/moderate", middleware.RequireScope(model.ScopeCommentsModerate), middleware.RequirePermission(model.PermCommentsModerate), commentHandler.Moderate)
+			comments.PUT("/:id", middleware.RequireScope(model.ScopeCommentsWrite), commentHandler.Update)
+			comments.DELETE("/:id", middleware.RequireScope(model.ScopeCommentsWrite), commentHandler.Delete)
 		}
 
 		// Rutas de autenticación
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/2fa/verify", authHandler.VerifyTwoFactor)
 			auth.POST("/register", authHandler.Register)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.GET("/verify", emailVerificationHandler.Verify)
+			auth.POST("/verify/resend", emailVerificationHandler.Resend)
+			auth.POST("/forgot-password", passwordResetHandler.Forgot)
+			auth.POST("/reset-password", passwordResetHandler.Reset)
+			auth.GET("/oidc", oidcHandler.Providers)
+			auth.GET("/oidc/:provider", oidcHandler.Begin)
+			auth.GET("/oidc/:provider/callback", oidcHandler.Callback)
+		}
+
+		protectedAuth := api.Group("/auth")
+		protectedAuth.Use(middleware.AuthMiddleware(tokenChecks))
+		{
+			protectedAuth.POST("/logout", middleware.RequireSession(), authHandler.Logout)
 		}
 	}
 
+	// Claves públicas para verificar los tokens de acceso
+	router.GET("/.well-known/jwks.json", signingKeyHandler.JWKS)
+
 	// Rutas
 	router.GET("/", func(ctx *gin.Context) {
 		ctx.JSON(200, gin.H{
@@ -61,9 +328,50 @@ func main() {
 		})
 	})
 
-	// Carga de configuración
-	cfg := config.Load()
-
 	// Ejecución del servidor
 	router.Run(cfg.PORT)
 }
+
+// newMailer crea el transporte de emails indicado en MAILDRIVER
+func newMailer(cfg *config.Config) (mail.Mailer, error) {
+	switch cfg.MAILDRIVER {
+	case "smtp":
+		return mail.NewSMTPMailer(cfg.SMTPHOST, cfg.SMTPPORT, cfg.SMTPUSERNAME, cfg.SMTPPASSWORD, cfg.MAILFROM), nil
+	case "file":
+		return mail.NewFileMailer(cfg.MAILDIR, cfg.MAILFROM), nil
+	default:
+		return nil, fmt.Errorf("MAILDRIVER desconocido: %q", cfg.MAILDRIVER)
+	}
+}
+
+// newLoginAttemptRepository crea el contador de inicios de sesión fallidos según
+// LOGINATTEMPTSTORE. En memoria no se comparte entre instancias de la API
+func newLoginAttemptRepository(cfg *config.Config, db *gorm.DB) (domainRepository.LoginAttemptRepositoryInterface, error) {
+	switch cfg.LOGINATTEMPTSTORE {
+	case "database":
+		return repository.NewLoginAttemptRepository(db), nil
+	case "memory":
+		return repository.NewMemoryLoginAttemptRepository(), nil
+	default:
+		return nil, fmt.Errorf("LOGINATTEMPTSTORE desconocido: %q", cfg.LOGINATTEMPTSTORE)
+	}
+}
+
+// newOIDCProviders crea los clientes de los proveedores OpenID Connect de
+// OIDCPROVIDERS. El proveedor redirige de vuelta a la ruta de callback bajo APPURL
+func newOIDCProviders(cfg *config.Config) (map[string]*oidc.Provider, error) {
+	providers := make(map[string]*oidc.Provider, len(cfg.OIDCPROVIDERS))
+	for _, provider := range cfg.OIDCPROVIDERS {
+		if provider.Issuer == "" || provider.ClientID == "" {
+			return nil, fmt.Errorf("el proveedor OIDC %q necesita emisor y client id", provider.Name)
+		}
+		providers[provider.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  strings.TrimSuffix(cfg.APPURL, "/") + "/api/v1/auth/oidc/" + provider.Name + "/callback",
+			Scopes:       provider.Scopes,
+		}, nil)
+	}
+	return providers, nil
+}
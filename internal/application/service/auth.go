@@ -1,56 +1,485 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
+	"log"
 	"time"
 
+	"github.com/UliVargas/blog-go/internal/domain/dto"
 	"github.com/UliVargas/blog-go/internal/domain/model"
-	"github.com/UliVargas/blog-go/internal/infrastructure/config"
 	"github.com/UliVargas/blog-go/internal/domain/repository"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
 	appErrors "github.com/UliVargas/blog-go/pkg/errors"
-	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/UliVargas/blog-go/pkg/password"
+	"github.com/UliVargas/blog-go/pkg/utils"
 )
 
+// refreshTokenBytes es la longitud en bytes de los tokens de renovación y
+// tokenIDBytes la de los identificadores de familia y de los jti
+const (
+	refreshTokenBytes = 32
+	tokenIDBytes      = 16
+)
+
+// twoFactorChallengePurpose separa la firma de los desafíos de verificación en
+// dos pasos de la de otros tokens firmados, y twoFactorChallengeTTL es el tiempo
+// que tiene el usuario para introducir el código
+const (
+	twoFactorChallengePurpose = "2fa-challenge"
+	twoFactorChallengeTTL     = 5 * time.Minute
+)
+
+// twoFactorChallengeClaims es el contenido firmado de un desafío. ID permite
+// canjearlo una sola vez e IP es la del inicio de sesión que lo emitió, para
+// que los códigos incorrectos cuenten contra los mismos límites que la contraseña
+type twoFactorChallengeClaims struct {
+	ID        string `json:"jti"`
+	UserID    uint   `json:"uid"`
+	IP        string `json:"ip"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// AuthService gestiona el registro y las sesiones. Si requireVerifiedEmail está
+// activo, solo pueden iniciar sesión los usuarios con el email verificado. Los
+// inicios de sesión fallidos se limitan con throttle y el segundo factor de los
+// usuarios que lo tienen activado se comprueba con twoFactor. Los tokens de
+// acceso los firma signer. Cada inicio de sesión se registra como una sesión del
+// dispositivo en sessionRepo. Las contraseñas se cifran y comprueban con hasher
+// y los desafíos de verificación en dos pasos se firman con secret
 type AuthService struct {
-	userRepo repository.UserRepositoryInterface
+	userRepo             repository.UserRepositoryInterface
+	refreshTokenRepo     repository.RefreshTokenRepositoryInterface
+	revokedTokenRepo     repository.RevokedTokenRepositoryInterface
+	sessionRepo          repository.SessionRepositoryInterface
+	verifier             domainService.EmailVerificationServiceInterface
+	throttle             domainService.LoginThrottleInterface
+	twoFactor            domainService.TwoFactorServiceInterface
+	signer               domainService.AccessTokenSignerInterface
+	hasher               domainService.PasswordHasherInterface
+	secret               string
+	accessTokenTTL       time.Duration
+	refreshTokenTTL      time.Duration
+	requireVerifiedEmail bool
 }
 
-func NewAuthService(userRepo repository.UserRepositoryInterface) *AuthService {
-	return &AuthService{userRepo}
+func NewAuthService(userRepo repository.UserRepositoryInterface, refreshTokenRepo repository.RefreshTokenRepositoryInterface, revokedTokenRepo repository.RevokedTokenRepositoryInterface, sessionRepo repository.SessionRepositoryInterface, verifier domainService.EmailVerificationServiceInterface, throttle domainService.LoginThrottleInterface, twoFactor domainService.TwoFactorServiceInterface, signer domainService.AccessTokenSignerInterface, hasher domainService.PasswordHasherInterface, secret string, accessTokenTTL, refreshTokenTTL time.Duration, requireVerifiedEmail bool) *AuthService {
+	return &AuthService{userRepo, refreshTokenRepo, revokedTokenRepo, sessionRepo, verifier, throttle, twoFactor, signer, hasher, secret, accessTokenTTL, refreshTokenTTL, requireVerifiedEmail}
 }
 
-func (s *AuthService) Login(email, password string) (string, error) {
+// Login inicia sesión con email y contraseña. La IP del cliente se usa junto con
+// el email para limitar los intentos fallidos. Si el usuario
+// tiene activada la verificación en dos pasos se devuelve un desafío en lugar
+// de los tokens
+func (s *AuthService) Login(email, password string, client dto.ClientInfo) (dto.LoginResult, error) {
+	// Un email o una IP bloqueados se rechazan sin comprobar la contraseña
+	now := time.Now()
+	ip := client.IP
+	if err := s.throttle.Check(email, ip, now); err != nil {
+		return dto.LoginResult{}, err
+	}
+
 	// Buscar usuario por email
 	user, err := s.userRepo.GetByEmail(email)
 	if err != nil {
 		if errors.Is(err, appErrors.ErrUserNotFound) {
-			return "", appErrors.ErrInvalidCredentials
+			return dto.LoginResult{}, s.loginFailed(email, ip, now)
 		}
-		return "", err
+		return dto.LoginResult{}, err
 	}
 
 	// Verificar contraseña
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	if err := s.hasher.Verify(user.Password, password); err != nil {
+		return dto.LoginResult{}, s.loginFailed(email, ip, now)
+	}
+	s.rehashPassword(user, password)
+
+	// Se comprueba después de la contraseña para no revelar el estado de la cuenta
+	if s.requireVerifiedEmail && user.EmailVerifiedAt == nil {
+		return dto.LoginResult{}, appErrors.ErrEmailNotVerified
+	}
+
+	// Los fallos del email se mantienen hasta superar el segundo factor, para que
+	// conocer la contraseña no permita probar códigos sin límite
+	if user.TwoFactorEnabled() {
+		challenge, err := s.newTwoFactorChallenge(user, ip, now)
+		if err != nil {
+			return dto.LoginResult{}, err
+		}
+		return dto.LoginResult{Challenge: &challenge}, nil
+	}
+	if err := s.throttle.Succeed(email); err != nil {
+		return dto.LoginResult{}, err
+	}
+
+	tokens, err := s.openSession(user, client)
+	if err != nil {
+		return dto.LoginResult{}, err
+	}
+	return dto.LoginResult{Tokens: tokens}, nil
+}
+
+// rehashPassword vuelve a cifrar la contraseña recién comprobada si su hash usa
+// un algoritmo o unos parámetros distintos de los configurados. El hash solo se
+// reemplaza si sigue siendo el que se comprobó, para no deshacer un cambio de
+// contraseña simultáneo, y un fallo no impide iniciar sesión
+func (s *AuthService) rehashPassword(user model.User, password string) {
+	if !s.hasher.NeedsRehash(user.Password) {
+		return
+	}
+	hashedPassword, err := s.hasher.Hash(password)
+	if err == nil {
+		_, err = s.userRepo.ReplacePasswordHash(user.ID, user.Password, hashedPassword)
+	}
+	if err != nil {
+		log.Printf("No se pudo actualizar el hash de la contraseña del usuario %d: %v", user.ID, err)
+	}
+}
+
+// StartSession abre la sesión de un usuario que ya demostró su identidad, como
+// al volver de un proveedor OpenID Connect. Se aplican las mismas condiciones que
+// en Login: el email verificado si se exige y el segundo factor si está activado
+func (s *AuthService) StartSession(user model.User, client dto.ClientInfo) (dto.LoginResult, error) {
+	if s.requireVerifiedEmail && user.EmailVerifiedAt == nil {
+		return dto.LoginResult{}, appErrors.ErrEmailNotVerified
+	}
+	if user.TwoFactorEnabled() {
+		challenge, err := s.newTwoFactorChallenge(user, client.IP, time.Now())
+		if err != nil {
+			return dto.LoginResult{}, err
+		}
+		return dto.LoginResult{Challenge: &challenge}, nil
+	}
+
+	tokens, err := s.openSession(user, client)
+	if err != nil {
+		return dto.LoginResult{}, err
+	}
+	return dto.LoginResult{Tokens: tokens}, nil
+}
+
+// VerifyTwoFactor completa el inicio de sesión de un usuario con verificación en
+// dos pasos canjeando el desafío y un código TOTP o de recuperación por un par de
+// tokens. Cada desafío se puede canjear una sola vez y los códigos incorrectos
+// cuentan como intentos fallidos tanto desde la IP del inicio de sesión como
+// desde la que presenta el desafío
+func (s *AuthService) VerifyTwoFactor(challengeToken, code string, client dto.ClientInfo) (dto.TokenPair, error) {
+	now := time.Now()
+	ip := client.IP
+	data, err := utils.VerifySignedToken(challengeToken, s.secret, twoFactorChallengePurpose)
+	if err != nil {
+		return dto.TokenPair{}, appErrors.ErrInvalidTwoFactorChallenge
+	}
+	var claims twoFactorChallengeClaims
+	if err := json.Unmarshal(data, &claims); err != nil || now.Unix() > claims.ExpiresAt {
+		return dto.TokenPair{}, appErrors.ErrInvalidTwoFactorChallenge
+	}
+
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			return dto.TokenPair{}, appErrors.ErrInvalidTwoFactorChallenge
+		}
+		return dto.TokenPair{}, err
+	}
+	// Desactivar la verificación o cambiar la contraseña invalida los desafíos pendientes
+	if !user.TwoFactorEnabled() || (user.PasswordChangedAt != nil && user.PasswordChangedAt.Unix() > claims.IssuedAt) {
+		return dto.TokenPair{}, appErrors.ErrInvalidTwoFactorChallenge
+	}
+
+	consumed, err := s.revokedTokenRepo.IsRevoked(claims.ID)
+	if err != nil {
+		return dto.TokenPair{}, err
+	}
+	if consumed {
+		return dto.TokenPair{}, appErrors.ErrInvalidTwoFactorChallenge
+	}
+
+	ips := []string{claims.IP}
+	if ip != claims.IP {
+		ips = append(ips, ip)
+	}
+	for _, ip := range ips {
+		if err := s.throttle.Check(user.Email, ip, now); err != nil {
+			return dto.TokenPair{}, err
+		}
+	}
+	if err := s.twoFactor.Authenticate(user, code); err != nil {
+		if errors.Is(err, appErrors.ErrInvalidTwoFactorCode) {
+			for _, ip := range ips {
+				if err := s.throttle.Fail(user.Email, ip, now); err != nil {
+					return dto.TokenPair{}, err
+				}
+			}
+		}
+		return dto.TokenPair{}, err
+	}
+
+	// El desafío queda consumido hasta que expire, para que no se pueda volver a canjear
+	err = s.revokedTokenRepo.Create(model.RevokedToken{JTI: claims.ID, UserID: user.ID, ExpiresAt: time.Unix(claims.ExpiresAt, 0)})
+	if err != nil {
+		return dto.TokenPair{}, err
+	}
+	if err := s.throttle.Succeed(user.Email); err != nil {
+		return dto.TokenPair{}, err
+	}
+
+	return s.openSession(user, client)
+}
+
+// Refresh canjea un token de renovación por un nuevo par de tokens. Cada token
+// de renovación solo se puede canjear una vez: si se presenta uno ya canjeado se
+// asume que fue robado y se revoca toda su familia, cerrando también la sesión
+// del usuario legítimo. Renovar actualiza la última actividad de la sesión y el
+// dispositivo desde el que se usa
+func (s *AuthService) Refresh(refreshToken string, client dto.ClientInfo) (dto.TokenPair, error) {
+	now := time.Now()
+	stored, err := s.refreshTokenRepo.GetByHash(utils.HashToken(refreshToken))
 	if err != nil {
-		return "", appErrors.ErrInvalidCredentials
+		return dto.TokenPair{}, err
+	}
+
+	if stored.UsedAt != nil && stored.RevokedAt == nil {
+		return dto.TokenPair{}, s.revokeFamily(stored.FamilyID, now)
+	}
+	if !stored.IsActive(now) {
+		return dto.TokenPair{}, appErrors.ErrInvalidRefreshToken
+	}
+
+	// Una sesión cerrada desde otro dispositivo ya no se puede renovar
+	// Una familia sin sesión pertenece a una sesión revocada que ya se purgó
+	session, err := s.sessionRepo.GetByFamily(stored.FamilyID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrSessionNotFound) {
+			return dto.TokenPair{}, appErrors.ErrInvalidRefreshToken
+		}
+		return dto.TokenPair{}, err
+	}
+	if session.RevokedAt != nil {
+		return dto.TokenPair{}, appErrors.ErrInvalidRefreshToken
+	}
+
+	// Otra petición pudo canjear el mismo token entre la lectura y la escritura
+	marked, err := s.refreshTokenRepo.MarkUsed(stored.ID, now)
+	if err != nil {
+		return dto.TokenPair{}, err
+	}
+	if !marked {
+		return dto.TokenPair{}, s.revokeFamily(stored.FamilyID, now)
+	}
+
+	// El usuario se vuelve a leer para que el nuevo token refleje su rol actual
+	user, err := s.userRepo.GetByID(stored.UserID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			return dto.TokenPair{}, appErrors.ErrInvalidRefreshToken
+		}
+		return dto.TokenPair{}, err
+	}
+
+	if err := s.sessionRepo.TouchLastSeen(session.ID, client.IP, client.UserAgent, now); err != nil {
+		return dto.TokenPair{}, err
+	}
+
+	return s.issueTokens(user, session)
+}
+
+// Logout revoca el token de acceso identificado por jti hasta su expiración, la
+// sesión sessionID y, si se envía, la familia del token de renovación de la
+// misma sesión. Un token de renovación desconocido o de otro usuario se ignora
+// para que cerrar sesión siempre tenga éxito
+func (s *AuthService) Logout(userID, sessionID uint, jti string, expiresAt time.Time, refreshToken string) error {
+	now := time.Now()
+
+	if jti != "" {
+		err := s.revokedTokenRepo.Create(model.RevokedToken{JTI: jti, UserID: userID, ExpiresAt: expiresAt})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Los tokens emitidos antes de registrar las sesiones no tienen sid
+	if sessionID != 0 {
+		if _, err := s.sessionRepo.Revoke(sessionID, userID, now); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+	stored, err := s.refreshTokenRepo.GetByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, appErrors.ErrInvalidRefreshToken) {
+			return nil
+		}
+		return err
+	}
+	if stored.UserID != userID {
+		return nil
+	}
+	return s.refreshTokenRepo.RevokeFamily(stored.FamilyID, now)
+}
+
+// ChangePassword reemplaza la contraseña tras confirmar la actual. Todos los
+// tokens y sesiones anteriores al cambio dejan de ser válidos, por lo que se abre
+// una nueva sesión para el dispositivo desde el que se cambió
+func (s *AuthService) ChangePassword(userID uint, currentPassword, newPassword string, client dto.ClientInfo) (dto.TokenPair, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return dto.TokenPair{}, err
+	}
+	if err := s.hasher.Verify(user.Password, currentPassword); err != nil {
+		return dto.TokenPair{}, appErrors.ErrIncorrectPassword
+	}
+
+	hashedPassword, err := hashPassword(s.hasher, newPassword)
+	if err != nil {
+		return dto.TokenPair{}, err
+	}
+
+	now := time.Now()
+	updated, err := s.userRepo.UpdateFields(userID, map[string]any{
+		"password":            hashedPassword,
+		"password_changed_at": now,
+	})
+	if err != nil {
+		return dto.TokenPair{}, err
+	}
+	if err := s.refreshTokenRepo.RevokeByUser(userID, now); err != nil {
+		return dto.TokenPair{}, err
+	}
+	if _, err := s.sessionRepo.RevokeByUser(userID, 0, now); err != nil {
+		return dto.TokenPair{}, err
+	}
+
+	return s.openSession(updated, client)
+}
+
+// PurgeRevokedTokens elimina las revocaciones de tokens que ya expiraron y
+// devuelve cuántas se eliminaron
+func (s *AuthService) PurgeRevokedTokens(now time.Time) (int64, error) {
+	return s.revokedTokenRepo.DeleteExpired(now)
+}
+
+// newTwoFactorChallenge firma el desafío que identifica al usuario entre la
+// comprobación de la contraseña y la del segundo factor
+func (s *AuthService) newTwoFactorChallenge(user model.User, ip string, now time.Time) (dto.TwoFactorChallenge, error) {
+	jti, err := utils.GenerateToken(tokenIDBytes)
+	if err != nil {
+		return dto.TwoFactorChallenge{}, appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+	data, err := json.Marshal(twoFactorChallengeClaims{
+		ID:        jti,
+		UserID:    user.ID,
+		IP:        ip,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(twoFactorChallengeTTL).Unix(),
+	})
+	if err != nil {
+		return dto.TwoFactorChallenge{}, appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+
+	token := utils.SignToken(data, s.secret, twoFactorChallengePurpose)
+	return dto.TwoFactorChallenge{Token: token, ExpiresIn: twoFactorChallengeTTL}, nil
+}
+
+// hashPassword cifra una contraseña elegida por el usuario. Las que superan la
+// longitud máxima se rechazan como un error del cliente
+func hashPassword(hasher domainService.PasswordHasherInterface, plain string) (string, error) {
+	hashed, err := hasher.Hash(plain)
+	if errors.Is(err, password.ErrTooLong) {
+		return "", appErrors.ErrPasswordTooLong
+	}
+	if err != nil {
+		return "", appErrors.NewInternalServerError(err, "Error al procesar la contraseña")
+	}
+	return hashed, nil
+}
+
+// loginFailed registra el intento fallido y devuelve el error que se informa al
+// cliente. Los emails desconocidos cuentan igual para no revelar cuáles existen
+func (s *AuthService) loginFailed(email, ip string, now time.Time) error {
+	if err := s.throttle.Fail(email, ip, now); err != nil {
+		return err
+	}
+	return appErrors.ErrInvalidCredentials
+}
+
+// revokeFamily revoca la familia de un token reutilizado y devuelve el error que
+// se informa al cliente
+func (s *AuthService) revokeFamily(familyID string, now time.Time) error {
+	if err := s.refreshTokenRepo.RevokeFamily(familyID, now); err != nil {
+		return err
+	}
+	return appErrors.ErrRefreshTokenReused
+}
+
+// openSession registra una nueva sesión del dispositivo, con su propia familia
+// de tokens de renovación, y emite sus primeros tokens
+func (s *AuthService) openSession(user model.User, client dto.ClientInfo) (dto.TokenPair, error) {
+	familyID, err := utils.GenerateToken(tokenIDBytes)
+	if err != nil {
+		return dto.TokenPair{}, appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+
+	session, err := s.sessionRepo.Create(model.Session{
+		UserID:     user.ID,
+		FamilyID:   familyID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		LastSeenAt: time.Now(),
+	})
+	if err != nil {
+		return dto.TokenPair{}, err
+	}
+	return s.issueTokens(user, session)
+}
+
+// issueTokens genera un token de acceso, que incluye el rol del usuario y la
+// sesión, y un token de renovación de la familia de la sesión
+func (s *AuthService) issueTokens(user model.User, session model.Session) (dto.TokenPair, error) {
+	now := time.Now()
+
+	// El jti identifica al token para poder revocarlo antes de su expiración
+	jti, err := utils.GenerateToken(tokenIDBytes)
+	if err != nil {
+		return dto.TokenPair{}, appErrors.NewInternalServerError(err, "Error al generar token")
 	}
 
 	// Crear token JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	accessToken, err := s.signer.Sign(map[string]any{
 		"user_id": user.ID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
-		"iat":     time.Now().Unix(),
+		"role":    string(user.Role),
+		"jti":     jti,
+		"sid":     session.ID,
+		"exp":     now.Add(s.accessTokenTTL).Unix(),
+		"iat":     now.Unix(),
 	})
+	if err != nil {
+		return dto.TokenPair{}, appErrors.NewInternalServerError(err, "Error al generar token")
+	}
 
-	cfg := config.Load()
-
-	tokenString, err := token.SignedString([]byte(cfg.JWTSECRET))
+	refreshToken, err := utils.GenerateToken(refreshTokenBytes)
 	if err != nil {
-		return "", appErrors.NewInternalServerError(err, "Error al generar token")
+		return dto.TokenPair{}, appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+	_, err = s.refreshTokenRepo.Create(model.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  session.FamilyID,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: now.Add(s.refreshTokenTTL),
+	})
+	if err != nil {
+		return dto.TokenPair{}, err
 	}
 
-	return tokenString, nil
+	return dto.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.accessTokenTTL,
+	}, nil
 }
 
 func (s *AuthService) Register(user model.User) error {
@@ -66,12 +495,25 @@ func (s *AuthService) Register(user model.User) error {
 	}
 
 	// Hashear la contraseña
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := hashPassword(s.hasher, user.Password)
 	if err != nil {
-		return appErrors.NewInternalServerError(err, "Error al procesar la contraseña")
+		return err
 	}
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
+
+	// El rol nunca lo elige el cliente
+	user.Role = model.DefaultRole
 
 	// Crear el usuario
-	return s.userRepo.Create(user)
+	created, err := s.userRepo.Create(user)
+	if err != nil {
+		return err
+	}
+
+	// El registro no falla si no se pudo enviar el enlace: el usuario puede
+	// solicitar otro
+	if err := s.verifier.Send(created); err != nil {
+		log.Printf("No se pudo enviar el email de verificación al usuario %d: %v", created.ID, err)
+	}
+	return nil
 }
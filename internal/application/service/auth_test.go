@@ -3,11 +3,18 @@ package service
 import (
 	"errors"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/UliVargas/blog-go/internal/domain/dto"
 	"github.com/UliVargas/blog-go/internal/domain/model"
 	"github.com/UliVargas/blog-go/internal/domain/repository"
 	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/jwtkeys"
+	"github.com/UliVargas/blog-go/pkg/password"
+	"github.com/UliVargas/blog-go/pkg/query"
+	"github.com/UliVargas/blog-go/pkg/utils"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,9 +26,9 @@ type MockUserRepositoryAuth struct {
 	mock.Mock
 }
 
-func (m *MockUserRepositoryAuth) GetAll() ([]model.User, error) {
-	args := m.Called()
-	return args.Get(0).([]model.User), args.Error(1)
+func (m *MockUserRepositoryAuth) GetAll(spec query.Spec) (query.Page[model.User], error) {
+	args := m.Called(spec)
+	return args.Get(0).(query.Page[model.User]), args.Error(1)
 }
 
 func (m *MockUserRepositoryAuth) GetByID(id uint) (model.User, error) {
@@ -34,9 +41,9 @@ func (m *MockUserRepositoryAuth) GetByEmail(email string) (model.User, error) {
 	return args.Get(0).(model.User), args.Error(1)
 }
 
-func (m *MockUserRepositoryAuth) Create(user model.User) error {
+func (m *MockUserRepositoryAuth) Create(user model.User) (model.User, error) {
 	args := m.Called(user)
-	return args.Error(0)
+	return args.Get(0).(model.User), args.Error(1)
 }
 
 func (m *MockUserRepositoryAuth) Update(user model.User) (model.User, error) {
@@ -44,18 +51,177 @@ func (m *MockUserRepositoryAuth) Update(user model.User) (model.User, error) {
 	return args.Get(0).(model.User), args.Error(1)
 }
 
+func (m *MockUserRepositoryAuth) UpdateFields(id uint, fields map[string]any) (model.User, error) {
+	args := m.Called(id, fields)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (m *MockUserRepositoryAuth) AdvanceTOTPStep(id uint, step int64) (bool, error) {
+	args := m.Called(id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepositoryAuth) ReplacePasswordHash(id uint, current, replacement string) (bool, error) {
+	args := m.Called(id, current, replacement)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockUserRepositoryAuth) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-// NewAuthServiceWithMock creates an AuthService with a mock repository for testing
+// MockRefreshTokenRepository mocks the RefreshTokenRepository for auth testing
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(token model.RefreshToken) (model.RefreshToken, error) {
+	args := m.Called(token)
+	return args.Get(0).(model.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(hash string) (model.RefreshToken, error) {
+	args := m.Called(hash)
+	return args.Get(0).(model.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	args := m.Called(id, usedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(familyID string, revokedAt time.Time) error {
+	args := m.Called(familyID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeByUser(userID uint, revokedAt time.Time) error {
+	args := m.Called(userID, revokedAt)
+	return args.Error(0)
+}
+
+// MockRevokedTokenRepository mocks the RevokedTokenRepository for auth testing
+type MockRevokedTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRevokedTokenRepository) Create(token model.RevokedToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRevokedTokenRepository) IsRevoked(jti string) (bool, error) {
+	args := m.Called(jti)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRevokedTokenRepository) DeleteExpired(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockEmailVerifier mocks the EmailVerificationService
+type MockEmailVerifier struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerifier) Send(user model.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerifier) Verify(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerifier) Resend(email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+// MockLoginThrottle mocks the LoginThrottle
+type MockLoginThrottle struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottle) Check(email, ip string, now time.Time) error {
+	args := m.Called(email, ip, now)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottle) Fail(email, ip string, now time.Time) error {
+	args := m.Called(email, ip, now)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottle) Succeed(email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottle) Unlock(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// allowAllThrottle returns a throttle that never blocks a login
+func allowAllThrottle() *MockLoginThrottle {
+	throttle := &MockLoginThrottle{}
+	throttle.On("Check", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	throttle.On("Fail", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	throttle.On("Succeed", mock.Anything).Return(nil).Maybe()
+	return throttle
+}
+
+// openSessions returns a session repository that accepts every new session
+func openSessions() *MockSessionRepository {
+	sessions := &MockSessionRepository{}
+	sessions.On("Create", mock.Anything).Return(model.Session{ID: 1, FamilyID: "family"}, nil).Maybe()
+	return sessions
+}
+
+// testSecret signs the two-factor challenges and, through testSigner, the
+// access tokens
+const testSecret = "test-secret"
+
+// testSigner signs access tokens with an HS256 test secret
+func testSigner() *jwtkeys.KeySet {
+	return jwtkeys.NewKeySet(testSecret)
+}
+
+// testHasher prefers bcrypt at its minimum cost, so the fixtures hashed with
+// bcrypt.MinCost are never rehashed on login
+func testHasher() *password.Hasher {
+	hasher, err := password.New(password.AlgorithmBcrypt, password.Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1}, password.Bcrypt{Cost: bcrypt.MinCost})
+	if err != nil {
+		panic(err)
+	}
+	return hasher
+}
+
+// NewAuthServiceWithMock creates an AuthService with a mock repository for testing.
+// Refresh tokens are accepted without expectations; use newAuthServiceWithTokenMock
+// to assert on them
 func NewAuthServiceWithMock() (*AuthService, *MockUserRepositoryAuth) {
-	mockRepo := &MockUserRepositoryAuth{}
-	service := NewAuthService(mockRepo)
+	service, mockRepo, mockTokens, _, mockSessions := newAuthServiceWithTokenMock()
+	mockTokens.On("Create", mock.Anything).Return(model.RefreshToken{}, nil).Maybe()
+	mockSessions.On("Create", mock.Anything).Return(model.Session{ID: 1, FamilyID: "family"}, nil).Maybe()
 	return service, mockRepo
 }
 
+func newAuthServiceWithTokenMock() (*AuthService, *MockUserRepositoryAuth, *MockRefreshTokenRepository, *MockRevokedTokenRepository, *MockSessionRepository) {
+	mockRepo := &MockUserRepositoryAuth{}
+	mockTokens := &MockRefreshTokenRepository{}
+	mockRevoked := &MockRevokedTokenRepository{}
+	mockSessions := &MockSessionRepository{}
+	mockVerifier := &MockEmailVerifier{}
+	mockVerifier.On("Send", mock.Anything).Return(nil).Maybe()
+	service := NewAuthService(mockRepo, mockTokens, mockRevoked, mockSessions, mockVerifier, allowAllThrottle(), nil, testSigner(), testHasher(), testSecret, 15*time.Minute, 30*24*time.Hour, false)
+	return service, mockRepo, mockTokens, mockRevoked, mockSessions
+}
+
 func TestNewAuthService(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -76,7 +242,7 @@ func TestNewAuthService(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := NewAuthService(tt.userRepo)
+			result := NewAuthService(tt.userRepo, nil, nil, nil, nil, nil, nil, nil, nil, "", time.Minute, time.Hour, false)
 			if tt.wantNil {
 				assert.Nil(t, result)
 			} else {
@@ -89,7 +255,7 @@ func TestNewAuthService(t *testing.T) {
 
 func TestAuthService_Login(t *testing.T) {
 	// Create a hashed password for testing
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
 
 	tests := []struct {
 		name         string
@@ -169,7 +335,8 @@ func TestAuthService_Login(t *testing.T) {
 			tt.mockSetup(mockRepo)
 
 			// Execute
-			token, err := service.Login(tt.email, tt.password)
+			result, err := service.Login(tt.email, tt.password, dto.ClientInfo{IP: "10.0.0.1"})
+			token := result.Tokens.AccessToken
 
 			// Assert
 			if tt.wantError != nil {
@@ -194,6 +361,119 @@ func TestAuthService_Login(t *testing.T) {
 	}
 }
 
+func TestAuthService_Login_Throttle(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := model.User{ID: 1, Email: "test@example.com", Password: string(hashedPassword)}
+
+	t.Run("blocked login is rejected without checking the password", func(t *testing.T) {
+		mockRepo, throttle := &MockUserRepositoryAuth{}, &MockLoginThrottle{}
+		service := NewAuthService(mockRepo, nil, nil, nil, nil, throttle, nil, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, false)
+		blocked := appErrors.NewRetryAfterError(appErrors.ErrTooManyLoginAttempts, time.Minute)
+		throttle.On("Check", "test@example.com", "10.0.0.1", mock.Anything).Return(blocked)
+
+		_, err := service.Login("test@example.com", "password123", dto.ClientInfo{IP: "10.0.0.1"})
+
+		assert.ErrorIs(t, err, appErrors.ErrTooManyLoginAttempts)
+		mockRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+	})
+
+	for _, tt := range []struct {
+		name     string
+		email    string
+		user     model.User
+		err      error
+		password string
+	}{
+		{"wrong password counts as a failure", "test@example.com", user, nil, "wrong"},
+		{"unknown email counts as a failure", "ghost@example.com", model.User{}, appErrors.ErrUserNotFound, "password123"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, throttle := &MockUserRepositoryAuth{}, &MockLoginThrottle{}
+			service := NewAuthService(mockRepo, nil, nil, nil, nil, throttle, nil, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, false)
+			throttle.On("Check", tt.email, "10.0.0.1", mock.Anything).Return(nil)
+			throttle.On("Fail", tt.email, "10.0.0.1", mock.Anything).Return(nil)
+			mockRepo.On("GetByEmail", tt.email).Return(tt.user, tt.err)
+
+			_, err := service.Login(tt.email, tt.password, dto.ClientInfo{IP: "10.0.0.1"})
+
+			assert.ErrorIs(t, err, appErrors.ErrInvalidCredentials)
+			throttle.AssertExpectations(t)
+			throttle.AssertNotCalled(t, "Succeed", mock.Anything)
+		})
+	}
+
+	t.Run("successful login clears the failures", func(t *testing.T) {
+		mockRepo, mockTokens, throttle := &MockUserRepositoryAuth{}, &MockRefreshTokenRepository{}, &MockLoginThrottle{}
+		service := NewAuthService(mockRepo, mockTokens, nil, openSessions(), nil, throttle, nil, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, false)
+		throttle.On("Check", "test@example.com", "10.0.0.1", mock.Anything).Return(nil)
+		throttle.On("Succeed", "test@example.com").Return(nil)
+		mockRepo.On("GetByEmail", "test@example.com").Return(user, nil)
+		mockTokens.On("Create", mock.Anything).Return(model.RefreshToken{}, nil)
+
+		_, err := service.Login("test@example.com", "password123", dto.ClientInfo{IP: "10.0.0.1"})
+
+		assert.NoError(t, err)
+		throttle.AssertExpectations(t)
+		throttle.AssertNotCalled(t, "Fail", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_Login_Rehash(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := model.User{ID: 1, Email: "test@example.com", Password: string(hashedPassword)}
+	argon2id, err := password.New(password.AlgorithmArgon2id, password.Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1}, password.Bcrypt{Cost: bcrypt.MinCost})
+	assert.NoError(t, err)
+	isArgon2id := mock.MatchedBy(func(hash string) bool {
+		return strings.HasPrefix(hash, "$argon2id$")
+	})
+
+	newService := func(hasher *password.Hasher) (*AuthService, *MockUserRepositoryAuth) {
+		mockRepo, mockTokens := &MockUserRepositoryAuth{}, &MockRefreshTokenRepository{}
+		mockTokens.On("Create", mock.Anything).Return(model.RefreshToken{}, nil)
+		mockRepo.On("GetByEmail", "test@example.com").Return(user, nil)
+		return NewAuthService(mockRepo, mockTokens, nil, openSessions(), nil, allowAllThrottle(), nil, testSigner(), hasher, testSecret, time.Minute, time.Hour, false), mockRepo
+	}
+
+	t.Run("outdated hash is replaced", func(t *testing.T) {
+		service, mockRepo := newService(argon2id)
+		mockRepo.On("ReplacePasswordHash", uint(1), user.Password, isArgon2id).Return(true, nil)
+
+		result, err := service.Login("test@example.com", "password123", dto.ClientInfo{})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Tokens.AccessToken)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("failed replacement does not block the login", func(t *testing.T) {
+		service, mockRepo := newService(argon2id)
+		mockRepo.On("ReplacePasswordHash", uint(1), user.Password, isArgon2id).Return(false, errors.New("database error"))
+
+		result, err := service.Login("test@example.com", "password123", dto.ClientInfo{})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Tokens.AccessToken)
+	})
+
+	t.Run("current hash is kept", func(t *testing.T) {
+		service, mockRepo := newService(testHasher())
+
+		_, err := service.Login("test@example.com", "password123", dto.ClientInfo{})
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "ReplacePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("wrong password is not rehashed", func(t *testing.T) {
+		service, mockRepo := newService(argon2id)
+
+		_, err := service.Login("test@example.com", "wrong", dto.ClientInfo{})
+
+		assert.ErrorIs(t, err, appErrors.ErrInvalidCredentials)
+		mockRepo.AssertNotCalled(t, "ReplacePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func TestAuthService_Register(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -216,8 +496,8 @@ func TestAuthService_Register(t *testing.T) {
 				)
 				// Create user successfully
 				m.On("Create", mock.MatchedBy(func(user model.User) bool {
-					return user.Email == "newuser@example.com" && user.Name == "New User"
-				})).Return(nil)
+					return user.Email == "newuser@example.com" && user.Name == "New User" && user.Role == model.DefaultRole
+				})).Return(model.User{ID: 1, Email: "newuser@example.com"}, nil)
 			},
 			wantError: nil,
 		},
@@ -273,7 +553,7 @@ func TestAuthService_Register(t *testing.T) {
 				// Error creating user
 				m.On("Create", mock.MatchedBy(func(user model.User) bool {
 					return user.Email == "newuser@example.com"
-				})).Return(errors.New("database insert error"))
+				})).Return(model.User{}, errors.New("database insert error"))
 			},
 			wantError: errors.New("database insert error"),
 		},
@@ -323,7 +603,7 @@ func TestAuthService_Register_PasswordHashing(t *testing.T) {
 	mockRepo.On("Create", mock.MatchedBy(func(u model.User) bool {
 		capturedUser = u
 		return true
-	})).Return(nil)
+	})).Return(model.User{ID: 1}, nil)
 
 	// Execute
 	err := service.Register(user)
@@ -346,7 +626,7 @@ func TestAuthService_Login_JWTSigningError(t *testing.T) {
 	service, mockRepo := NewAuthServiceWithMock()
 
 	// Create a hashed password for testing
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
 
 	// Mock setup
 	mockRepo.On("GetByEmail", "test@example.com").Return(
@@ -370,7 +650,9 @@ func TestAuthService_Login_JWTSigningError(t *testing.T) {
 	}()
 
 	// Execute
-	token, err := service.Login("test@example.com", "password123")
+	result, err := service.Login("test@example.com", "password123", dto.ClientInfo{IP: "10.0.0.1"})
+	tokens := result.Tokens
+	token := tokens.AccessToken
 
 	// Assert - this should succeed and generate a valid token
 	assert.NoError(t, err)
@@ -398,7 +680,7 @@ func TestAuthService_Register_ValidPassword(t *testing.T) {
 
 	mockRepo.On("Create", mock.MatchedBy(func(u model.User) bool {
 		return u.Email == "test@example.com" && u.Name == "Test User"
-	})).Return(nil)
+	})).Return(model.User{ID: 1}, nil)
 
 	// Execute
 	err := service.Register(user)
@@ -409,3 +691,449 @@ func TestAuthService_Register_ValidPassword(t *testing.T) {
 	// Verify mock expectations
 	mockRepo.AssertExpectations(t)
 }
+
+func TestAuthService_Login_IssuesRefreshToken(t *testing.T) {
+	service, mockRepo, mockTokens, _, mockSessions := newAuthServiceWithTokenMock()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	mockRepo.On("GetByEmail", "test@example.com").Return(model.User{ID: 1, Password: string(hashedPassword), Role: model.RoleAuthor}, nil)
+
+	var opened model.Session
+	mockSessions.On("Create", mock.MatchedBy(func(session model.Session) bool {
+		opened = session
+		return true
+	})).Return(model.Session{ID: 5, FamilyID: "family"}, nil)
+
+	var stored model.RefreshToken
+	mockTokens.On("Create", mock.MatchedBy(func(token model.RefreshToken) bool {
+		stored = token
+		return true
+	})).Return(model.RefreshToken{}, nil)
+
+	result, err := service.Login("test@example.com", "password123", dto.ClientInfo{IP: "10.0.0.1", UserAgent: "Firefox"})
+	tokens := result.Tokens
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Equal(t, 15*time.Minute, tokens.ExpiresIn)
+	assert.Equal(t, uint(1), stored.UserID)
+	assert.Equal(t, "family", stored.FamilyID, "the refresh token belongs to the session's family")
+	assert.Equal(t, uint(1), opened.UserID)
+	assert.NotEmpty(t, opened.FamilyID)
+	assert.Equal(t, "10.0.0.1", opened.IP)
+	assert.Equal(t, "Firefox", opened.UserAgent)
+	assert.Equal(t, utils.HashToken(tokens.RefreshToken), stored.TokenHash)
+	assert.NotEqual(t, tokens.RefreshToken, stored.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), stored.ExpiresAt, time.Minute)
+	mockTokens.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+
+	claims := jwt.MapClaims{}
+	_, _, err = new(jwt.Parser).ParseUnverified(tokens.AccessToken, claims)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, claims["jti"], "access tokens must carry a jti to be revocable")
+	assert.Equal(t, float64(5), claims["sid"], "access tokens must carry their session")
+	assert.Equal(t, "author", claims["role"])
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	hash := utils.HashToken("refresh-token")
+	active := model.RefreshToken{ID: 3, UserID: 1, FamilyID: "family", TokenHash: hash, ExpiresAt: time.Now().Add(time.Hour)}
+	session := model.Session{ID: 4, UserID: 1, FamilyID: "family"}
+	client := dto.ClientInfo{IP: "10.0.0.2", UserAgent: "Firefox"}
+	usedAt := time.Now().Add(-time.Minute)
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		mockSetup func(*MockUserRepositoryAuth, *MockRefreshTokenRepository, *MockSessionRepository)
+		wantError error
+	}{
+		{
+			name: "success - token is rotated within its family",
+			mockSetup: func(users *MockUserRepositoryAuth, m *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				m.On("GetByHash", hash).Return(active, nil)
+				sessions.On("GetByFamily", "family").Return(session, nil)
+				m.On("MarkUsed", uint(3), mock.Anything).Return(true, nil)
+				users.On("GetByID", uint(1)).Return(model.User{ID: 1, Role: model.RoleEditor}, nil)
+				sessions.On("TouchLastSeen", uint(4), "10.0.0.2", "Firefox", mock.Anything).Return(nil)
+				m.On("Create", mock.MatchedBy(func(token model.RefreshToken) bool {
+					return token.FamilyID == "family" && token.UserID == 1 && token.TokenHash != hash
+				})).Return(model.RefreshToken{}, nil)
+			},
+		},
+		{
+			name: "error - family without a session is rejected",
+			mockSetup: func(users *MockUserRepositoryAuth, m *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				m.On("GetByHash", hash).Return(active, nil)
+				sessions.On("GetByFamily", "family").Return(model.Session{}, appErrors.ErrSessionNotFound)
+			},
+			wantError: appErrors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "error - session revoked from another device",
+			mockSetup: func(users *MockUserRepositoryAuth, m *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				revoked := session
+				revoked.RevokedAt = &revokedAt
+				m.On("GetByHash", hash).Return(active, nil)
+				sessions.On("GetByFamily", "family").Return(revoked, nil)
+			},
+			wantError: appErrors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "error - user no longer exists",
+			mockSetup: func(users *MockUserRepositoryAuth, m *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				m.On("GetByHash", hash).Return(active, nil)
+				sessions.On("GetByFamily", "family").Return(session, nil)
+				m.On("MarkUsed", uint(3), mock.Anything).Return(true, nil)
+				users.On("GetByID", uint(1)).Return(model.User{}, appErrors.ErrUserNotFound)
+			},
+			wantError: appErrors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "error - unknown token",
+			mockSetup: func(users *MockUserRepositoryAuth, m *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				m.On("GetByHash", hash).Return(model.RefreshToken{}, appErrors.ErrInvalidRefreshToken)
+			},
+			wantError: appErrors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "error - expired token",
+			mockSetup: func(users *MockUserRepositoryAuth, m *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				expired := active
+				expired.ExpiresAt = time.Now().Add(-time.Second)
+				m.On("GetByHash", hash).Return(expired, nil)
+			},
+			wantError: appErrors.ErrInvalidRefreshToken,
+		},
+		{
+			name: "error - reused token revokes the family",
+			mockSetup: func(users *MockUserRepositoryAuth, m *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				used := active
+				used.UsedAt = &usedAt
+				m.On("GetByHash", hash).Return(used, nil)
+				m.On("RevokeFamily", "family", mock.Anything).Return(nil)
+			},
+			wantError: appErrors.ErrRefreshTokenReused,
+		},
+		{
+			name: "error - concurrent use revokes the family",
+			mockSetup: func(users *MockUserRepositoryAuth, m *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				m.On("GetByHash", hash).Return(active, nil)
+				sessions.On("GetByFamily", "family").Return(session, nil)
+				m.On("MarkUsed", uint(3), mock.Anything).Return(false, nil)
+				m.On("RevokeFamily", "family", mock.Anything).Return(nil)
+			},
+			wantError: appErrors.ErrRefreshTokenReused,
+		},
+		{
+			name: "error - token of an already revoked family",
+			mockSetup: func(users *MockUserRepositoryAuth, m *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				revoked := active
+				revoked.UsedAt = &usedAt
+				revoked.RevokedAt = &revokedAt
+				m.On("GetByHash", hash).Return(revoked, nil)
+			},
+			wantError: appErrors.ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo, mockTokens, _, mockSessions := newAuthServiceWithTokenMock()
+			tt.mockSetup(mockRepo, mockTokens, mockSessions)
+
+			tokens, err := service.Refresh("refresh-token", client)
+
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+				assert.Empty(t, tokens.AccessToken)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, tokens.AccessToken)
+				assert.NotEmpty(t, tokens.RefreshToken)
+				assert.NotEqual(t, "refresh-token", tokens.RefreshToken)
+
+				claims := jwt.MapClaims{}
+				_, _, err := new(jwt.Parser).ParseUnverified(tokens.AccessToken, claims)
+				assert.NoError(t, err)
+				assert.Equal(t, "editor", claims["role"], "the refreshed token carries the current role")
+				assert.Equal(t, float64(4), claims["sid"])
+			}
+			mockRepo.AssertExpectations(t)
+			mockTokens.AssertExpectations(t)
+			mockSessions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_Refresh_AfterSessionRevokedAndPurged(t *testing.T) {
+	authService, _, mockTokens, _, mockSessions := newAuthServiceWithTokenMock()
+	sessionService := NewSessionService(mockSessions, 15*time.Minute, 30*24*time.Hour)
+	hash := utils.HashToken("refresh-token")
+
+	// The session is revoked from another device and later purged
+	mockSessions.On("Revoke", uint(4), uint(1), mock.Anything).Return(true, nil)
+	mockSessions.On("DeleteInactive", mock.Anything, mock.Anything).Return(int64(1), nil)
+	assert.NoError(t, sessionService.Revoke(1, 4))
+	_, err := sessionService.Purge(time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	// Even if its refresh token still looks active, the orphaned family cannot be refreshed
+	mockTokens.On("GetByHash", hash).Return(model.RefreshToken{ID: 3, UserID: 1, FamilyID: "family", TokenHash: hash, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	mockSessions.On("GetByFamily", "family").Return(model.Session{}, appErrors.ErrSessionNotFound)
+
+	tokens, err := authService.Refresh("refresh-token", dto.ClientInfo{IP: "10.0.0.2"})
+
+	assert.ErrorIs(t, err, appErrors.ErrInvalidRefreshToken)
+	assert.Empty(t, tokens.AccessToken)
+	mockSessions.AssertNotCalled(t, "Create", mock.Anything)
+	mockTokens.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	expiresAt := time.Now().Add(10 * time.Minute)
+	hash := utils.HashToken("refresh-token")
+
+	tests := []struct {
+		name         string
+		sessionID    uint
+		refreshToken string
+		mockSetup    func(*MockRefreshTokenRepository, *MockRevokedTokenRepository, *MockSessionRepository)
+		wantError    error
+	}{
+		{
+			name: "success - access token revoked",
+			mockSetup: func(tokens *MockRefreshTokenRepository, revoked *MockRevokedTokenRepository, sessions *MockSessionRepository) {
+				revoked.On("Create", model.RevokedToken{JTI: "jti-1", UserID: 1, ExpiresAt: expiresAt}).Return(nil)
+			},
+		},
+		{
+			name:      "success - session revoked too",
+			sessionID: 4,
+			mockSetup: func(tokens *MockRefreshTokenRepository, revoked *MockRevokedTokenRepository, sessions *MockSessionRepository) {
+				revoked.On("Create", mock.Anything).Return(nil)
+				sessions.On("Revoke", uint(4), uint(1), mock.Anything).Return(true, nil)
+			},
+		},
+		{
+			name:         "success - refresh token family revoked too",
+			refreshToken: "refresh-token",
+			mockSetup: func(tokens *MockRefreshTokenRepository, revoked *MockRevokedTokenRepository, sessions *MockSessionRepository) {
+				revoked.On("Create", mock.Anything).Return(nil)
+				tokens.On("GetByHash", hash).Return(model.RefreshToken{ID: 3, UserID: 1, FamilyID: "family"}, nil)
+				tokens.On("RevokeFamily", "family", mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:         "success - unknown refresh token is ignored",
+			refreshToken: "refresh-token",
+			mockSetup: func(tokens *MockRefreshTokenRepository, revoked *MockRevokedTokenRepository, sessions *MockSessionRepository) {
+				revoked.On("Create", mock.Anything).Return(nil)
+				tokens.On("GetByHash", hash).Return(model.RefreshToken{}, appErrors.ErrInvalidRefreshToken)
+			},
+		},
+		{
+			name:         "success - refresh token of another user is not revoked",
+			refreshToken: "refresh-token",
+			mockSetup: func(tokens *MockRefreshTokenRepository, revoked *MockRevokedTokenRepository, sessions *MockSessionRepository) {
+				revoked.On("Create", mock.Anything).Return(nil)
+				tokens.On("GetByHash", hash).Return(model.RefreshToken{ID: 3, UserID: 2, FamilyID: "family"}, nil)
+			},
+		},
+		{
+			name: "error - revocation store fails",
+			mockSetup: func(tokens *MockRefreshTokenRepository, revoked *MockRevokedTokenRepository, sessions *MockSessionRepository) {
+				revoked.On("Create", mock.Anything).Return(appErrors.ErrDatabaseConnection)
+			},
+			wantError: appErrors.ErrDatabaseConnection,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, mockTokens, mockRevoked, mockSessions := newAuthServiceWithTokenMock()
+			tt.mockSetup(mockTokens, mockRevoked, mockSessions)
+
+			err := service.Logout(1, tt.sessionID, "jti-1", expiresAt, tt.refreshToken)
+
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockTokens.AssertExpectations(t)
+			mockRevoked.AssertExpectations(t)
+			mockSessions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	user := model.User{ID: 1, Password: string(hashedPassword), Role: model.RoleAuthor}
+
+	t.Run("success - password replaced and sessions revoked", func(t *testing.T) {
+		service, mockRepo, mockTokens, _, mockSessions := newAuthServiceWithTokenMock()
+		mockRepo.On("GetByID", uint(1)).Return(user, nil)
+		var changedAt time.Time
+		mockRepo.On("UpdateFields", uint(1), mock.MatchedBy(func(fields map[string]any) bool {
+			password, _ := fields["password"].(string)
+			changedAt, _ = fields["password_changed_at"].(time.Time)
+			return bcrypt.CompareHashAndPassword([]byte(password), []byte("new-password")) == nil
+		})).Return(user, nil)
+		mockTokens.On("RevokeByUser", uint(1), mock.Anything).Return(nil)
+		mockSessions.On("RevokeByUser", uint(1), uint(0), mock.Anything).Return([]uint{4, 5}, nil)
+		mockSessions.On("Create", mock.MatchedBy(func(session model.Session) bool {
+			return session.UserID == 1 && session.IP == "10.0.0.1"
+		})).Return(model.Session{ID: 6, FamilyID: "family"}, nil)
+		mockTokens.On("Create", mock.Anything).Return(model.RefreshToken{}, nil)
+
+		tokens, err := service.ChangePassword(1, "old-password", "new-password", dto.ClientInfo{IP: "10.0.0.1"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.NotEmpty(t, tokens.RefreshToken)
+		assert.WithinDuration(t, time.Now(), changedAt, time.Minute)
+		mockRepo.AssertExpectations(t)
+		mockTokens.AssertExpectations(t)
+		mockSessions.AssertExpectations(t)
+
+		// El nuevo token no puede ser anterior al cambio o el middleware lo rechazaría
+		claims := jwt.MapClaims{}
+		_, _, err = new(jwt.Parser).ParseUnverified(tokens.AccessToken, claims)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, int64(claims["iat"].(float64)), changedAt.Unix())
+	})
+
+	t.Run("error - incorrect current password", func(t *testing.T) {
+		service, mockRepo, mockTokens, _, _ := newAuthServiceWithTokenMock()
+		mockRepo.On("GetByID", uint(1)).Return(user, nil)
+
+		_, err := service.ChangePassword(1, "wrong-password", "new-password", dto.ClientInfo{})
+
+		assert.ErrorIs(t, err, appErrors.ErrIncorrectPassword)
+		mockRepo.AssertNotCalled(t, "UpdateFields", mock.Anything, mock.Anything)
+		mockTokens.AssertNotCalled(t, "RevokeByUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error - new password longer than the hasher accepts", func(t *testing.T) {
+		service, mockRepo, mockTokens, _, _ := newAuthServiceWithTokenMock()
+		mockRepo.On("GetByID", uint(1)).Return(user, nil)
+
+		_, err := service.ChangePassword(1, "old-password", strings.Repeat("ñ", password.MaxLength), dto.ClientInfo{})
+
+		assert.ErrorIs(t, err, appErrors.ErrPasswordTooLong)
+		mockRepo.AssertNotCalled(t, "UpdateFields", mock.Anything, mock.Anything)
+		mockTokens.AssertNotCalled(t, "RevokeByUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error - user not found", func(t *testing.T) {
+		service, mockRepo, _, _, _ := newAuthServiceWithTokenMock()
+		mockRepo.On("GetByID", uint(1)).Return(model.User{}, appErrors.ErrUserNotFound)
+
+		_, err := service.ChangePassword(1, "old-password", "new-password", dto.ClientInfo{})
+
+		assert.ErrorIs(t, err, appErrors.ErrUserNotFound)
+	})
+}
+
+func TestAuthService_PurgeRevokedTokens(t *testing.T) {
+	service, _, _, mockRevoked, _ := newAuthServiceWithTokenMock()
+	now := time.Now()
+	mockRevoked.On("DeleteExpired", now).Return(int64(4), nil)
+
+	purged, err := service.PurgeRevokedTokens(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+	mockRevoked.AssertExpectations(t)
+}
+
+func TestAuthService_Register_SendsVerification(t *testing.T) {
+	for _, sendErr := range []error{nil, appErrors.ErrDatabaseConnection} {
+		mockRepo, mockVerifier := &MockUserRepositoryAuth{}, &MockEmailVerifier{}
+		service := NewAuthService(mockRepo, nil, nil, nil, mockVerifier, nil, nil, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, false)
+		created := model.User{ID: 7, Email: "new@example.com"}
+		mockRepo.On("GetByEmail", "new@example.com").Return(model.User{}, appErrors.ErrUserNotFound)
+		mockRepo.On("Create", mock.Anything).Return(created, nil)
+		mockVerifier.On("Send", created).Return(sendErr)
+
+		// Registration succeeds even if the link could not be delivered
+		err := service.Register(model.User{Email: "new@example.com", Password: "password123"})
+
+		assert.NoError(t, err)
+		mockVerifier.AssertExpectations(t)
+	}
+}
+
+func TestAuthService_Login_RequiresVerifiedEmail(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name      string
+		require   bool
+		user      model.User
+		password  string
+		wantError error
+	}{
+		{"unverified user is rejected", true, model.User{ID: 1, Password: string(hashedPassword)}, "password123", appErrors.ErrEmailNotVerified},
+		{"wrong password is reported first", true, model.User{ID: 1, Password: string(hashedPassword)}, "wrong", appErrors.ErrInvalidCredentials},
+		{"verified user logs in", true, model.User{ID: 1, Password: string(hashedPassword), EmailVerifiedAt: &verifiedAt}, "password123", nil},
+		{"flag disabled allows unverified users", false, model.User{ID: 1, Password: string(hashedPassword)}, "password123", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, mockTokens := &MockUserRepositoryAuth{}, &MockRefreshTokenRepository{}
+			service := NewAuthService(mockRepo, mockTokens, nil, openSessions(), nil, allowAllThrottle(), nil, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, tt.require)
+			mockRepo.On("GetByEmail", "test@example.com").Return(tt.user, nil)
+			mockTokens.On("Create", mock.Anything).Return(model.RefreshToken{}, nil).Maybe()
+
+			_, err := service.Login("test@example.com", tt.password, dto.ClientInfo{IP: "10.0.0.1"})
+
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+				mockTokens.AssertNotCalled(t, "Create", mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthService_StartSession(t *testing.T) {
+	verifiedAt := time.Now()
+
+	t.Run("success - tokens issued", func(t *testing.T) {
+		mockTokens := &MockRefreshTokenRepository{}
+		service := NewAuthService(nil, mockTokens, nil, openSessions(), nil, nil, nil, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, true)
+		mockTokens.On("Create", mock.Anything).Return(model.RefreshToken{}, nil)
+
+		result, err := service.StartSession(model.User{ID: 1, EmailVerifiedAt: &verifiedAt}, dto.ClientInfo{})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Tokens.AccessToken)
+		assert.Nil(t, result.Challenge)
+	})
+
+	t.Run("two-factor users get a challenge", func(t *testing.T) {
+		mockTokens := &MockRefreshTokenRepository{}
+		service := NewAuthService(nil, mockTokens, nil, openSessions(), nil, nil, nil, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, false)
+
+		result, err := service.StartSession(model.User{ID: 1, TOTPEnabledAt: &verifiedAt}, dto.ClientInfo{})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result.Challenge)
+		mockTokens.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("error - email not verified", func(t *testing.T) {
+		service := NewAuthService(nil, nil, nil, nil, nil, nil, nil, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, true)
+
+		_, err := service.StartSession(model.User{ID: 1}, dto.ClientInfo{})
+
+		assert.ErrorIs(t, err, appErrors.ErrEmailNotVerified)
+	})
+}
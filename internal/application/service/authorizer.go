@@ -0,0 +1,71 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+)
+
+// Authorizer concentra las políticas de autorización sobre recursos concretos.
+// A diferencia de los permisos por rol, que se comprueban en las rutas, estas
+// reglas dependen del recurso: quién es su autor o cuándo se creó
+type Authorizer struct {
+	commentEditWindow time.Duration
+	now               func() time.Time
+}
+
+func NewAuthorizer(commentEditWindow time.Duration) *Authorizer {
+	return &Authorizer{commentEditWindow, time.Now}
+}
+
+// Authorize aplica la política de la acción. Las acciones desconocidas se deniegan
+func (a *Authorizer) Authorize(principal model.Principal, action model.Action, resource any) error {
+	switch action {
+	case model.ActionPostEdit, model.ActionPostDelete:
+		// Los autores gestionan sus publicaciones y los editores las de cualquiera
+		post, ok := resource.(model.Post)
+		if !ok {
+			return invalidResource(action, resource)
+		}
+		if post.AuthorID == principal.UserID || principal.Role.Can(model.PermPostsEditAny) {
+			return nil
+		}
+		return appErrors.ErrNotPostAuthor
+
+	case model.ActionCommentEdit:
+		// Solo el autor puede editar su comentario, y únicamente durante el plazo de edición
+		comment, ok := resource.(model.Comment)
+		if !ok {
+			return invalidResource(action, resource)
+		}
+		if comment.AuthorID != principal.UserID {
+			return appErrors.ErrNotCommentAuthor
+		}
+		if a.now().Sub(comment.CreatedAt) > a.commentEditWindow {
+			return appErrors.ErrCommentEditExpired
+		}
+		return nil
+
+	case model.ActionCommentDelete:
+		// El autor de la publicación también puede eliminar los comentarios que recibe.
+		// El comentario debe incluir su publicación
+		comment, ok := resource.(model.Comment)
+		if !ok {
+			return invalidResource(action, resource)
+		}
+		if comment.AuthorID == principal.UserID || comment.Post.AuthorID == principal.UserID {
+			return nil
+		}
+		return appErrors.ErrNotCommentAuthor
+	}
+
+	return appErrors.ErrForbidden
+}
+
+// invalidResource indica un error de programación: el servicio pasó un recurso
+// que no corresponde a la acción
+func invalidResource(action model.Action, resource any) error {
+	return appErrors.NewInternalServerError(fmt.Errorf("recurso %T no válido para la acción %s", resource, action), "Error al verificar los permisos")
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	post := model.Post{ID: 1, AuthorID: 1}
+	comment := model.Comment{ID: 1, AuthorID: 2, CreatedAt: now.Add(-10 * time.Minute), Post: model.Post{ID: 1, AuthorID: 1}}
+	oldComment := model.Comment{ID: 2, AuthorID: 2, CreatedAt: now.Add(-16 * time.Minute)}
+
+	tests := []struct {
+		name      string
+		principal model.Principal
+		action    model.Action
+		resource  any
+		wantError error
+	}{
+		{"author edits own post", model.Principal{UserID: 1, Role: model.RoleAuthor}, model.ActionPostEdit, post, nil},
+		{"author cannot edit another author's post", model.Principal{UserID: 2, Role: model.RoleAuthor}, model.ActionPostEdit, post, appErrors.ErrNotPostAuthor},
+		{"editor edits any post", model.Principal{UserID: 3, Role: model.RoleEditor}, model.ActionPostEdit, post, nil},
+		{"admin edits any post", model.Principal{UserID: 4, Role: model.RoleAdmin}, model.ActionPostEdit, post, nil},
+		{"reader cannot delete another author's post", model.Principal{UserID: 5, Role: model.RoleReader}, model.ActionPostDelete, post, appErrors.ErrNotPostAuthor},
+		{"editor deletes any post", model.Principal{UserID: 3, Role: model.RoleEditor}, model.ActionPostDelete, post, nil},
+		{"commenter edits own comment within the window", model.Principal{UserID: 2, Role: model.RoleReader}, model.ActionCommentEdit, comment, nil},
+		{"commenter cannot edit after the window", model.Principal{UserID: 2, Role: model.RoleReader}, model.ActionCommentEdit, oldComment, appErrors.ErrCommentEditExpired},
+		{"editor cannot edit another user's comment", model.Principal{UserID: 3, Role: model.RoleEditor}, model.ActionCommentEdit, comment, appErrors.ErrNotCommentAuthor},
+		{"commenter deletes own comment", model.Principal{UserID: 2, Role: model.RoleReader}, model.ActionCommentDelete, comment, nil},
+		{"post author deletes a comment on their post", model.Principal{UserID: 1, Role: model.RoleAuthor}, model.ActionCommentDelete, comment, nil},
+		{"another user cannot delete the comment", model.Principal{UserID: 9, Role: model.RoleAuthor}, model.ActionCommentDelete, comment, appErrors.ErrNotCommentAuthor},
+		{"unknown action is denied", model.Principal{UserID: 1, Role: model.RoleAdmin}, model.Action("post:launch"), post, appErrors.ErrForbidden},
+	}
+
+	authorizer := NewAuthorizer(15 * time.Minute)
+	authorizer.now = func() time.Time { return now }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(tt.principal, tt.action, tt.resource)
+
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_Authorize_InvalidResource(t *testing.T) {
+	authorizer := NewAuthorizer(15 * time.Minute)
+
+	err := authorizer.Authorize(model.Principal{UserID: 1}, model.ActionPostEdit, model.Comment{})
+
+	var appErr *appErrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 500, appErr.StatusCode)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/utils"
+)
+
+type CategoryService struct {
+	categoryRepo repository.CategoryRepositoryInterface
+}
+
+func NewCategoryService(categoryRepo repository.CategoryRepositoryInterface) *CategoryService {
+	return &CategoryService{categoryRepo}
+}
+
+// GetTree devuelve todas las categorías organizadas como árbol
+func (s *CategoryService) GetTree() ([]dto.CategoryNode, error) {
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Agrupar por categoría padre; la clave 0 corresponde a las categorías raíz
+	children := make(map[uint][]model.Category)
+	for _, category := range categories {
+		var parentID uint
+		if category.ParentID != nil {
+			parentID = *category.ParentID
+		}
+		children[parentID] = append(children[parentID], category)
+	}
+	return buildCategoryTree(children, 0), nil
+}
+
+// Create crea la categoría con un slug generado a partir del nombre
+func (s *CategoryService) Create(category model.Category) (model.Category, error) {
+	category.Name = strings.TrimSpace(category.Name)
+	category.Slug = utils.Slugify(category.Name)
+	if category.Slug == "" {
+		return model.Category{}, appErrors.ErrInvalidInput
+	}
+
+	_, err := s.categoryRepo.GetBySlug(category.Slug)
+	if err == nil {
+		return model.Category{}, appErrors.ErrCategoryExists
+	}
+	if !errors.Is(err, appErrors.ErrCategoryNotFound) {
+		return model.Category{}, err
+	}
+
+	if category.ParentID != nil {
+		if _, err := s.categoryRepo.GetByID(*category.ParentID); err != nil {
+			return model.Category{}, err
+		}
+	}
+
+	return s.categoryRepo.Create(category)
+}
+
+func buildCategoryTree(children map[uint][]model.Category, parentID uint) []dto.CategoryNode {
+	nodes := make([]dto.CategoryNode, 0, len(children[parentID]))
+	for _, category := range children[parentID] {
+		nodes = append(nodes, dto.CategoryNode{
+			CategoryResponse: dto.NewCategoryResponse(category),
+			Children:         buildCategoryTree(children, category.ID),
+		})
+	}
+	return nodes
+}
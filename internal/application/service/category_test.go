@@ -0,0 +1,119 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCategoryRepository es un mock para el repositorio de categorías
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) GetAll() ([]model.Category, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetByID(id uint) (model.Category, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetBySlug(slug string) (model.Category, error) {
+	args := m.Called(slug)
+	return args.Get(0).(model.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetDescendantIDs(id uint) ([]uint, error) {
+	args := m.Called(id)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Create(category model.Category) (model.Category, error) {
+	args := m.Called(category)
+	return args.Get(0).(model.Category), args.Error(1)
+}
+
+// NewCategoryServiceWithMock creates a CategoryService with a mock repository for testing
+func NewCategoryServiceWithMock() (*CategoryService, *MockCategoryRepository) {
+	mockRepo := &MockCategoryRepository{}
+	service := NewCategoryService(mockRepo)
+	return service, mockRepo
+}
+
+func TestCategoryService_GetTree(t *testing.T) {
+	categoryService, mockRepo := NewCategoryServiceWithMock()
+	mockRepo.On("GetAll").Return([]model.Category{
+		{ID: 1, Name: "Backend", Slug: "backend"},
+		{ID: 2, Name: "Go", Slug: "go", ParentID: uintPtr(1)},
+		{ID: 3, Name: "Concurrencia", Slug: "concurrencia", ParentID: uintPtr(2)},
+		{ID: 4, Name: "Frontend", Slug: "frontend"},
+	}, nil)
+
+	tree, err := categoryService.GetTree()
+
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "backend", tree[0].Slug)
+	assert.Equal(t, "go", tree[0].Children[0].Slug)
+	assert.Equal(t, "concurrencia", tree[0].Children[0].Children[0].Slug)
+	assert.Empty(t, tree[1].Children)
+}
+
+func TestCategoryService_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		category      model.Category
+		mockSetup     func(*MockCategoryRepository)
+		expectedError error
+	}{
+		{
+			name:     "success - child category",
+			category: model.Category{Name: "Go", ParentID: uintPtr(1)},
+			mockSetup: func(m *MockCategoryRepository) {
+				m.On("GetBySlug", "go").Return(model.Category{}, appErrors.ErrCategoryNotFound)
+				m.On("GetByID", uint(1)).Return(model.Category{ID: 1}, nil)
+				m.On("Create", model.Category{Name: "Go", Slug: "go", ParentID: uintPtr(1)}).
+					Return(model.Category{ID: 2, Name: "Go", Slug: "go", ParentID: uintPtr(1)}, nil)
+			},
+		},
+		{
+			name:     "error - slug already used",
+			category: model.Category{Name: "Go"},
+			mockSetup: func(m *MockCategoryRepository) {
+				m.On("GetBySlug", "go").Return(model.Category{ID: 2}, nil)
+			},
+			expectedError: appErrors.ErrCategoryExists,
+		},
+		{
+			name:     "error - parent does not exist",
+			category: model.Category{Name: "Go", ParentID: uintPtr(9)},
+			mockSetup: func(m *MockCategoryRepository) {
+				m.On("GetBySlug", "go").Return(model.Category{}, appErrors.ErrCategoryNotFound)
+				m.On("GetByID", uint(9)).Return(model.Category{}, appErrors.ErrCategoryNotFound)
+			},
+			expectedError: appErrors.ErrCategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryService, mockRepo := NewCategoryServiceWithMock()
+			tt.mockSetup(mockRepo)
+
+			_, err := categoryService.Create(tt.category)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+)
+
+const (
+	// DefaultCommentDepth es la profundidad del árbol cuando no se indica otra
+	DefaultCommentDepth = 5
+	// MaxCommentDepth es la profundidad máxima que se puede solicitar
+	MaxCommentDepth = 10
+)
+
+type CommentService struct {
+	commentRepo repository.CommentRepositoryInterface
+	postRepo    repository.PostRepositoryInterface
+	authorizer  domainService.AuthorizerInterface
+}
+
+func NewCommentService(commentRepo repository.CommentRepositoryInterface, postRepo repository.PostRepositoryInterface, authorizer domainService.AuthorizerInterface) *CommentService {
+	return &CommentService{commentRepo, postRepo, authorizer}
+}
+
+// GetTree devuelve los comentarios aprobados de la publicación como un árbol
+// de respuestas de hasta depth niveles
+func (s *CommentService) GetTree(postID uint, viewerID uint, depth int) ([]dto.CommentNode, error) {
+	post, err := s.postRepo.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if !post.IsPublished() && post.AuthorID != viewerID {
+		return nil, appErrors.ErrPostNotFound
+	}
+
+	if depth <= 0 {
+		depth = DefaultCommentDepth
+	}
+	if depth > MaxCommentDepth {
+		depth = MaxCommentDepth
+	}
+
+	comments, err := s.commentRepo.GetByPost(postID, model.CommentStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	// Agrupar por comentario padre; la clave 0 corresponde al primer nivel
+	children := make(map[uint][]model.Comment)
+	for _, comment := range comments {
+		var parentID uint
+		if comment.ParentID != nil {
+			parentID = *comment.ParentID
+		}
+		children[parentID] = append(children[parentID], comment)
+	}
+	return buildCommentTree(children, 0, 1, depth), nil
+}
+
+// GetPending devuelve la cola de moderación. Las rutas de moderación exigen el
+// permiso de moderar comentarios, que abarca todas las publicaciones
+func (s *CommentService) GetPending() ([]model.Comment, error) {
+	return s.commentRepo.GetPending()
+}
+
+// Create guarda el comentario. Los comentarios de quien aún no tiene ninguno
+// aprobado quedan pendientes de moderación
+func (s *CommentService) Create(comment model.Comment) (model.Comment, error) {
+	post, err := s.postRepo.GetByID(comment.PostID)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if !post.IsPublished() {
+		return model.Comment{}, appErrors.ErrPostNotFound
+	}
+
+	if comment.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(*comment.ParentID)
+		if errors.Is(err, appErrors.ErrCommentNotFound) {
+			return model.Comment{}, appErrors.ErrInvalidParentComment
+		}
+		if err != nil {
+			return model.Comment{}, err
+		}
+		if parent.PostID != comment.PostID || !parent.IsApproved() {
+			return model.Comment{}, appErrors.ErrInvalidParentComment
+		}
+	}
+
+	comment.Status = model.CommentStatusApproved
+	if comment.AuthorID != post.AuthorID {
+		approved, err := s.commentRepo.CountApprovedByAuthor(comment.AuthorID)
+		if err != nil {
+			return model.Comment{}, err
+		}
+		if approved == 0 {
+			comment.Status = model.CommentStatusPending
+		}
+	}
+
+	return s.commentRepo.Create(comment)
+}
+
+// Update modifica el texto del comentario según la política de edición de comentarios
+func (s *CommentService) Update(id uint, body string, principal model.Principal) (model.Comment, error) {
+	comment, err := s.commentRepo.GetByID(id)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if err := s.authorizer.Authorize(principal, model.ActionCommentEdit, comment); err != nil {
+		return model.Comment{}, err
+	}
+
+	comment.Body = body
+	return s.commentRepo.Update(comment)
+}
+
+// Delete elimina el comentario según la política de eliminación de comentarios
+func (s *CommentService) Delete(id uint, principal model.Principal) error {
+	comment, err := s.commentRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	// La política tiene en cuenta al autor de la publicación
+	if comment.Post, err = s.postRepo.GetByID(comment.PostID); err != nil {
+		return err
+	}
+	if err := s.authorizer.Authorize(principal, model.ActionCommentDelete, comment); err != nil {
+		return err
+	}
+
+	return s.commentRepo.Delete(id)
+}
+
+// Moderate cambia el estado de los comentarios indicados; el resultado es
+// cuántos cambiaron
+func (s *CommentService) Moderate(ids []uint, status model.CommentStatus) (int64, error) {
+	return s.commentRepo.UpdateStatus(ids, status)
+}
+
+// buildCommentTree construye las respuestas de parentID. Los comentarios del último
+// nivel permitido no incluyen sus respuestas, pero indican que existen
+func buildCommentTree(children map[uint][]model.Comment, parentID uint, level int, depth int) []dto.CommentNode {
+	nodes := make([]dto.CommentNode, 0, len(children[parentID]))
+	for _, comment := range children[parentID] {
+		node := dto.CommentNode{CommentResponse: dto.NewCommentResponse(comment), Replies: []dto.CommentNode{}}
+		if level < depth {
+			node.Replies = buildCommentTree(children, comment.ID, level+1, depth)
+		} else {
+			node.MoreReplies = len(children[comment.ID]) > 0
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
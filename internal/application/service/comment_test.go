@@ -0,0 +1,355 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCommentRepository es un mock para el repositorio de comentarios
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) GetByID(id uint) (model.Comment, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetByPost(postID uint, status model.CommentStatus) ([]model.Comment, error) {
+	args := m.Called(postID, status)
+	return args.Get(0).([]model.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetPending() ([]model.Comment, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) CountApprovedByAuthor(authorID uint) (int64, error) {
+	args := m.Called(authorID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCommentRepository) Create(comment model.Comment) (model.Comment, error) {
+	args := m.Called(comment)
+	return args.Get(0).(model.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) Update(comment model.Comment) (model.Comment, error) {
+	args := m.Called(comment)
+	return args.Get(0).(model.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) UpdateStatus(ids []uint, status model.CommentStatus) (int64, error) {
+	args := m.Called(ids, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// NewCommentServiceWithMock creates a CommentService with mock repositories for testing
+func NewCommentServiceWithMock() (*CommentService, *MockCommentRepository, *MockPostRepository) {
+	mockComments := &MockCommentRepository{}
+	mockPosts := &MockPostRepository{}
+	service := NewCommentService(mockComments, mockPosts, NewAuthorizer(15*time.Minute))
+	return service, mockComments, mockPosts
+}
+
+func uintPtr(value uint) *uint {
+	return &value
+}
+
+func TestCommentService_GetTree(t *testing.T) {
+	published := model.Post{ID: 1, Status: model.PostStatusPublished, AuthorID: 9}
+	comments := []model.Comment{
+		{ID: 1, PostID: 1, Body: "raíz"},
+		{ID: 2, PostID: 1, ParentID: uintPtr(1), Body: "nivel 2"},
+		{ID: 3, PostID: 1, ParentID: uintPtr(2), Body: "nivel 3"},
+		{ID: 4, PostID: 1, Body: "otra raíz"},
+		{ID: 5, PostID: 1, ParentID: uintPtr(99), Body: "huérfano"},
+	}
+
+	t.Run("builds nested replies", func(t *testing.T) {
+		commentService, mockComments, mockPosts := NewCommentServiceWithMock()
+		mockPosts.On("GetByID", uint(1)).Return(published, nil)
+		mockComments.On("GetByPost", uint(1), model.CommentStatusApproved).Return(comments, nil)
+
+		tree, err := commentService.GetTree(1, 0, 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, tree, 2)
+		assert.Equal(t, uint(1), tree[0].ID)
+		assert.Equal(t, uint(2), tree[0].Replies[0].ID)
+		assert.Equal(t, uint(3), tree[0].Replies[0].Replies[0].ID)
+		assert.Empty(t, tree[1].Replies)
+	})
+
+	t.Run("depth limit cuts the tree and flags more replies", func(t *testing.T) {
+		commentService, mockComments, mockPosts := NewCommentServiceWithMock()
+		mockPosts.On("GetByID", uint(1)).Return(published, nil)
+		mockComments.On("GetByPost", uint(1), model.CommentStatusApproved).Return(comments, nil)
+
+		tree, err := commentService.GetTree(1, 0, 2)
+
+		assert.NoError(t, err)
+		level2 := tree[0].Replies[0]
+		assert.Empty(t, level2.Replies)
+		assert.True(t, level2.MoreReplies)
+		assert.False(t, tree[1].MoreReplies)
+	})
+
+	t.Run("draft posts are hidden from other users", func(t *testing.T) {
+		commentService, _, mockPosts := NewCommentServiceWithMock()
+		mockPosts.On("GetByID", uint(1)).Return(model.Post{ID: 1, Status: model.PostStatusDraft, AuthorID: 9}, nil)
+
+		_, err := commentService.GetTree(1, 2, 0)
+
+		assert.ErrorIs(t, err, appErrors.ErrPostNotFound)
+	})
+}
+
+func TestBuildCommentTree_Empty(t *testing.T) {
+	tree := buildCommentTree(map[uint][]model.Comment{}, 0, 1, DefaultCommentDepth)
+
+	assert.Equal(t, []dto.CommentNode{}, tree)
+}
+
+func TestCommentService_Create(t *testing.T) {
+	published := model.Post{ID: 1, Status: model.PostStatusPublished, AuthorID: 9}
+
+	tests := []struct {
+		name           string
+		comment        model.Comment
+		mockSetup      func(*MockCommentRepository, *MockPostRepository)
+		expectedStatus model.CommentStatus
+		expectedError  error
+	}{
+		{
+			name:    "first-time commenter goes to moderation",
+			comment: model.Comment{PostID: 1, AuthorID: 2, Body: "Hola"},
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(published, nil)
+				c.On("CountApprovedByAuthor", uint(2)).Return(int64(0), nil)
+			},
+			expectedStatus: model.CommentStatusPending,
+		},
+		{
+			name:    "known commenter is approved",
+			comment: model.Comment{PostID: 1, AuthorID: 2, Body: "Hola"},
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(published, nil)
+				c.On("CountApprovedByAuthor", uint(2)).Return(int64(3), nil)
+			},
+			expectedStatus: model.CommentStatusApproved,
+		},
+		{
+			name:    "post author is approved",
+			comment: model.Comment{PostID: 1, AuthorID: 9, Body: "Gracias"},
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(published, nil)
+			},
+			expectedStatus: model.CommentStatusApproved,
+		},
+		{
+			name:    "reply to an approved comment",
+			comment: model.Comment{PostID: 1, ParentID: uintPtr(5), AuthorID: 9, Body: "Respuesta"},
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(published, nil)
+				c.On("GetByID", uint(5)).Return(model.Comment{ID: 5, PostID: 1, Status: model.CommentStatusApproved}, nil)
+			},
+			expectedStatus: model.CommentStatusApproved,
+		},
+		{
+			name:    "reply to a comment of another post",
+			comment: model.Comment{PostID: 1, ParentID: uintPtr(5), AuthorID: 2, Body: "Respuesta"},
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(published, nil)
+				c.On("GetByID", uint(5)).Return(model.Comment{ID: 5, PostID: 2, Status: model.CommentStatusApproved}, nil)
+			},
+			expectedError: appErrors.ErrInvalidParentComment,
+		},
+		{
+			name:    "reply to a pending comment",
+			comment: model.Comment{PostID: 1, ParentID: uintPtr(5), AuthorID: 2, Body: "Respuesta"},
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(published, nil)
+				c.On("GetByID", uint(5)).Return(model.Comment{ID: 5, PostID: 1, Status: model.CommentStatusPending}, nil)
+			},
+			expectedError: appErrors.ErrInvalidParentComment,
+		},
+		{
+			name:    "reply to a missing comment",
+			comment: model.Comment{PostID: 1, ParentID: uintPtr(5), AuthorID: 2, Body: "Respuesta"},
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(published, nil)
+				c.On("GetByID", uint(5)).Return(model.Comment{}, appErrors.ErrCommentNotFound)
+			},
+			expectedError: appErrors.ErrInvalidParentComment,
+		},
+		{
+			name:    "unpublished post",
+			comment: model.Comment{PostID: 1, AuthorID: 2, Body: "Hola"},
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(model.Post{ID: 1, Status: model.PostStatusDraft, AuthorID: 9}, nil)
+			},
+			expectedError: appErrors.ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentService, mockComments, mockPosts := NewCommentServiceWithMock()
+			tt.mockSetup(mockComments, mockPosts)
+			if tt.expectedError == nil {
+				mockComments.On("Create", mock.MatchedBy(func(c model.Comment) bool {
+					return c.Status == tt.expectedStatus
+				})).Return(model.Comment{ID: 1, Status: tt.expectedStatus}, nil)
+			}
+
+			comment, err := commentService.Create(tt.comment)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, comment.Status)
+			}
+			mockComments.AssertExpectations(t)
+			mockPosts.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCommentService_Update(t *testing.T) {
+	tests := []struct {
+		name          string
+		comment       model.Comment
+		userID        uint
+		expectedError error
+	}{
+		{
+			name:    "author within the edit window",
+			comment: model.Comment{ID: 1, AuthorID: 2, Body: "Hola", CreatedAt: time.Now().Add(-5 * time.Minute)},
+			userID:  2,
+		},
+		{
+			name:          "edit window expired",
+			comment:       model.Comment{ID: 1, AuthorID: 2, Body: "Hola", CreatedAt: time.Now().Add(-time.Hour)},
+			userID:        2,
+			expectedError: appErrors.ErrCommentEditExpired,
+		},
+		{
+			name:          "not the author",
+			comment:       model.Comment{ID: 1, AuthorID: 2, Body: "Hola", CreatedAt: time.Now()},
+			userID:        3,
+			expectedError: appErrors.ErrNotCommentAuthor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentService, mockComments, _ := NewCommentServiceWithMock()
+			mockComments.On("GetByID", uint(1)).Return(tt.comment, nil)
+			if tt.expectedError == nil {
+				mockComments.On("Update", mock.MatchedBy(func(c model.Comment) bool {
+					return c.Body == "Editado"
+				})).Return(model.Comment{ID: 1, Body: "Editado"}, nil)
+			}
+
+			comment, err := commentService.Update(1, "Editado", model.Principal{UserID: tt.userID})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "Editado", comment.Body)
+			}
+			mockComments.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCommentService_Delete(t *testing.T) {
+	comment := model.Comment{ID: 1, PostID: 1, AuthorID: 2}
+
+	tests := []struct {
+		name          string
+		userID        uint
+		mockSetup     func(*MockCommentRepository, *MockPostRepository)
+		expectedError error
+	}{
+		{
+			name:   "comment author",
+			userID: 2,
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(model.Post{ID: 1, AuthorID: 9}, nil)
+				c.On("Delete", uint(1)).Return(nil)
+			},
+		},
+		{
+			name:   "post author",
+			userID: 9,
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(model.Post{ID: 1, AuthorID: 9}, nil)
+				c.On("Delete", uint(1)).Return(nil)
+			},
+		},
+		{
+			name:   "another user",
+			userID: 3,
+			mockSetup: func(c *MockCommentRepository, p *MockPostRepository) {
+				p.On("GetByID", uint(1)).Return(model.Post{ID: 1, AuthorID: 9}, nil)
+			},
+			expectedError: appErrors.ErrNotCommentAuthor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentService, mockComments, mockPosts := NewCommentServiceWithMock()
+			mockComments.On("GetByID", uint(1)).Return(comment, nil)
+			tt.mockSetup(mockComments, mockPosts)
+
+			err := commentService.Delete(1, model.Principal{UserID: tt.userID})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockComments.AssertExpectations(t)
+			mockPosts.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCommentService_GetPending(t *testing.T) {
+	commentService, mockComments, _ := NewCommentServiceWithMock()
+	mockComments.On("GetPending").Return([]model.Comment{{ID: 1}}, nil)
+
+	comments, err := commentService.GetPending()
+
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	mockComments.AssertExpectations(t)
+}
+
+func TestCommentService_Moderate(t *testing.T) {
+	commentService, mockComments, _ := NewCommentServiceWithMock()
+	mockComments.On("UpdateStatus", []uint{1, 2}, model.CommentStatusSpam).Return(int64(2), nil)
+
+	updated, err := commentService.Moderate([]uint{1, 2}, model.CommentStatusSpam)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+	mockComments.AssertExpectations(t)
+}
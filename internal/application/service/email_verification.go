@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/utils"
+)
+
+// verificationPurpose separa la firma de los enlaces de verificación de la de
+// cualquier otro token firmado con el mismo secreto
+const verificationPurpose = "email-verification"
+
+// verificationClaims es el contenido firmado de un enlace de verificación
+type verificationClaims struct {
+	UserID    uint   `json:"uid"`
+	Email     string `json:"email"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// EmailVerificationService envía y comprueba los enlaces de verificación de
+// email, firmados con secret
+type EmailVerificationService struct {
+	userRepo       repository.UserRepositoryInterface
+	notifier       domainService.AccountNotifierInterface
+	secret         string
+	ttl            time.Duration
+	resendInterval time.Duration
+}
+
+func NewEmailVerificationService(userRepo repository.UserRepositoryInterface, notifier domainService.AccountNotifierInterface, secret string, ttl, resendInterval time.Duration) *EmailVerificationService {
+	return &EmailVerificationService{userRepo, notifier, secret, ttl, resendInterval}
+}
+
+// Send envía al usuario un enlace para verificar su email actual. El momento del
+// envío se guarda en el usuario y forma parte del token, de modo que solo el
+// último enlace enviado es válido
+func (s *EmailVerificationService) Send(user model.User) error {
+	now := time.Now()
+	if _, err := s.userRepo.UpdateFields(user.ID, map[string]any{"verification_sent_at": now}); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(verificationClaims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.ttl).Unix(),
+	})
+	if err != nil {
+		return appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+
+	token := utils.SignToken(data, s.secret, verificationPurpose)
+	return s.notifier.SendVerification(user, token)
+}
+
+// Verify marca como verificado el email del token. El token deja de ser válido
+// si expiró, si ya se usó, si se envió otro más reciente o si el usuario cambió
+// de email desde que se generó
+func (s *EmailVerificationService) Verify(token string) error {
+	now := time.Now()
+
+	data, err := utils.VerifySignedToken(token, s.secret, verificationPurpose)
+	if err != nil {
+		return appErrors.ErrInvalidVerificationToken
+	}
+	var claims verificationClaims
+	if err := json.Unmarshal(data, &claims); err != nil || now.Unix() > claims.ExpiresAt {
+		return appErrors.ErrInvalidVerificationToken
+	}
+
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			return appErrors.ErrInvalidVerificationToken
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil || user.Email != claims.Email ||
+		user.VerificationSentAt == nil || user.VerificationSentAt.Unix() != claims.IssuedAt {
+		return appErrors.ErrInvalidVerificationToken
+	}
+
+	_, err = s.userRepo.UpdateFields(user.ID, map[string]any{
+		"email_verified_at":    now,
+		"verification_sent_at": nil,
+	})
+	return err
+}
+
+// Resend vuelve a enviar el enlace de verificación. No informa si el email no
+// existe, ya está verificado o se envió un enlace hace menos de resendInterval,
+// para no revelar qué cuentas existen
+func (s *EmailVerificationService) Resend(email string) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+	if user.VerificationSentAt != nil && time.Since(*user.VerificationSentAt) < s.resendInterval {
+		return nil
+	}
+	return s.Send(user)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAccountNotifier mocks the AccountNotifier and keeps the last token sent
+type MockAccountNotifier struct {
+	mock.Mock
+	token string
+}
+
+func (m *MockAccountNotifier) SendVerification(user model.User, token string) error {
+	m.token = token
+	args := m.Called(user, token)
+	return args.Error(0)
+}
+
+func (m *MockAccountNotifier) SendPasswordReset(user model.User, token string) error {
+	m.token = token
+	args := m.Called(user, token)
+	return args.Error(0)
+}
+
+// issueVerificationToken sends a verification link with the given ttl and
+// returns the token and the send time stored for the user
+func issueVerificationToken(t *testing.T, user model.User, ttl time.Duration) (string, time.Time) {
+	mockRepo, mockNotifier := &MockUserRepository{}, &MockAccountNotifier{}
+	var sentAt time.Time
+	mockRepo.On("UpdateFields", user.ID, mock.MatchedBy(func(fields map[string]any) bool {
+		sentAt, _ = fields["verification_sent_at"].(time.Time)
+		return true
+	})).Return(user, nil)
+	mockNotifier.On("SendVerification", user, mock.Anything).Return(nil)
+
+	err := NewEmailVerificationService(mockRepo, mockNotifier, testSecret, ttl, time.Minute).Send(user)
+
+	require.NoError(t, err)
+	require.False(t, sentAt.IsZero())
+	return mockNotifier.token, sentAt
+}
+
+func TestEmailVerificationService_SendAndVerify(t *testing.T) {
+	user := model.User{ID: 1, Email: "john@example.com"}
+	token, sentAt := issueVerificationToken(t, user, time.Hour)
+
+	mockRepo := &MockUserRepository{}
+	mockRepo.On("GetByID", uint(1)).Return(model.User{ID: 1, Email: "john@example.com", VerificationSentAt: &sentAt}, nil)
+	mockRepo.On("UpdateFields", uint(1), mock.MatchedBy(func(fields map[string]any) bool {
+		_, verified := fields["email_verified_at"].(time.Time)
+		return verified && fields["verification_sent_at"] == nil
+	})).Return(model.User{}, nil)
+
+	err := NewEmailVerificationService(mockRepo, nil, testSecret, time.Hour, time.Minute).Verify(token)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEmailVerificationService_Verify_Invalid(t *testing.T) {
+	user := model.User{ID: 1, Email: "john@example.com"}
+	token, sentAt := issueVerificationToken(t, user, time.Hour)
+	expired, expiredSentAt := issueVerificationToken(t, user, -time.Minute)
+	verifiedAt := time.Now()
+	newerSentAt := sentAt.Add(time.Minute)
+
+	tests := []struct {
+		name   string
+		token  string
+		stored model.User
+		err    error
+	}{
+		{"tampered token", token + "x", user, nil},
+		{"expired token", expired, model.User{ID: 1, Email: "john@example.com", VerificationSentAt: &expiredSentAt}, nil},
+		{"already used", token, model.User{ID: 1, Email: "john@example.com", EmailVerifiedAt: &verifiedAt}, nil},
+		{"newer link sent", token, model.User{ID: 1, Email: "john@example.com", VerificationSentAt: &newerSentAt}, nil},
+		{"email changed", token, model.User{ID: 1, Email: "new@example.com", VerificationSentAt: &sentAt}, nil},
+		{"user deleted", token, model.User{}, appErrors.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockUserRepository{}
+			mockRepo.On("GetByID", uint(1)).Return(tt.stored, tt.err).Maybe()
+
+			err := NewEmailVerificationService(mockRepo, nil, testSecret, time.Hour, time.Minute).Verify(tt.token)
+
+			assert.ErrorIs(t, err, appErrors.ErrInvalidVerificationToken)
+			mockRepo.AssertNotCalled(t, "UpdateFields", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEmailVerificationService_Verify_RejectsOtherSecret(t *testing.T) {
+	token, _ := issueVerificationToken(t, model.User{ID: 1, Email: "john@example.com"}, time.Hour)
+
+	err := NewEmailVerificationService(&MockUserRepository{}, nil, "rotated-secret", time.Hour, time.Minute).Verify(token)
+
+	assert.ErrorIs(t, err, appErrors.ErrInvalidVerificationToken)
+}
+
+func TestEmailVerificationService_Resend(t *testing.T) {
+	recent := time.Now().Add(-10 * time.Second)
+	old := time.Now().Add(-time.Hour)
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name     string
+		user     model.User
+		err      error
+		wantSend bool
+	}{
+		{"unknown email is ignored", model.User{}, appErrors.ErrUserNotFound, false},
+		{"verified user is ignored", model.User{ID: 1, Email: "john@example.com", EmailVerifiedAt: &verifiedAt}, nil, false},
+		{"throttled while the last link is recent", model.User{ID: 1, Email: "john@example.com", VerificationSentAt: &recent}, nil, false},
+		{"sends after the interval", model.User{ID: 1, Email: "john@example.com", VerificationSentAt: &old}, nil, true},
+		{"sends when no link was sent", model.User{ID: 1, Email: "john@example.com"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, mockNotifier := &MockUserRepository{}, &MockAccountNotifier{}
+			mockRepo.On("GetByEmail", "john@example.com").Return(tt.user, tt.err)
+			mockRepo.On("UpdateFields", uint(1), mock.Anything).Return(tt.user, nil).Maybe()
+			mockNotifier.On("SendVerification", tt.user, mock.Anything).Return(nil).Maybe()
+
+			err := NewEmailVerificationService(mockRepo, mockNotifier, testSecret, time.Hour, time.Minute).Resend("john@example.com")
+
+			assert.NoError(t, err)
+			if tt.wantSend {
+				mockNotifier.AssertCalled(t, "SendVerification", tt.user, mock.Anything)
+			} else {
+				mockNotifier.AssertNotCalled(t, "SendVerification", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestEmailVerificationService_Resend_DatabaseError(t *testing.T) {
+	mockRepo := &MockUserRepository{}
+	mockRepo.On("GetByEmail", "john@example.com").Return(model.User{}, appErrors.ErrDatabaseConnection)
+
+	err := NewEmailVerificationService(mockRepo, nil, testSecret, time.Hour, time.Minute).Resend("john@example.com")
+
+	assert.ErrorIs(t, err, appErrors.ErrDatabaseConnection)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+)
+
+// LoginThrottlePolicy configura la protección contra fuerza bruta. Cada fallo
+// retrasa el siguiente intento Backoff, duplicándose con cada fallo consecutivo
+// sin superar Lockout, y al llegar al máximo de fallos el email o la IP quedan
+// bloqueados durante Lockout. Los fallos se olvidan si pasa Window sin ninguno
+type LoginThrottlePolicy struct {
+	MaxFailures   int
+	MaxIPFailures int
+	Backoff       time.Duration
+	Lockout       time.Duration
+	Window        time.Duration
+}
+
+// LoginThrottle limita los intentos de inicio de sesión por email y por IP. El
+// límite por IP es más alto porque varios usuarios pueden compartir la misma
+type LoginThrottle struct {
+	attemptRepo repository.LoginAttemptRepositoryInterface
+	userRepo    repository.UserRepositoryInterface
+	policy      LoginThrottlePolicy
+}
+
+func NewLoginThrottle(attemptRepo repository.LoginAttemptRepositoryInterface, userRepo repository.UserRepositoryInterface, policy LoginThrottlePolicy) *LoginThrottle {
+	return &LoginThrottle{attemptRepo, userRepo, policy}
+}
+
+// throttleKey es una clave del contador junto con su máximo de fallos
+type throttleKey struct {
+	key         string
+	maxFailures int
+}
+
+func (t *LoginThrottle) keys(email, ip string) []throttleKey {
+	keys := []throttleKey{{emailKey(email), t.policy.MaxFailures}}
+	if ip != "" {
+		keys = append(keys, throttleKey{"ip:" + ip, t.policy.MaxIPFailures})
+	}
+	return keys
+}
+
+// emailKey normaliza el email para que las variaciones de mayúsculas no eviten el límite
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// Check devuelve ErrTooManyLoginAttempts, con el tiempo que falta para poder
+// reintentar, si el email o la IP están bloqueados
+func (t *LoginThrottle) Check(email, ip string, now time.Time) error {
+	var wait time.Duration
+	for _, k := range t.keys(email, ip) {
+		attempt, err := t.attemptRepo.Get(k.key)
+		if err != nil {
+			return err
+		}
+		wait = max(wait, attempt.RetryAfter(now))
+	}
+	if wait > 0 {
+		return appErrors.NewRetryAfterError(appErrors.ErrTooManyLoginAttempts, wait)
+	}
+	return nil
+}
+
+// Fail registra un intento fallido y bloquea el email y la IP hasta que se
+// admita el siguiente
+func (t *LoginThrottle) Fail(email, ip string, now time.Time) error {
+	for _, k := range t.keys(email, ip) {
+		attempt, err := t.attemptRepo.RecordFailure(k.key, now, t.policy.Window)
+		if err != nil {
+			return err
+		}
+		if err := t.attemptRepo.Lock(k.key, now.Add(t.delay(attempt.Failures, k.maxFailures))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// delay devuelve la espera tras el fallo número failures
+func (t *LoginThrottle) delay(failures, maxFailures int) time.Duration {
+	if failures >= maxFailures {
+		return t.policy.Lockout
+	}
+	delay := t.policy.Backoff
+	for i := 1; i < failures && delay < t.policy.Lockout; i++ {
+		delay *= 2
+	}
+	return min(delay, t.policy.Lockout)
+}
+
+// Succeed olvida los fallos del email tras un inicio de sesión correcto. Los de
+// la IP se mantienen para que acertar con una cuenta propia no permita seguir
+// probando contraseñas de otras
+func (t *LoginThrottle) Succeed(email string) error {
+	return t.attemptRepo.Reset(emailKey(email))
+}
+
+// Unlock levanta el bloqueo de la cuenta de un usuario y olvida sus fallos
+func (t *LoginThrottle) Unlock(userID uint) error {
+	user, err := t.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	return t.attemptRepo.Reset(emailKey(user.Email))
+}
+
+// PurgeExpired elimina los contadores que ya no afectan a los siguientes
+// intentos y devuelve cuántos se eliminaron
+func (t *LoginThrottle) PurgeExpired(now time.Time) (int64, error) {
+	return t.attemptRepo.DeleteExpired(now, t.policy.Window)
+}
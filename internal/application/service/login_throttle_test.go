@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLoginAttemptRepository mocks the LoginAttemptRepository
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptRepository) Get(key string) (model.LoginAttempt, error) {
+	args := m.Called(key)
+	return args.Get(0).(model.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) RecordFailure(key string, now time.Time, window time.Duration) (model.LoginAttempt, error) {
+	args := m.Called(key, now, window)
+	return args.Get(0).(model.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) Lock(key string, until time.Time) error {
+	args := m.Called(key, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) Reset(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) DeleteExpired(now time.Time, window time.Duration) (int64, error) {
+	args := m.Called(now, window)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var testThrottlePolicy = LoginThrottlePolicy{
+	MaxFailures:   5,
+	MaxIPFailures: 20,
+	Backoff:       time.Second,
+	Lockout:       15 * time.Minute,
+	Window:        time.Hour,
+}
+
+func newLoginThrottleWithMocks() (*LoginThrottle, *MockLoginAttemptRepository, *MockUserRepository) {
+	attempts := &MockLoginAttemptRepository{}
+	users := &MockUserRepository{}
+	return NewLoginThrottle(attempts, users, testThrottlePolicy), attempts, users
+}
+
+func TestLoginThrottle_Check(t *testing.T) {
+	now := time.Now()
+	soon, later := now.Add(10*time.Second), now.Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		email     model.LoginAttempt
+		ip        model.LoginAttempt
+		wantRetry time.Duration
+	}{
+		{"no failures", model.LoginAttempt{}, model.LoginAttempt{}, 0},
+		{"expired lock", model.LoginAttempt{Failures: 2, LockedUntil: &now}, model.LoginAttempt{}, 0},
+		{"email locked", model.LoginAttempt{Failures: 5, LockedUntil: &later}, model.LoginAttempt{}, time.Minute},
+		{"longest wait wins", model.LoginAttempt{Failures: 2, LockedUntil: &soon}, model.LoginAttempt{Failures: 20, LockedUntil: &later}, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttle, attempts, _ := newLoginThrottleWithMocks()
+			attempts.On("Get", "email:john@example.com").Return(tt.email, nil)
+			attempts.On("Get", "ip:10.0.0.1").Return(tt.ip, nil)
+
+			// Emails are normalised so case variations share the counter
+			err := throttle.Check(" John@Example.com", "10.0.0.1", now)
+
+			if tt.wantRetry == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var retryErr *appErrors.RetryAfterError
+			assert.True(t, errors.As(err, &retryErr))
+			assert.ErrorIs(t, err, appErrors.ErrTooManyLoginAttempts)
+			assert.Equal(t, tt.wantRetry, retryErr.RetryAfter)
+		})
+	}
+}
+
+func TestLoginThrottle_Fail(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		failures   int
+		ipFailures int
+		wantEmail  time.Duration
+		wantIP     time.Duration
+	}{
+		{"first failure waits the base delay", 1, 1, time.Second, time.Second},
+		{"delay doubles with each failure", 4, 4, 8 * time.Second, 8 * time.Second},
+		{"email locked at the maximum", 5, 5, 15 * time.Minute, 16 * time.Second},
+		{"delay never exceeds the lockout", 5, 19, 15 * time.Minute, 15 * time.Minute},
+		{"ip locked at its own maximum", 1, 20, time.Second, 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttle, attempts, _ := newLoginThrottleWithMocks()
+			attempts.On("RecordFailure", "email:john@example.com", now, time.Hour).Return(model.LoginAttempt{Failures: tt.failures}, nil)
+			attempts.On("RecordFailure", "ip:10.0.0.1", now, time.Hour).Return(model.LoginAttempt{Failures: tt.ipFailures}, nil)
+			attempts.On("Lock", "email:john@example.com", now.Add(tt.wantEmail)).Return(nil)
+			attempts.On("Lock", "ip:10.0.0.1", now.Add(tt.wantIP)).Return(nil)
+
+			err := throttle.Fail("john@example.com", "10.0.0.1", now)
+
+			assert.NoError(t, err)
+			attempts.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginThrottle_Fail_WithoutIP(t *testing.T) {
+	throttle, attempts, _ := newLoginThrottleWithMocks()
+	now := time.Now()
+	attempts.On("RecordFailure", "email:john@example.com", now, time.Hour).Return(model.LoginAttempt{Failures: 1}, nil)
+	attempts.On("Lock", "email:john@example.com", now.Add(time.Second)).Return(nil)
+
+	assert.NoError(t, throttle.Fail("john@example.com", "", now))
+	attempts.AssertExpectations(t)
+}
+
+func TestLoginThrottle_Succeed(t *testing.T) {
+	throttle, attempts, _ := newLoginThrottleWithMocks()
+	attempts.On("Reset", "email:john@example.com").Return(nil)
+
+	assert.NoError(t, throttle.Succeed("John@example.com"))
+	attempts.AssertExpectations(t)
+}
+
+func TestLoginThrottle_Unlock(t *testing.T) {
+	t.Run("success - account failures cleared", func(t *testing.T) {
+		throttle, attempts, users := newLoginThrottleWithMocks()
+		users.On("GetByID", uint(3)).Return(model.User{ID: 3, Email: "john@example.com"}, nil)
+		attempts.On("Reset", "email:john@example.com").Return(nil)
+
+		assert.NoError(t, throttle.Unlock(3))
+		attempts.AssertExpectations(t)
+	})
+
+	t.Run("error - user not found", func(t *testing.T) {
+		throttle, attempts, users := newLoginThrottleWithMocks()
+		users.On("GetByID", uint(3)).Return(model.User{}, appErrors.ErrUserNotFound)
+
+		assert.ErrorIs(t, throttle.Unlock(3), appErrors.ErrUserNotFound)
+		attempts.AssertNotCalled(t, "Reset", mock.Anything)
+	})
+}
+
+func TestLoginThrottle_PurgeExpired(t *testing.T) {
+	throttle, attempts, _ := newLoginThrottleWithMocks()
+	now := time.Now()
+	attempts.On("DeleteExpired", now, time.Hour).Return(int64(3), nil)
+
+	purged, err := throttle.PurgeExpired(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/oidc"
+	"github.com/UliVargas/blog-go/pkg/utils"
+)
+
+// oidcStatePurpose separa la firma del estado de los inicios de sesión externos
+// de la de otros tokens firmados, y oidcStateTTL es el tiempo que tiene el
+// usuario para volver del proveedor
+const (
+	oidcStatePurpose = "oidc-state"
+	oidcStateTTL     = 10 * time.Minute
+)
+
+// oidcStateClaims es el contenido firmado del estado, que el navegador guarda
+// en una cookie. Al proveedor solo se envían State y el challenge del verifier,
+// por lo que interceptar la vuelta no basta para canjear el código
+type oidcStateClaims struct {
+	Provider  string `json:"p"`
+	State     string `json:"s"`
+	Nonce     string `json:"n"`
+	Verifier  string `json:"v"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// OIDCService gestiona el inicio de sesión con proveedores OpenID Connect. La
+// primera vez que se usa una cuenta externa se vincula al usuario con el mismo
+// email o, si no existe, se crea uno nuevo. La sesión la abre sessions y la
+// contraseña aleatoria de los usuarios nuevos se cifra con hasher. El estado que
+// se guarda en el navegador mientras dura el inicio de sesión se firma con secret
+type OIDCService struct {
+	providers    map[string]*oidc.Provider
+	userRepo     repository.UserRepositoryInterface
+	identityRepo repository.IdentityRepositoryInterface
+	sessions     domainService.SessionIssuerInterface
+	hasher       domainService.PasswordHasherInterface
+	secret       string
+}
+
+func NewOIDCService(providers map[string]*oidc.Provider, userRepo repository.UserRepositoryInterface, identityRepo repository.IdentityRepositoryInterface, sessions domainService.SessionIssuerInterface, hasher domainService.PasswordHasherInterface, secret string) *OIDCService {
+	return &OIDCService{providers, userRepo, identityRepo, sessions, hasher, secret}
+}
+
+// Providers devuelve los nombres de los proveedores configurados
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Begin prepara el inicio de sesión con el proveedor: genera el state, el nonce
+// y el verifier de PKCE y devuelve la URL del proveedor junto con el estado que
+// se debe presentar en Callback
+func (s *OIDCService) Begin(name string) (dto.OIDCAuthorization, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return dto.OIDCAuthorization{}, appErrors.ErrOIDCProviderNotFound
+	}
+
+	state, err := utils.GenerateToken(tokenIDBytes)
+	if err != nil {
+		return dto.OIDCAuthorization{}, appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+	nonce, err := utils.GenerateToken(tokenIDBytes)
+	if err != nil {
+		return dto.OIDCAuthorization{}, appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		return dto.OIDCAuthorization{}, appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+
+	authURL, err := provider.AuthCodeURL(state, nonce, verifier)
+	if err != nil {
+		return dto.OIDCAuthorization{}, providerError(name, err)
+	}
+
+	data, err := json.Marshal(oidcStateClaims{
+		Provider:  name,
+		State:     state,
+		Nonce:     nonce,
+		Verifier:  verifier,
+		ExpiresAt: time.Now().Add(oidcStateTTL).Unix(),
+	})
+	if err != nil {
+		return dto.OIDCAuthorization{}, appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+
+	return dto.OIDCAuthorization{
+		URL:       authURL,
+		State:     utils.SignToken(data, s.secret, oidcStatePurpose),
+		ExpiresIn: oidcStateTTL,
+	}, nil
+}
+
+// Callback completa el inicio de sesión cuando el proveedor redirige de vuelta
+// con code y state. stateCookie es el estado devuelto por Begin y client el
+// dispositivo en el que se abre la sesión
+func (s *OIDCService) Callback(name, code, state, stateCookie string, client dto.ClientInfo) (dto.LoginResult, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return dto.LoginResult{}, appErrors.ErrOIDCProviderNotFound
+	}
+
+	data, err := utils.VerifySignedToken(stateCookie, s.secret, oidcStatePurpose)
+	if err != nil {
+		return dto.LoginResult{}, appErrors.ErrInvalidOIDCState
+	}
+	var claims oidcStateClaims
+	if err := json.Unmarshal(data, &claims); err != nil || time.Now().Unix() > claims.ExpiresAt {
+		return dto.LoginResult{}, appErrors.ErrInvalidOIDCState
+	}
+	if claims.Provider != name || subtle.ConstantTimeCompare([]byte(claims.State), []byte(state)) != 1 {
+		return dto.LoginResult{}, appErrors.ErrInvalidOIDCState
+	}
+
+	identity, err := provider.Authenticate(code, claims.Verifier, claims.Nonce)
+	if err != nil {
+		return dto.LoginResult{}, providerError(name, err)
+	}
+
+	user, err := s.resolveUser(name, identity)
+	if err != nil {
+		return dto.LoginResult{}, err
+	}
+	return s.sessions.StartSession(user, client)
+}
+
+// resolveUser devuelve el usuario vinculado a la cuenta externa. Si no hay
+// ninguno, la vincula al usuario con el mismo email o crea uno nuevo, siempre
+// que el proveedor haya verificado el email
+func (s *OIDCService) resolveUser(provider string, claims oidc.Claims) (model.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(provider, claims.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(identity.UserID)
+	}
+	if !errors.Is(err, appErrors.ErrIdentityNotFound) {
+		return model.User{}, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return model.User{}, appErrors.ErrOIDCEmailNotVerified
+	}
+	identity = model.Identity{Provider: provider, Subject: claims.Subject, Email: claims.Email}
+
+	user, err := s.userRepo.GetByEmail(claims.Email)
+	if err == nil {
+		// Cualquiera pudo registrar una cuenta local con ese email sin verificarlo;
+		// vincularla le daría acceso a la cuenta del dueño real del email
+		if user.EmailVerifiedAt == nil {
+			return model.User{}, appErrors.ErrOIDCAccountNotLinkable
+		}
+		identity.UserID = user.ID
+		if _, err := s.identityRepo.Create(identity); err != nil {
+			return model.User{}, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, appErrors.ErrUserNotFound) {
+		return model.User{}, err
+	}
+
+	user, err = s.newExternalUser(claims)
+	if err != nil {
+		return model.User{}, err
+	}
+	created, err := s.identityRepo.CreateWithUser(user, identity)
+	if err != nil {
+		return model.User{}, err
+	}
+	return created.User, nil
+}
+
+// newExternalUser prepara el usuario de una cuenta externa. Su contraseña es
+// aleatoria y nadie la conoce: para iniciar sesión con email y contraseña debe
+// restablecerla antes
+func (s *OIDCService) newExternalUser(claims oidc.Claims) (model.User, error) {
+	password, err := utils.GenerateToken(refreshTokenBytes)
+	if err != nil {
+		return model.User{}, appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+	hashedPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		return model.User{}, appErrors.NewInternalServerError(err, "Error al procesar la contraseña")
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+	verifiedAt := time.Now()
+	return model.User{
+		Name:            name,
+		Email:           claims.Email,
+		EmailVerifiedAt: &verifiedAt,
+		Password:        hashedPassword,
+		Role:            model.DefaultRole,
+	}, nil
+}
+
+// providerError registra el error del proveedor y devuelve el que se informa al
+// cliente, sin detalles
+func providerError(provider string, err error) error {
+	log.Printf("Error en el inicio de sesión con %s: %v", provider, err)
+	if errors.Is(err, oidc.ErrDiscovery) {
+		return appErrors.ErrOIDCProviderUnavailable
+	}
+	return appErrors.ErrOIDCAuthenticationFailed
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/oidc"
+	"github.com/UliVargas/blog-go/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockIdentityRepository mocks the IdentityRepository
+type MockIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdentityRepository) GetByProviderSubject(provider, subject string) (model.Identity, error) {
+	args := m.Called(provider, subject)
+	return args.Get(0).(model.Identity), args.Error(1)
+}
+
+func (m *MockIdentityRepository) Create(identity model.Identity) (model.Identity, error) {
+	args := m.Called(identity)
+	return args.Get(0).(model.Identity), args.Error(1)
+}
+
+func (m *MockIdentityRepository) CreateWithUser(user model.User, identity model.Identity) (model.Identity, error) {
+	args := m.Called(user, identity)
+	return args.Get(0).(model.Identity), args.Error(1)
+}
+
+// MockSessionIssuer mocks the AuthService session issuing
+type MockSessionIssuer struct {
+	mock.Mock
+}
+
+func (m *MockSessionIssuer) StartSession(user model.User, client dto.ClientInfo) (dto.LoginResult, error) {
+	args := m.Called(user, client)
+	return args.Get(0).(dto.LoginResult), args.Error(1)
+}
+
+func newOIDCServiceWithMocks(server *oidctest.Server) (*OIDCService, *MockUserRepository, *MockIdentityRepository, *MockSessionIssuer) {
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       server.URL,
+		ClientID:     server.ClientID,
+		ClientSecret: server.ClientSecret,
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/test/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	}, nil)
+	users, identities, sessions := &MockUserRepository{}, &MockIdentityRepository{}, &MockSessionIssuer{}
+	service := NewOIDCService(map[string]*oidc.Provider{"test": provider}, users, identities, sessions, testHasher(), testSecret)
+	return service, users, identities, sessions
+}
+
+// loginWithProvider starts the flow and signs in at the stand-in, returning
+// what the provider and the browser send back to the callback
+func loginWithProvider(t *testing.T, service *OIDCService, server *oidctest.Server, claims map[string]any) (code, state, cookie string) {
+	authorization, err := service.Begin("test")
+	require.NoError(t, err)
+	code, state, err = server.Authorize(authorization.URL, claims)
+	require.NoError(t, err)
+	return code, state, authorization.State
+}
+
+func TestOIDCService_Providers(t *testing.T) {
+	service := NewOIDCService(map[string]*oidc.Provider{"google": nil, "gitlab": nil}, nil, nil, nil, nil, "")
+
+	assert.Equal(t, []string{"gitlab", "google"}, service.Providers())
+}
+
+func TestOIDCService_Begin(t *testing.T) {
+	server := oidctest.NewServer("blog", "secret")
+	defer server.Close()
+
+	t.Run("success - redirect to the provider", func(t *testing.T) {
+		service, _, _, _ := newOIDCServiceWithMocks(server)
+
+		authorization, err := service.Begin("test")
+
+		require.NoError(t, err)
+		assert.Contains(t, authorization.URL, server.URL+"/authorize?")
+		assert.NotEmpty(t, authorization.State)
+		assert.NotContains(t, authorization.URL, authorization.State)
+		assert.Equal(t, oidcStateTTL, authorization.ExpiresIn)
+	})
+
+	t.Run("error - unknown provider", func(t *testing.T) {
+		service, _, _, _ := newOIDCServiceWithMocks(server)
+
+		_, err := service.Begin("other")
+
+		assert.ErrorIs(t, err, appErrors.ErrOIDCProviderNotFound)
+	})
+
+	t.Run("error - provider unavailable", func(t *testing.T) {
+		down := oidctest.NewServer("blog", "secret")
+		service, _, _, _ := newOIDCServiceWithMocks(down)
+		down.Close()
+
+		_, err := service.Begin("test")
+
+		assert.ErrorIs(t, err, appErrors.ErrOIDCProviderUnavailable)
+	})
+}
+
+func TestOIDCService_Callback(t *testing.T) {
+	server := oidctest.NewServer("blog", "secret")
+	defer server.Close()
+	claims := map[string]any{"sub": "sub-1", "email": "john@example.com", "email_verified": true, "name": "John"}
+	verifiedAt := time.Now()
+	tokens := dto.LoginResult{Tokens: dto.TokenPair{AccessToken: "jwt-token"}}
+	client := dto.ClientInfo{IP: "10.0.0.1", UserAgent: "Firefox"}
+
+	t.Run("linked identity signs in", func(t *testing.T) {
+		service, users, identities, sessions := newOIDCServiceWithMocks(server)
+		user := model.User{ID: 3, Email: "john@example.com"}
+		identities.On("GetByProviderSubject", "test", "sub-1").Return(model.Identity{UserID: 3}, nil)
+		users.On("GetByID", uint(3)).Return(user, nil)
+		sessions.On("StartSession", user, client).Return(tokens, nil)
+
+		code, state, cookie := loginWithProvider(t, service, server, claims)
+		result, err := service.Callback("test", code, state, cookie, client)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tokens, result)
+		identities.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("verified local account is linked", func(t *testing.T) {
+		service, users, identities, sessions := newOIDCServiceWithMocks(server)
+		user := model.User{ID: 3, Email: "john@example.com", EmailVerifiedAt: &verifiedAt}
+		identities.On("GetByProviderSubject", "test", "sub-1").Return(model.Identity{}, appErrors.ErrIdentityNotFound)
+		users.On("GetByEmail", "john@example.com").Return(user, nil)
+		identities.On("Create", model.Identity{UserID: 3, Provider: "test", Subject: "sub-1", Email: "john@example.com"}).Return(model.Identity{ID: 1}, nil)
+		sessions.On("StartSession", user, client).Return(tokens, nil)
+
+		code, state, cookie := loginWithProvider(t, service, server, claims)
+		_, err := service.Callback("test", code, state, cookie, client)
+
+		assert.NoError(t, err)
+		identities.AssertExpectations(t)
+	})
+
+	t.Run("new user is created", func(t *testing.T) {
+		service, users, identities, sessions := newOIDCServiceWithMocks(server)
+		identities.On("GetByProviderSubject", "test", "sub-1").Return(model.Identity{}, appErrors.ErrIdentityNotFound)
+		users.On("GetByEmail", "john@example.com").Return(model.User{}, appErrors.ErrUserNotFound)
+		identities.On("CreateWithUser", mock.MatchedBy(func(user model.User) bool {
+			return user.Name == "John" && user.Email == "john@example.com" && user.EmailVerifiedAt != nil &&
+				user.Role == model.DefaultRole && user.Password != ""
+		}), model.Identity{Provider: "test", Subject: "sub-1", Email: "john@example.com"}).
+			Return(model.Identity{ID: 1, UserID: 9, User: model.User{ID: 9}}, nil)
+		sessions.On("StartSession", model.User{ID: 9}, client).Return(tokens, nil)
+
+		code, state, cookie := loginWithProvider(t, service, server, claims)
+		_, err := service.Callback("test", code, state, cookie, client)
+
+		assert.NoError(t, err)
+		identities.AssertExpectations(t)
+	})
+
+	t.Run("error - unverified local account", func(t *testing.T) {
+		service, users, identities, sessions := newOIDCServiceWithMocks(server)
+		identities.On("GetByProviderSubject", "test", "sub-1").Return(model.Identity{}, appErrors.ErrIdentityNotFound)
+		users.On("GetByEmail", "john@example.com").Return(model.User{ID: 3, Email: "john@example.com"}, nil)
+
+		code, state, cookie := loginWithProvider(t, service, server, claims)
+		_, err := service.Callback("test", code, state, cookie, client)
+
+		assert.ErrorIs(t, err, appErrors.ErrOIDCAccountNotLinkable)
+		identities.AssertNotCalled(t, "Create", mock.Anything)
+		sessions.AssertNotCalled(t, "StartSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error - email not verified by the provider", func(t *testing.T) {
+		service, _, identities, _ := newOIDCServiceWithMocks(server)
+		identities.On("GetByProviderSubject", "test", "sub-2").Return(model.Identity{}, appErrors.ErrIdentityNotFound)
+
+		code, state, cookie := loginWithProvider(t, service, server, map[string]any{"sub": "sub-2", "email": "john@example.com", "email_verified": false})
+		_, err := service.Callback("test", code, state, cookie, client)
+
+		assert.ErrorIs(t, err, appErrors.ErrOIDCEmailNotVerified)
+	})
+
+	t.Run("error - code already used", func(t *testing.T) {
+		service, users, identities, sessions := newOIDCServiceWithMocks(server)
+		identities.On("GetByProviderSubject", "test", "sub-1").Return(model.Identity{UserID: 3}, nil)
+		users.On("GetByID", uint(3)).Return(model.User{ID: 3}, nil)
+		sessions.On("StartSession", mock.Anything, mock.Anything).Return(tokens, nil)
+
+		code, state, cookie := loginWithProvider(t, service, server, claims)
+		_, err := service.Callback("test", code, state, cookie, client)
+		require.NoError(t, err)
+		_, err = service.Callback("test", code, state, cookie, client)
+
+		assert.ErrorIs(t, err, appErrors.ErrOIDCAuthenticationFailed)
+	})
+}
+
+func TestOIDCService_Callback_InvalidState(t *testing.T) {
+	server := oidctest.NewServer("blog", "secret")
+	defer server.Close()
+	service, _, identities, _ := newOIDCServiceWithMocks(server)
+	code, state, cookie := loginWithProvider(t, service, server, map[string]any{"sub": "sub-1"})
+
+	tests := []struct {
+		name     string
+		provider string
+		state    string
+		cookie   string
+		want     error
+	}{
+		{"state mismatch", "test", "other-state", cookie, appErrors.ErrInvalidOIDCState},
+		{"tampered cookie", "test", state, cookie + "x", appErrors.ErrInvalidOIDCState},
+		{"missing cookie", "test", state, "", appErrors.ErrInvalidOIDCState},
+		{"unknown provider", "other", state, cookie, appErrors.ErrOIDCProviderNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Callback(tt.provider, code, tt.state, tt.cookie, dto.ClientInfo{})
+
+			assert.ErrorIs(t, err, tt.want)
+			identities.AssertNotCalled(t, "GetByProviderSubject", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/utils"
+)
+
+type PasswordResetService struct {
+	userRepo         repository.UserRepositoryInterface
+	resetTokenRepo   repository.PasswordResetTokenRepositoryInterface
+	refreshTokenRepo repository.RefreshTokenRepositoryInterface
+	sessionRepo      repository.SessionRepositoryInterface
+	notifier         domainService.AccountNotifierInterface
+	hasher           domainService.PasswordHasherInterface
+	ttl              time.Duration
+	resendInterval   time.Duration
+	// dispatch ejecuta en segundo plano el envío del enlace
+	dispatch func(func())
+}
+
+func NewPasswordResetService(userRepo repository.UserRepositoryInterface, resetTokenRepo repository.PasswordResetTokenRepositoryInterface, refreshTokenRepo repository.RefreshTokenRepositoryInterface, sessionRepo repository.SessionRepositoryInterface, notifier domainService.AccountNotifierInterface, hasher domainService.PasswordHasherInterface, ttl, resendInterval time.Duration) *PasswordResetService {
+	return &PasswordResetService{userRepo, resetTokenRepo, refreshTokenRepo, sessionRepo, notifier, hasher, ttl, resendInterval, func(f func()) { go f() }}
+}
+
+// Forgot envía al usuario un enlace para restablecer su contraseña. Si el email
+// no está registrado, ya se envió un enlace hace menos de resendInterval o el
+// envío falla no se informa. El enlace se genera y envía en segundo plano para
+// que el tiempo de respuesta tampoco revele qué cuentas existen
+func (s *PasswordResetService) Forgot(email string) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	s.dispatch(func() {
+		if err := s.sendReset(user); err != nil {
+			log.Printf("No se pudo enviar el enlace de restablecimiento al usuario %d: %v", user.ID, err)
+		}
+	})
+	return nil
+}
+
+// sendReset genera un token de restablecimiento y lo envía al usuario, salvo
+// que ya tenga uno generado hace menos de resendInterval
+func (s *PasswordResetService) sendReset(user model.User) error {
+	now := time.Now()
+	recent, err := s.resetTokenRepo.ExistsCreatedSince(user.ID, now.Add(-s.resendInterval))
+	if err != nil || recent {
+		return err
+	}
+
+	token, err := utils.GenerateToken(refreshTokenBytes)
+	if err != nil {
+		return err
+	}
+	_, err = s.resetTokenRepo.Create(model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: now.Add(s.ttl),
+	})
+	if err != nil {
+		return err
+	}
+	return s.notifier.SendPasswordReset(user, token)
+}
+
+// Reset cambia la contraseña del usuario del token. El token se consume aunque
+// haya otros pendientes, que quedan invalidados, y se revocan todas las sesiones
+// abiertas del usuario, incluidos los tokens de acceso emitidos antes del cambio
+func (s *PasswordResetService) Reset(token, password string) error {
+	now := time.Now()
+	stored, err := s.resetTokenRepo.GetByHash(utils.HashToken(token))
+	if err != nil {
+		return err
+	}
+	if !stored.IsActive(now) {
+		return appErrors.ErrInvalidResetToken
+	}
+
+	// Otra petición pudo usar el mismo token entre la lectura y la escritura
+	marked, err := s.resetTokenRepo.MarkUsed(stored.ID, now)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return appErrors.ErrInvalidResetToken
+	}
+
+	hashedPassword, err := hashPassword(s.hasher, password)
+	if err != nil {
+		return err
+	}
+	fields := map[string]any{"password": hashedPassword, "password_changed_at": now}
+	if _, err := s.userRepo.UpdateFields(stored.UserID, fields); err != nil {
+		return err
+	}
+
+	if err := s.resetTokenRepo.InvalidateByUser(stored.UserID, now); err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.RevokeByUser(stored.UserID, now); err != nil {
+		return err
+	}
+	_, err = s.sessionRepo.RevokeByUser(stored.UserID, 0, now)
+	return err
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockPasswordResetTokenRepository mocks the PasswordResetTokenRepository
+type MockPasswordResetTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetTokenRepository) Create(token model.PasswordResetToken) (model.PasswordResetToken, error) {
+	args := m.Called(token)
+	return args.Get(0).(model.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetTokenRepository) GetByHash(hash string) (model.PasswordResetToken, error) {
+	args := m.Called(hash)
+	return args.Get(0).(model.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetTokenRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	args := m.Called(id, usedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPasswordResetTokenRepository) InvalidateByUser(userID uint, usedAt time.Time) error {
+	args := m.Called(userID, usedAt)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetTokenRepository) ExistsCreatedSince(userID uint, since time.Time) (bool, error) {
+	args := m.Called(userID, since)
+	return args.Bool(0), args.Error(1)
+}
+
+func newPasswordResetServiceWithMocks() (*PasswordResetService, *MockUserRepository, *MockPasswordResetTokenRepository, *MockRefreshTokenRepository, *MockSessionRepository, *MockAccountNotifier) {
+	users := &MockUserRepository{}
+	resets := &MockPasswordResetTokenRepository{}
+	refreshTokens := &MockRefreshTokenRepository{}
+	sessions := &MockSessionRepository{}
+	notifier := &MockAccountNotifier{}
+	service := NewPasswordResetService(users, resets, refreshTokens, sessions, notifier, testHasher(), time.Hour, time.Minute)
+	// Run deliveries inline so the tests can observe them
+	service.dispatch = func(f func()) { f() }
+	return service, users, resets, refreshTokens, sessions, notifier
+}
+
+func TestPasswordResetService_Forgot(t *testing.T) {
+	user := model.User{ID: 1, Email: "john@example.com"}
+
+	t.Run("success - stores the hash and sends the token", func(t *testing.T) {
+		service, users, resets, _, _, notifier := newPasswordResetServiceWithMocks()
+		users.On("GetByEmail", "john@example.com").Return(user, nil)
+		resets.On("ExistsCreatedSince", uint(1), mock.Anything).Return(false, nil)
+		var stored model.PasswordResetToken
+		resets.On("Create", mock.MatchedBy(func(token model.PasswordResetToken) bool {
+			stored = token
+			return true
+		})).Return(model.PasswordResetToken{}, nil)
+		notifier.On("SendPasswordReset", user, mock.Anything).Return(nil)
+
+		err := service.Forgot("john@example.com")
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, notifier.token)
+		assert.Equal(t, uint(1), stored.UserID)
+		assert.Equal(t, utils.HashToken(notifier.token), stored.TokenHash)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+	})
+
+	t.Run("unknown email is not reported", func(t *testing.T) {
+		service, users, resets, _, _, notifier := newPasswordResetServiceWithMocks()
+		users.On("GetByEmail", "ghost@example.com").Return(model.User{}, appErrors.ErrUserNotFound)
+
+		err := service.Forgot("ghost@example.com")
+
+		assert.NoError(t, err)
+		resets.AssertNotCalled(t, "Create", mock.Anything)
+		notifier.AssertNotCalled(t, "SendPasswordReset", mock.Anything, mock.Anything)
+	})
+
+	t.Run("delivery failure is not reported", func(t *testing.T) {
+		service, users, resets, _, _, notifier := newPasswordResetServiceWithMocks()
+		users.On("GetByEmail", "john@example.com").Return(user, nil)
+		resets.On("ExistsCreatedSince", uint(1), mock.Anything).Return(false, nil)
+		resets.On("Create", mock.Anything).Return(model.PasswordResetToken{}, nil)
+		notifier.On("SendPasswordReset", user, mock.Anything).Return(appErrors.ErrDatabaseConnection)
+
+		assert.NoError(t, service.Forgot("john@example.com"))
+	})
+
+	t.Run("link sent recently is not resent", func(t *testing.T) {
+		service, users, resets, _, _, notifier := newPasswordResetServiceWithMocks()
+		users.On("GetByEmail", "john@example.com").Return(user, nil)
+		resets.On("ExistsCreatedSince", uint(1), mock.MatchedBy(func(since time.Time) bool {
+			return time.Since(since) >= time.Minute
+		})).Return(true, nil)
+
+		assert.NoError(t, service.Forgot("john@example.com"))
+		resets.AssertNotCalled(t, "Create", mock.Anything)
+		notifier.AssertNotCalled(t, "SendPasswordReset", mock.Anything, mock.Anything)
+	})
+
+	t.Run("link is generated and sent after responding", func(t *testing.T) {
+		service, users, resets, _, _, notifier := newPasswordResetServiceWithMocks()
+		var pending func()
+		service.dispatch = func(f func()) { pending = f }
+		users.On("GetByEmail", "john@example.com").Return(user, nil)
+		resets.On("ExistsCreatedSince", uint(1), mock.Anything).Return(false, nil)
+		resets.On("Create", mock.Anything).Return(model.PasswordResetToken{}, nil)
+		notifier.On("SendPasswordReset", user, mock.Anything).Return(nil)
+
+		assert.NoError(t, service.Forgot("john@example.com"))
+		resets.AssertNotCalled(t, "Create", mock.Anything)
+
+		pending()
+		resets.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	})
+}
+
+func TestPasswordResetService_Reset(t *testing.T) {
+	hash := utils.HashToken("reset-token")
+	active := model.PasswordResetToken{ID: 3, UserID: 1, TokenHash: hash, ExpiresAt: time.Now().Add(time.Hour)}
+	usedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		mockSetup func(*MockUserRepository, *MockPasswordResetTokenRepository, *MockRefreshTokenRepository, *MockSessionRepository)
+		wantError error
+	}{
+		{
+			name: "success - password changed and sessions revoked",
+			mockSetup: func(users *MockUserRepository, resets *MockPasswordResetTokenRepository, refreshTokens *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				resets.On("GetByHash", hash).Return(active, nil)
+				resets.On("MarkUsed", uint(3), mock.Anything).Return(true, nil)
+				users.On("UpdateFields", uint(1), mock.MatchedBy(func(fields map[string]any) bool {
+					password, _ := fields["password"].(string)
+					_, changed := fields["password_changed_at"].(time.Time)
+					return changed && bcrypt.CompareHashAndPassword([]byte(password), []byte("new-password")) == nil
+				})).Return(model.User{ID: 1}, nil)
+				resets.On("InvalidateByUser", uint(1), mock.Anything).Return(nil)
+				refreshTokens.On("RevokeByUser", uint(1), mock.Anything).Return(nil)
+				sessions.On("RevokeByUser", uint(1), uint(0), mock.Anything).Return([]uint{4, 5}, nil)
+			},
+		},
+		{
+			name: "error - sessions cannot be revoked",
+			mockSetup: func(users *MockUserRepository, resets *MockPasswordResetTokenRepository, refreshTokens *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				resets.On("GetByHash", hash).Return(active, nil)
+				resets.On("MarkUsed", uint(3), mock.Anything).Return(true, nil)
+				users.On("UpdateFields", uint(1), mock.Anything).Return(model.User{ID: 1}, nil)
+				resets.On("InvalidateByUser", uint(1), mock.Anything).Return(nil)
+				refreshTokens.On("RevokeByUser", uint(1), mock.Anything).Return(nil)
+				sessions.On("RevokeByUser", uint(1), uint(0), mock.Anything).Return([]uint(nil), appErrors.ErrDatabaseConnection)
+			},
+			wantError: appErrors.ErrDatabaseConnection,
+		},
+		{
+			name: "error - unknown token",
+			mockSetup: func(users *MockUserRepository, resets *MockPasswordResetTokenRepository, refreshTokens *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				resets.On("GetByHash", hash).Return(model.PasswordResetToken{}, appErrors.ErrInvalidResetToken)
+			},
+			wantError: appErrors.ErrInvalidResetToken,
+		},
+		{
+			name: "error - expired token",
+			mockSetup: func(users *MockUserRepository, resets *MockPasswordResetTokenRepository, refreshTokens *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				resets.On("GetByHash", hash).Return(model.PasswordResetToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+			},
+			wantError: appErrors.ErrInvalidResetToken,
+		},
+		{
+			name: "error - token already used",
+			mockSetup: func(users *MockUserRepository, resets *MockPasswordResetTokenRepository, refreshTokens *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				resets.On("GetByHash", hash).Return(model.PasswordResetToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}, nil)
+			},
+			wantError: appErrors.ErrInvalidResetToken,
+		},
+		{
+			name: "error - token used concurrently",
+			mockSetup: func(users *MockUserRepository, resets *MockPasswordResetTokenRepository, refreshTokens *MockRefreshTokenRepository, sessions *MockSessionRepository) {
+				resets.On("GetByHash", hash).Return(active, nil)
+				resets.On("MarkUsed", uint(3), mock.Anything).Return(false, nil)
+			},
+			wantError: appErrors.ErrInvalidResetToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, users, resets, refreshTokens, sessions, _ := newPasswordResetServiceWithMocks()
+			tt.mockSetup(users, resets, refreshTokens, sessions)
+
+			err := service.Reset("reset-token", "new-password")
+
+			if errors.Is(tt.wantError, appErrors.ErrInvalidResetToken) {
+				assert.ErrorIs(t, err, tt.wantError)
+				users.AssertNotCalled(t, "UpdateFields", mock.Anything, mock.Anything)
+			} else if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+			} else {
+				assert.NoError(t, err)
+			}
+			users.AssertExpectations(t)
+			resets.AssertExpectations(t)
+			refreshTokens.AssertExpectations(t)
+			sessions.AssertExpectations(t)
+		})
+	}
+}
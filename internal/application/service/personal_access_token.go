@@ -0,0 +1,109 @@
+package service
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/utils"
+)
+
+// accessTokenBytes es la longitud en bytes de la parte aleatoria de los tokens
+// de acceso personal y lastUsedPrecision el intervalo mínimo entre dos
+// actualizaciones de su último uso
+const (
+	accessTokenBytes  = 32
+	lastUsedPrecision = time.Minute
+)
+
+// PersonalAccessTokenService gestiona los tokens de acceso personal con los que
+// los scripts y las tareas de CI se autentican sin la contraseña del usuario
+type PersonalAccessTokenService struct {
+	tokenRepo repository.PersonalAccessTokenRepositoryInterface
+}
+
+func NewPersonalAccessTokenService(tokenRepo repository.PersonalAccessTokenRepositoryInterface) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{tokenRepo}
+}
+
+// Create genera un token para el usuario y devuelve, junto con sus datos, el
+// token en claro, que no se vuelve a mostrar
+func (s *PersonalAccessTokenService) Create(userID uint, req dto.CreateAccessTokenRequest) (model.PersonalAccessToken, string, error) {
+	scopes := model.Scopes{}
+	for _, name := range req.Scopes {
+		scope := model.Scope(strings.TrimSpace(name))
+		if !scope.IsValid() {
+			return model.PersonalAccessToken{}, "", appErrors.ErrInvalidScope
+		}
+		if !scopes.Has(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return model.PersonalAccessToken{}, "", appErrors.ErrInvalidTokenExpiry
+	}
+
+	secret, err := utils.GenerateToken(accessTokenBytes)
+	if err != nil {
+		return model.PersonalAccessToken{}, "", appErrors.NewInternalServerError(err, "Error al generar token")
+	}
+	token := model.AccessTokenPrefix + secret
+
+	created, err := s.tokenRepo.Create(model.PersonalAccessToken{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		TokenHash: utils.HashToken(token),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return model.PersonalAccessToken{}, "", err
+	}
+	return created, token, nil
+}
+
+// List devuelve los tokens no revocados del usuario
+func (s *PersonalAccessTokenService) List(userID uint) ([]model.PersonalAccessToken, error) {
+	return s.tokenRepo.GetByUser(userID)
+}
+
+// Revoke revoca un token del usuario. Los tokens de otros usuarios se tratan
+// como inexistentes
+func (s *PersonalAccessTokenService) Revoke(userID, id uint) error {
+	revoked, err := s.tokenRepo.Revoke(id, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return appErrors.ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+// Authenticate valida un token presentado por un cliente y lo devuelve con su
+// usuario, cuyo rol se lee en cada petición. Registra además su último uso
+func (s *PersonalAccessTokenService) Authenticate(token string) (model.PersonalAccessToken, error) {
+	if !strings.HasPrefix(token, model.AccessTokenPrefix) {
+		return model.PersonalAccessToken{}, appErrors.ErrInvalidAccessToken
+	}
+
+	now := time.Now()
+	stored, err := s.tokenRepo.GetByHash(utils.HashToken(token))
+	if err != nil {
+		return model.PersonalAccessToken{}, err
+	}
+	// Los usuarios eliminados no se cargan con el token
+	if !stored.IsActive(now) || stored.User.ID == 0 {
+		return model.PersonalAccessToken{}, appErrors.ErrInvalidAccessToken
+	}
+
+	// No registrar el uso no debe impedir la petición
+	if err := s.tokenRepo.TouchLastUsed(stored.ID, now, lastUsedPrecision); err != nil {
+		log.Printf("No se pudo registrar el uso del token de acceso %d: %v", stored.ID, err)
+	}
+	return stored, nil
+}
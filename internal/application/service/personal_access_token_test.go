@@ -0,0 +1,176 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPersonalAccessTokenRepository mocks the PersonalAccessTokenRepository
+type MockPersonalAccessTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockPersonalAccessTokenRepository) Create(token model.PersonalAccessToken) (model.PersonalAccessToken, error) {
+	args := m.Called(token)
+	return args.Get(0).(model.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) GetByHash(hash string) (model.PersonalAccessToken, error) {
+	args := m.Called(hash)
+	return args.Get(0).(model.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) GetByUser(userID uint) ([]model.PersonalAccessToken, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) Revoke(id, userID uint, revokedAt time.Time) (bool, error) {
+	args := m.Called(id, userID, revokedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) TouchLastUsed(id uint, usedAt time.Time, precision time.Duration) error {
+	args := m.Called(id, usedAt, precision)
+	return args.Error(0)
+}
+
+func TestPersonalAccessTokenService_Create(t *testing.T) {
+	t.Run("success - stores the hash and returns the token once", func(t *testing.T) {
+		mockRepo := &MockPersonalAccessTokenRepository{}
+		expiresAt := time.Now().Add(24 * time.Hour)
+		var stored model.PersonalAccessToken
+		mockRepo.On("Create", mock.MatchedBy(func(token model.PersonalAccessToken) bool {
+			stored = token
+			return true
+		})).Return(model.PersonalAccessToken{ID: 1, UserID: 1, Name: "CI"}, nil)
+
+		created, token, err := NewPersonalAccessTokenService(mockRepo).Create(1, dto.CreateAccessTokenRequest{
+			Name:      " CI ",
+			Scopes:    []string{"posts:read", "posts:write", "posts:read"},
+			ExpiresAt: &expiresAt,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), created.ID)
+		assert.True(t, strings.HasPrefix(token, model.AccessTokenPrefix))
+		assert.Equal(t, utils.HashToken(token), stored.TokenHash)
+		assert.Equal(t, "CI", stored.Name)
+		assert.Equal(t, model.Scopes{model.ScopePostsRead, model.ScopePostsWrite}, stored.Scopes)
+		assert.Equal(t, &expiresAt, stored.ExpiresAt)
+	})
+
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name      string
+		req       dto.CreateAccessTokenRequest
+		wantError error
+	}{
+		{"error - unknown scope", dto.CreateAccessTokenRequest{Name: "CI", Scopes: []string{"posts:admin"}}, appErrors.ErrInvalidScope},
+		{"error - expiry in the past", dto.CreateAccessTokenRequest{Name: "CI", Scopes: []string{"posts:read"}, ExpiresAt: &past}, appErrors.ErrInvalidTokenExpiry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockPersonalAccessTokenRepository{}
+
+			_, token, err := NewPersonalAccessTokenService(mockRepo).Create(1, tt.req)
+
+			assert.ErrorIs(t, err, tt.wantError)
+			assert.Empty(t, token)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestPersonalAccessTokenService_Revoke(t *testing.T) {
+	tests := []struct {
+		name      string
+		revoked   bool
+		wantError error
+	}{
+		{"success - token revoked", true, nil},
+		{"error - unknown or foreign token", false, appErrors.ErrAccessTokenNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockPersonalAccessTokenRepository{}
+			mockRepo.On("Revoke", uint(5), uint(1), mock.Anything).Return(tt.revoked, nil)
+
+			err := NewPersonalAccessTokenService(mockRepo).Revoke(1, 5)
+
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPersonalAccessTokenService_Authenticate(t *testing.T) {
+	token := model.AccessTokenPrefix + "secret"
+	hash := utils.HashToken(token)
+	past := time.Now().Add(-time.Minute)
+	user := model.User{ID: 1, Role: model.RoleAuthor}
+	active := model.PersonalAccessToken{ID: 3, UserID: 1, User: user, TokenHash: hash, Scopes: model.Scopes{model.ScopePostsRead}}
+
+	t.Run("success - records the last use", func(t *testing.T) {
+		mockRepo := &MockPersonalAccessTokenRepository{}
+		mockRepo.On("GetByHash", hash).Return(active, nil)
+		mockRepo.On("TouchLastUsed", uint(3), mock.Anything, time.Minute).Return(nil)
+
+		got, err := NewPersonalAccessTokenService(mockRepo).Authenticate(token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, active, got)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("success - last use failure is ignored", func(t *testing.T) {
+		mockRepo := &MockPersonalAccessTokenRepository{}
+		mockRepo.On("GetByHash", hash).Return(active, nil)
+		mockRepo.On("TouchLastUsed", uint(3), mock.Anything, time.Minute).Return(appErrors.ErrDatabaseConnection)
+
+		_, err := NewPersonalAccessTokenService(mockRepo).Authenticate(token)
+
+		assert.NoError(t, err)
+	})
+
+	tests := []struct {
+		name      string
+		token     string
+		stored    model.PersonalAccessToken
+		err       error
+		wantError error
+	}{
+		{"error - missing prefix", "secret", model.PersonalAccessToken{}, nil, appErrors.ErrInvalidAccessToken},
+		{"error - unknown token", token, model.PersonalAccessToken{}, appErrors.ErrInvalidAccessToken, appErrors.ErrInvalidAccessToken},
+		{"error - expired token", token, model.PersonalAccessToken{ID: 3, User: user, ExpiresAt: &past}, nil, appErrors.ErrInvalidAccessToken},
+		{"error - revoked token", token, model.PersonalAccessToken{ID: 3, User: user, RevokedAt: &past}, nil, appErrors.ErrInvalidAccessToken},
+		{"error - deleted user", token, model.PersonalAccessToken{ID: 3, UserID: 1}, nil, appErrors.ErrInvalidAccessToken},
+		{"error - database failure", token, model.PersonalAccessToken{}, appErrors.ErrDatabaseConnection, appErrors.ErrDatabaseConnection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockPersonalAccessTokenRepository{}
+			mockRepo.On("GetByHash", hash).Return(tt.stored, tt.err).Maybe()
+
+			_, err := NewPersonalAccessTokenService(mockRepo).Authenticate(tt.token)
+
+			assert.ErrorIs(t, err, tt.wantError)
+			mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
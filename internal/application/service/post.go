@@ -0,0 +1,318 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/markdown"
+	"github.com/UliVargas/blog-go/pkg/query"
+	"github.com/UliVargas/blog-go/pkg/utils"
+)
+
+// defaultSlug se usa cuando el título no contiene caracteres aptos para un slug
+const defaultSlug = "post"
+
+type PostService struct {
+	postRepo     repository.PostRepositoryInterface
+	revisionRepo repository.PostRevisionRepositoryInterface
+	tagRepo      repository.TagRepositoryInterface
+	categoryRepo repository.CategoryRepositoryInterface
+	authorizer   domainService.AuthorizerInterface
+}
+
+func NewPostService(postRepo repository.PostRepositoryInterface, revisionRepo repository.PostRevisionRepositoryInterface, tagRepo repository.TagRepositoryInterface, categoryRepo repository.CategoryRepositoryInterface, authorizer domainService.AuthorizerInterface) *PostService {
+	return &PostService{postRepo, revisionRepo, tagRepo, categoryRepo, authorizer}
+}
+
+func (s *PostService) GetPublished(spec query.Spec) (query.Page[model.Post], error) {
+	return s.postRepo.GetPublished(spec)
+}
+
+// GetPublishedByTag devuelve una página de las publicaciones publicadas con la
+// etiqueta; el slug puede ser el actual de la etiqueta o uno de sus alias
+func (s *PostService) GetPublishedByTag(slug string, spec query.Spec) (query.Page[model.Post], error) {
+	tag, err := s.tagRepo.GetBySlug(slug)
+	if err != nil {
+		return query.Page[model.Post]{}, err
+	}
+	return s.postRepo.GetPublishedByTag(tag.ID, spec)
+}
+
+// GetPublishedByCategory devuelve una página de las publicaciones publicadas de
+// la categoría y de todas sus subcategorías
+func (s *PostService) GetPublishedByCategory(slug string, spec query.Spec) (query.Page[model.Post], error) {
+	category, err := s.categoryRepo.GetBySlug(slug)
+	if err != nil {
+		return query.Page[model.Post]{}, err
+	}
+	ids, err := s.categoryRepo.GetDescendantIDs(category.ID)
+	if err != nil {
+		return query.Page[model.Post]{}, err
+	}
+	return s.postRepo.GetPublishedByCategories(ids, spec)
+}
+
+func (s *PostService) GetByAuthor(authorID uint) ([]model.Post, error) {
+	return s.postRepo.GetByAuthor(authorID)
+}
+
+// GetByID devuelve la publicación si está publicada o si quien la consulta es su autor
+func (s *PostService) GetByID(id uint, viewerID uint) (model.Post, error) {
+	post, err := s.postRepo.GetByID(id)
+	if err != nil {
+		return model.Post{}, err
+	}
+	if !post.IsPublished() && post.AuthorID != viewerID {
+		return model.Post{}, appErrors.ErrPostNotFound
+	}
+	return post, nil
+}
+
+// GetBySlug busca la publicación por su slug actual o por uno anterior.
+// El segundo valor indica que el slug es antiguo y debe redirigirse al actual
+func (s *PostService) GetBySlug(slug string, viewerID uint) (model.Post, bool, error) {
+	moved := false
+	post, err := s.postRepo.GetBySlug(slug)
+	if errors.Is(err, appErrors.ErrPostNotFound) {
+		moved = true
+		post, err = s.postRepo.GetByPreviousSlug(slug)
+	}
+	if err != nil {
+		return model.Post{}, false, err
+	}
+	if !post.IsPublished() && post.AuthorID != viewerID {
+		return model.Post{}, false, appErrors.ErrPostNotFound
+	}
+	return post, moved, nil
+}
+
+func (s *PostService) Create(post model.Post) (model.Post, error) {
+	slug, err := s.uniqueSlug(post.Title, 0)
+	if err != nil {
+		return model.Post{}, err
+	}
+	post.Slug = slug
+
+	if err := s.resolveTaxonomy(&post); err != nil {
+		return model.Post{}, err
+	}
+	if post.CategoryID != nil && *post.CategoryID == 0 {
+		post.CategoryID = nil
+	}
+
+	if post.Status == "" {
+		post.Status = model.PostStatusDraft
+	}
+	if err := applyStatus(&post, model.Post{}, time.Now()); err != nil {
+		return model.Post{}, err
+	}
+	if err := renderBody(&post); err != nil {
+		return model.Post{}, err
+	}
+
+	created, err := s.postRepo.Create(post)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	// La primera revisión conserva el contenido original
+	if err := s.recordRevision(created, created.AuthorID); err != nil {
+		return model.Post{}, err
+	}
+	return created, nil
+}
+
+func (s *PostService) Update(post model.Post, principal model.Principal) (model.Post, error) {
+	existing, err := s.getAuthorizedPost(post.ID, principal, model.ActionPostEdit)
+	if err != nil {
+		return model.Post{}, err
+	}
+	contentChanged := existing.Title != post.Title || existing.Body != post.Body
+	previous := existing
+
+	// El slug se regenera si se envía uno nuevo o si cambia el título
+	source := post.Slug
+	if source == "" && post.Title != existing.Title {
+		source = post.Title
+	}
+	if source != "" && utils.Slugify(source) != existing.Slug {
+		slug, err := s.uniqueSlug(source, existing.ID)
+		if err != nil {
+			return model.Post{}, err
+		}
+		existing.Slug = slug
+	}
+
+	// Solo se actualizan los campos editables
+	existing.Title = post.Title
+	existing.Body = post.Body
+	existing.Excerpt = post.Excerpt
+	if post.Status != "" {
+		existing.Status = post.Status
+		existing.PublishAt = post.PublishAt
+	}
+
+	// Las etiquetas y la categoría solo cambian si se envían
+	if err := s.resolveTaxonomy(&post); err != nil {
+		return model.Post{}, err
+	}
+	existing.Tags = post.Tags
+	if post.CategoryID != nil {
+		existing.CategoryID = post.CategoryID
+		if *post.CategoryID == 0 {
+			existing.CategoryID = nil
+		}
+	}
+	if err := applyStatus(&existing, previous, time.Now()); err != nil {
+		return model.Post{}, err
+	}
+	if err := renderBody(&existing); err != nil {
+		return model.Post{}, err
+	}
+
+	updated, err := s.postRepo.Update(existing)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	// Los cambios que no afectan al contenido (por ejemplo, el estado) no generan revisión
+	if contentChanged {
+		if err := s.recordRevision(updated, principal.UserID); err != nil {
+			return model.Post{}, err
+		}
+	}
+	return updated, nil
+}
+
+func (s *PostService) Delete(id uint, principal model.Principal) error {
+	if _, err := s.getAuthorizedPost(id, principal, model.ActionPostDelete); err != nil {
+		return err
+	}
+
+	return s.postRepo.Delete(id)
+}
+
+// PublishScheduled publica las publicaciones programadas cuya fecha ya pasó.
+// La ejecuta periódicamente el planificador iniciado en main.go
+func (s *PostService) PublishScheduled(now time.Time) (int64, error) {
+	return s.postRepo.PublishDue(now)
+}
+
+// getAuthorizedPost obtiene la publicación y verifica que el principal pueda
+// realizar la acción sobre ella
+func (s *PostService) getAuthorizedPost(id uint, principal model.Principal, action model.Action) (model.Post, error) {
+	post, err := s.postRepo.GetByID(id)
+	if err != nil {
+		return model.Post{}, err
+	}
+	if err := s.authorizer.Authorize(principal, action, post); err != nil {
+		return model.Post{}, err
+	}
+	return post, nil
+}
+
+// uniqueSlug genera un slug a partir del texto y le añade un sufijo numérico
+// (-2, -3, ...) mientras esté en uso por otra publicación
+func (s *PostService) uniqueSlug(text string, postID uint) (string, error) {
+	base := utils.Slugify(text)
+	if base == "" {
+		base = defaultSlug
+	}
+
+	candidate := base
+	for suffix := 2; ; suffix++ {
+		exists, err := s.postRepo.SlugExists(candidate, postID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, suffix)
+	}
+}
+
+// resolveTaxonomy sustituye los nombres de etiquetas por etiquetas existentes o
+// nuevas, sin duplicados, y comprueba que la categoría exista
+func (s *PostService) resolveTaxonomy(post *model.Post) error {
+	if post.Tags != nil {
+		seen := make(map[string]bool)
+		tags := make([]model.Tag, 0, len(post.Tags))
+		for _, tag := range post.Tags {
+			name := strings.TrimSpace(tag.Name)
+			slug := utils.Slugify(name)
+			if slug == "" || seen[slug] {
+				continue
+			}
+			seen[slug] = true
+			tags = append(tags, model.Tag{Name: name, Slug: slug})
+		}
+
+		post.Tags = tags
+		if len(tags) > 0 {
+			resolved, err := s.tagRepo.FindOrCreate(tags)
+			if err != nil {
+				return err
+			}
+			post.Tags = resolved
+		}
+	}
+
+	if post.CategoryID != nil && *post.CategoryID != 0 {
+		if _, err := s.categoryRepo.GetByID(*post.CategoryID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyStatus valida el estado de la publicación y ajusta sus fechas de
+// publicación. La fecha programada solo tiene que ser futura si cambia respecto
+// a previous, el estado guardado, para que editar o restaurar una publicación
+// programada cuya fecha ya pasó no falle mientras el programador la publica
+func applyStatus(post *model.Post, previous model.Post, now time.Time) error {
+	switch post.Status {
+	case model.PostStatusScheduled:
+		if post.PublishAt == nil {
+			return appErrors.ErrInvalidPublishAt
+		}
+		rescheduled := previous.Status != model.PostStatusScheduled || previous.PublishAt == nil || !post.PublishAt.Equal(*previous.PublishAt)
+		if rescheduled && !post.PublishAt.After(now) {
+			return appErrors.ErrInvalidPublishAt
+		}
+		post.PublishedAt = nil
+	case model.PostStatusPublished:
+		post.PublishAt = nil
+		if post.PublishedAt == nil {
+			post.PublishedAt = &now
+		}
+	case model.PostStatusDraft:
+		post.PublishAt = nil
+		post.PublishedAt = nil
+	}
+	return nil
+}
+
+// renderBody convierte el cuerpo en Markdown a HTML sanitizado y actualiza
+// la tabla de contenidos, el número de palabras y el tiempo de lectura
+func renderBody(post *model.Post) error {
+	doc, err := markdown.Render(post.Body)
+	if err != nil {
+		return appErrors.NewInternalServerError(err, "Error al procesar el contenido")
+	}
+
+	post.BodyHTML = doc.HTML
+	post.WordCount = doc.WordCount
+	post.ReadingTime = doc.ReadingTime
+	post.TOC = make([]model.TOCEntry, 0, len(doc.TOC))
+	for _, heading := range doc.TOC {
+		post.TOC = append(post.TOC, model.TOCEntry{Level: heading.Level, Text: heading.Text, ID: heading.ID})
+	}
+	return nil
+}
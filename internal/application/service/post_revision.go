@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/diff"
+)
+
+// GetRevisions lista el historial de revisiones de una publicación que el
+// usuario puede editar
+func (s *PostService) GetRevisions(postID uint, principal model.Principal) ([]model.PostRevision, error) {
+	if _, err := s.getAuthorizedPost(postID, principal, model.ActionPostEdit); err != nil {
+		return nil, err
+	}
+	return s.revisionRepo.GetByPost(postID)
+}
+
+// DiffRevisions compara dos revisiones en modo "unified" (por líneas) o "word" (por palabras)
+func (s *PostService) DiffRevisions(postID uint, from, to int, mode string, principal model.Principal) (dto.RevisionDiffResponse, error) {
+	if _, err := s.getAuthorizedPost(postID, principal, model.ActionPostEdit); err != nil {
+		return dto.RevisionDiffResponse{}, err
+	}
+
+	fromRevision, err := s.revisionRepo.GetByNumber(postID, from)
+	if err != nil {
+		return dto.RevisionDiffResponse{}, err
+	}
+	toRevision, err := s.revisionRepo.GetByNumber(postID, to)
+	if err != nil {
+		return dto.RevisionDiffResponse{}, err
+	}
+
+	response := dto.RevisionDiffResponse{From: from, To: to, Mode: mode}
+	switch mode {
+	case "word":
+		response.Title = diff.Words(fromRevision.Title, toRevision.Title)
+		response.Body = diff.Words(fromRevision.Body, toRevision.Body)
+	default:
+		response.Mode = "unified"
+		fromName, toName := fmt.Sprintf("revision %d", from), fmt.Sprintf("revision %d", to)
+		response.Title = diff.Unified(fromRevision.Title, toRevision.Title, fromName, toName)
+		response.Body = diff.Unified(fromRevision.Body, toRevision.Body, fromName, toName)
+	}
+	return response, nil
+}
+
+// RestoreRevision devuelve la publicación al contenido de una revisión anterior.
+// La restauración se guarda a su vez como una revisión nueva, por lo que no se pierde historial
+func (s *PostService) RestoreRevision(postID uint, number int, principal model.Principal) (model.Post, error) {
+	existing, err := s.getAuthorizedPost(postID, principal, model.ActionPostEdit)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	revision, err := s.revisionRepo.GetByNumber(postID, number)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	return s.Update(model.Post{
+		ID:      postID,
+		Title:   revision.Title,
+		Slug:    existing.Slug,
+		Body:    revision.Body,
+		Excerpt: existing.Excerpt,
+	}, principal)
+}
+
+// recordRevision guarda una copia inmutable del contenido actual de la publicación
+func (s *PostService) recordRevision(post model.Post, editorID uint) error {
+	_, err := s.revisionRepo.Create(model.PostRevision{
+		PostID:   post.ID,
+		Title:    post.Title,
+		Body:     post.Body,
+		EditorID: editorID,
+	})
+	return err
+}
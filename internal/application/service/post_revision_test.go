@@ -0,0 +1,144 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/diff"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostService_GetRevisions(t *testing.T) {
+	post := model.Post{ID: 1, AuthorID: 1}
+
+	t.Run("author lists revisions", func(t *testing.T) {
+		postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+		revisions := []model.PostRevision{{PostID: 1, Number: 2}, {PostID: 1, Number: 1}}
+		mockRepo.On("GetByID", uint(1)).Return(post, nil)
+		mockRevisions.On("GetByPost", uint(1)).Return(revisions, nil)
+
+		result, err := postService.GetRevisions(1, model.Principal{UserID: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, revisions, result)
+		mockRevisions.AssertExpectations(t)
+	})
+
+	t.Run("other users are rejected", func(t *testing.T) {
+		postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+		mockRepo.On("GetByID", uint(1)).Return(post, nil)
+
+		_, err := postService.GetRevisions(1, model.Principal{UserID: 2})
+
+		assert.ErrorIs(t, err, appErrors.ErrNotPostAuthor)
+		mockRevisions.AssertNotCalled(t, "GetByPost", mock.Anything)
+	})
+}
+
+func TestPostService_DiffRevisions(t *testing.T) {
+	post := model.Post{ID: 1, AuthorID: 1}
+	first := model.PostRevision{PostID: 1, Number: 1, Title: "Hola", Body: "uno\ndos"}
+	second := model.PostRevision{PostID: 1, Number: 2, Title: "Hola mundo", Body: "uno\ntres"}
+
+	tests := []struct {
+		name  string
+		mode  string
+		check func(t *testing.T, title, body any)
+	}{
+		{
+			name: "unified by default",
+			mode: "",
+			check: func(t *testing.T, title, body any) {
+				assert.Equal(t, "--- revision 1\n+++ revision 2\n@@ -1,2 +1,2 @@\n uno\n-dos\n+tres\n", body)
+				assert.Contains(t, title, "+Hola mundo")
+			},
+		},
+		{
+			name: "word level",
+			mode: "word",
+			check: func(t *testing.T, title, body any) {
+				assert.Equal(t, []diff.Change{{Op: diff.OpEqual, Text: "Hola"}, {Op: diff.OpInsert, Text: "mundo"}}, title)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+			mockRepo.On("GetByID", uint(1)).Return(post, nil)
+			mockRevisions.On("GetByNumber", uint(1), 1).Return(first, nil)
+			mockRevisions.On("GetByNumber", uint(1), 2).Return(second, nil)
+
+			result, err := postService.DiffRevisions(1, 1, 2, tt.mode, model.Principal{UserID: 1})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, result.From)
+			assert.Equal(t, 2, result.To)
+			tt.check(t, result.Title, result.Body)
+		})
+	}
+
+	t.Run("missing revision", func(t *testing.T) {
+		postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+		mockRepo.On("GetByID", uint(1)).Return(post, nil)
+		mockRevisions.On("GetByNumber", uint(1), 1).Return(first, nil)
+		mockRevisions.On("GetByNumber", uint(1), 9).Return(model.PostRevision{}, appErrors.ErrRevisionNotFound)
+
+		_, err := postService.DiffRevisions(1, 1, 9, "unified", model.Principal{UserID: 1})
+
+		assert.ErrorIs(t, err, appErrors.ErrRevisionNotFound)
+	})
+
+	t.Run("revisions as long as the largest body", func(t *testing.T) {
+		large := model.PostRevision{PostID: 1, Number: 3, Title: "Hola", Body: strings.Repeat("palabra ", 12500)}
+
+		postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+		mockRepo.On("GetByID", uint(1)).Return(post, nil)
+		mockRevisions.On("GetByNumber", uint(1), 1).Return(first, nil)
+		mockRevisions.On("GetByNumber", uint(1), 3).Return(large, nil)
+
+		result, err := postService.DiffRevisions(1, 1, 3, "word", model.Principal{UserID: 1})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Body)
+	})
+}
+
+func TestPostService_RestoreRevision(t *testing.T) {
+	current := model.Post{ID: 1, Title: "Actual", Slug: "actual", Body: "Texto pisado", Status: model.PostStatusPublished, AuthorID: 1}
+	old := model.PostRevision{PostID: 1, Number: 1, Title: "Original", Body: "Texto original"}
+
+	postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+	mockRepo.On("GetByID", uint(1)).Return(current, nil)
+	mockRevisions.On("GetByNumber", uint(1), 1).Return(old, nil)
+	mockRepo.On("Update", mock.MatchedBy(func(p model.Post) bool {
+		// Se restaura el contenido conservando el slug y el estado actuales
+		return p.Title == "Original" && p.Body == "Texto original" && p.Slug == "actual" && p.Status == model.PostStatusPublished
+	})).Return(model.Post{ID: 1, Title: "Original", Body: "Texto original", AuthorID: 1}, nil)
+	mockRevisions.On("Create", mock.MatchedBy(func(r model.PostRevision) bool {
+		return r.PostID == 1 && r.Title == "Original" && r.EditorID == 1
+	})).Return(model.PostRevision{Number: 3}, nil)
+
+	post, err := postService.RestoreRevision(1, 1, model.Principal{UserID: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Original", post.Title)
+	mockRepo.AssertExpectations(t)
+	mockRevisions.AssertExpectations(t)
+}
+
+func TestPostService_Update_SkipsRevisionWithoutContentChanges(t *testing.T) {
+	draft := model.Post{ID: 1, Title: "Hola", Slug: "hola", Body: "Body", Status: model.PostStatusDraft, AuthorID: 1}
+
+	postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+	mockRepo.On("GetByID", uint(1)).Return(draft, nil)
+	mockRepo.On("Update", mock.Anything).Return(draft, nil)
+
+	_, err := postService.Update(model.Post{ID: 1, Title: "Hola", Body: "Body", Status: model.PostStatusPublished}, model.Principal{UserID: 1})
+
+	assert.NoError(t, err)
+	mockRevisions.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newPostServiceWithTaxonomyMock devuelve un PostService cuyos repositorios de
+// etiquetas y categorías también son mocks accesibles desde el test
+func newPostServiceWithTaxonomyMock() (*PostService, *MockPostRepository, *MockTagRepository, *MockCategoryRepository) {
+	mockRepo := &MockPostRepository{}
+	mockRevisions := &MockPostRevisionRepository{}
+	allowRevisions(mockRevisions)
+	mockTags := &MockTagRepository{}
+	mockCategories := &MockCategoryRepository{}
+	service := NewPostService(mockRepo, mockRevisions, mockTags, mockCategories, NewAuthorizer(15*time.Minute))
+	return service, mockRepo, mockTags, mockCategories
+}
+
+func TestPostService_Create_ResolvesTags(t *testing.T) {
+	postService, mockRepo, mockTags, _ := newPostServiceWithTaxonomyMock()
+	mockRepo.On("SlugExists", "title", uint(0)).Return(false, nil)
+	// Los nombres se normalizan y los duplicados se descartan
+	mockTags.On("FindOrCreate", []model.Tag{
+		{Name: "Go", Slug: "go"},
+		{Name: "Programación", Slug: "programacion"},
+	}).Return([]model.Tag{{ID: 1, Name: "Go", Slug: "go"}, {ID: 2, Name: "Programación", Slug: "programacion"}}, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(p model.Post) bool {
+		return len(p.Tags) == 2 && p.Tags[0].ID == 1 && p.Tags[1].ID == 2
+	})).Return(model.Post{ID: 1}, nil)
+
+	_, err := postService.Create(model.Post{
+		Title:    "Title",
+		Body:     "Body",
+		AuthorID: 1,
+		Tags:     []model.Tag{{Name: " Go "}, {Name: "Programación"}, {Name: "GO"}, {Name: "!!"}},
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockTags.AssertExpectations(t)
+}
+
+func TestPostService_Create_UnknownCategory(t *testing.T) {
+	postService, mockRepo, _, mockCategories := newPostServiceWithTaxonomyMock()
+	mockRepo.On("SlugExists", "title", uint(0)).Return(false, nil)
+	mockCategories.On("GetByID", uint(9)).Return(model.Category{}, appErrors.ErrCategoryNotFound)
+
+	_, err := postService.Create(model.Post{Title: "Title", Body: "Body", AuthorID: 1, CategoryID: uintPtr(9)})
+
+	assert.ErrorIs(t, err, appErrors.ErrCategoryNotFound)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestPostService_Update_Taxonomy(t *testing.T) {
+	existing := model.Post{
+		ID: 1, Title: "Hola", Slug: "hola", Body: "Body", AuthorID: 1,
+		CategoryID: uintPtr(3),
+		Tags:       []model.Tag{{ID: 1, Name: "Go", Slug: "go"}},
+	}
+
+	tests := []struct {
+		name      string
+		input     model.Post
+		mockSetup func(*MockTagRepository, *MockCategoryRepository)
+		check     func(*testing.T, model.Post)
+	}{
+		{
+			name:      "omitted tags and category are kept",
+			input:     model.Post{ID: 1, Title: "Hola", Body: "Body"},
+			mockSetup: func(*MockTagRepository, *MockCategoryRepository) {},
+			check: func(t *testing.T, p model.Post) {
+				assert.Nil(t, p.Tags)
+				assert.Equal(t, uint(3), *p.CategoryID)
+			},
+		},
+		{
+			name:      "empty tags and category 0 clear them",
+			input:     model.Post{ID: 1, Title: "Hola", Body: "Body", Tags: []model.Tag{}, CategoryID: uintPtr(0)},
+			mockSetup: func(*MockTagRepository, *MockCategoryRepository) {},
+			check: func(t *testing.T, p model.Post) {
+				assert.NotNil(t, p.Tags)
+				assert.Empty(t, p.Tags)
+				assert.Nil(t, p.CategoryID)
+			},
+		},
+		{
+			name:  "new tags and category replace the current ones",
+			input: model.Post{ID: 1, Title: "Hola", Body: "Body", Tags: []model.Tag{{Name: "SQL"}}, CategoryID: uintPtr(4)},
+			mockSetup: func(tags *MockTagRepository, categories *MockCategoryRepository) {
+				tags.On("FindOrCreate", []model.Tag{{Name: "SQL", Slug: "sql"}}).Return([]model.Tag{{ID: 5, Name: "SQL", Slug: "sql"}}, nil)
+				categories.On("GetByID", uint(4)).Return(model.Category{ID: 4}, nil)
+			},
+			check: func(t *testing.T, p model.Post) {
+				assert.Equal(t, []model.Tag{{ID: 5, Name: "SQL", Slug: "sql"}}, p.Tags)
+				assert.Equal(t, uint(4), *p.CategoryID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postService, mockRepo, mockTags, mockCategories := newPostServiceWithTaxonomyMock()
+			mockRepo.On("GetByID", uint(1)).Return(existing, nil)
+			tt.mockSetup(mockTags, mockCategories)
+			var captured model.Post
+			mockRepo.On("Update", mock.MatchedBy(func(p model.Post) bool {
+				captured = p
+				return true
+			})).Return(model.Post{ID: 1}, nil)
+
+			_, err := postService.Update(tt.input, model.Principal{UserID: 1})
+
+			assert.NoError(t, err)
+			tt.check(t, captured)
+			mockTags.AssertExpectations(t)
+			mockCategories.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostService_GetPublishedByTag(t *testing.T) {
+	postService, mockRepo, mockTags, _ := newPostServiceWithTaxonomyMock()
+	mockTags.On("GetBySlug", "golang").Return(model.Tag{ID: 1, Slug: "go"}, nil)
+	spec := query.Spec{Limit: 10}
+	mockRepo.On("GetPublishedByTag", uint(1), spec).Return(query.Page[model.Post]{Data: []model.Post{{ID: 1}}}, nil)
+
+	page, err := postService.GetPublishedByTag("golang", spec)
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Data, 1)
+}
+
+func TestPostService_GetPublishedByCategory(t *testing.T) {
+	t.Run("includes descendant categories", func(t *testing.T) {
+		postService, mockRepo, _, mockCategories := newPostServiceWithTaxonomyMock()
+		mockCategories.On("GetBySlug", "backend").Return(model.Category{ID: 1}, nil)
+		mockCategories.On("GetDescendantIDs", uint(1)).Return([]uint{1, 2, 3}, nil)
+		spec := query.Spec{Limit: 10}
+		mockRepo.On("GetPublishedByCategories", []uint{1, 2, 3}, spec).
+			Return(query.Page[model.Post]{Data: []model.Post{{ID: 1}, {ID: 2}}}, nil)
+
+		page, err := postService.GetPublishedByCategory("backend", spec)
+
+		assert.NoError(t, err)
+		assert.Len(t, page.Data, 2)
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		postService, _, _, mockCategories := newPostServiceWithTaxonomyMock()
+		mockCategories.On("GetBySlug", "nada").Return(model.Category{}, appErrors.ErrCategoryNotFound)
+
+		_, err := postService.GetPublishedByCategory("nada", query.Spec{})
+
+		assert.ErrorIs(t, err, appErrors.ErrCategoryNotFound)
+	})
+}
@@ -0,0 +1,623 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPostRepository es un mock para el repositorio de publicaciones
+type MockPostRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostRepository) GetPublished(spec query.Spec) (query.Page[model.Post], error) {
+	args := m.Called(spec)
+	return args.Get(0).(query.Page[model.Post]), args.Error(1)
+}
+
+func (m *MockPostRepository) GetPublishedByTag(tagID uint, spec query.Spec) (query.Page[model.Post], error) {
+	args := m.Called(tagID, spec)
+	return args.Get(0).(query.Page[model.Post]), args.Error(1)
+}
+
+func (m *MockPostRepository) GetPublishedByCategories(categoryIDs []uint, spec query.Spec) (query.Page[model.Post], error) {
+	args := m.Called(categoryIDs, spec)
+	return args.Get(0).(query.Page[model.Post]), args.Error(1)
+}
+
+func (m *MockPostRepository) GetByAuthor(authorID uint) ([]model.Post, error) {
+	args := m.Called(authorID)
+	return args.Get(0).([]model.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) GetByID(id uint) (model.Post, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) GetBySlug(slug string) (model.Post, error) {
+	args := m.Called(slug)
+	return args.Get(0).(model.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) GetByPreviousSlug(slug string) (model.Post, error) {
+	args := m.Called(slug)
+	return args.Get(0).(model.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) SlugExists(slug string, excludePostID uint) (bool, error) {
+	args := m.Called(slug, excludePostID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPostRepository) Create(post model.Post) (model.Post, error) {
+	args := m.Called(post)
+	return args.Get(0).(model.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) Update(post model.Post) (model.Post, error) {
+	args := m.Called(post)
+	return args.Get(0).(model.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) PublishDue(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockPostRevisionRepository es un mock para el repositorio de revisiones
+type MockPostRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostRevisionRepository) GetByPost(postID uint) ([]model.PostRevision, error) {
+	args := m.Called(postID)
+	return args.Get(0).([]model.PostRevision), args.Error(1)
+}
+
+func (m *MockPostRevisionRepository) GetByNumber(postID uint, number int) (model.PostRevision, error) {
+	args := m.Called(postID, number)
+	return args.Get(0).(model.PostRevision), args.Error(1)
+}
+
+func (m *MockPostRevisionRepository) Create(revision model.PostRevision) (model.PostRevision, error) {
+	args := m.Called(revision)
+	return args.Get(0).(model.PostRevision), args.Error(1)
+}
+
+// NewPostServiceWithMock creates a PostService with mock repositories for testing
+func NewPostServiceWithMock() (*PostService, *MockPostRepository, *MockPostRevisionRepository) {
+	mockRepo := &MockPostRepository{}
+	mockRevisions := &MockPostRevisionRepository{}
+	service := NewPostService(mockRepo, mockRevisions, &MockTagRepository{}, &MockCategoryRepository{}, NewAuthorizer(15*time.Minute))
+	return service, mockRepo, mockRevisions
+}
+
+// allowRevisions acepta cualquier revisión que el servicio guarde durante el test
+func allowRevisions(m *MockPostRevisionRepository) {
+	m.On("Create", mock.Anything).Return(model.PostRevision{}, nil).Maybe()
+}
+
+func TestNewPostService(t *testing.T) {
+	mockRepo := &MockPostRepository{}
+	mockRevisions := &MockPostRevisionRepository{}
+	mockTags := &MockTagRepository{}
+	mockCategories := &MockCategoryRepository{}
+	authorizer := NewAuthorizer(time.Minute)
+	postService := NewPostService(mockRepo, mockRevisions, mockTags, mockCategories, authorizer)
+
+	assert.NotNil(t, postService)
+	assert.Equal(t, mockRepo, postService.postRepo)
+	assert.Equal(t, mockRevisions, postService.revisionRepo)
+	assert.Equal(t, mockTags, postService.tagRepo)
+	assert.Equal(t, mockCategories, postService.categoryRepo)
+	assert.Equal(t, authorizer, postService.authorizer)
+}
+
+func TestPostService_GetPublished(t *testing.T) {
+	postService, mockRepo, _ := NewPostServiceWithMock()
+	spec := query.Spec{Limit: 20, SortKey: "-published_at", Desc: true}
+	page := query.Page[model.Post]{Data: []model.Post{{ID: 1, Title: "First", Status: model.PostStatusPublished, AuthorID: 1}}}
+	mockRepo.On("GetPublished", spec).Return(page, nil)
+
+	result, err := postService.GetPublished(spec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPostService_GetByID(t *testing.T) {
+	draft := model.Post{ID: 1, Status: model.PostStatusDraft, AuthorID: 1}
+	published := model.Post{ID: 2, Status: model.PostStatusPublished, AuthorID: 1}
+
+	tests := []struct {
+		name          string
+		post          model.Post
+		viewerID      uint
+		expectedError error
+	}{
+		{name: "published post is visible to anyone", post: published, viewerID: 0},
+		{name: "draft is visible to its author", post: draft, viewerID: 1},
+		{name: "draft is hidden from other users", post: draft, viewerID: 2, expectedError: appErrors.ErrPostNotFound},
+		{name: "draft is hidden from anonymous users", post: draft, viewerID: 0, expectedError: appErrors.ErrPostNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postService, mockRepo, _ := NewPostServiceWithMock()
+			mockRepo.On("GetByID", tt.post.ID).Return(tt.post, nil)
+
+			post, err := postService.GetByID(tt.post.ID, tt.viewerID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Equal(t, model.Post{}, post)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.post, post)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostService_Create(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		post          model.Post
+		check         func(t *testing.T, p model.Post)
+		expectedError error
+	}{
+		{
+			name: "defaults to draft",
+			post: model.Post{Title: "Title", Body: "Body", AuthorID: 1},
+			check: func(t *testing.T, p model.Post) {
+				assert.Equal(t, model.PostStatusDraft, p.Status)
+				assert.Nil(t, p.PublishedAt)
+			},
+		},
+		{
+			name: "published sets published_at",
+			post: model.Post{Title: "Title", Body: "Body", Status: model.PostStatusPublished, AuthorID: 1},
+			check: func(t *testing.T, p model.Post) {
+				assert.NotNil(t, p.PublishedAt)
+			},
+		},
+		{
+			name: "scheduled with future date",
+			post: model.Post{Title: "Title", Body: "Body", Status: model.PostStatusScheduled, PublishAt: &future, AuthorID: 1},
+			check: func(t *testing.T, p model.Post) {
+				assert.Equal(t, &future, p.PublishAt)
+				assert.Nil(t, p.PublishedAt)
+			},
+		},
+		{
+			name:          "scheduled with past date",
+			post:          model.Post{Title: "Title", Body: "Body", Status: model.PostStatusScheduled, PublishAt: &past, AuthorID: 1},
+			expectedError: appErrors.ErrInvalidPublishAt,
+		},
+		{
+			name:          "scheduled without date",
+			post:          model.Post{Title: "Title", Body: "Body", Status: model.PostStatusScheduled, AuthorID: 1},
+			expectedError: appErrors.ErrInvalidPublishAt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+			allowRevisions(mockRevisions)
+			mockRepo.On("SlugExists", "title", uint(0)).Return(false, nil)
+			var captured model.Post
+			if tt.expectedError == nil {
+				mockRepo.On("Create", mock.MatchedBy(func(p model.Post) bool {
+					captured = p
+					return true
+				})).Return(model.Post{ID: 1}, nil)
+			}
+
+			post, err := postService.Create(tt.post)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint(1), post.ID)
+				tt.check(t, captured)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostService_Create_SlugCollision(t *testing.T) {
+	postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+	allowRevisions(mockRevisions)
+	mockRepo.On("SlugExists", "el-ano-del-nino", uint(0)).Return(true, nil)
+	mockRepo.On("SlugExists", "el-ano-del-nino-2", uint(0)).Return(true, nil)
+	mockRepo.On("SlugExists", "el-ano-del-nino-3", uint(0)).Return(false, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(p model.Post) bool {
+		return p.Slug == "el-ano-del-nino-3"
+	})).Return(model.Post{ID: 1, Slug: "el-ano-del-nino-3"}, nil)
+
+	post, err := postService.Create(model.Post{Title: "El año del niño", Body: "Body", AuthorID: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "el-ano-del-nino-3", post.Slug)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPostService_Update_Slug(t *testing.T) {
+	existing := model.Post{ID: 1, Title: "Hola", Slug: "hola", Body: "Body", AuthorID: 1}
+
+	tests := []struct {
+		name         string
+		input        model.Post
+		mockSetup    func(*MockPostRepository)
+		expectedSlug string
+	}{
+		{
+			name:         "unchanged title keeps the slug",
+			input:        model.Post{ID: 1, Title: "Hola", Body: "Otro cuerpo"},
+			mockSetup:    func(m *MockPostRepository) {},
+			expectedSlug: "hola",
+		},
+		{
+			name:  "explicit slug is normalized",
+			input: model.Post{ID: 1, Title: "Hola", Slug: "Mi Slug Único", Body: "Body"},
+			mockSetup: func(m *MockPostRepository) {
+				m.On("SlugExists", "mi-slug-unico", uint(1)).Return(false, nil)
+			},
+			expectedSlug: "mi-slug-unico",
+		},
+		{
+			name:  "new title regenerates the slug",
+			input: model.Post{ID: 1, Title: "Hola mundo", Body: "Body"},
+			mockSetup: func(m *MockPostRepository) {
+				m.On("SlugExists", "hola-mundo", uint(1)).Return(false, nil)
+			},
+			expectedSlug: "hola-mundo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+			allowRevisions(mockRevisions)
+			mockRepo.On("GetByID", uint(1)).Return(existing, nil)
+			tt.mockSetup(mockRepo)
+			mockRepo.On("Update", mock.MatchedBy(func(p model.Post) bool {
+				return p.Slug == tt.expectedSlug
+			})).Return(model.Post{ID: 1, Slug: tt.expectedSlug}, nil)
+
+			post, err := postService.Update(tt.input, model.Principal{UserID: 1})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSlug, post.Slug)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostService_Create_RendersMarkdown(t *testing.T) {
+	postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+	allowRevisions(mockRevisions)
+	mockRepo.On("SlugExists", "title", uint(0)).Return(false, nil)
+	var captured model.Post
+	mockRepo.On("Create", mock.MatchedBy(func(p model.Post) bool {
+		captured = p
+		return true
+	})).Return(model.Post{ID: 1}, nil)
+
+	_, err := postService.Create(model.Post{
+		Title:    "Title",
+		Body:     "## Primeros pasos\n\nHola <script>alert(1)</script> mundo",
+		AuthorID: 1,
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, captured.BodyHTML, `<h2 id="primeros-pasos">Primeros pasos</h2>`)
+	assert.NotContains(t, captured.BodyHTML, "<script")
+	assert.Equal(t, []model.TOCEntry{{Level: 2, Text: "Primeros pasos", ID: "primeros-pasos"}}, captured.TOC)
+	assert.Equal(t, 5, captured.WordCount)
+	assert.Equal(t, 1, captured.ReadingTime)
+}
+
+func TestPostService_Update_RerendersMarkdown(t *testing.T) {
+	postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+	allowRevisions(mockRevisions)
+	existing := model.Post{ID: 1, Title: "Hola", Slug: "hola", Body: "Antes", BodyHTML: "<p>Antes</p>\n", AuthorID: 1}
+	mockRepo.On("GetByID", uint(1)).Return(existing, nil)
+	mockRepo.On("Update", mock.MatchedBy(func(p model.Post) bool {
+		return p.BodyHTML == "<p><em>Después</em></p>\n" && p.WordCount == 1
+	})).Return(model.Post{ID: 1}, nil)
+
+	_, err := postService.Update(model.Post{ID: 1, Title: "Hola", Body: "*Después*"}, model.Principal{UserID: 1})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPostService_GetBySlug(t *testing.T) {
+	published := model.Post{ID: 1, Slug: "hola-mundo", Status: model.PostStatusPublished, AuthorID: 1}
+	draft := model.Post{ID: 2, Slug: "borrador", Status: model.PostStatusDraft, AuthorID: 1}
+
+	tests := []struct {
+		name          string
+		slug          string
+		viewerID      uint
+		mockSetup     func(*MockPostRepository)
+		expectedMoved bool
+		expectedError error
+	}{
+		{
+			name: "current slug",
+			slug: "hola-mundo",
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetBySlug", "hola-mundo").Return(published, nil)
+			},
+		},
+		{
+			name: "previous slug is reported as moved",
+			slug: "hola",
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetBySlug", "hola").Return(model.Post{}, appErrors.ErrPostNotFound)
+				m.On("GetByPreviousSlug", "hola").Return(published, nil)
+			},
+			expectedMoved: true,
+		},
+		{
+			name: "unknown slug",
+			slug: "nada",
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetBySlug", "nada").Return(model.Post{}, appErrors.ErrPostNotFound)
+				m.On("GetByPreviousSlug", "nada").Return(model.Post{}, appErrors.ErrPostNotFound)
+			},
+			expectedError: appErrors.ErrPostNotFound,
+		},
+		{
+			name:     "draft hidden from other users",
+			slug:     "borrador",
+			viewerID: 2,
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetBySlug", "borrador").Return(draft, nil)
+			},
+			expectedError: appErrors.ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postService, mockRepo, _ := NewPostServiceWithMock()
+			tt.mockSetup(mockRepo)
+
+			post, moved, err := postService.GetBySlug(tt.slug, tt.viewerID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, published.Slug, post.Slug)
+				assert.Equal(t, tt.expectedMoved, moved)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostService_PublishScheduled(t *testing.T) {
+	postService, mockRepo, _ := NewPostServiceWithMock()
+	now := time.Now()
+	mockRepo.On("PublishDue", now).Return(int64(3), nil)
+
+	published, err := postService.PublishScheduled(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), published)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPostService_Update(t *testing.T) {
+	existing := model.Post{ID: 1, Title: "Old", Slug: "old", Body: "Old body", AuthorID: 1}
+
+	tests := []struct {
+		name          string
+		userID        uint
+		role          model.Role
+		mockSetup     func(*MockPostRepository)
+		expectedTitle string
+		expectedError error
+	}{
+		{
+			name:   "success - author updates own post",
+			userID: 1,
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetByID", uint(1)).Return(existing, nil)
+				m.On("SlugExists", "new", uint(1)).Return(false, nil)
+				m.On("Update", mock.MatchedBy(func(p model.Post) bool {
+					return p.ID == 1 && p.Title == "New" && p.Slug == "new" && p.AuthorID == 1
+				})).Return(model.Post{ID: 1, Title: "New", Slug: "new", Body: "New body", AuthorID: 1}, nil)
+			},
+			expectedTitle: "New",
+		},
+		{
+			name:   "success - editor updates another author's post",
+			userID: 5,
+			role:   model.RoleEditor,
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetByID", uint(1)).Return(existing, nil)
+				m.On("SlugExists", "new", uint(1)).Return(false, nil)
+				m.On("Update", mock.MatchedBy(func(p model.Post) bool {
+					return p.ID == 1 && p.AuthorID == 1
+				})).Return(model.Post{ID: 1, Title: "New", Slug: "new", Body: "New body", AuthorID: 1}, nil)
+			},
+			expectedTitle: "New",
+		},
+		{
+			name:   "error - user is not the author",
+			userID: 2,
+			role:   model.RoleAuthor,
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetByID", uint(1)).Return(existing, nil)
+			},
+			expectedError: appErrors.ErrNotPostAuthor,
+		},
+		{
+			name:   "error - post not found",
+			userID: 1,
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetByID", uint(1)).Return(model.Post{}, appErrors.ErrPostNotFound)
+			},
+			expectedError: appErrors.ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+			allowRevisions(mockRevisions)
+			tt.mockSetup(mockRepo)
+
+			post, err := postService.Update(model.Post{ID: 1, Title: "New", Body: "New body"}, model.Principal{UserID: tt.userID, Role: tt.role})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedTitle, post.Title)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostService_Update_ScheduledPost(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	earlier := past.Add(-time.Hour)
+	// The scheduler has not published the post yet although its date has passed
+	existing := model.Post{ID: 1, Title: "Title", Slug: "title", Body: "Body", Status: model.PostStatusScheduled, PublishAt: &past, AuthorID: 1}
+
+	tests := []struct {
+		name          string
+		update        model.Post
+		expectedError error
+	}{
+		{
+			name:   "content edit keeps the schedule",
+			update: model.Post{ID: 1, Title: "Title", Body: "New body"},
+		},
+		{
+			name:   "resending the same schedule",
+			update: model.Post{ID: 1, Title: "Title", Body: "New body", Status: model.PostStatusScheduled, PublishAt: &past},
+		},
+		{
+			name:          "error - moving the schedule to the past",
+			update:        model.Post{ID: 1, Title: "Title", Body: "New body", Status: model.PostStatusScheduled, PublishAt: &earlier},
+			expectedError: appErrors.ErrInvalidPublishAt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postService, mockRepo, mockRevisions := NewPostServiceWithMock()
+			allowRevisions(mockRevisions)
+			mockRepo.On("GetByID", uint(1)).Return(existing, nil)
+			mockRepo.On("Update", mock.MatchedBy(func(p model.Post) bool {
+				return p.Status == model.PostStatusScheduled && p.PublishAt.Equal(past)
+			})).Return(existing, nil).Maybe()
+
+			_, err := postService.Update(tt.update, model.Principal{UserID: 1})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				mockRepo.AssertCalled(t, "Update", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestPostService_Delete(t *testing.T) {
+	existing := model.Post{ID: 1, Title: "Title", AuthorID: 1}
+
+	tests := []struct {
+		name          string
+		userID        uint
+		role          model.Role
+		mockSetup     func(*MockPostRepository)
+		expectedError error
+	}{
+		{
+			name:   "success - author deletes own post",
+			userID: 1,
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetByID", uint(1)).Return(existing, nil)
+				m.On("Delete", uint(1)).Return(nil)
+			},
+		},
+		{
+			name:   "success - editor deletes another author's post",
+			userID: 5,
+			role:   model.RoleEditor,
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetByID", uint(1)).Return(existing, nil)
+				m.On("Delete", uint(1)).Return(nil)
+			},
+		},
+		{
+			name:   "error - user is not the author",
+			userID: 2,
+			role:   model.RoleAuthor,
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetByID", uint(1)).Return(existing, nil)
+			},
+			expectedError: appErrors.ErrNotPostAuthor,
+		},
+		{
+			name:   "error - database error",
+			userID: 1,
+			mockSetup: func(m *MockPostRepository) {
+				m.On("GetByID", uint(1)).Return(existing, nil)
+				m.On("Delete", uint(1)).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postService, mockRepo, _ := NewPostServiceWithMock()
+			tt.mockSetup(mockRepo)
+
+			err := postService.Delete(1, model.Principal{UserID: tt.userID, Role: tt.role})
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
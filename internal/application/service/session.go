@@ -0,0 +1,66 @@
+package service
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+)
+
+// SessionService gestiona las sesiones abiertas de los usuarios en sus
+// dispositivos. Revocar una sesión rechaza de inmediato sus tokens de acceso y
+// impide renovar sus tokens de renovación
+type SessionService struct {
+	sessionRepo     repository.SessionRepositoryInterface
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+func NewSessionService(sessionRepo repository.SessionRepositoryInterface, accessTokenTTL, refreshTokenTTL time.Duration) *SessionService {
+	return &SessionService{sessionRepo, accessTokenTTL, refreshTokenTTL}
+}
+
+// List devuelve las sesiones activas del usuario marcando la actual
+func (s *SessionService) List(userID, currentID uint) ([]dto.SessionResponse, error) {
+	sessions, err := s.sessionRepo.GetActiveByUser(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		response[i] = dto.SessionResponse{Session: session, Current: session.ID == currentID}
+	}
+	return response, nil
+}
+
+// Revoke cierra una sesión del usuario. Las sesiones de otros usuarios o ya
+// cerradas se tratan como inexistentes
+func (s *SessionService) Revoke(userID, id uint) error {
+	revoked, err := s.sessionRepo.Revoke(id, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return appErrors.ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeOthers cierra todas las sesiones del usuario salvo la actual y devuelve
+// cuántas se cerraron
+func (s *SessionService) RevokeOthers(userID, currentID uint) (int, error) {
+	ids, err := s.sessionRepo.RevokeByUser(userID, currentID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// Purge elimina las sesiones que ya no tienen tokens vigentes: las inactivas
+// durante más que la vigencia de los tokens de renovación y las revocadas hace
+// más que la de los tokens de acceso. Devuelve cuántas se eliminaron
+func (s *SessionService) Purge(now time.Time) (int64, error) {
+	return s.sessionRepo.DeleteInactive(now.Add(-s.refreshTokenTTL), now.Add(-s.accessTokenTTL))
+}
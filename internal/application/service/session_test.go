@@ -0,0 +1,122 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSessionRepository mocks the SessionRepository
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(session model.Session) (model.Session, error) {
+	args := m.Called(session)
+	return args.Get(0).(model.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) GetByFamily(familyID string) (model.Session, error) {
+	args := m.Called(familyID)
+	return args.Get(0).(model.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) GetActiveByUser(userID uint, now time.Time) ([]model.Session, error) {
+	args := m.Called(userID, now)
+	return args.Get(0).([]model.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) TouchLastSeen(id uint, ip, userAgent string, seenAt time.Time) error {
+	args := m.Called(id, ip, userAgent, seenAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) Revoke(id, userID uint, revokedAt time.Time) (bool, error) {
+	args := m.Called(id, userID, revokedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeByUser(userID, exceptID uint, revokedAt time.Time) ([]uint, error) {
+	args := m.Called(userID, exceptID, revokedAt)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockSessionRepository) IsRevoked(id uint) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) DeleteInactive(lastSeenBefore, revokedBefore time.Time) (int64, error) {
+	args := m.Called(lastSeenBefore, revokedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestSessionService_List(t *testing.T) {
+	sessions := &MockSessionRepository{}
+	service := NewSessionService(sessions, 15*time.Minute, 30*24*time.Hour)
+	sessions.On("GetActiveByUser", uint(1), mock.Anything).Return([]model.Session{
+		{ID: 2, UserAgent: "Firefox"},
+		{ID: 1, UserAgent: "curl"},
+	}, nil)
+
+	result, err := service.List(1, 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.True(t, result[0].Current)
+	assert.False(t, result[1].Current)
+	assert.Equal(t, "curl", result[1].UserAgent)
+	sessions.AssertExpectations(t)
+}
+
+func TestSessionService_Revoke(t *testing.T) {
+	t.Run("success - session revoked", func(t *testing.T) {
+		sessions := &MockSessionRepository{}
+		service := NewSessionService(sessions, time.Minute, time.Hour)
+		sessions.On("Revoke", uint(3), uint(1), mock.Anything).Return(true, nil)
+
+		err := service.Revoke(1, 3)
+
+		assert.NoError(t, err)
+		sessions.AssertExpectations(t)
+	})
+
+	t.Run("error - session of another user or already revoked", func(t *testing.T) {
+		sessions := &MockSessionRepository{}
+		service := NewSessionService(sessions, time.Minute, time.Hour)
+		sessions.On("Revoke", uint(3), uint(1), mock.Anything).Return(false, nil)
+
+		err := service.Revoke(1, 3)
+
+		assert.ErrorIs(t, err, appErrors.ErrSessionNotFound)
+	})
+}
+
+func TestSessionService_RevokeOthers(t *testing.T) {
+	sessions := &MockSessionRepository{}
+	service := NewSessionService(sessions, time.Minute, time.Hour)
+	sessions.On("RevokeByUser", uint(1), uint(2), mock.Anything).Return([]uint{3, 4}, nil)
+
+	revoked, err := service.RevokeOthers(1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, revoked)
+	sessions.AssertExpectations(t)
+}
+
+func TestSessionService_Purge(t *testing.T) {
+	sessions := &MockSessionRepository{}
+	service := NewSessionService(sessions, 15*time.Minute, 30*24*time.Hour)
+	now := time.Now()
+	sessions.On("DeleteInactive", now.Add(-30*24*time.Hour), now.Add(-15*time.Minute)).Return(int64(3), nil)
+
+	purged, err := service.Purge(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	sessions.AssertExpectations(t)
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	"github.com/UliVargas/blog-go/pkg/jwtkeys"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKeyPurpose separa el cifrado de las claves privadas de otros usos de
+// JWTSECRET, y keyReloadInterval limita las recargas de claves que provocan
+// los tokens con un kid desconocido
+const (
+	signingKeyPurpose = "signing-key"
+	keyReloadInterval = time.Minute
+)
+
+// SigningKeyService gestiona las claves con las que se firman los tokens de
+// acceso. Con HS256 se firma con JWTSECRET; con RS256 o EdDSA se usan claves
+// asimétricas guardadas en la base de datos, compartidas por todas las
+// instancias, que se rotan cada rotation. Las claves anteriores siguen
+// verificando tokens durante retention, que debe cubrir la vigencia de los
+// tokens de acceso
+type SigningKeyService struct {
+	keyRepo   repository.SigningKeyRepositoryInterface
+	keys      *jwtkeys.KeySet
+	secret    string
+	algorithm string
+	rotation  time.Duration
+	retention time.Duration
+
+	mu         sync.Mutex
+	lastReload time.Time
+}
+
+// NewSigningKeyService crea el servicio sin claves cargadas: se cargan con
+// Rotate. Los tokens HS256 sin kid solo se aceptan si el algoritmo es HS256,
+// de modo que al pasar a claves asimétricas los clientes renuevan sus tokens
+func NewSigningKeyService(keyRepo repository.SigningKeyRepositoryInterface, secret, algorithm string, rotation, retention time.Duration) *SigningKeyService {
+	hmacSecret := ""
+	if algorithm == jwtkeys.HS256 {
+		hmacSecret = secret
+	}
+	return &SigningKeyService{
+		keyRepo:   keyRepo,
+		keys:      jwtkeys.NewKeySet(hmacSecret),
+		secret:    secret,
+		algorithm: algorithm,
+		rotation:  rotation,
+		retention: retention,
+	}
+}
+
+// signingKey es una clave guardada junto con su clave privada descifrada
+type signingKey struct {
+	stored model.SigningKey
+	key    jwtkeys.Key
+}
+
+// Rotate genera una clave nueva si la activa es más antigua que rotation o no
+// hay ninguna del algoritmo configurado, retira las demás, elimina las que ya
+// terminaron su retiro y recarga las claves. Devuelve si se generó una clave
+func (s *SigningKeyService) Rotate(now time.Time) (bool, error) {
+	stored, err := s.keyRepo.GetValid(now)
+	if err != nil {
+		return false, err
+	}
+	keys := s.decrypt(stored)
+
+	rotated := false
+	active := s.active(keys)
+	if s.algorithm != jwtkeys.HS256 && (active == nil || !active.stored.CreatedAt.After(now.Add(-s.rotation))) {
+		created, err := s.generate(now)
+		if err != nil {
+			return false, err
+		}
+		keys = append(keys, created)
+		active = &keys[len(keys)-1]
+		rotated = true
+	}
+
+	var retire []uint
+	for _, key := range stored {
+		if key.RetiresAt == nil && (active == nil || key.ID != active.stored.ID) {
+			retire = append(retire, key.ID)
+		}
+	}
+	if len(retire) > 0 {
+		if err := s.keyRepo.Retire(retire, now.Add(s.retention)); err != nil {
+			return false, err
+		}
+	}
+	if _, err := s.keyRepo.DeleteRetired(now); err != nil {
+		return false, err
+	}
+
+	s.publish(active, keys)
+	return rotated, nil
+}
+
+// Sign firma las claims de un token de acceso con la clave activa
+func (s *SigningKeyService) Sign(claims map[string]any) (string, error) {
+	return s.keys.Sign(claims)
+}
+
+// Parse verifica un token de acceso. Si su kid es desconocido, porque otra
+// instancia acaba de rotar las claves, se recargan antes de rechazarlo
+func (s *SigningKeyService) Parse(token string) (*jwt.Token, error) {
+	parsed, err := s.keys.Parse(token)
+	if !errors.Is(err, jwtkeys.ErrUnknownKey) || !s.reloadAllowed(time.Now()) {
+		return parsed, err
+	}
+
+	if err := s.reload(time.Now()); err != nil {
+		log.Printf("No se pudieron recargar las claves de firma: %v", err)
+		return parsed, jwtkeys.ErrUnknownKey
+	}
+	return s.keys.Parse(token)
+}
+
+// JWKS devuelve las claves públicas que verifican los tokens vigentes
+func (s *SigningKeyService) JWKS() jwtkeys.JWKS {
+	return s.keys.JWKS()
+}
+
+// reload carga las claves guardadas sin rotarlas
+func (s *SigningKeyService) reload(now time.Time) error {
+	stored, err := s.keyRepo.GetValid(now)
+	if err != nil {
+		return err
+	}
+	keys := s.decrypt(stored)
+	s.publish(s.active(keys), keys)
+	return nil
+}
+
+func (s *SigningKeyService) reloadAllowed(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastReload) < keyReloadInterval {
+		return false
+	}
+	s.lastReload = now
+	return true
+}
+
+// decrypt descifra las claves guardadas. Las que no se pueden descifrar, por
+// ejemplo porque cambió JWTSECRET, se descartan y terminan retirándose
+func (s *SigningKeyService) decrypt(stored []model.SigningKey) []signingKey {
+	keys := make([]signingKey, 0, len(stored))
+	for _, key := range stored {
+		der, err := utils.Decrypt(key.PrivateKey, s.secret, signingKeyPurpose)
+		if err == nil {
+			var parsed jwtkeys.Key
+			if parsed, err = jwtkeys.ParseKey(key.KID, key.Algorithm, der); err == nil {
+				keys = append(keys, signingKey{key, parsed})
+				continue
+			}
+		}
+		log.Printf("Se descarta la clave de firma %s: %v", key.KID, err)
+	}
+	return keys
+}
+
+// active devuelve la clave más reciente sin retirar del algoritmo configurado
+func (s *SigningKeyService) active(keys []signingKey) *signingKey {
+	var active *signingKey
+	for i := range keys {
+		key := &keys[i]
+		if key.stored.RetiresAt != nil || key.stored.Algorithm != s.algorithm {
+			continue
+		}
+		if active == nil || !key.stored.CreatedAt.Before(active.stored.CreatedAt) {
+			active = key
+		}
+	}
+	return active
+}
+
+// generate crea y guarda una clave nueva
+func (s *SigningKeyService) generate(now time.Time) (signingKey, error) {
+	key, err := jwtkeys.Generate(s.algorithm)
+	if err != nil {
+		return signingKey{}, err
+	}
+	der, err := key.MarshalPrivateKey()
+	if err != nil {
+		return signingKey{}, err
+	}
+	encrypted, err := utils.Encrypt(der, s.secret, signingKeyPurpose)
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	stored, err := s.keyRepo.Create(model.SigningKey{
+		KID:        key.ID,
+		Algorithm:  key.Algorithm,
+		PrivateKey: encrypted,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return signingKey{}, err
+	}
+	return signingKey{stored, key}, nil
+}
+
+// publish reemplaza las claves con las que se firman y verifican los tokens
+func (s *SigningKeyService) publish(active *signingKey, keys []signingKey) {
+	verification := make([]jwtkeys.Key, 0, len(keys))
+	for _, key := range keys {
+		verification = append(verification, key.key)
+	}
+
+	var signing *jwtkeys.Key
+	if active != nil {
+		signing = &active.key
+	}
+	s.keys.Replace(signing, verification)
+}
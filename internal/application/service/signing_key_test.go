@@ -0,0 +1,216 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/jwtkeys"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSigningKeyRepository mocks the SigningKeyRepository
+type MockSigningKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockSigningKeyRepository) Create(key model.SigningKey) (model.SigningKey, error) {
+	args := m.Called(key)
+	return args.Get(0).(model.SigningKey), args.Error(1)
+}
+
+func (m *MockSigningKeyRepository) GetValid(now time.Time) ([]model.SigningKey, error) {
+	args := m.Called(now)
+	return args.Get(0).([]model.SigningKey), args.Error(1)
+}
+
+func (m *MockSigningKeyRepository) Retire(ids []uint, retiresAt time.Time) error {
+	args := m.Called(ids, retiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSigningKeyRepository) DeleteRetired(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// storedSigningKey generates a key and returns it as stored in the database,
+// encrypted with secret
+func storedSigningKey(t *testing.T, id uint, algorithm, secret string, createdAt time.Time) model.SigningKey {
+	key, err := jwtkeys.Generate(algorithm)
+	require.NoError(t, err)
+	der, err := key.MarshalPrivateKey()
+	require.NoError(t, err)
+	encrypted, err := utils.Encrypt(der, secret, signingKeyPurpose)
+	require.NoError(t, err)
+	return model.SigningKey{ID: id, KID: key.ID, Algorithm: algorithm, PrivateKey: encrypted, CreatedAt: createdAt}
+}
+
+// signedKID signs a token with the service and returns its kid
+func signedKID(t *testing.T, service *SigningKeyService) any {
+	signed, err := service.Sign(map[string]any{"user_id": 1, "exp": time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	token, err := service.Parse(signed)
+	require.NoError(t, err)
+	return token.Header["kid"]
+}
+
+func TestSigningKeyService_Rotate_HS256(t *testing.T) {
+	mockRepo := &MockSigningKeyRepository{}
+	mockRepo.On("GetValid", mock.Anything).Return([]model.SigningKey{}, nil)
+	mockRepo.On("DeleteRetired", mock.Anything).Return(int64(0), nil)
+	service := NewSigningKeyService(mockRepo, "secret", jwtkeys.HS256, 24*time.Hour, time.Hour)
+
+	rotated, err := service.Rotate(time.Now())
+
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	assert.Nil(t, signedKID(t, service))
+	assert.Empty(t, service.JWKS().Keys)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Retire", mock.Anything, mock.Anything)
+}
+
+func TestSigningKeyService_Rotate_GeneratesFirstKey(t *testing.T) {
+	for _, algorithm := range []string{jwtkeys.RS256, jwtkeys.EdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			mockRepo := &MockSigningKeyRepository{}
+			mockRepo.On("GetValid", mock.Anything).Return([]model.SigningKey{}, nil)
+			mockRepo.On("DeleteRetired", mock.Anything).Return(int64(0), nil)
+			var stored model.SigningKey
+			mockRepo.On("Create", mock.MatchedBy(func(key model.SigningKey) bool {
+				stored = key
+				return true
+			})).Return(model.SigningKey{ID: 1}, nil)
+			service := NewSigningKeyService(mockRepo, "secret", algorithm, 24*time.Hour, time.Hour)
+
+			rotated, err := service.Rotate(time.Now())
+
+			require.NoError(t, err)
+			assert.True(t, rotated)
+			assert.Equal(t, algorithm, stored.Algorithm)
+			assert.Equal(t, stored.KID, signedKID(t, service))
+			assert.Len(t, service.JWKS().Keys, 1)
+
+			// La clave privada se guarda cifrada
+			der, err := utils.Decrypt(stored.PrivateKey, "secret", signingKeyPurpose)
+			require.NoError(t, err)
+			_, err = jwtkeys.ParseKey(stored.KID, algorithm, der)
+			assert.NoError(t, err)
+			mockRepo.AssertNotCalled(t, "Retire", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSigningKeyService_Rotate(t *testing.T) {
+	now := time.Now()
+	recent := storedSigningKey(t, 1, jwtkeys.EdDSA, "secret", now.Add(-time.Hour))
+	expired := storedSigningKey(t, 2, jwtkeys.EdDSA, "secret", now.Add(-48*time.Hour))
+	otherAlgorithm := storedSigningKey(t, 3, jwtkeys.RS256, "secret", now.Add(-time.Hour))
+	undecryptable := storedSigningKey(t, 4, jwtkeys.EdDSA, "old-secret", now.Add(-time.Hour))
+	retiresAt := now.Add(30 * time.Minute)
+	retiring := storedSigningKey(t, 5, jwtkeys.EdDSA, "secret", now.Add(-72*time.Hour))
+	retiring.RetiresAt = &retiresAt
+
+	tests := []struct {
+		name       string
+		stored     []model.SigningKey
+		wantRotate bool
+		wantRetire []uint
+		wantKID    string
+		wantJWKS   int
+	}{
+		{"recent key stays active", []model.SigningKey{retiring, recent}, false, nil, recent.KID, 2},
+		{"old key is rotated and keeps verifying", []model.SigningKey{expired}, true, []uint{2}, "", 2},
+		{"key of another algorithm is replaced", []model.SigningKey{otherAlgorithm}, true, []uint{3}, "", 2},
+		{"undecryptable key is replaced", []model.SigningKey{undecryptable}, true, []uint{4}, "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockSigningKeyRepository{}
+			mockRepo.On("GetValid", now).Return(tt.stored, nil)
+			mockRepo.On("DeleteRetired", now).Return(int64(0), nil)
+			var created model.SigningKey
+			mockRepo.On("Create", mock.MatchedBy(func(key model.SigningKey) bool {
+				created = key
+				return true
+			})).Return(model.SigningKey{ID: 10}, nil).Maybe()
+			if tt.wantRetire != nil {
+				mockRepo.On("Retire", tt.wantRetire, now.Add(time.Hour)).Return(nil)
+			}
+			service := NewSigningKeyService(mockRepo, "secret", jwtkeys.EdDSA, 24*time.Hour, time.Hour)
+
+			rotated, err := service.Rotate(now)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRotate, rotated)
+			wantKID := tt.wantKID
+			if tt.wantRotate {
+				wantKID = created.KID
+			}
+			assert.Equal(t, wantKID, signedKID(t, service))
+			assert.Len(t, service.JWKS().Keys, tt.wantJWKS)
+			mockRepo.AssertExpectations(t)
+			if tt.wantRetire == nil {
+				mockRepo.AssertNotCalled(t, "Retire", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestSigningKeyService_Parse_ReloadsUnknownKey(t *testing.T) {
+	now := time.Now()
+	first := storedSigningKey(t, 1, jwtkeys.RS256, "secret", now.Add(-time.Hour))
+	second := storedSigningKey(t, 2, jwtkeys.RS256, "secret", now)
+
+	// Otra instancia rota las claves y firma con la nueva
+	otherRepo := &MockSigningKeyRepository{}
+	otherRepo.On("GetValid", mock.Anything).Return([]model.SigningKey{first, second}, nil)
+	other := NewSigningKeyService(otherRepo, "secret", jwtkeys.RS256, 24*time.Hour, time.Hour)
+	require.NoError(t, other.reload(now))
+	signed, err := other.Sign(map[string]any{"user_id": 1, "exp": now.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+
+	mockRepo := &MockSigningKeyRepository{}
+	mockRepo.On("GetValid", mock.Anything).Return([]model.SigningKey{first}, nil).Once()
+	mockRepo.On("GetValid", mock.Anything).Return([]model.SigningKey{first, second}, nil).Once()
+	service := NewSigningKeyService(mockRepo, "secret", jwtkeys.RS256, 24*time.Hour, time.Hour)
+	require.NoError(t, service.reload(now))
+
+	token, err := service.Parse(signed)
+
+	require.NoError(t, err)
+	assert.Equal(t, second.KID, token.Header["kid"])
+	assert.Equal(t, second.KID, signedKID(t, service))
+
+	// Los kid desconocidos no recargan las claves más de una vez por intervalo
+	unknownKey, err := jwtkeys.Generate(jwtkeys.RS256)
+	require.NoError(t, err)
+	unknown := jwtkeys.NewKeySet("")
+	unknown.Replace(&unknownKey, []jwtkeys.Key{unknownKey})
+	forged, err := unknown.Sign(map[string]any{"user_id": 1, "exp": now.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+
+	_, err = service.Parse(forged)
+
+	assert.ErrorIs(t, err, jwtkeys.ErrUnknownKey)
+	mockRepo.AssertNumberOfCalls(t, "GetValid", 2)
+}
+
+func TestSigningKeyService_RejectsLegacyTokensWithAsymmetricKeys(t *testing.T) {
+	legacy, err := jwtkeys.NewKeySet("secret").Sign(map[string]any{"user_id": 1, "exp": time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+
+	mockRepo := &MockSigningKeyRepository{}
+	mockRepo.On("GetValid", mock.Anything).Return([]model.SigningKey{storedSigningKey(t, 1, jwtkeys.EdDSA, "secret", time.Now())}, nil)
+	service := NewSigningKeyService(mockRepo, "secret", jwtkeys.EdDSA, 24*time.Hour, time.Hour)
+	require.NoError(t, service.reload(time.Now()))
+
+	_, err = service.Parse(legacy)
+
+	assert.Error(t, err)
+}
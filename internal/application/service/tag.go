@@ -0,0 +1,80 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/utils"
+)
+
+type TagService struct {
+	tagRepo repository.TagRepositoryInterface
+}
+
+func NewTagService(tagRepo repository.TagRepositoryInterface) *TagService {
+	return &TagService{tagRepo}
+}
+
+// GetCloud devuelve las etiquetas en uso con su número de publicaciones
+func (s *TagService) GetCloud() ([]model.TagCount, error) {
+	return s.tagRepo.GetCloud()
+}
+
+// Rename cambia el nombre y el slug de la etiqueta; el slug anterior sigue
+// funcionando como alias
+func (s *TagService) Rename(id uint, name string) (model.Tag, error) {
+	tag, err := s.tagRepo.GetByID(id)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	name = strings.TrimSpace(name)
+	slug := utils.Slugify(name)
+	if slug == "" {
+		return model.Tag{}, appErrors.ErrInvalidInput
+	}
+	inUse, err := s.tagRepo.SlugInUse(slug, tag.ID)
+	if err != nil {
+		return model.Tag{}, err
+	}
+	if inUse {
+		return model.Tag{}, appErrors.ErrTagExists
+	}
+
+	tag.Name = name
+	tag.Slug = slug
+	return s.tagRepo.Rename(tag)
+}
+
+// Merge fusiona la etiqueta de origen en la de destino
+func (s *TagService) Merge(sourceID uint, targetID uint) error {
+	if sourceID == targetID {
+		return appErrors.ErrInvalidTagMerge
+	}
+	return s.tagRepo.Merge(sourceID, targetID)
+}
+
+// AddAlias registra un slug alternativo para la etiqueta
+func (s *TagService) AddAlias(id uint, alias string) (model.TagAlias, error) {
+	tag, err := s.tagRepo.GetByID(id)
+	if err != nil {
+		return model.TagAlias{}, err
+	}
+
+	slug := utils.Slugify(alias)
+	if slug == "" {
+		return model.TagAlias{}, appErrors.ErrInvalidInput
+	}
+	// El alias no puede coincidir con ningún slug ni alias existente, incluidos los de la propia etiqueta
+	inUse, err := s.tagRepo.SlugInUse(slug, 0)
+	if err != nil {
+		return model.TagAlias{}, err
+	}
+	if inUse {
+		return model.TagAlias{}, appErrors.ErrTagExists
+	}
+
+	return s.tagRepo.CreateAlias(model.TagAlias{TagID: tag.ID, Slug: slug})
+}
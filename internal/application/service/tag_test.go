@@ -0,0 +1,169 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagRepository es un mock para el repositorio de etiquetas
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) GetByID(id uint) (model.Tag, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetBySlug(slug string) (model.Tag, error) {
+	args := m.Called(slug)
+	return args.Get(0).(model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetCloud() ([]model.TagCount, error) {
+	args := m.Called()
+	return args.Get(0).([]model.TagCount), args.Error(1)
+}
+
+func (m *MockTagRepository) SlugInUse(slug string, excludeTagID uint) (bool, error) {
+	args := m.Called(slug, excludeTagID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTagRepository) FindOrCreate(tags []model.Tag) ([]model.Tag, error) {
+	args := m.Called(tags)
+	return args.Get(0).([]model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Rename(tag model.Tag) (model.Tag, error) {
+	args := m.Called(tag)
+	return args.Get(0).(model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Merge(sourceID uint, targetID uint) error {
+	args := m.Called(sourceID, targetID)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) CreateAlias(alias model.TagAlias) (model.TagAlias, error) {
+	args := m.Called(alias)
+	return args.Get(0).(model.TagAlias), args.Error(1)
+}
+
+// NewTagServiceWithMock creates a TagService with a mock repository for testing
+func NewTagServiceWithMock() (*TagService, *MockTagRepository) {
+	mockRepo := &MockTagRepository{}
+	service := NewTagService(mockRepo)
+	return service, mockRepo
+}
+
+func TestTagService_Rename(t *testing.T) {
+	tests := []struct {
+		name          string
+		newName       string
+		mockSetup     func(*MockTagRepository)
+		expectedSlug  string
+		expectedError error
+	}{
+		{
+			name:    "success - slug follows the new name",
+			newName: "  Programación en Go ",
+			mockSetup: func(m *MockTagRepository) {
+				m.On("SlugInUse", "programacion-en-go", uint(1)).Return(false, nil)
+				m.On("Rename", model.Tag{ID: 1, Name: "Programación en Go", Slug: "programacion-en-go"}).
+					Return(model.Tag{ID: 1, Name: "Programación en Go", Slug: "programacion-en-go"}, nil)
+			},
+			expectedSlug: "programacion-en-go",
+		},
+		{
+			name:    "error - slug used by another tag",
+			newName: "SQL",
+			mockSetup: func(m *MockTagRepository) {
+				m.On("SlugInUse", "sql", uint(1)).Return(true, nil)
+			},
+			expectedError: appErrors.ErrTagExists,
+		},
+		{
+			name:          "error - name without valid characters",
+			newName:       "¿?",
+			mockSetup:     func(m *MockTagRepository) {},
+			expectedError: appErrors.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagService, mockRepo := NewTagServiceWithMock()
+			mockRepo.On("GetByID", uint(1)).Return(model.Tag{ID: 1, Name: "Golang", Slug: "golang"}, nil)
+			tt.mockSetup(mockRepo)
+
+			tag, err := tagService.Rename(1, tt.newName)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedSlug, tag.Slug)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagService_Merge(t *testing.T) {
+	t.Run("merges into another tag", func(t *testing.T) {
+		tagService, mockRepo := NewTagServiceWithMock()
+		mockRepo.On("Merge", uint(2), uint(1)).Return(nil)
+
+		err := tagService.Merge(2, 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects merging a tag into itself", func(t *testing.T) {
+		tagService, mockRepo := NewTagServiceWithMock()
+
+		err := tagService.Merge(1, 1)
+
+		assert.ErrorIs(t, err, appErrors.ErrInvalidTagMerge)
+		mockRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTagService_AddAlias(t *testing.T) {
+	tests := []struct {
+		name          string
+		inUse         bool
+		expectedError error
+	}{
+		{name: "success - alias created"},
+		{name: "error - alias already in use", inUse: true, expectedError: appErrors.ErrTagExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagService, mockRepo := NewTagServiceWithMock()
+			mockRepo.On("GetByID", uint(1)).Return(model.Tag{ID: 1, Name: "Go", Slug: "go"}, nil)
+			mockRepo.On("SlugInUse", "golang", uint(0)).Return(tt.inUse, nil)
+			if !tt.inUse {
+				mockRepo.On("CreateAlias", model.TagAlias{TagID: 1, Slug: "golang"}).
+					Return(model.TagAlias{ID: 1, TagID: 1, Slug: "golang"}, nil)
+			}
+
+			alias, err := tagService.AddAlias(1, "Golang")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "golang", alias.Slug)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
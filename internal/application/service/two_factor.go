@@ -0,0 +1,234 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/domain/repository"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/totp"
+	"github.com/UliVargas/blog-go/pkg/utils"
+)
+
+// recoveryCodeCount es la cantidad de códigos de recuperación que se generan,
+// recoveryCodeBytes su entropía y totpSkew los pasos de desfase de reloj que se
+// toleran en los códigos TOTP. Los secretos TOTP se guardan cifrados con una
+// clave derivada de JWTSECRET para totpSecretPurpose
+const (
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 5
+	totpSkew          = 1
+	totpSecretPurpose = "totp-secret"
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorService gestiona la verificación en dos pasos con TOTP. La activación
+// tiene dos fases: Setup genera el secreto y Confirm lo activa con un primer
+// código, de modo que un secreto mal importado no deja al usuario sin acceso
+type TwoFactorService struct {
+	userRepo         repository.UserRepositoryInterface
+	recoveryCodeRepo repository.RecoveryCodeRepositoryInterface
+	hasher           domainService.PasswordHasherInterface
+	secret           string
+	issuer           string
+}
+
+func NewTwoFactorService(userRepo repository.UserRepositoryInterface, recoveryCodeRepo repository.RecoveryCodeRepositoryInterface, hasher domainService.PasswordHasherInterface, secret, issuer string) *TwoFactorService {
+	return &TwoFactorService{userRepo, recoveryCodeRepo, hasher, secret, issuer}
+}
+
+// Setup genera un nuevo secreto pendiente de confirmar. Repetirlo antes de
+// confirmar reemplaza el secreto anterior
+func (s *TwoFactorService) Setup(userID uint) (dto.TwoFactorSetup, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return dto.TwoFactorSetup{}, err
+	}
+	if user.TwoFactorEnabled() {
+		return dto.TwoFactorSetup{}, appErrors.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return dto.TwoFactorSetup{}, appErrors.NewInternalServerError(err, "Error al generar el secreto")
+	}
+	encrypted, err := s.encryptSecret(secret)
+	if err != nil {
+		return dto.TwoFactorSetup{}, appErrors.NewInternalServerError(err, "Error al cifrar el secreto")
+	}
+	if _, err := s.userRepo.UpdateFields(userID, map[string]any{"totp_secret": encrypted}); err != nil {
+		return dto.TwoFactorSetup{}, err
+	}
+
+	return dto.TwoFactorSetup{Secret: secret, URI: totp.URI(s.issuer, user.Email, secret)}, nil
+}
+
+// Confirm activa la verificación en dos pasos si el código corresponde al secreto
+// pendiente y devuelve los códigos de recuperación, que no se vuelven a mostrar
+func (s *TwoFactorService) Confirm(userID uint, code string) ([]string, error) {
+	now := time.Now()
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, appErrors.ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, appErrors.ErrTwoFactorNotEnabled
+	}
+
+	secret, err := s.decryptSecret(user.TOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := totp.Validate(secret, strings.TrimSpace(code), now, totpSkew)
+	if !ok {
+		return nil, appErrors.ErrInvalidTwoFactorCode
+	}
+
+	codes, err := s.replaceRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{"totp_enabled_at": now, "totp_last_step": step}
+	if _, err := s.userRepo.UpdateFields(userID, fields); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable desactiva la verificación en dos pasos tras confirmar la contraseña y
+// un código TOTP o de recuperación
+func (s *TwoFactorService) Disable(userID uint, password, code string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled() {
+		return appErrors.ErrTwoFactorNotEnabled
+	}
+	if err := s.hasher.Verify(user.Password, password); err != nil {
+		return appErrors.ErrIncorrectPassword
+	}
+	if err := s.Authenticate(user, code); err != nil {
+		return err
+	}
+
+	if err := s.recoveryCodeRepo.DeleteByUser(userID); err != nil {
+		return err
+	}
+	_, err = s.userRepo.UpdateFields(userID, map[string]any{
+		"totp_secret":     "",
+		"totp_enabled_at": nil,
+		"totp_last_step":  0,
+	})
+	return err
+}
+
+// Authenticate comprueba el segundo factor del usuario. code es un código TOTP,
+// que no se admite dos veces, o un código de recuperación, que se consume
+func (s *TwoFactorService) Authenticate(user model.User, code string) error {
+	now := time.Now()
+	code = strings.TrimSpace(code)
+
+	if isTOTPCode(code) {
+		secret, err := s.decryptSecret(user.TOTPSecret)
+		if err != nil {
+			return err
+		}
+		step, ok := totp.Validate(secret, code, now, totpSkew)
+		if !ok {
+			return appErrors.ErrInvalidTwoFactorCode
+		}
+		advanced, err := s.userRepo.AdvanceTOTPStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			return appErrors.ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.recoveryCodeRepo.Use(user.ID, utils.HashToken(normalizeRecoveryCode(code)), now)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return appErrors.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// encryptSecret cifra el secreto TOTP para guardarlo, de modo que una filtración
+// de la base de datos no permita generar códigos
+func (s *TwoFactorService) encryptSecret(secret string) (string, error) {
+	encrypted, err := utils.Encrypt([]byte(secret), s.secret, totpSecretPurpose)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(encrypted), nil
+}
+
+// decryptSecret descifra el secreto TOTP guardado. Si no se puede descifrar,
+// por ejemplo porque cambió JWTSECRET, el usuario aún puede usar sus códigos
+// de recuperación
+func (s *TwoFactorService) decryptSecret(stored string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(stored)
+	if err != nil {
+		return "", appErrors.NewInternalServerError(utils.ErrInvalidCiphertext, "Error al descifrar el secreto")
+	}
+	secret, err := utils.Decrypt(data, s.secret, totpSecretPurpose)
+	if err != nil {
+		return "", appErrors.NewInternalServerError(err, "Error al descifrar el secreto")
+	}
+	return string(secret), nil
+}
+
+// replaceRecoveryCodes genera nuevos códigos de recuperación, invalidando los
+// anteriores, y guarda sus hashes
+func (s *TwoFactorService) replaceRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	stored := make([]model.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, appErrors.NewInternalServerError(err, "Error al generar los códigos de recuperación")
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))
+		codes[i] = code[:4] + "-" + code[4:]
+		stored[i] = model.RecoveryCode{UserID: userID, CodeHash: utils.HashToken(code)}
+	}
+
+	if err := s.recoveryCodeRepo.Replace(userID, stored); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// isTOTPCode indica si code tiene el formato de un código TOTP
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeRecoveryCode admite los códigos de recuperación con o sin guion y en
+// mayúsculas
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/totp"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockRecoveryCodeRepository mocks the RecoveryCodeRepository
+type MockRecoveryCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecoveryCodeRepository) Replace(userID uint, codes []model.RecoveryCode) error {
+	args := m.Called(userID, codes)
+	return args.Error(0)
+}
+
+func (m *MockRecoveryCodeRepository) Use(userID uint, hash string, usedAt time.Time) (bool, error) {
+	args := m.Called(userID, hash, usedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRecoveryCodeRepository) DeleteByUser(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// MockTwoFactorService mocks the TwoFactorService
+type MockTwoFactorService struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorService) Setup(userID uint) (dto.TwoFactorSetup, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.TwoFactorSetup), args.Error(1)
+}
+
+func (m *MockTwoFactorService) Confirm(userID uint, code string) ([]string, error) {
+	args := m.Called(userID, code)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTwoFactorService) Disable(userID uint, password, code string) error {
+	args := m.Called(userID, password, code)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorService) Authenticate(user model.User, code string) error {
+	args := m.Called(user, code)
+	return args.Error(0)
+}
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// encryptedTOTPSecret returns secret as TwoFactorService stores it
+func encryptedTOTPSecret(t *testing.T, secret string) string {
+	encrypted, err := utils.Encrypt([]byte(secret), testSecret, totpSecretPurpose)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(encrypted)
+}
+
+func currentTOTPCode(t *testing.T) string {
+	code, err := totp.Code(testTOTPSecret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func newTwoFactorServiceWithMocks() (*TwoFactorService, *MockUserRepository, *MockRecoveryCodeRepository) {
+	users := &MockUserRepository{}
+	codes := &MockRecoveryCodeRepository{}
+	return NewTwoFactorService(users, codes, testHasher(), testSecret, "Blog"), users, codes
+}
+
+func TestTwoFactorService_Setup(t *testing.T) {
+	t.Run("success - pending secret stored", func(t *testing.T) {
+		service, users, _ := newTwoFactorServiceWithMocks()
+		users.On("GetByID", uint(1)).Return(model.User{ID: 1, Email: "john@example.com"}, nil)
+		var stored string
+		users.On("UpdateFields", uint(1), mock.MatchedBy(func(fields map[string]any) bool {
+			stored, _ = fields["totp_secret"].(string)
+			return len(fields) == 1
+		})).Return(model.User{}, nil)
+
+		setup, err := service.Setup(1)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, stored, setup.Secret)
+		decrypted, err := service.decryptSecret(stored)
+		assert.NoError(t, err)
+		assert.Equal(t, setup.Secret, decrypted)
+		assert.True(t, strings.HasPrefix(setup.URI, "otpauth://totp/Blog:john@example.com?"))
+		assert.Contains(t, setup.URI, "secret="+setup.Secret)
+	})
+
+	t.Run("error - already enabled", func(t *testing.T) {
+		service, users, _ := newTwoFactorServiceWithMocks()
+		enabledAt := time.Now()
+		users.On("GetByID", uint(1)).Return(model.User{ID: 1, TOTPEnabledAt: &enabledAt}, nil)
+
+		_, err := service.Setup(1)
+
+		assert.ErrorIs(t, err, appErrors.ErrTwoFactorAlreadyEnabled)
+		users.AssertNotCalled(t, "UpdateFields", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTwoFactorService_Confirm(t *testing.T) {
+	pending := model.User{ID: 1, TOTPSecret: encryptedTOTPSecret(t, testTOTPSecret)}
+
+	t.Run("success - enabled with hashed recovery codes", func(t *testing.T) {
+		service, users, codes := newTwoFactorServiceWithMocks()
+		users.On("GetByID", uint(1)).Return(pending, nil)
+		var stored []model.RecoveryCode
+		codes.On("Replace", uint(1), mock.MatchedBy(func(c []model.RecoveryCode) bool {
+			stored = c
+			return true
+		})).Return(nil)
+		users.On("UpdateFields", uint(1), mock.MatchedBy(func(fields map[string]any) bool {
+			_, enabled := fields["totp_enabled_at"].(time.Time)
+			step, _ := fields["totp_last_step"].(int64)
+			return enabled && step > 0
+		})).Return(model.User{}, nil)
+
+		recovery, err := service.Confirm(1, currentTOTPCode(t))
+
+		require.NoError(t, err)
+		require.Len(t, recovery, recoveryCodeCount)
+		require.Len(t, stored, recoveryCodeCount)
+		for i, code := range recovery {
+			assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, code)
+			assert.Equal(t, utils.HashToken(strings.ReplaceAll(code, "-", "")), stored[i].CodeHash)
+		}
+		users.AssertExpectations(t)
+	})
+
+	t.Run("error - wrong code", func(t *testing.T) {
+		service, users, codes := newTwoFactorServiceWithMocks()
+		users.On("GetByID", uint(1)).Return(pending, nil)
+
+		_, err := service.Confirm(1, "000000x")
+
+		assert.ErrorIs(t, err, appErrors.ErrInvalidTwoFactorCode)
+		codes.AssertNotCalled(t, "Replace", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error - secret encrypted with another key", func(t *testing.T) {
+		service, users, codes := newTwoFactorServiceWithMocks()
+		service.secret = "rotated-secret"
+		users.On("GetByID", uint(1)).Return(pending, nil)
+
+		_, err := service.Confirm(1, currentTOTPCode(t))
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, appErrors.ErrInvalidTwoFactorCode)
+		codes.AssertNotCalled(t, "Replace", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error - setup not started", func(t *testing.T) {
+		service, users, _ := newTwoFactorServiceWithMocks()
+		users.On("GetByID", uint(1)).Return(model.User{ID: 1}, nil)
+
+		_, err := service.Confirm(1, "123456")
+
+		assert.ErrorIs(t, err, appErrors.ErrTwoFactorNotEnabled)
+	})
+}
+
+func TestTwoFactorService_Authenticate(t *testing.T) {
+	enabledAt := time.Now()
+	user := model.User{ID: 1, TOTPSecret: encryptedTOTPSecret(t, testTOTPSecret), TOTPEnabledAt: &enabledAt}
+
+	t.Run("totp code accepted once", func(t *testing.T) {
+		service, users, _ := newTwoFactorServiceWithMocks()
+		code := currentTOTPCode(t)
+		users.On("AdvanceTOTPStep", uint(1), mock.Anything).Return(true, nil).Once()
+		users.On("AdvanceTOTPStep", uint(1), mock.Anything).Return(false, nil).Once()
+
+		assert.NoError(t, service.Authenticate(user, code))
+		assert.ErrorIs(t, service.Authenticate(user, code), appErrors.ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("wrong totp code", func(t *testing.T) {
+		service, users, _ := newTwoFactorServiceWithMocks()
+		code := currentTOTPCode(t)
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+
+		err := service.Authenticate(user, wrong)
+
+		assert.ErrorIs(t, err, appErrors.ErrInvalidTwoFactorCode)
+		users.AssertNotCalled(t, "AdvanceTOTPStep", mock.Anything, mock.Anything)
+	})
+
+	t.Run("plaintext secret is rejected", func(t *testing.T) {
+		service, users, _ := newTwoFactorServiceWithMocks()
+		plaintext := model.User{ID: 1, TOTPSecret: testTOTPSecret, TOTPEnabledAt: &enabledAt}
+
+		err := service.Authenticate(plaintext, currentTOTPCode(t))
+
+		assert.Error(t, err)
+		users.AssertNotCalled(t, "AdvanceTOTPStep", mock.Anything, mock.Anything)
+	})
+
+	t.Run("recovery code is normalised and consumed", func(t *testing.T) {
+		service, _, codes := newTwoFactorServiceWithMocks()
+		codes.On("Use", uint(1), utils.HashToken("abcdefgh"), mock.Anything).Return(true, nil)
+
+		assert.NoError(t, service.Authenticate(user, " ABCD-EFGH "))
+		codes.AssertExpectations(t)
+	})
+
+	t.Run("used recovery code", func(t *testing.T) {
+		service, _, codes := newTwoFactorServiceWithMocks()
+		codes.On("Use", uint(1), mock.Anything, mock.Anything).Return(false, nil)
+
+		assert.ErrorIs(t, service.Authenticate(user, "abcd-efgh"), appErrors.ErrInvalidTwoFactorCode)
+	})
+}
+
+func TestTwoFactorService_Disable(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	enabledAt := time.Now()
+	user := model.User{ID: 1, Password: string(hashedPassword), TOTPSecret: encryptedTOTPSecret(t, testTOTPSecret), TOTPEnabledAt: &enabledAt}
+
+	t.Run("success - secret and recovery codes removed", func(t *testing.T) {
+		service, users, codes := newTwoFactorServiceWithMocks()
+		users.On("GetByID", uint(1)).Return(user, nil)
+		users.On("AdvanceTOTPStep", uint(1), mock.Anything).Return(true, nil)
+		codes.On("DeleteByUser", uint(1)).Return(nil)
+		users.On("UpdateFields", uint(1), map[string]any{
+			"totp_secret":     "",
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Return(model.User{}, nil)
+
+		err := service.Disable(1, "password123", currentTOTPCode(t))
+
+		assert.NoError(t, err)
+		users.AssertExpectations(t)
+		codes.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name      string
+		user      model.User
+		password  string
+		code      string
+		wantError error
+	}{
+		{"not enabled", model.User{ID: 1, Password: string(hashedPassword)}, "password123", "123456", appErrors.ErrTwoFactorNotEnabled},
+		{"wrong password", user, "wrong", "123456", appErrors.ErrIncorrectPassword},
+		{"wrong code", user, "password123", "abcd-efgh", appErrors.ErrInvalidTwoFactorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, users, codes := newTwoFactorServiceWithMocks()
+			users.On("GetByID", uint(1)).Return(tt.user, nil)
+			codes.On("Use", uint(1), mock.Anything, mock.Anything).Return(false, nil).Maybe()
+
+			err := service.Disable(1, tt.password, tt.code)
+
+			assert.ErrorIs(t, err, tt.wantError)
+			codes.AssertNotCalled(t, "DeleteByUser", mock.Anything)
+			users.AssertNotCalled(t, "UpdateFields", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthService_Login_TwoFactorChallenge(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	enabledAt := time.Now()
+	user := model.User{ID: 1, Email: "test@example.com", Password: string(hashedPassword), TOTPEnabledAt: &enabledAt}
+
+	mockRepo, mockTokens, throttle := &MockUserRepositoryAuth{}, &MockRefreshTokenRepository{}, &MockLoginThrottle{}
+	service := NewAuthService(mockRepo, mockTokens, nil, openSessions(), nil, throttle, &MockTwoFactorService{}, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, false)
+	throttle.On("Check", "test@example.com", "10.0.0.1", mock.Anything).Return(nil)
+	mockRepo.On("GetByEmail", "test@example.com").Return(user, nil)
+
+	result, err := service.Login("test@example.com", "password123", dto.ClientInfo{IP: "10.0.0.1"})
+
+	require.NoError(t, err)
+	require.NotNil(t, result.Challenge)
+	assert.NotEmpty(t, result.Challenge.Token)
+	assert.Equal(t, twoFactorChallengeTTL, result.Challenge.ExpiresIn)
+	assert.Empty(t, result.Tokens.AccessToken)
+	mockTokens.AssertNotCalled(t, "Create", mock.Anything)
+	throttle.AssertNotCalled(t, "Succeed", mock.Anything)
+}
+
+func TestAuthService_VerifyTwoFactor(t *testing.T) {
+	enabledAt := time.Now()
+	user := model.User{ID: 1, Email: "test@example.com", TOTPEnabledAt: &enabledAt}
+	challenge, err := (&AuthService{secret: testSecret}).newTwoFactorChallenge(user, "10.0.0.1", time.Now())
+	require.NoError(t, err)
+	expired, err := (&AuthService{secret: testSecret}).newTwoFactorChallenge(user, "10.0.0.1", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	forged, err := (&AuthService{secret: "another-secret"}).newTwoFactorChallenge(user, "10.0.0.1", time.Now())
+	require.NoError(t, err)
+	changedAt := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		challenge string
+		stored    model.User
+		consumed  bool
+		authErr   error
+		wantError error
+	}{
+		{"success - tokens issued", challenge.Token, user, false, nil, nil},
+		{"wrong code counts as a failure", challenge.Token, user, false, appErrors.ErrInvalidTwoFactorCode, appErrors.ErrInvalidTwoFactorCode},
+		{"challenge already redeemed", challenge.Token, user, true, nil, appErrors.ErrInvalidTwoFactorChallenge},
+		{"tampered challenge", challenge.Token + "x", user, false, nil, appErrors.ErrInvalidTwoFactorChallenge},
+		{"expired challenge", expired.Token, user, false, nil, appErrors.ErrInvalidTwoFactorChallenge},
+		{"challenge signed with another secret", forged.Token, user, false, nil, appErrors.ErrInvalidTwoFactorChallenge},
+		{"two-factor disabled since", challenge.Token, model.User{ID: 1, Email: "test@example.com"}, false, nil, appErrors.ErrInvalidTwoFactorChallenge},
+		{"password changed since", challenge.Token, model.User{ID: 1, Email: "test@example.com", TOTPEnabledAt: &enabledAt, PasswordChangedAt: &changedAt}, false, nil, appErrors.ErrInvalidTwoFactorChallenge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo, mockTokens, revoked, throttle, twoFactor := &MockUserRepositoryAuth{}, &MockRefreshTokenRepository{}, &MockRevokedTokenRepository{}, &MockLoginThrottle{}, &MockTwoFactorService{}
+			service := NewAuthService(mockRepo, mockTokens, revoked, openSessions(), nil, throttle, twoFactor, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, false)
+			mockRepo.On("GetByID", uint(1)).Return(tt.stored, nil).Maybe()
+			revoked.On("IsRevoked", mock.Anything).Return(tt.consumed, nil).Maybe()
+			revoked.On("Create", mock.Anything).Return(nil).Maybe()
+			throttle.On("Check", "test@example.com", "10.0.0.1", mock.Anything).Return(nil).Maybe()
+			throttle.On("Fail", "test@example.com", "10.0.0.1", mock.Anything).Return(nil).Maybe()
+			throttle.On("Succeed", "test@example.com").Return(nil).Maybe()
+			twoFactor.On("Authenticate", tt.stored, "123456").Return(tt.authErr).Maybe()
+			mockTokens.On("Create", mock.Anything).Return(model.RefreshToken{}, nil).Maybe()
+
+			tokens, err := service.VerifyTwoFactor(tt.challenge, "123456", dto.ClientInfo{IP: "10.0.0.1"})
+
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+				mockTokens.AssertNotCalled(t, "Create", mock.Anything)
+				revoked.AssertNotCalled(t, "Create", mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, tokens.AccessToken)
+				throttle.AssertCalled(t, "Succeed", "test@example.com")
+				revoked.AssertCalled(t, "Create", mock.MatchedBy(func(token model.RevokedToken) bool {
+					return token.JTI != "" && token.UserID == 1 && token.ExpiresAt.After(time.Now())
+				}))
+			}
+			if tt.authErr != nil {
+				throttle.AssertCalled(t, "Fail", "test@example.com", "10.0.0.1", mock.Anything)
+			} else {
+				throttle.AssertNotCalled(t, "Fail", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthService_VerifyTwoFactor_Throttled(t *testing.T) {
+	enabledAt := time.Now()
+	user := model.User{ID: 1, Email: "test@example.com", TOTPEnabledAt: &enabledAt}
+	challenge, err := (&AuthService{secret: testSecret}).newTwoFactorChallenge(user, "10.0.0.1", time.Now())
+	require.NoError(t, err)
+
+	mockRepo, revoked, throttle, twoFactor := &MockUserRepositoryAuth{}, &MockRevokedTokenRepository{}, &MockLoginThrottle{}, &MockTwoFactorService{}
+	service := NewAuthService(mockRepo, nil, revoked, nil, nil, throttle, twoFactor, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, false)
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	revoked.On("IsRevoked", mock.Anything).Return(false, nil)
+	throttle.On("Check", "test@example.com", "10.0.0.1", mock.Anything).
+		Return(appErrors.NewRetryAfterError(appErrors.ErrTooManyLoginAttempts, time.Minute))
+
+	_, err = service.VerifyTwoFactor(challenge.Token, "123456", dto.ClientInfo{IP: "10.0.0.1"})
+
+	assert.ErrorIs(t, err, appErrors.ErrTooManyLoginAttempts)
+	twoFactor.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_VerifyTwoFactor_WrongCodeFromAnotherIP(t *testing.T) {
+	enabledAt := time.Now()
+	user := model.User{ID: 1, Email: "test@example.com", TOTPEnabledAt: &enabledAt}
+	challenge, err := (&AuthService{secret: testSecret}).newTwoFactorChallenge(user, "10.0.0.1", time.Now())
+	require.NoError(t, err)
+
+	mockRepo, revoked, throttle, twoFactor := &MockUserRepositoryAuth{}, &MockRevokedTokenRepository{}, &MockLoginThrottle{}, &MockTwoFactorService{}
+	service := NewAuthService(mockRepo, nil, revoked, nil, nil, throttle, twoFactor, testSigner(), testHasher(), testSecret, time.Minute, time.Hour, false)
+	mockRepo.On("GetByID", uint(1)).Return(user, nil)
+	revoked.On("IsRevoked", mock.Anything).Return(false, nil)
+	throttle.On("Check", "test@example.com", mock.Anything, mock.Anything).Return(nil)
+	throttle.On("Fail", "test@example.com", mock.Anything, mock.Anything).Return(nil)
+	twoFactor.On("Authenticate", user, "000000").Return(appErrors.ErrInvalidTwoFactorCode)
+
+	_, err = service.VerifyTwoFactor(challenge.Token, "000000", dto.ClientInfo{IP: "10.0.0.2"})
+
+	// The guess counts against the IP that passed the password check and the one presenting the challenge
+	assert.ErrorIs(t, err, appErrors.ErrInvalidTwoFactorCode)
+	throttle.AssertCalled(t, "Check", "test@example.com", "10.0.0.1", mock.Anything)
+	throttle.AssertCalled(t, "Check", "test@example.com", "10.0.0.2", mock.Anything)
+	throttle.AssertCalled(t, "Fail", "test@example.com", "10.0.0.1", mock.Anything)
+	throttle.AssertCalled(t, "Fail", "test@example.com", "10.0.0.2", mock.Anything)
+}
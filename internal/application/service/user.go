@@ -1,20 +1,30 @@
 package service
 
 import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
 	"github.com/UliVargas/blog-go/internal/domain/model"
 	"github.com/UliVargas/blog-go/internal/domain/repository"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
 )
 
 type UserService struct {
 	userRepo repository.UserRepositoryInterface
+	verifier domainService.EmailVerificationServiceInterface
+	hasher   domainService.PasswordHasherInterface
 }
 
-func NewUserService(userRepo repository.UserRepositoryInterface) *UserService {
-	return &UserService{userRepo}
+func NewUserService(userRepo repository.UserRepositoryInterface, verifier domainService.EmailVerificationServiceInterface, hasher domainService.PasswordHasherInterface) *UserService {
+	return &UserService{userRepo, verifier, hasher}
 }
 
-func (s *UserService) GetAll() ([]model.User, error) {
-	return s.userRepo.GetAll()
+func (s *UserService) GetAll(spec query.Spec) (query.Page[model.User], error) {
+	return s.userRepo.GetAll(spec)
 }
 
 func (s *UserService) GetByID(id uint) (model.User, error) {
@@ -28,3 +38,77 @@ func (s *UserService) Update(user model.User) (model.User, error) {
 func (s *UserService) Delete(id uint) error {
 	return s.userRepo.Delete(id)
 }
+
+// UpdateProfile aplica los cambios presentes en la petición. Cambiar el email
+// invalida su verificación y envía un enlace para verificar el nuevo
+func (s *UserService) UpdateProfile(id uint, req dto.UpdateProfileRequest) (model.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	fields := map[string]any{}
+	if req.Name != nil {
+		fields["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.Bio != nil {
+		fields["bio"] = strings.TrimSpace(*req.Bio)
+	}
+	if req.Website != nil {
+		fields["website"] = strings.TrimSpace(*req.Website)
+	}
+	if req.AvatarURL != nil {
+		fields["avatar_url"] = strings.TrimSpace(*req.AvatarURL)
+	}
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if email != user.Email {
+			existing, err := s.userRepo.GetByEmail(email)
+			if err != nil && !errors.Is(err, appErrors.ErrUserNotFound) {
+				return model.User{}, err
+			}
+			if existing.ID != 0 {
+				return model.User{}, appErrors.ErrEmailExists
+			}
+			fields["email"] = email
+			fields["email_verified_at"] = nil
+		}
+	}
+
+	if len(fields) == 0 {
+		return user, nil
+	}
+	updated, err := s.userRepo.UpdateFields(id, fields)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if _, changed := fields["email"]; changed {
+		if err := s.verifier.Send(updated); err != nil {
+			log.Printf("No se pudo enviar el email de verificación al usuario %d: %v", updated.ID, err)
+		}
+	}
+	return updated, nil
+}
+
+// DeleteAccount elimina la cuenta tras confirmar la contraseña actual. Las
+// publicaciones y comentarios del usuario se eliminan en cascada
+func (s *UserService) DeleteAccount(id uint, password string) error {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.hasher.Verify(user.Password, password); err != nil {
+		return appErrors.ErrIncorrectPassword
+	}
+	return s.userRepo.Delete(id)
+}
+
+// UpdateRole asigna un rol al usuario. El cambio se refleja en sus permisos
+// cuando renueva su token de acceso
+func (s *UserService) UpdateRole(id uint, role model.Role) (model.User, error) {
+	if !role.IsValid() {
+		return model.User{}, appErrors.ErrInvalidInput
+	}
+	return s.userRepo.UpdateFields(id, map[string]any{"role": role})
+}
@@ -4,10 +4,13 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/UliVargas/blog-go/internal/domain/dto"
 	"github.com/UliVargas/blog-go/internal/domain/model"
 	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // MockUserRepository es un mock para el repositorio de usuarios
@@ -15,9 +18,9 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) GetAll() ([]model.User, error) {
-	args := m.Called()
-	return args.Get(0).([]model.User), args.Error(1)
+func (m *MockUserRepository) GetAll(spec query.Spec) (query.Page[model.User], error) {
+	args := m.Called(spec)
+	return args.Get(0).(query.Page[model.User]), args.Error(1)
 }
 
 func (m *MockUserRepository) GetByID(id uint) (model.User, error) {
@@ -30,9 +33,9 @@ func (m *MockUserRepository) GetByEmail(email string) (model.User, error) {
 	return args.Get(0).(model.User), args.Error(1)
 }
 
-func (m *MockUserRepository) Create(user model.User) error {
+func (m *MockUserRepository) Create(user model.User) (model.User, error) {
 	args := m.Called(user)
-	return args.Error(0)
+	return args.Get(0).(model.User), args.Error(1)
 }
 
 func (m *MockUserRepository) Update(user model.User) (model.User, error) {
@@ -40,6 +43,21 @@ func (m *MockUserRepository) Update(user model.User) (model.User, error) {
 	return args.Get(0).(model.User), args.Error(1)
 }
 
+func (m *MockUserRepository) UpdateFields(id uint, fields map[string]any) (model.User, error) {
+	args := m.Called(id, fields)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) AdvanceTOTPStep(id uint, step int64) (bool, error) {
+	args := m.Called(id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) ReplacePasswordHash(id uint, current, replacement string) (bool, error) {
+	args := m.Called(id, current, replacement)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockUserRepository) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -48,7 +66,9 @@ func (m *MockUserRepository) Delete(id uint) error {
 // NewUserServiceWithMock creates a UserService with a mock repository for testing
 func NewUserServiceWithMock() (*UserService, *MockUserRepository) {
 	mockRepo := &MockUserRepository{}
-	service := NewUserService(mockRepo)
+	mockVerifier := &MockEmailVerifier{}
+	mockVerifier.On("Send", mock.Anything).Return(nil).Maybe()
+	service := NewUserService(mockRepo, mockVerifier, testHasher())
 	return service, mockRepo
 }
 
@@ -59,33 +79,40 @@ func TestNewUserService(t *testing.T) {
 }
 
 func TestUserService_GetAll(t *testing.T) {
+	spec := query.Spec{Limit: 2, SortKey: "created_at", Sort: query.Field{Column: "created_at", Kind: query.KindTime}}
+
 	tests := []struct {
 		name          string
 		mockSetup     func(*MockUserRepository)
-		expectedUsers []model.User
+		expectedPage  query.Page[model.User]
 		expectedError error
 	}{
 		{
 			name: "success - users found",
 			mockSetup: func(mockRepo *MockUserRepository) {
-				users := []model.User{
-					{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "hashedpassword"},
-					{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Password: "hashedpassword2"},
+				page := query.Page[model.User]{
+					Data: []model.User{
+						{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "hashedpassword"},
+						{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Password: "hashedpassword2"},
+					},
+					NextCursor: "next",
 				}
-				mockRepo.On("GetAll").Return(users, nil)
+				mockRepo.On("GetAll", spec).Return(page, nil)
 			},
-			expectedUsers: []model.User{
-				{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "hashedpassword"},
-				{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Password: "hashedpassword2"},
+			expectedPage: query.Page[model.User]{
+				Data: []model.User{
+					{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "hashedpassword"},
+					{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Password: "hashedpassword2"},
+				},
+				NextCursor: "next",
 			},
 			expectedError: nil,
 		},
 		{
 			name: "error - database error",
 			mockSetup: func(mockRepo *MockUserRepository) {
-				mockRepo.On("GetAll").Return([]model.User{}, errors.New("database error"))
+				mockRepo.On("GetAll", spec).Return(query.Page[model.User]{}, errors.New("database error"))
 			},
-			expectedUsers: []model.User{},
 			expectedError: errors.New("database error"),
 		},
 	}
@@ -96,14 +123,14 @@ func TestUserService_GetAll(t *testing.T) {
 
 			tt.mockSetup(mockRepo)
 
-			users, err := userService.GetAll()
+			page, err := userService.GetAll(spec)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError.Error(), err.Error())
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedUsers, users)
+				assert.Equal(t, tt.expectedPage, page)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -267,3 +294,160 @@ func TestUserService_Delete(t *testing.T) {
 		})
 	}
 }
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	current := model.User{ID: 1, Name: "John Doe", Email: "john@example.com", Bio: "Old bio"}
+
+	tests := []struct {
+		name           string
+		req            dto.UpdateProfileRequest
+		mockSetup      func(*MockUserRepository)
+		expectedError  error
+		expectedFields map[string]any
+	}{
+		{
+			name: "success - only provided fields are updated",
+			req:  dto.UpdateProfileRequest{Bio: stringPtr(" New bio "), Website: stringPtr("")},
+			mockSetup: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", uint(1)).Return(current, nil)
+			},
+			expectedFields: map[string]any{"bio": "New bio", "website": ""},
+		},
+		{
+			name: "success - email change requires verification again",
+			req:  dto.UpdateProfileRequest{Email: stringPtr("new@example.com")},
+			mockSetup: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", uint(1)).Return(current, nil)
+				mockRepo.On("GetByEmail", "new@example.com").Return(model.User{}, appErrors.ErrUserNotFound)
+			},
+			expectedFields: map[string]any{"email": "new@example.com", "email_verified_at": nil},
+		},
+		{
+			name: "success - same email is ignored",
+			req:  dto.UpdateProfileRequest{Email: stringPtr("john@example.com")},
+			mockSetup: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", uint(1)).Return(current, nil)
+			},
+		},
+		{
+			name: "error - email already registered",
+			req:  dto.UpdateProfileRequest{Email: stringPtr("jane@example.com")},
+			mockSetup: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", uint(1)).Return(current, nil)
+				mockRepo.On("GetByEmail", "jane@example.com").Return(model.User{ID: 2}, nil)
+			},
+			expectedError: appErrors.ErrEmailExists,
+		},
+		{
+			name: "error - user not found",
+			req:  dto.UpdateProfileRequest{Name: stringPtr("John")},
+			mockSetup: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", uint(1)).Return(model.User{}, appErrors.ErrUserNotFound)
+			},
+			expectedError: appErrors.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userService, mockRepo := NewUserServiceWithMock()
+			tt.mockSetup(mockRepo)
+			if tt.expectedFields != nil {
+				mockRepo.On("UpdateFields", uint(1), tt.expectedFields).Return(current, nil)
+			}
+
+			user, err := userService.UpdateProfile(1, tt.req)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, current.ID, user.ID)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_UpdateProfile_SendsVerification(t *testing.T) {
+	current := model.User{ID: 1, Email: "john@example.com"}
+	updated := model.User{ID: 1, Email: "new@example.com"}
+
+	t.Run("email change sends a link to the new address", func(t *testing.T) {
+		mockRepo, mockVerifier := &MockUserRepository{}, &MockEmailVerifier{}
+		mockRepo.On("GetByID", uint(1)).Return(current, nil)
+		mockRepo.On("GetByEmail", "new@example.com").Return(model.User{}, appErrors.ErrUserNotFound)
+		mockRepo.On("UpdateFields", uint(1), mock.Anything).Return(updated, nil)
+		mockVerifier.On("Send", updated).Return(appErrors.ErrDatabaseConnection)
+
+		// A failed delivery does not undo the profile change
+		user, err := NewUserService(mockRepo, mockVerifier, testHasher()).UpdateProfile(1, dto.UpdateProfileRequest{Email: stringPtr("new@example.com")})
+
+		assert.NoError(t, err)
+		assert.Equal(t, updated, user)
+		mockVerifier.AssertExpectations(t)
+	})
+
+	t.Run("other changes do not send a link", func(t *testing.T) {
+		mockRepo, mockVerifier := &MockUserRepository{}, &MockEmailVerifier{}
+		mockRepo.On("GetByID", uint(1)).Return(current, nil)
+		mockRepo.On("UpdateFields", uint(1), mock.Anything).Return(current, nil)
+
+		_, err := NewUserService(mockRepo, mockVerifier, testHasher()).UpdateProfile(1, dto.UpdateProfileRequest{Name: stringPtr("John")})
+
+		assert.NoError(t, err)
+		mockVerifier.AssertNotCalled(t, "Send", mock.Anything)
+	})
+}
+
+func TestUserService_DeleteAccount(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	user := model.User{ID: 1, Password: string(hash)}
+
+	t.Run("success - account deleted", func(t *testing.T) {
+		userService, mockRepo := NewUserServiceWithMock()
+		mockRepo.On("GetByID", uint(1)).Return(user, nil)
+		mockRepo.On("Delete", uint(1)).Return(nil)
+
+		assert.NoError(t, userService.DeleteAccount(1, "password123"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("error - incorrect password", func(t *testing.T) {
+		userService, mockRepo := NewUserServiceWithMock()
+		mockRepo.On("GetByID", uint(1)).Return(user, nil)
+
+		err := userService.DeleteAccount(1, "wrong")
+
+		assert.ErrorIs(t, err, appErrors.ErrIncorrectPassword)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestUserService_UpdateRole(t *testing.T) {
+	t.Run("success - role assigned", func(t *testing.T) {
+		service, mockRepo := NewUserServiceWithMock()
+		mockRepo.On("UpdateFields", uint(3), map[string]any{"role": model.RoleEditor}).
+			Return(model.User{ID: 3, Role: model.RoleEditor}, nil)
+
+		user, err := service.UpdateRole(3, model.RoleEditor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.RoleEditor, user.Role)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("error - unknown role", func(t *testing.T) {
+		service, mockRepo := NewUserServiceWithMock()
+
+		_, err := service.UpdateRole(3, model.Role("superuser"))
+
+		assert.ErrorIs(t, err, appErrors.ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "UpdateFields", mock.Anything, mock.Anything)
+	})
+}
@@ -1,21 +1,88 @@
 package dto
 
-import "github.com/UliVargas/blog-go/internal/domain/model"
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+)
 
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
 }
 
+// LoginResponse contiene el token de acceso de corta duración (Token) y el token
+// de renovación con el que se obtiene uno nuevo en /auth/refresh
 type LoginResponse struct {
-	Message string `json:"message"`
-	Token   string `json:"token"`
+	Message      string `json:"message"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// TokenPair es el resultado de iniciar sesión o renovar los tokens
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// LoginResult es el resultado de iniciar sesión: el par de tokens o, si el
+// usuario tiene activada la verificación en dos pasos, el desafío que se canjea
+// por los tokens en /auth/2fa/verify
+type LoginResult struct {
+	Tokens    TokenPair
+	Challenge *TwoFactorChallenge
+}
+
+func NewLoginResponse(message string, tokens TokenPair) LoginResponse {
+	return LoginResponse{
+		Message:      message,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+	}
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// LogoutRequest permite revocar junto con el token de acceso el token de
+// renovación de la misma sesión
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ResendVerificationRequest solicita un nuevo enlace de verificación de email
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ForgotPasswordRequest solicita un enlace para restablecer la contraseña
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest establece una nueva contraseña con el token recibido
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=6,max=72"`
+}
+
+// ChangePasswordRequest reemplaza la contraseña confirmando la actual. La nueva
+// debe tener al menos 8 caracteres y ser distinta de la actual
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72,nefield=CurrentPassword"`
 }
 
 type RegisterRequest struct {
 	Name     string `json:"name" validate:"required,min=3,max=50"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required,min=6,max=72"`
 }
 
 func (r *RegisterRequest) ToUser() model.User {
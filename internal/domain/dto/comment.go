@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+)
+
+type CreateCommentRequest struct {
+	Body     string `json:"body" validate:"required,max=5000"`
+	ParentID *uint  `json:"parent_id" validate:"omitempty,min=1"`
+}
+
+func (r *CreateCommentRequest) ToComment(postID uint, authorID uint) model.Comment {
+	return model.Comment{
+		PostID:   postID,
+		ParentID: r.ParentID,
+		AuthorID: authorID,
+		Body:     r.Body,
+	}
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=5000"`
+}
+
+// CommentTreeQuery limita la profundidad del árbol de comentarios devuelto
+type CommentTreeQuery struct {
+	Depth int `form:"depth" validate:"omitempty,min=1,max=10"`
+}
+
+// ModerateCommentsRequest cambia el estado de varios comentarios a la vez
+type ModerateCommentsRequest struct {
+	IDs    []uint `json:"ids" validate:"required,min=1,max=100,dive,min=1"`
+	Status string `json:"status" validate:"required,oneof=approved rejected spam"`
+}
+
+type ModerateCommentsResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// CommentResponse es la vista de un comentario
+type CommentResponse struct {
+	ID        uint                `json:"id"`
+	PostID    uint                `json:"post_id"`
+	ParentID  *uint               `json:"parent_id"`
+	AuthorID  uint                `json:"author_id"`
+	Body      string              `json:"body"`
+	Status    model.CommentStatus `json:"status"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+func NewCommentResponse(comment model.Comment) CommentResponse {
+	return CommentResponse{
+		ID:        comment.ID,
+		PostID:    comment.PostID,
+		ParentID:  comment.ParentID,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		Status:    comment.Status,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
+
+// NewCommentResponses proyecta una lista de comentarios
+func NewCommentResponses(comments []model.Comment) []CommentResponse {
+	return mapAll(comments, NewCommentResponse)
+}
+
+// CommentNode es un comentario con sus respuestas anidadas. MoreReplies indica
+// que el comentario tiene respuestas por debajo del límite de profundidad
+type CommentNode struct {
+	CommentResponse
+	Replies     []CommentNode `json:"replies"`
+	MoreReplies bool          `json:"more_replies,omitempty"`
+}
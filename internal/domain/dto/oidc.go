@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// OIDCAuthorization es el inicio de sesión con un proveedor OpenID Connect: URL
+// es la página del proveedor a la que se redirige al usuario y State el valor
+// firmado que el navegador guarda en una cookie durante ExpiresIn, hasta volver
+type OIDCAuthorization struct {
+	URL       string
+	State     string
+	ExpiresIn time.Duration
+}
+
+// OIDCProvidersResponse lista los proveedores con los que se puede iniciar sesión
+type OIDCProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
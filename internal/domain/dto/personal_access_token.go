@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+)
+
+// CreateAccessTokenRequest crea un token de acceso personal. Sin ExpiresAt el
+// token no expira
+type CreateAccessTokenRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAccessTokenResponse incluye el token en claro, que solo se muestra una vez
+type CreatedAccessTokenResponse struct {
+	Message     string              `json:"message"`
+	Token       string              `json:"token"`
+	AccessToken AccessTokenResponse `json:"access_token"`
+}
+
+// AccessTokenResponse es la vista de un token de acceso personal, sin su hash
+type AccessTokenResponse struct {
+	ID         uint         `json:"id"`
+	UserID     uint         `json:"user_id"`
+	Name       string       `json:"name"`
+	Scopes     model.Scopes `json:"scopes"`
+	ExpiresAt  *time.Time   `json:"expires_at"`
+	LastUsedAt *time.Time   `json:"last_used_at"`
+	RevokedAt  *time.Time   `json:"revoked_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+func NewAccessTokenResponse(token model.PersonalAccessToken) AccessTokenResponse {
+	return AccessTokenResponse{
+		ID:         token.ID,
+		UserID:     token.UserID,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+// NewAccessTokenResponses proyecta una lista de tokens de acceso personal
+func NewAccessTokenResponses(tokens []model.PersonalAccessToken) []AccessTokenResponse {
+	return mapAll(tokens, NewAccessTokenResponse)
+}
@@ -0,0 +1,186 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/query"
+)
+
+type CreatePostRequest struct {
+	Title      string     `json:"title" validate:"required,min=3,max=200"`
+	Body       string     `json:"body" validate:"required,max=100000"`
+	Excerpt    string     `json:"excerpt" validate:"max=500"`
+	Status     string     `json:"status" validate:"omitempty,oneof=draft scheduled published archived"`
+	PublishAt  *time.Time `json:"publish_at"`
+	Tags       []string   `json:"tags" validate:"omitempty,max=20,dive,required,max=50"`
+	CategoryID *uint      `json:"category_id"`
+}
+
+func (r *CreatePostRequest) ToPost(authorID uint) model.Post {
+	return model.Post{
+		Title:      r.Title,
+		Body:       r.Body,
+		Excerpt:    r.Excerpt,
+		Status:     model.PostStatus(r.Status),
+		PublishAt:  r.PublishAt,
+		AuthorID:   authorID,
+		Tags:       tagsFromNames(r.Tags),
+		CategoryID: r.CategoryID,
+	}
+}
+
+// UpdatePostRequest reemplaza el contenido de la publicación. Si Tags es nil se
+// conservan las etiquetas actuales y una lista vacía las elimina; CategoryID nil
+// conserva la categoría actual y 0 la elimina
+type UpdatePostRequest struct {
+	Title      string     `json:"title" validate:"required,min=3,max=200"`
+	Slug       string     `json:"slug" validate:"omitempty,max=100"`
+	Body       string     `json:"body" validate:"required,max=100000"`
+	Excerpt    string     `json:"excerpt" validate:"max=500"`
+	Status     string     `json:"status" validate:"omitempty,oneof=draft scheduled published archived"`
+	PublishAt  *time.Time `json:"publish_at"`
+	Tags       []string   `json:"tags" validate:"omitempty,max=20,dive,required,max=50"`
+	CategoryID *uint      `json:"category_id"`
+}
+
+func (r *UpdatePostRequest) ToPost(id uint) model.Post {
+	return model.Post{
+		ID:         id,
+		Title:      r.Title,
+		Slug:       r.Slug,
+		Body:       r.Body,
+		Excerpt:    r.Excerpt,
+		Status:     model.PostStatus(r.Status),
+		PublishAt:  r.PublishAt,
+		Tags:       tagsFromNames(r.Tags),
+		CategoryID: r.CategoryID,
+	}
+}
+
+// tagsFromNames convierte los nombres recibidos en etiquetas sin resolver;
+// el servicio se encarga de buscarlas o crearlas
+func tagsFromNames(names []string) []model.Tag {
+	if names == nil {
+		return nil
+	}
+	tags := make([]model.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, model.Tag{Name: name})
+	}
+	return tags
+}
+
+type RevisionDiffQuery struct {
+	From int    `form:"from" validate:"required,min=1"`
+	To   int    `form:"to" validate:"required,min=1"`
+	Mode string `form:"mode" validate:"omitempty,oneof=unified word"`
+}
+
+// RevisionDiffResponse contiene las diferencias entre dos revisiones. En modo
+// "unified" Title y Body son textos en formato diff; en modo "word" son listas de cambios
+type RevisionDiffResponse struct {
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	Mode  string `json:"mode"`
+	Title any    `json:"title"`
+	Body  any    `json:"body"`
+}
+
+// Los handlers no serializan model.Post ni model.PostRevision directamente:
+// responden con estas proyecciones, que solo exponen campos explícitos aunque
+// el modelo cargue el autor u otras asociaciones
+
+// PostResponse es la vista de una publicación
+type PostResponse struct {
+	ID          uint             `json:"id"`
+	Title       string           `json:"title"`
+	Slug        string           `json:"slug"`
+	Body        string           `json:"body"`
+	BodyHTML    string           `json:"body_html"`
+	TOC         []model.TOCEntry `json:"toc"`
+	WordCount   int              `json:"word_count"`
+	ReadingTime int              `json:"reading_time_minutes"`
+	Excerpt     string           `json:"excerpt"`
+	Status      model.PostStatus `json:"status"`
+	PublishAt   *time.Time       `json:"publish_at,omitempty"`
+	PublishedAt *time.Time       `json:"published_at,omitempty"`
+	AuthorID    uint             `json:"author_id"`
+	CategoryID  *uint            `json:"category_id"`
+	Tags        []TagResponse    `json:"tags"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// PostRevisionResponse es la vista de una revisión de una publicación
+type PostRevisionResponse struct {
+	ID        uint      `json:"id"`
+	PostID    uint      `json:"post_id"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	EditorID  uint      `json:"editor_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewPostResponse(post model.Post) PostResponse {
+	return PostResponse{
+		ID:          post.ID,
+		Title:       post.Title,
+		Slug:        post.Slug,
+		Body:        post.Body,
+		BodyHTML:    post.BodyHTML,
+		TOC:         post.TOC,
+		WordCount:   post.WordCount,
+		ReadingTime: post.ReadingTime,
+		Excerpt:     post.Excerpt,
+		Status:      post.Status,
+		PublishAt:   post.PublishAt,
+		PublishedAt: post.PublishedAt,
+		AuthorID:    post.AuthorID,
+		CategoryID:  post.CategoryID,
+		Tags:        mapAll(post.Tags, NewTagResponse),
+		CreatedAt:   post.CreatedAt,
+		UpdatedAt:   post.UpdatedAt,
+	}
+}
+
+func NewPostRevisionResponse(revision model.PostRevision) PostRevisionResponse {
+	return PostRevisionResponse{
+		ID:        revision.ID,
+		PostID:    revision.PostID,
+		Number:    revision.Number,
+		Title:     revision.Title,
+		Body:      revision.Body,
+		EditorID:  revision.EditorID,
+		CreatedAt: revision.CreatedAt,
+	}
+}
+
+// NewPostResponses proyecta una lista de publicaciones
+func NewPostResponses(posts []model.Post) []PostResponse {
+	return mapAll(posts, NewPostResponse)
+}
+
+// NewPostPage proyecta una página de publicaciones
+func NewPostPage(page query.Page[model.Post]) query.Page[PostResponse] {
+	return query.MapPage(page, NewPostResponse)
+}
+
+// NewPostRevisionResponses proyecta el historial de revisiones
+func NewPostRevisionResponses(revisions []model.PostRevision) []PostRevisionResponse {
+	return mapAll(revisions, NewPostRevisionResponse)
+}
+
+// mapAll aplica fn a cada elemento. Una lista nil se mantiene nil para que la
+// respuesta conserve la misma forma que el modelo
+func mapAll[T, R any](items []T, fn func(T) R) []R {
+	if items == nil {
+		return nil
+	}
+	result := make([]R, 0, len(items))
+	for _, item := range items {
+		result = append(result, fn(item))
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "github.com/UliVargas/blog-go/pkg/query"
+
+// UserListSchema define los ordenamientos y filtros permitidos en el listado de usuarios
+var UserListSchema = query.Schema{
+	Sorts: map[string]query.Field{
+		"id":         {Column: "id", Kind: query.KindInt},
+		"name":       {Column: "name", Kind: query.KindString},
+		"created_at": {Column: "created_at", Kind: query.KindTime},
+	},
+	Filters: map[string]query.Filter{
+		"name":           {Column: "name", Kind: query.KindString, Operator: query.OpContains},
+		"created_after":  {Column: "created_at", Kind: query.KindTime, Operator: query.OpAfter},
+		"created_before": {Column: "created_at", Kind: query.KindTime, Operator: query.OpBefore},
+	},
+	DefaultSort: "created_at",
+}
+
+// PostListSchema define los ordenamientos y filtros permitidos en el listado de publicaciones
+var PostListSchema = query.Schema{
+	Sorts: map[string]query.Field{
+		"id":           {Column: "id", Kind: query.KindInt},
+		"title":        {Column: "title", Kind: query.KindString},
+		"published_at": {Column: "published_at", Kind: query.KindTime},
+	},
+	Filters: map[string]query.Filter{
+		"title":            {Column: "title", Kind: query.KindString, Operator: query.OpContains},
+		"author_id":        {Column: "author_id", Kind: query.KindInt, Operator: query.OpEqual},
+		"published_after":  {Column: "published_at", Kind: query.KindTime, Operator: query.OpAfter},
+		"published_before": {Column: "published_at", Kind: query.KindTime, Operator: query.OpBefore},
+	},
+	DefaultSort: "-published_at",
+}
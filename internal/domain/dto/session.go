@@ -0,0 +1,22 @@
+package dto
+
+import "github.com/UliVargas/blog-go/internal/domain/model"
+
+// ClientInfo identifica el dispositivo desde el que se inicia o renueva una sesión
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionResponse es una sesión activa del usuario. Current indica si es la
+// sesión desde la que se hace la petición
+type SessionResponse struct {
+	model.Session
+	Current bool `json:"current"`
+}
+
+// RevokeSessionsResponse indica cuántas sesiones se cerraron
+type RevokeSessionsResponse struct {
+	Message string `json:"message"`
+	Revoked int    `json:"revoked"`
+}
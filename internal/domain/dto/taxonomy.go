@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+)
+
+type RenameTagRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// MergeTagRequest indica la etiqueta en la que se fusiona la etiqueta de la URL
+type MergeTagRequest struct {
+	TargetID uint `json:"target_id" validate:"required,min=1"`
+}
+
+type TagAliasRequest struct {
+	Alias string `json:"alias" validate:"required,max=100"`
+}
+
+type CreateCategoryRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	ParentID *uint  `json:"parent_id" validate:"omitempty,min=1"`
+}
+
+func (r *CreateCategoryRequest) ToCategory() model.Category {
+	return model.Category{
+		Name:     r.Name,
+		ParentID: r.ParentID,
+	}
+}
+
+// TagResponse es la vista de una etiqueta
+type TagResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagCountResponse es una etiqueta de la nube con su número de publicaciones
+type TagCountResponse struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Count int64  `json:"count"`
+}
+
+// TagAliasResponse es la vista de un slug alternativo de una etiqueta
+type TagAliasResponse struct {
+	ID        uint      `json:"id"`
+	TagID     uint      `json:"tag_id"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CategoryResponse es la vista de una categoría
+type CategoryResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	ParentID  *uint     `json:"parent_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewTagResponse(tag model.Tag) TagResponse {
+	return TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Slug:      tag.Slug,
+		CreatedAt: tag.CreatedAt,
+	}
+}
+
+func NewTagCountResponse(tag model.TagCount) TagCountResponse {
+	return TagCountResponse{
+		ID:    tag.ID,
+		Name:  tag.Name,
+		Slug:  tag.Slug,
+		Count: tag.Count,
+	}
+}
+
+// NewTagCountResponses proyecta la nube de etiquetas
+func NewTagCountResponses(cloud []model.TagCount) []TagCountResponse {
+	return mapAll(cloud, NewTagCountResponse)
+}
+
+func NewTagAliasResponse(alias model.TagAlias) TagAliasResponse {
+	return TagAliasResponse{
+		ID:        alias.ID,
+		TagID:     alias.TagID,
+		Slug:      alias.Slug,
+		CreatedAt: alias.CreatedAt,
+	}
+}
+
+func NewCategoryResponse(category model.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:        category.ID,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		ParentID:  category.ParentID,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+	}
+}
+
+// CategoryNode es una categoría con sus subcategorías
+type CategoryNode struct {
+	CategoryResponse
+	Children []CategoryNode `json:"children"`
+}
@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+// TwoFactorChallenge es el token de corta duración que se entrega tras validar la
+// contraseña de un usuario con verificación en dos pasos
+type TwoFactorChallenge struct {
+	Token     string
+	ExpiresIn time.Duration
+}
+
+// TwoFactorChallengeResponse pide al cliente el código de verificación, que se
+// envía junto con ChallengeToken a /auth/2fa/verify
+type TwoFactorChallengeResponse struct {
+	Message           string `json:"message"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int64  `json:"expires_in"`
+}
+
+func NewTwoFactorChallengeResponse(challenge TwoFactorChallenge) TwoFactorChallengeResponse {
+	return TwoFactorChallengeResponse{
+		Message:           "Introduce el código de verificación en dos pasos",
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge.Token,
+		ExpiresIn:         int64(challenge.ExpiresIn.Seconds()),
+	}
+}
+
+// VerifyTwoFactorRequest completa el inicio de sesión con un código TOTP o un
+// código de recuperación
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+// TwoFactorSetup es el secreto generado al iniciar la activación, junto con el
+// enlace otpauth:// para importarlo en una aplicación de autenticación
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// ConfirmTwoFactorRequest activa la verificación en dos pasos con un código
+// generado a partir del nuevo secreto
+type ConfirmTwoFactorRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// RecoveryCodesResponse contiene los códigos de recuperación, que solo se
+// muestran una vez
+type RecoveryCodesResponse struct {
+	Message       string   `json:"message"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// DisableTwoFactorRequest desactiva la verificación en dos pasos confirmando la
+// contraseña y un código TOTP o de recuperación
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/query"
+)
+
+// Los handlers nunca serializan model.User directamente: según quién consulte
+// se responde con una de estas proyecciones, ninguna de las cuales incluye la
+// contraseña
+
+// PublicUserResponse es el perfil visible para cualquier usuario
+type PublicUserResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+	Bio       string `json:"bio"`
+	Website   string `json:"website"`
+}
+
+// UserSettings agrupa las preferencias del usuario
+type UserSettings struct {
+	Locale string `json:"locale"`
+}
+
+// SelfUserResponse es la vista del propio usuario: añade el email, el rol y sus preferencias
+type SelfUserResponse struct {
+	PublicUserResponse
+	Email           string       `json:"email"`
+	EmailVerifiedAt *time.Time   `json:"email_verified_at"`
+	Role            model.Role   `json:"role"`
+	Settings        UserSettings `json:"settings"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
+
+// AdminUserResponse es la vista de administración con los datos de auditoría
+type AdminUserResponse struct {
+	SelfUserResponse
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewPublicUserResponse(user model.User) PublicUserResponse {
+	return PublicUserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		AvatarURL: user.AvatarURL,
+		Bio:       user.Bio,
+		Website:   user.Website,
+	}
+}
+
+func NewSelfUserResponse(user model.User) SelfUserResponse {
+	return SelfUserResponse{
+		PublicUserResponse: NewPublicUserResponse(user),
+		Email:              user.Email,
+		EmailVerifiedAt:    user.EmailVerifiedAt,
+		Role:               user.Role,
+		Settings:           UserSettings{Locale: user.Locale},
+		CreatedAt:          user.CreatedAt,
+	}
+}
+
+func NewAdminUserResponse(user model.User) AdminUserResponse {
+	return AdminUserResponse{
+		SelfUserResponse: NewSelfUserResponse(user),
+		UpdatedAt:        user.UpdatedAt,
+	}
+}
+
+// UpdateProfileRequest actualiza parcialmente el perfil: solo se modifican los
+// campos presentes en la petición. Bio, Website y AvatarURL se vacían enviando ""
+type UpdateProfileRequest struct {
+	Name      *string `json:"name" validate:"omitempty,min=3,max=50"`
+	Email     *string `json:"email" validate:"omitempty,email"`
+	Bio       *string `json:"bio" validate:"omitempty,max=500"`
+	Website   *string `json:"website" validate:"omitempty,max=200,eq=|url"`
+	AvatarURL *string `json:"avatar_url" validate:"omitempty,max=500,eq=|url"`
+}
+
+// DeleteAccountRequest confirma la eliminación de la cuenta con la contraseña actual
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// UpdateRoleRequest asigna un rol a un usuario
+type UpdateRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin editor author reader"`
+}
+
+// NewPublicUserPage proyecta una página de usuarios a perfiles públicos
+func NewPublicUserPage(page query.Page[model.User]) query.Page[PublicUserResponse] {
+	return query.MapPage(page, NewPublicUserResponse)
+}
+
+// NewAdminUserPage proyecta una página de usuarios a la vista de administración
+func NewAdminUserPage(page query.Page[model.User]) query.Page[AdminUserResponse] {
+	return query.MapPage(page, NewAdminUserResponse)
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// CommentStatus representa el estado de moderación de un comentario
+type CommentStatus string
+
+const (
+	CommentStatusPending  CommentStatus = "pending"
+	CommentStatusApproved CommentStatus = "approved"
+	CommentStatusRejected CommentStatus = "rejected"
+	CommentStatusSpam     CommentStatus = "spam"
+)
+
+// Comment es un comentario de una publicación; ParentID apunta al comentario
+// al que responde, o es nil si es un comentario de primer nivel
+type Comment struct {
+	ID        uint          `gorm:"primaryKey" json:"id"`
+	PostID    uint          `gorm:"not null;index" json:"post_id"`
+	Post      Post          `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"-"`
+	ParentID  *uint         `gorm:"index" json:"parent_id"`
+	Parent    *Comment      `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE" json:"-"`
+	AuthorID  uint          `gorm:"not null;index" json:"author_id"`
+	Author    User          `gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE" json:"-"`
+	Body      string        `gorm:"type:text;not null" json:"body"`
+	Status    CommentStatus `gorm:"size:20;not null;default:pending;index" json:"status"`
+	CreatedAt time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// IsApproved indica si el comentario es visible públicamente
+func (c Comment) IsApproved() bool {
+	return c.Status == CommentStatusApproved
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Identity vincula un usuario con su cuenta en un proveedor OpenID Connect. El
+// par Provider y Subject identifica la cuenta externa; Email es el que tenía al
+// vincularla y solo es informativo
+type Identity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Provider  string    `gorm:"size:50;not null;uniqueIndex:idx_identities_provider_subject" json:"provider"`
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_identities_provider_subject" json:"-"`
+	Email     string    `gorm:"size:320" json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// LoginAttempt cuenta los inicios de sesión fallidos consecutivos de una clave,
+// que identifica un email o una IP. Mientras LockedUntil no haya pasado se
+// rechazan los intentos de esa clave sin comprobar la contraseña
+type LoginAttempt struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Key          string     `gorm:"size:320;not null;uniqueIndex" json:"key"`
+	Failures     int        `gorm:"not null" json:"failures"`
+	LastFailedAt time.Time  `gorm:"not null;index" json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"`
+}
+
+// RetryAfter devuelve cuánto falta para que la clave admita un nuevo intento, o
+// cero si ya lo admite
+func (a LoginAttempt) RetryAfter(now time.Time) time.Duration {
+	if a.LockedUntil == nil || !now.Before(*a.LockedUntil) {
+		return 0
+	}
+	return a.LockedUntil.Sub(now)
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AccessTokenPrefix encabeza los tokens de acceso personal, para distinguirlos
+// de los JWT y para que los escáneres de secretos los reconozcan
+const AccessTokenPrefix = "blog_pat_"
+
+// Scope limita lo que puede hacer un token de acceso personal, con el formato
+// recurso:acción. Los scopes se suman a los permisos del rol: un token con
+// posts:write de un lector sigue sin poder publicar
+type Scope string
+
+const (
+	ScopePostsRead        Scope = "posts:read"
+	ScopePostsWrite       Scope = "posts:write"
+	ScopeCommentsWrite    Scope = "comments:write"
+	ScopeCommentsModerate Scope = "comments:moderate"
+	ScopeTaxonomyWrite    Scope = "taxonomy:write"
+	ScopeUsersRead        Scope = "users:read"
+	ScopeUsersWrite       Scope = "users:write"
+)
+
+var scopes = map[Scope]bool{
+	ScopePostsRead:        true,
+	ScopePostsWrite:       true,
+	ScopeCommentsWrite:    true,
+	ScopeCommentsModerate: true,
+	ScopeTaxonomyWrite:    true,
+	ScopeUsersRead:        true,
+	ScopeUsersWrite:       true,
+}
+
+// IsValid indica si el scope existe
+func (s Scope) IsValid() bool {
+	return scopes[s]
+}
+
+// Scopes es la lista de scopes de un token. Se guarda como texto separado por espacios
+type Scopes []Scope
+
+// Has indica si la lista incluye el scope
+func (s Scopes) Has(scope Scope) bool {
+	for _, granted := range s {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Scopes) Value() (driver.Value, error) {
+	values := make([]string, len(s))
+	for i, scope := range s {
+		values[i] = string(scope)
+	}
+	return strings.Join(values, " "), nil
+}
+
+func (s *Scopes) Scan(value any) error {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("scopes: tipo no admitido %T", value)
+	}
+
+	*s = Scopes{}
+	for _, scope := range strings.Fields(text) {
+		*s = append(*s, Scope(scope))
+	}
+	return nil
+}
+
+// PersonalAccessToken es un token de larga duración para scripts y tareas de CI.
+// Solo se muestra al crearlo; después únicamente se guarda su hash. Sin
+// ExpiresAt no expira, pero se puede revocar en cualquier momento
+type PersonalAccessToken struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	User       User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes     Scopes     `gorm:"type:text;not null" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive indica si el token todavía puede usarse
+func (t PersonalAccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// PostStatus representa el estado del ciclo de vida de una publicación
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusScheduled PostStatus = "scheduled"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+type Post struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Title       string     `gorm:"not null" json:"title"`
+	Slug        string     `gorm:"size:255;uniqueIndex" json:"slug"`
+	Body        string     `gorm:"type:text;not null" json:"body"`
+	BodyHTML    string     `gorm:"type:text" json:"body_html"`
+	TOC         []TOCEntry `gorm:"type:text;serializer:json" json:"toc"`
+	WordCount   int        `gorm:"not null;default:0" json:"word_count"`
+	ReadingTime int        `gorm:"not null;default:0" json:"reading_time_minutes"`
+	Excerpt     string     `gorm:"size:500" json:"excerpt"`
+	Status      PostStatus `gorm:"size:20;not null;default:draft;index" json:"status"`
+	PublishAt   *time.Time `gorm:"index" json:"publish_at,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	AuthorID    uint       `gorm:"not null;index" json:"author_id"`
+	Author      User       `gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE" json:"-"`
+	CategoryID  *uint      `gorm:"index" json:"category_id"`
+	Category    *Category  `gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL" json:"-"`
+	Tags        []Tag      `gorm:"many2many:post_tags;constraint:OnDelete:CASCADE" json:"tags"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TOCEntry es una entrada de la tabla de contenidos generada a partir
+// de los encabezados del cuerpo en Markdown
+type TOCEntry struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+// IsPublished indica si la publicación es visible públicamente
+func (p Post) IsPublished() bool {
+	return p.Status == PostStatusPublished
+}
+
+// PostSlug guarda los slugs anteriores de una publicación para redirigir
+// las URLs antiguas al slug actual
+type PostSlug struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PostID    uint      `gorm:"not null;index" json:"post_id"`
+	Post      Post      `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"-"`
+	Slug      string    `gorm:"size:255;not null;uniqueIndex" json:"slug"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// PostRevision es una copia inmutable del contenido de una publicación
+// que se guarda con cada creación, edición o restauración
+type PostRevision struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PostID    uint      `gorm:"not null;uniqueIndex:idx_post_revision_number" json:"post_id"`
+	Post      Post      `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"-"`
+	Number    int       `gorm:"not null;uniqueIndex:idx_post_revision_number" json:"number"`
+	Title     string    `gorm:"not null" json:"title"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	EditorID  uint      `gorm:"not null;index" json:"editor_id"`
+	Editor    User      `gorm:"foreignKey:EditorID" json:"-"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
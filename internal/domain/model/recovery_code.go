@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// RecoveryCode es un código de un solo uso que sustituye al código TOTP cuando
+// el usuario no tiene acceso a su aplicación de autenticación. Como los demás
+// tokens, solo se guarda su hash
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	User      User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
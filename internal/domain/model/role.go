@@ -0,0 +1,68 @@
+package model
+
+// Role es el rol de un usuario, que determina los permisos que tiene
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleAuthor Role = "author"
+	RoleReader Role = "reader"
+)
+
+// DefaultRole es el rol con el que se registran los usuarios
+const DefaultRole = RoleAuthor
+
+// Permission es una acción que un rol puede tener permitida, con el formato
+// recurso:acción
+type Permission string
+
+const (
+	PermPostsPublish     Permission = "posts:publish"
+	PermPostsEditAny     Permission = "posts:edit_any"
+	PermCommentsCreate   Permission = "comments:create"
+	PermCommentsModerate Permission = "comments:moderate"
+	PermTaxonomyManage   Permission = "taxonomy:manage"
+	PermUsersManage      Permission = "users:manage"
+)
+
+// rolePermissions es la tabla de políticas: los permisos de cada rol. Es la
+// única fuente de verdad sobre qué puede hacer cada rol
+var rolePermissions = map[Role][]Permission{
+	RoleReader: {PermCommentsCreate},
+	RoleAuthor: {PermCommentsCreate, PermPostsPublish},
+	RoleEditor: {PermCommentsCreate, PermCommentsModerate, PermPostsPublish, PermPostsEditAny, PermTaxonomyManage},
+	RoleAdmin:  {PermCommentsCreate, PermCommentsModerate, PermPostsPublish, PermPostsEditAny, PermTaxonomyManage, PermUsersManage},
+}
+
+// IsValid indica si el rol está definido en la tabla de políticas
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can indica si el rol tiene el permiso. Un rol desconocido no tiene ninguno
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Principal es el usuario autenticado que solicita una acción
+type Principal struct {
+	UserID uint
+	Role   Role
+}
+
+// Action es una operación sobre un recurso concreto que requiere autorización
+type Action string
+
+const (
+	ActionPostEdit      Action = "post:edit"
+	ActionPostDelete    Action = "post:delete"
+	ActionCommentEdit   Action = "comment:edit"
+	ActionCommentDelete Action = "comment:delete"
+)
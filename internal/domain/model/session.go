@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Session es un inicio de sesión en un dispositivo. Agrupa la familia de tokens
+// de renovación abierta al iniciar sesión y los tokens de acceso emitidos con
+// ella, que la identifican con el claim sid. Revocar la sesión invalida ambos.
+// LastSeenAt se actualiza al iniciar sesión y al renovar los tokens
+type Session struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"-"`
+	User       User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	FamilyID   string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	UserAgent  string     `gorm:"size:512" json:"user_agent"`
+	IP         string     `gorm:"size:45" json:"ip"`
+	LastSeenAt time.Time  `gorm:"not null" json:"last_seen_at"`
+	RevokedAt  *time.Time `gorm:"index" json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// SigningKey es una clave asimétrica con la que se firman los tokens de acceso,
+// identificada en los tokens por su kid. La clave privada se guarda cifrada con
+// JWTSECRET. La clave activa es la más reciente sin RetiresAt; al rotar, las
+// anteriores siguen verificando tokens hasta RetiresAt y después se eliminan
+type SigningKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	KID        string     `gorm:"column:kid;size:64;not null;uniqueIndex" json:"kid"`
+	Algorithm  string     `gorm:"size:10;not null" json:"algorithm"`
+	PrivateKey []byte     `gorm:"not null" json:"-"`
+	RetiresAt  *time.Time `gorm:"index" json:"retires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
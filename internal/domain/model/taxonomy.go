@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Tag es una etiqueta libre que se asigna a las publicaciones
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:50;not null" json:"name"`
+	Slug      string    `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TagAlias es un slug alternativo que resuelve a una etiqueta. Se crean al
+// renombrar o fusionar etiquetas para que las URLs antiguas sigan funcionando
+type TagAlias struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TagID     uint      `gorm:"not null;index" json:"tag_id"`
+	Tag       Tag       `gorm:"foreignKey:TagID;constraint:OnDelete:CASCADE" json:"-"`
+	Slug      string    `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TagCount es una etiqueta junto con el número de publicaciones publicadas que la usan
+type TagCount struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Count int64  `json:"count"`
+}
+
+// Category es un nodo del árbol de categorías; ParentID es nil en las categorías raíz
+type Category struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	Slug      string    `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	ParentID  *uint     `gorm:"index" json:"parent_id"`
+	Parent    *Category `gorm:"foreignKey:ParentID;constraint:OnDelete:RESTRICT" json:"-"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
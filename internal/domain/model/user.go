@@ -2,11 +2,38 @@ package model
 
 import "time"
 
+// DefaultLocale es el idioma con el que se crean los usuarios
+const DefaultLocale = "es"
+
+// User es la cuenta de un usuario. EmailVerifiedAt es nil mientras el email
+// actual no se haya verificado, incluido tras cambiarlo. VerificationSentAt es
+// el momento en que se envió el último enlace de verificación: solo ese enlace
+// es válido, por lo que cada reenvío invalida los anteriores. PasswordChangedAt
+// es el último cambio de contraseña: los tokens emitidos antes dejan de ser válidos.
+// TOTPSecret guarda cifrado el secreto de la verificación en dos pasos, que solo
+// está activa una vez confirmada (TOTPEnabledAt); TOTPLastStep es el paso del último
+// código aceptado, para que cada código se pueda usar una sola vez
 type User struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Name      string    `gorm:"not null" json:"name"`
-	Email     string    `gorm:"not null;unique" json:"email"`
-	Password  string    `gorm:"not null" json:"password"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                 uint       `gorm:"primaryKey" json:"id"`
+	Name               string     `gorm:"not null" json:"name"`
+	Email              string     `gorm:"not null;unique" json:"email"`
+	EmailVerifiedAt    *time.Time `json:"email_verified_at"`
+	VerificationSentAt *time.Time `json:"-"`
+	Password           string     `gorm:"not null" json:"-"`
+	PasswordChangedAt  *time.Time `json:"-"`
+	TOTPSecret         string     `gorm:"column:totp_secret;size:255" json:"-"`
+	TOTPEnabledAt      *time.Time `gorm:"column:totp_enabled_at" json:"-"`
+	TOTPLastStep       int64      `gorm:"column:totp_last_step;not null;default:0" json:"-"`
+	Role               Role       `gorm:"size:20;not null;default:author" json:"role"`
+	Bio                string     `gorm:"type:text" json:"bio"`
+	Website            string     `gorm:"size:200" json:"website"`
+	AvatarURL          string     `gorm:"size:500" json:"avatar_url"`
+	Locale             string     `gorm:"size:5;not null;default:es" json:"locale"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TwoFactorEnabled indica si el usuario tiene activada la verificación en dos pasos
+func (u User) TwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil
 }
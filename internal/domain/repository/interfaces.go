@@ -1,15 +1,170 @@
 package repository
 
-import "github.com/UliVargas/blog-go/internal/domain/model"
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/query"
+)
 
 // UserRepositoryInterface define el contrato para las operaciones del repositorio de usuarios
 // Esta interfaz pertenece a la capa de dominio ya que define el contrato
 // que el dominio espera de la capa de infraestructura
 type UserRepositoryInterface interface {
-	GetAll() ([]model.User, error)
+	GetAll(spec query.Spec) (query.Page[model.User], error)
 	GetByID(id uint) (model.User, error)
 	GetByEmail(email string) (model.User, error)
-	Create(user model.User) error
+	Create(user model.User) (model.User, error)
 	Update(user model.User) (model.User, error)
+	UpdateFields(id uint, fields map[string]any) (model.User, error)
+	AdvanceTOTPStep(id uint, step int64) (bool, error)
+	ReplacePasswordHash(id uint, current, replacement string) (bool, error)
 	Delete(id uint) error
-}
\ No newline at end of file
+}
+
+// RefreshTokenRepositoryInterface define el contrato para los tokens de renovación
+type RefreshTokenRepositoryInterface interface {
+	Create(token model.RefreshToken) (model.RefreshToken, error)
+	GetByHash(hash string) (model.RefreshToken, error)
+	MarkUsed(id uint, usedAt time.Time) (bool, error)
+	RevokeFamily(familyID string, revokedAt time.Time) error
+	RevokeByUser(userID uint, revokedAt time.Time) error
+}
+
+// PasswordResetTokenRepositoryInterface define el contrato para los tokens de
+// restablecimiento de contraseña
+type PasswordResetTokenRepositoryInterface interface {
+	Create(token model.PasswordResetToken) (model.PasswordResetToken, error)
+	GetByHash(hash string) (model.PasswordResetToken, error)
+	MarkUsed(id uint, usedAt time.Time) (bool, error)
+	InvalidateByUser(userID uint, usedAt time.Time) error
+	ExistsCreatedSince(userID uint, since time.Time) (bool, error)
+}
+
+// RecoveryCodeRepositoryInterface define el contrato para los códigos de
+// recuperación de la verificación en dos pasos
+type RecoveryCodeRepositoryInterface interface {
+	Replace(userID uint, codes []model.RecoveryCode) error
+	Use(userID uint, hash string, usedAt time.Time) (bool, error)
+	DeleteByUser(userID uint) error
+}
+
+// IdentityRepositoryInterface define el contrato para las cuentas de proveedores
+// OpenID Connect vinculadas a los usuarios. CreateWithUser crea el usuario y su
+// identidad en una misma transacción
+type IdentityRepositoryInterface interface {
+	GetByProviderSubject(provider, subject string) (model.Identity, error)
+	Create(identity model.Identity) (model.Identity, error)
+	CreateWithUser(user model.User, identity model.Identity) (model.Identity, error)
+}
+
+// PersonalAccessTokenRepositoryInterface define el contrato para los tokens de
+// acceso personal. GetByHash devuelve el token con su usuario
+type PersonalAccessTokenRepositoryInterface interface {
+	Create(token model.PersonalAccessToken) (model.PersonalAccessToken, error)
+	GetByHash(hash string) (model.PersonalAccessToken, error)
+	GetByUser(userID uint) ([]model.PersonalAccessToken, error)
+	Revoke(id, userID uint, revokedAt time.Time) (bool, error)
+	TouchLastUsed(id uint, usedAt time.Time, precision time.Duration) error
+}
+
+// RevokedTokenRepositoryInterface define el contrato para los tokens de acceso revocados
+type RevokedTokenRepositoryInterface interface {
+	Create(token model.RevokedToken) error
+	IsRevoked(jti string) (bool, error)
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// SessionRepositoryInterface define el contrato para las sesiones de los
+// usuarios. GetActiveByUser devuelve, de la más reciente a la más antigua, las
+// sesiones sin revocar que conservan un token de renovación vigente. Revoke y
+// RevokeByUser revocan también las familias de tokens de renovación de las
+// sesiones y RevokeByUser devuelve los IDs de las sesiones que revocó
+type SessionRepositoryInterface interface {
+	Create(session model.Session) (model.Session, error)
+	GetByFamily(familyID string) (model.Session, error)
+	GetActiveByUser(userID uint, now time.Time) ([]model.Session, error)
+	TouchLastSeen(id uint, ip, userAgent string, seenAt time.Time) error
+	Revoke(id, userID uint, revokedAt time.Time) (bool, error)
+	RevokeByUser(userID, exceptID uint, revokedAt time.Time) ([]uint, error)
+	IsRevoked(id uint) (bool, error)
+	DeleteInactive(lastSeenBefore, revokedBefore time.Time) (int64, error)
+}
+
+// SigningKeyRepositoryInterface define el contrato para las claves de firma de
+// los tokens de acceso. GetValid devuelve, de la más antigua a la más reciente,
+// las claves que todavía no se han retirado o cuyo retiro es posterior a now
+type SigningKeyRepositoryInterface interface {
+	Create(key model.SigningKey) (model.SigningKey, error)
+	GetValid(now time.Time) ([]model.SigningKey, error)
+	Retire(ids []uint, retiresAt time.Time) error
+	DeleteRetired(now time.Time) (int64, error)
+}
+
+// LoginAttemptRepositoryInterface define el contrato para el contador de inicios
+// de sesión fallidos. Get devuelve un registro vacío si la clave no tiene fallos y
+// RecordFailure reinicia la cuenta si el último fallo es anterior a window
+type LoginAttemptRepositoryInterface interface {
+	Get(key string) (model.LoginAttempt, error)
+	RecordFailure(key string, now time.Time, window time.Duration) (model.LoginAttempt, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+	DeleteExpired(now time.Time, window time.Duration) (int64, error)
+}
+
+// PostRepositoryInterface define el contrato para las operaciones del repositorio de publicaciones
+type PostRepositoryInterface interface {
+	GetPublished(spec query.Spec) (query.Page[model.Post], error)
+	GetPublishedByTag(tagID uint, spec query.Spec) (query.Page[model.Post], error)
+	GetPublishedByCategories(categoryIDs []uint, spec query.Spec) (query.Page[model.Post], error)
+	GetByAuthor(authorID uint) ([]model.Post, error)
+	GetByID(id uint) (model.Post, error)
+	GetBySlug(slug string) (model.Post, error)
+	GetByPreviousSlug(slug string) (model.Post, error)
+	SlugExists(slug string, excludePostID uint) (bool, error)
+	Create(post model.Post) (model.Post, error)
+	Update(post model.Post) (model.Post, error)
+	Delete(id uint) error
+	PublishDue(now time.Time) (int64, error)
+}
+
+// PostRevisionRepositoryInterface define el contrato para el historial de revisiones.
+// Las revisiones son inmutables, por lo que no se exponen operaciones de edición
+type PostRevisionRepositoryInterface interface {
+	GetByPost(postID uint) ([]model.PostRevision, error)
+	GetByNumber(postID uint, number int) (model.PostRevision, error)
+	Create(revision model.PostRevision) (model.PostRevision, error)
+}
+
+// CommentRepositoryInterface define el contrato para las operaciones del repositorio de comentarios
+type CommentRepositoryInterface interface {
+	GetByID(id uint) (model.Comment, error)
+	GetByPost(postID uint, status model.CommentStatus) ([]model.Comment, error)
+	GetPending() ([]model.Comment, error)
+	CountApprovedByAuthor(authorID uint) (int64, error)
+	Create(comment model.Comment) (model.Comment, error)
+	Update(comment model.Comment) (model.Comment, error)
+	Delete(id uint) error
+	UpdateStatus(ids []uint, status model.CommentStatus) (int64, error)
+}
+
+// TagRepositoryInterface define el contrato para las operaciones del repositorio de etiquetas
+type TagRepositoryInterface interface {
+	GetByID(id uint) (model.Tag, error)
+	GetBySlug(slug string) (model.Tag, error)
+	GetCloud() ([]model.TagCount, error)
+	SlugInUse(slug string, excludeTagID uint) (bool, error)
+	FindOrCreate(tags []model.Tag) ([]model.Tag, error)
+	Rename(tag model.Tag) (model.Tag, error)
+	Merge(sourceID uint, targetID uint) error
+	CreateAlias(alias model.TagAlias) (model.TagAlias, error)
+}
+
+// CategoryRepositoryInterface define el contrato para las operaciones del repositorio de categorías
+type CategoryRepositoryInterface interface {
+	GetAll() ([]model.Category, error)
+	GetByID(id uint) (model.Category, error)
+	GetBySlug(slug string) (model.Category, error)
+	GetDescendantIDs(id uint) ([]uint, error)
+	Create(category model.Category) (model.Category, error)
+}
@@ -1,21 +1,176 @@
 package service
 
-import "github.com/UliVargas/blog-go/internal/domain/model"
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/jwtkeys"
+	"github.com/UliVargas/blog-go/pkg/query"
+)
 
 // UserServiceInterface define el contrato para las operaciones del servicio de usuarios
 // Esta interfaz pertenece a la capa de dominio ya que define el contrato
 // que el dominio espera de la capa de aplicación
 type UserServiceInterface interface {
-	GetAll() ([]model.User, error)
+	GetAll(spec query.Spec) (query.Page[model.User], error)
 	GetByID(id uint) (model.User, error)
 	Update(user model.User) (model.User, error)
 	Delete(id uint) error
+	UpdateProfile(id uint, req dto.UpdateProfileRequest) (model.User, error)
+	DeleteAccount(id uint, password string) error
+	UpdateRole(id uint, role model.Role) (model.User, error)
 }
 
 // AuthServiceInterface define el contrato para las operaciones del servicio de autenticación
 // Esta interfaz pertenece a la capa de dominio ya que define el contrato
 // que el dominio espera de la capa de aplicación
 type AuthServiceInterface interface {
-	Login(email, password string) (string, error)
+	Login(email, password string, client dto.ClientInfo) (dto.LoginResult, error)
+	VerifyTwoFactor(challengeToken, code string, client dto.ClientInfo) (dto.TokenPair, error)
+	Refresh(refreshToken string, client dto.ClientInfo) (dto.TokenPair, error)
+	Logout(userID, sessionID uint, jti string, expiresAt time.Time, refreshToken string) error
+	ChangePassword(userID uint, currentPassword, newPassword string, client dto.ClientInfo) (dto.TokenPair, error)
 	Register(user model.User) error
-}
\ No newline at end of file
+}
+
+// SessionIssuerInterface define el contrato para abrir la sesión de un usuario
+// autenticado por otros medios, como un proveedor OpenID Connect
+type SessionIssuerInterface interface {
+	StartSession(user model.User, client dto.ClientInfo) (dto.LoginResult, error)
+}
+
+// OIDCServiceInterface define el contrato para iniciar sesión con proveedores
+// OpenID Connect
+type OIDCServiceInterface interface {
+	Providers() []string
+	Begin(provider string) (dto.OIDCAuthorization, error)
+	Callback(provider, code, state, stateCookie string, client dto.ClientInfo) (dto.LoginResult, error)
+}
+
+// SessionServiceInterface define el contrato para las sesiones abiertas de un
+// usuario. currentID es la sesión desde la que se hace la petición
+type SessionServiceInterface interface {
+	List(userID, currentID uint) ([]dto.SessionResponse, error)
+	Revoke(userID, id uint) error
+	RevokeOthers(userID, currentID uint) (int, error)
+}
+
+// PasswordHasherInterface define el contrato para los hashes de las
+// contraseñas. Verify devuelve un error si la contraseña no coincide y
+// NeedsRehash indica si el hash usa otro algoritmo o parámetros anteriores a los
+// configurados
+type PasswordHasherInterface interface {
+	Hash(password string) (string, error)
+	Verify(encoded, password string) error
+	NeedsRehash(encoded string) bool
+}
+
+// AccessTokenSignerInterface define el contrato para firmar los tokens de acceso
+type AccessTokenSignerInterface interface {
+	Sign(claims map[string]any) (string, error)
+}
+
+// SigningKeyServiceInterface define el contrato para las claves de firma de los
+// tokens de acceso. Rotate genera una clave nueva cuando corresponde y JWKS
+// devuelve las claves públicas vigentes
+type SigningKeyServiceInterface interface {
+	AccessTokenSignerInterface
+	Rotate(now time.Time) (bool, error)
+	JWKS() jwtkeys.JWKS
+}
+
+// LoginThrottleInterface define el contrato para limitar los intentos de inicio
+// de sesión por email y por IP
+type LoginThrottleInterface interface {
+	Check(email, ip string, now time.Time) error
+	Fail(email, ip string, now time.Time) error
+	Succeed(email string) error
+	Unlock(userID uint) error
+}
+
+// TwoFactorServiceInterface define el contrato para la verificación en dos pasos
+type TwoFactorServiceInterface interface {
+	Setup(userID uint) (dto.TwoFactorSetup, error)
+	Confirm(userID uint, code string) ([]string, error)
+	Disable(userID uint, password, code string) error
+	Authenticate(user model.User, code string) error
+}
+
+// PersonalAccessTokenServiceInterface define el contrato para los tokens de
+// acceso personal. Authenticate valida un token y lo devuelve con su usuario
+type PersonalAccessTokenServiceInterface interface {
+	Create(userID uint, req dto.CreateAccessTokenRequest) (model.PersonalAccessToken, string, error)
+	List(userID uint) ([]model.PersonalAccessToken, error)
+	Revoke(userID, id uint) error
+	Authenticate(token string) (model.PersonalAccessToken, error)
+}
+
+// EmailVerificationServiceInterface define el contrato para la verificación del
+// email de los usuarios
+type EmailVerificationServiceInterface interface {
+	Send(user model.User) error
+	Verify(token string) error
+	Resend(email string) error
+}
+
+// PasswordResetServiceInterface define el contrato para restablecer la
+// contraseña de un usuario que no puede iniciar sesión
+type PasswordResetServiceInterface interface {
+	Forgot(email string) error
+	Reset(token, password string) error
+}
+
+// AccountNotifierInterface define el contrato para los avisos que se envían al
+// usuario sobre su cuenta. Lo implementa la capa de infraestructura
+type AccountNotifierInterface interface {
+	SendVerification(user model.User, token string) error
+	SendPasswordReset(user model.User, token string) error
+}
+
+// PostServiceInterface define el contrato para las operaciones del servicio de publicaciones
+type PostServiceInterface interface {
+	GetPublished(spec query.Spec) (query.Page[model.Post], error)
+	GetPublishedByTag(slug string, spec query.Spec) (query.Page[model.Post], error)
+	GetPublishedByCategory(slug string, spec query.Spec) (query.Page[model.Post], error)
+	GetByAuthor(authorID uint) ([]model.Post, error)
+	GetByID(id uint, viewerID uint) (model.Post, error)
+	GetBySlug(slug string, viewerID uint) (model.Post, bool, error)
+	Create(post model.Post) (model.Post, error)
+	Update(post model.Post, principal model.Principal) (model.Post, error)
+	Delete(id uint, principal model.Principal) error
+	GetRevisions(postID uint, principal model.Principal) ([]model.PostRevision, error)
+	DiffRevisions(postID uint, from, to int, mode string, principal model.Principal) (dto.RevisionDiffResponse, error)
+	RestoreRevision(postID uint, number int, principal model.Principal) (model.Post, error)
+}
+
+// CommentServiceInterface define el contrato para las operaciones del servicio de comentarios
+type CommentServiceInterface interface {
+	GetTree(postID uint, viewerID uint, depth int) ([]dto.CommentNode, error)
+	GetPending() ([]model.Comment, error)
+	Create(comment model.Comment) (model.Comment, error)
+	Update(id uint, body string, principal model.Principal) (model.Comment, error)
+	Delete(id uint, principal model.Principal) error
+	Moderate(ids []uint, status model.CommentStatus) (int64, error)
+}
+
+// TagServiceInterface define el contrato para las operaciones del servicio de etiquetas
+type TagServiceInterface interface {
+	GetCloud() ([]model.TagCount, error)
+	Rename(id uint, name string) (model.Tag, error)
+	Merge(sourceID uint, targetID uint) error
+	AddAlias(id uint, alias string) (model.TagAlias, error)
+}
+
+// CategoryServiceInterface define el contrato para las operaciones del servicio de categorías
+type CategoryServiceInterface interface {
+	GetTree() ([]dto.CategoryNode, error)
+	Create(category model.Category) (model.Category, error)
+}
+
+// AuthorizerInterface define el contrato de las políticas de autorización sobre
+// recursos concretos. Authorize devuelve nil si el principal puede realizar la
+// acción sobre el recurso, o el error de dominio que explica la denegación
+type AuthorizerInterface interface {
+	Authorize(principal model.Principal, action model.Action, resource any) error
+}
@@ -1,11 +1,109 @@
 package config
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
 	DBDSN     string
 	JWTSECRET string
 	PORT      string
+
+	// Intervalo con el que se publican las publicaciones programadas
+	PUBLISHINTERVAL time.Duration
+
+	// Tiempo durante el que el autor de un comentario puede editarlo
+	COMMENTEDITWINDOW time.Duration
+
+	// Vigencia de los tokens de acceso y de los tokens de renovación
+	ACCESSTOKENTTL  time.Duration
+	REFRESHTOKENTTL time.Duration
+
+	// Intervalo con el que se eliminan las revocaciones de tokens ya expirados
+	REVOCATIONPURGEINTERVAL time.Duration
+
+	// Intervalo con el que cada instancia recarga los tokens y las sesiones que
+	// revocaron otras instancias de la API, y tiempo que conserva en caché el
+	// último cambio de contraseña de cada usuario
+	CACHEREFRESHINTERVAL time.Duration
+
+	// Algoritmo de firma de los tokens de acceso: HS256 con JWTSECRET, o RS256 o
+	// EdDSA con claves que se rotan cada JWTKEYROTATION. Cada
+	// JWTKEYREFRESHINTERVAL se comprueba si toca rotar y se recargan las claves
+	JWTALGORITHM          string
+	JWTKEYROTATION        time.Duration
+	JWTKEYREFRESHINTERVAL time.Duration
+
+	// Vigencia de los enlaces de verificación de email y tiempo mínimo entre
+	// dos reenvíos al mismo usuario
+	EMAILVERIFICATIONTTL       time.Duration
+	VERIFICATIONRESENDINTERVAL time.Duration
+
+	// Vigencia de los enlaces para restablecer la contraseña y tiempo mínimo
+	// entre dos enlaces enviados al mismo usuario
+	PASSWORDRESETTTL      time.Duration
+	PASSWORDRESETINTERVAL time.Duration
+
+	// Si está activo, los usuarios no pueden iniciar sesión hasta verificar su email
+	REQUIREEMAILVERIFICATION bool
+
+	// URL pública de la aplicación, usada en los enlaces que se envían por email
+	APPURL string
+
+	// Transporte de los emails: "smtp" o "file", que los guarda como .eml en MAILDIR
+	MAILDRIVER string
+	MAILDIR    string
+	MAILFROM   string
+
+	// Servidor SMTP; sin usuario los emails se envían sin autenticación
+	SMTPHOST     string
+	SMTPPORT     string
+	SMTPUSERNAME string
+	SMTPPASSWORD string
+
+	// Protección contra fuerza bruta en el inicio de sesión. Cada fallo retrasa el
+	// siguiente intento LOGINBACKOFF, duplicándose con cada fallo consecutivo, y al
+	// llegar al máximo de fallos se bloquea durante LOGINLOCKOUT. Los fallos se
+	// olvidan si pasa LOGINFAILUREWINDOW sin ninguno nuevo
+	LOGINATTEMPTSTORE  string
+	LOGINMAXFAILURES   int
+	LOGINMAXIPFAILURES int
+	LOGINBACKOFF       time.Duration
+	LOGINLOCKOUT       time.Duration
+	LOGINFAILUREWINDOW time.Duration
+
+	// Proxies, por IP o rango CIDR, de los que se acepta la IP del cliente en
+	// X-Forwarded-For. Sin ninguno se usa la dirección de la conexión
+	TRUSTEDPROXIES []string
+
+	// Emisor que muestran las aplicaciones de autenticación junto a la cuenta
+	TOTPISSUER string
+
+	// Algoritmo con el que se cifran las contraseñas: "argon2id" o "bcrypt". Los
+	// hashes con otro algoritmo o parámetros se actualizan al iniciar sesión.
+	// ARGON2MEMORY se indica en KiB
+	PASSWORDALGORITHM string
+	ARGON2MEMORY      int
+	ARGON2ITERATIONS  int
+	ARGON2PARALLELISM int
+	BCRYPTCOST        int
+
+	// Proveedores OpenID Connect para iniciar sesión, indicados por nombre en
+	// OIDCPROVIDERS y configurados con OIDC_<NOMBRE>_*
+	OIDCPROVIDERS []OIDCProvider
+}
+
+// OIDCProvider es el registro de la aplicación en un proveedor OpenID Connect
+type OIDCProvider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
 }
 
 func Load() *Config {
@@ -13,5 +111,131 @@ func Load() *Config {
 		DBDSN:     os.Getenv("DBDSN"),
 		JWTSECRET: os.Getenv("JWTSECRET"),
 		PORT:      os.Getenv("PORT"),
+
+		PUBLISHINTERVAL:         getDuration("PUBLISHINTERVAL", time.Minute),
+		COMMENTEDITWINDOW:       getDuration("COMMENTEDITWINDOW", 15*time.Minute),
+		ACCESSTOKENTTL:          getDuration("ACCESSTOKENTTL", 15*time.Minute),
+		REFRESHTOKENTTL:         getDuration("REFRESHTOKENTTL", 30*24*time.Hour),
+		REVOCATIONPURGEINTERVAL: getDuration("REVOCATIONPURGEINTERVAL", time.Hour),
+		CACHEREFRESHINTERVAL:    getDuration("CACHEREFRESHINTERVAL", 10*time.Second),
+
+		JWTALGORITHM:          getString("JWTALGORITHM", "HS256"),
+		JWTKEYROTATION:        getDuration("JWTKEYROTATION", 30*24*time.Hour),
+		JWTKEYREFRESHINTERVAL: getDuration("JWTKEYREFRESHINTERVAL", 5*time.Minute),
+
+		EMAILVERIFICATIONTTL:       getDuration("EMAILVERIFICATIONTTL", 24*time.Hour),
+		VERIFICATIONRESENDINTERVAL: getDuration("VERIFICATIONRESENDINTERVAL", time.Minute),
+		PASSWORDRESETTTL:           getDuration("PASSWORDRESETTTL", time.Hour),
+		PASSWORDRESETINTERVAL:      getDuration("PASSWORDRESETINTERVAL", time.Minute),
+		REQUIREEMAILVERIFICATION:   getBool("REQUIREEMAILVERIFICATION", false),
+		APPURL:                     getString("APPURL", "http://localhost:8080"),
+
+		MAILDRIVER:   getString("MAILDRIVER", "file"),
+		MAILDIR:      getString("MAILDIR", "tmp/mail"),
+		MAILFROM:     getString("MAILFROM", "Blog <no-reply@localhost>"),
+		SMTPHOST:     os.Getenv("SMTPHOST"),
+		SMTPPORT:     getString("SMTPPORT", "587"),
+		SMTPUSERNAME: os.Getenv("SMTPUSERNAME"),
+		SMTPPASSWORD: os.Getenv("SMTPPASSWORD"),
+
+		LOGINATTEMPTSTORE:  getString("LOGINATTEMPTSTORE", "database"),
+		LOGINMAXFAILURES:   getInt("LOGINMAXFAILURES", 5),
+		LOGINMAXIPFAILURES: getInt("LOGINMAXIPFAILURES", 20),
+		LOGINBACKOFF:       getDuration("LOGINBACKOFF", time.Second),
+		LOGINLOCKOUT:       getDuration("LOGINLOCKOUT", 15*time.Minute),
+		LOGINFAILUREWINDOW: getDuration("LOGINFAILUREWINDOW", 15*time.Minute),
+
+		TRUSTEDPROXIES: getList("TRUSTEDPROXIES", nil),
+
+		TOTPISSUER: getString("TOTPISSUER", "Blog"),
+
+		PASSWORDALGORITHM: getString("PASSWORDALGORITHM", "argon2id"),
+		ARGON2MEMORY:      getInt("ARGON2MEMORY", 19456),
+		ARGON2ITERATIONS:  getInt("ARGON2ITERATIONS", 2),
+		ARGON2PARALLELISM: getInt("ARGON2PARALLELISM", 1),
+		BCRYPTCOST:        getInt("BCRYPTCOST", 10),
+
+		OIDCPROVIDERS: getOIDCProviders(),
 	}
-}
\ No newline at end of file
+}
+
+// MinJWTSecretLength es la longitud mínima de JWTSECRET. Con él se firman los
+// tokens de acceso HS256, los desafíos de verificación en dos pasos, los enlaces
+// de verificación de email y el estado de OpenID Connect, y se cifran las claves
+// de firma asimétricas y los secretos TOTP, así que un secreto vacío o corto
+// permite falsificarlos
+const MinJWTSecretLength = 32
+
+// Validate comprueba la configuración que no tiene un valor por defecto seguro
+func (c *Config) Validate() error {
+	if len(c.JWTSECRET) < MinJWTSecretLength {
+		return fmt.Errorf("JWTSECRET debe tener al menos %d caracteres", MinJWTSecretLength)
+	}
+	return nil
+}
+
+// getOIDCProviders lee los proveedores de OIDCPROVIDERS, una lista separada por
+// comas. Cada proveedor se configura con OIDC_<NOMBRE>_ISSUER, _CLIENTID,
+// _CLIENTSECRET y _SCOPES, separados por espacios o comas
+func getOIDCProviders() []OIDCProvider {
+	var providers []OIDCProvider
+	for _, name := range getList("OIDCPROVIDERS", nil) {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProvider{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENTID"),
+			ClientSecret: os.Getenv(prefix + "CLIENTSECRET"),
+			Scopes:       getList(prefix+"SCOPES", []string{"openid", "email", "profile"}),
+		})
+	}
+	return providers
+}
+
+// getDuration lee una duración (por ejemplo "30s" o "5m") o devuelve el valor por defecto
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// getInt lee un entero positivo o devuelve el valor por defecto
+func getInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// getBool lee un booleano ("true", "false", "1", "0"...) o devuelve el valor por defecto
+func getBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getList lee una lista separada por comas o espacios o devuelve el valor por
+// defecto si está vacía
+func getList(key string, fallback []string) []string {
+	values := strings.FieldsFunc(os.Getenv(key), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
+
+// getString lee una variable de entorno o devuelve el valor por defecto si está vacía
+func getString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -86,4 +87,280 @@ func TestConfig_Structure(t *testing.T) {
 	assert.Equal(t, "test-secret", config.JWTSECRET)
 	assert.Equal(t, "8080", config.PORT)
 	assert.IsType(t, &Config{}, config)
-}
\ No newline at end of file
+}
+
+func TestLoad_Durations(t *testing.T) {
+	original := os.Getenv("PUBLISHINTERVAL")
+	defer os.Setenv("PUBLISHINTERVAL", original)
+
+	t.Run("uses default when unset", func(t *testing.T) {
+		os.Unsetenv("PUBLISHINTERVAL")
+		assert.Equal(t, time.Minute, Load().PUBLISHINTERVAL)
+	})
+
+	t.Run("parses a valid duration", func(t *testing.T) {
+		os.Setenv("PUBLISHINTERVAL", "30s")
+		assert.Equal(t, 30*time.Second, Load().PUBLISHINTERVAL)
+	})
+
+	t.Run("uses default for invalid values", func(t *testing.T) {
+		os.Setenv("PUBLISHINTERVAL", "soon")
+		assert.Equal(t, time.Minute, Load().PUBLISHINTERVAL)
+	})
+
+	t.Run("comment edit window defaults to 15 minutes", func(t *testing.T) {
+		original := os.Getenv("COMMENTEDITWINDOW")
+		defer os.Setenv("COMMENTEDITWINDOW", original)
+
+		os.Unsetenv("COMMENTEDITWINDOW")
+		assert.Equal(t, 15*time.Minute, Load().COMMENTEDITWINDOW)
+	})
+
+	t.Run("token lifetimes default to 15 minutes and 30 days", func(t *testing.T) {
+		originalAccess := os.Getenv("ACCESSTOKENTTL")
+		originalRefresh := os.Getenv("REFRESHTOKENTTL")
+		defer os.Setenv("ACCESSTOKENTTL", originalAccess)
+		defer os.Setenv("REFRESHTOKENTTL", originalRefresh)
+
+		os.Unsetenv("ACCESSTOKENTTL")
+		os.Unsetenv("REFRESHTOKENTTL")
+		assert.Equal(t, 15*time.Minute, Load().ACCESSTOKENTTL)
+		assert.Equal(t, 30*24*time.Hour, Load().REFRESHTOKENTTL)
+
+		os.Setenv("ACCESSTOKENTTL", "5m")
+		assert.Equal(t, 5*time.Minute, Load().ACCESSTOKENTTL)
+	})
+
+	t.Run("password reset links expire after one hour by default", func(t *testing.T) {
+		original := os.Getenv("PASSWORDRESETTTL")
+		defer os.Setenv("PASSWORDRESETTTL", original)
+
+		os.Unsetenv("PASSWORDRESETTTL")
+		assert.Equal(t, time.Hour, Load().PASSWORDRESETTTL)
+
+		os.Setenv("PASSWORDRESETTTL", "30m")
+		assert.Equal(t, 30*time.Minute, Load().PASSWORDRESETTTL)
+	})
+
+	t.Run("password reset links can be requested once a minute by default", func(t *testing.T) {
+		original := os.Getenv("PASSWORDRESETINTERVAL")
+		defer os.Setenv("PASSWORDRESETINTERVAL", original)
+
+		os.Unsetenv("PASSWORDRESETINTERVAL")
+		assert.Equal(t, time.Minute, Load().PASSWORDRESETINTERVAL)
+
+		os.Setenv("PASSWORDRESETINTERVAL", "5m")
+		assert.Equal(t, 5*time.Minute, Load().PASSWORDRESETINTERVAL)
+	})
+
+	t.Run("revocation purge interval defaults to one hour", func(t *testing.T) {
+		original := os.Getenv("REVOCATIONPURGEINTERVAL")
+		defer os.Setenv("REVOCATIONPURGEINTERVAL", original)
+
+		os.Unsetenv("REVOCATIONPURGEINTERVAL")
+		assert.Equal(t, time.Hour, Load().REVOCATIONPURGEINTERVAL)
+	})
+
+	t.Run("cache refresh interval defaults to ten seconds", func(t *testing.T) {
+		original := os.Getenv("CACHEREFRESHINTERVAL")
+		defer os.Setenv("CACHEREFRESHINTERVAL", original)
+
+		os.Unsetenv("CACHEREFRESHINTERVAL")
+		assert.Equal(t, 10*time.Second, Load().CACHEREFRESHINTERVAL)
+
+		os.Setenv("CACHEREFRESHINTERVAL", "2s")
+		assert.Equal(t, 2*time.Second, Load().CACHEREFRESHINTERVAL)
+	})
+}
+
+func TestLoad_EmailVerification(t *testing.T) {
+	keys := []string{"EMAILVERIFICATIONTTL", "VERIFICATIONRESENDINTERVAL", "REQUIREEMAILVERIFICATION", "APPURL"}
+	for _, key := range keys {
+		original := os.Getenv(key)
+		defer os.Setenv(key, original)
+		os.Unsetenv(key)
+	}
+
+	t.Run("uses defaults when unset", func(t *testing.T) {
+		config := Load()
+
+		assert.Equal(t, 24*time.Hour, config.EMAILVERIFICATIONTTL)
+		assert.Equal(t, time.Minute, config.VERIFICATIONRESENDINTERVAL)
+		assert.False(t, config.REQUIREEMAILVERIFICATION)
+		assert.Equal(t, "http://localhost:8080", config.APPURL)
+	})
+
+	t.Run("reads the flag and the app url", func(t *testing.T) {
+		os.Setenv("REQUIREEMAILVERIFICATION", "true")
+		os.Setenv("APPURL", "https://blog.example.com")
+
+		config := Load()
+
+		assert.True(t, config.REQUIREEMAILVERIFICATION)
+		assert.Equal(t, "https://blog.example.com", config.APPURL)
+	})
+
+	t.Run("invalid flag keeps the default", func(t *testing.T) {
+		os.Setenv("REQUIREEMAILVERIFICATION", "maybe")
+		assert.False(t, Load().REQUIREEMAILVERIFICATION)
+	})
+}
+
+func TestLoad_Mail(t *testing.T) {
+	keys := []string{"MAILDRIVER", "MAILDIR", "MAILFROM", "SMTPPORT"}
+	for _, key := range keys {
+		original := os.Getenv(key)
+		defer os.Setenv(key, original)
+		os.Unsetenv(key)
+	}
+
+	config := Load()
+	assert.Equal(t, "file", config.MAILDRIVER)
+	assert.Equal(t, "tmp/mail", config.MAILDIR)
+	assert.Equal(t, "Blog <no-reply@localhost>", config.MAILFROM)
+	assert.Equal(t, "587", config.SMTPPORT)
+
+	os.Setenv("MAILDRIVER", "smtp")
+	os.Setenv("SMTPPORT", "2525")
+	config = Load()
+	assert.Equal(t, "smtp", config.MAILDRIVER)
+	assert.Equal(t, "2525", config.SMTPPORT)
+}
+
+func TestLoad_LoginThrottle(t *testing.T) {
+	keys := []string{"LOGINATTEMPTSTORE", "LOGINMAXFAILURES", "LOGINMAXIPFAILURES", "LOGINBACKOFF", "LOGINLOCKOUT", "LOGINFAILUREWINDOW"}
+	for _, key := range keys {
+		original := os.Getenv(key)
+		defer os.Setenv(key, original)
+		os.Unsetenv(key)
+	}
+
+	config := Load()
+	assert.Equal(t, "database", config.LOGINATTEMPTSTORE)
+	assert.Equal(t, 5, config.LOGINMAXFAILURES)
+	assert.Equal(t, 20, config.LOGINMAXIPFAILURES)
+	assert.Equal(t, time.Second, config.LOGINBACKOFF)
+	assert.Equal(t, 15*time.Minute, config.LOGINLOCKOUT)
+	assert.Equal(t, 15*time.Minute, config.LOGINFAILUREWINDOW)
+
+	os.Setenv("LOGINATTEMPTSTORE", "memory")
+	os.Setenv("LOGINMAXFAILURES", "3")
+	os.Setenv("LOGINMAXIPFAILURES", "-1")
+	os.Setenv("LOGINLOCKOUT", "1h")
+	config = Load()
+	assert.Equal(t, "memory", config.LOGINATTEMPTSTORE)
+	assert.Equal(t, 3, config.LOGINMAXFAILURES)
+	assert.Equal(t, 20, config.LOGINMAXIPFAILURES, "invalid values keep the default")
+	assert.Equal(t, time.Hour, config.LOGINLOCKOUT)
+}
+
+func TestLoad_TrustedProxies(t *testing.T) {
+	original := os.Getenv("TRUSTEDPROXIES")
+	defer os.Setenv("TRUSTEDPROXIES", original)
+
+	os.Unsetenv("TRUSTEDPROXIES")
+	assert.Empty(t, Load().TRUSTEDPROXIES, "no proxy is trusted by default")
+
+	os.Setenv("TRUSTEDPROXIES", "10.0.0.1, 192.168.0.0/16")
+	assert.Equal(t, []string{"10.0.0.1", "192.168.0.0/16"}, Load().TRUSTEDPROXIES)
+}
+
+func TestLoad_TOTPIssuer(t *testing.T) {
+	original := os.Getenv("TOTPISSUER")
+	defer os.Setenv("TOTPISSUER", original)
+
+	os.Unsetenv("TOTPISSUER")
+	assert.Equal(t, "Blog", Load().TOTPISSUER)
+
+	os.Setenv("TOTPISSUER", "Mi Blog")
+	assert.Equal(t, "Mi Blog", Load().TOTPISSUER)
+}
+
+func TestLoad_SigningKeys(t *testing.T) {
+	keys := []string{"JWTALGORITHM", "JWTKEYROTATION", "JWTKEYREFRESHINTERVAL"}
+	for _, key := range keys {
+		original := os.Getenv(key)
+		defer os.Setenv(key, original)
+		os.Unsetenv(key)
+	}
+
+	config := Load()
+	assert.Equal(t, "HS256", config.JWTALGORITHM)
+	assert.Equal(t, 30*24*time.Hour, config.JWTKEYROTATION)
+	assert.Equal(t, 5*time.Minute, config.JWTKEYREFRESHINTERVAL)
+
+	os.Setenv("JWTALGORITHM", "EdDSA")
+	os.Setenv("JWTKEYROTATION", "168h")
+	config = Load()
+	assert.Equal(t, "EdDSA", config.JWTALGORITHM)
+	assert.Equal(t, 7*24*time.Hour, config.JWTKEYROTATION)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{"missing secret", "", true},
+		{"short secret", "short-secret", true},
+		{"long enough secret", "0123456789abcdef0123456789abcdef", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Config{JWTSECRET: tt.secret}).Validate()
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "JWTSECRET")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoad_PasswordHashing(t *testing.T) {
+	keys := []string{"PASSWORDALGORITHM", "ARGON2MEMORY", "ARGON2ITERATIONS", "ARGON2PARALLELISM", "BCRYPTCOST"}
+	for _, key := range keys {
+		original := os.Getenv(key)
+		defer os.Setenv(key, original)
+		os.Unsetenv(key)
+	}
+
+	config := Load()
+	assert.Equal(t, "argon2id", config.PASSWORDALGORITHM)
+	assert.Equal(t, 19456, config.ARGON2MEMORY)
+	assert.Equal(t, 2, config.ARGON2ITERATIONS)
+	assert.Equal(t, 1, config.ARGON2PARALLELISM)
+	assert.Equal(t, 10, config.BCRYPTCOST)
+
+	os.Setenv("PASSWORDALGORITHM", "bcrypt")
+	os.Setenv("ARGON2MEMORY", "65536")
+	os.Setenv("BCRYPTCOST", "12")
+	os.Setenv("ARGON2ITERATIONS", "0")
+	config = Load()
+	assert.Equal(t, "bcrypt", config.PASSWORDALGORITHM)
+	assert.Equal(t, 65536, config.ARGON2MEMORY)
+	assert.Equal(t, 12, config.BCRYPTCOST)
+	assert.Equal(t, 2, config.ARGON2ITERATIONS, "invalid values keep the default")
+}
+
+func TestLoad_OIDCProviders(t *testing.T) {
+	t.Setenv("OIDCPROVIDERS", "")
+	assert.Empty(t, Load().OIDCPROVIDERS)
+
+	t.Setenv("OIDCPROVIDERS", "Google, gitlab")
+	t.Setenv("OIDC_GOOGLE_ISSUER", "https://accounts.google.com")
+	t.Setenv("OIDC_GOOGLE_CLIENTID", "google-client")
+	t.Setenv("OIDC_GOOGLE_CLIENTSECRET", "google-secret")
+	t.Setenv("OIDC_GOOGLE_SCOPES", "")
+	t.Setenv("OIDC_GITLAB_ISSUER", "https://gitlab.com")
+	t.Setenv("OIDC_GITLAB_CLIENTID", "gitlab-client")
+	t.Setenv("OIDC_GITLAB_CLIENTSECRET", "")
+	t.Setenv("OIDC_GITLAB_SCOPES", "openid,email")
+
+	assert.Equal(t, []OIDCProvider{
+		{Name: "google", Issuer: "https://accounts.google.com", ClientID: "google-client", ClientSecret: "google-secret", Scopes: []string{"openid", "email", "profile"}},
+		{Name: "gitlab", Issuer: "https://gitlab.com", ClientID: "gitlab-client", Scopes: []string{"openid", "email"}},
+	}, Load().OIDCPROVIDERS)
+}
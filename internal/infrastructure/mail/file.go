@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer guarda cada email como un archivo .eml en dir en lugar de enviarlo.
+// Sirve en desarrollo: los archivos se abren con cualquier cliente de correo
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir, from}
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	now := time.Now()
+	data, err := build(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	// El prefijo con la fecha mantiene los archivos ordenados por envío
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405.000000"), messageID()[:8])
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o644)
+}
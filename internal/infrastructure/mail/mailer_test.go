@@ -0,0 +1,106 @@
+package mail
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMessage = Message{
+	To:      "john@example.com",
+	Subject: "Verifica tu email",
+	Text:    "Hola John: abre el enlace",
+	HTML:    "<p>Hola John: abre el enlace</p>",
+}
+
+// parseMessage parses a built message and returns its subject and the decoded
+// body of each part, keyed by media type
+func parseMessage(t *testing.T, data []byte) (*mail.Message, string, map[string]string) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[partType] = string(body)
+	}
+	return msg, subject, parts
+}
+
+func TestBuild(t *testing.T) {
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	data, err := build("Blog <no-reply@example.com>", testMessage, date)
+
+	require.NoError(t, err)
+	msg, subject, parts := parseMessage(t, data)
+	assert.Equal(t, "Blog <no-reply@example.com>", msg.Header.Get("From"))
+	assert.Equal(t, "john@example.com", msg.Header.Get("To"))
+	assert.Equal(t, "Verifica tu email", subject)
+	assert.Equal(t, "Mon, 15 Jan 2024 10:00:00 +0000", msg.Header.Get("Date"))
+	assert.Equal(t, testMessage.Text, parts["text/plain"])
+	assert.Equal(t, testMessage.HTML, parts["text/html"])
+}
+
+func TestBuild_TextOnly(t *testing.T) {
+	data, err := build("no-reply@example.com", Message{To: "john@example.com", Subject: "Contraseña", Text: "Hola"}, time.Now())
+
+	require.NoError(t, err)
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", msg.Header.Get("Content-Type"))
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.Equal(t, "Contraseña", subject)
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer := NewFileMailer(dir, "no-reply@example.com")
+
+	require.NoError(t, mailer.Send(testMessage))
+	require.NoError(t, mailer.Send(testMessage))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	_, subject, parts := parseMessage(t, data)
+	assert.Equal(t, "Verifica tu email", subject)
+	assert.True(t, strings.HasPrefix(parts["text/plain"], "Hola John"))
+}
+
+func TestMemoryMailer_Send(t *testing.T) {
+	mailer := NewMemoryMailer()
+
+	require.NoError(t, mailer.Send(testMessage))
+	messages := mailer.Messages()
+	messages[0].To = "changed"
+
+	assert.Equal(t, []Message{testMessage}, mailer.Messages())
+}
@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"fmt"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPMailer envía los emails a través de un servidor SMTP. Si no se indica
+// usuario se envían sin autenticación
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{host, port, username, password, from}
+}
+
+func (m *SMTPMailer) Send(msg Message) error {
+	sender, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("remitente inválido: %w", err)
+	}
+	recipient, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("destinatario inválido: %w", err)
+	}
+
+	data, err := build(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+	return smtp.SendMail(m.host+":"+m.port, auth, sender.Address, []string{recipient.Address}, data)
+}
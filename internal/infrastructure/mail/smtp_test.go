@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts a single connection, answers the minimal SMTP dialog
+// and sends the envelope and the data it received on the returned channel
+func fakeSMTPServer(t *testing.T) (string, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch {
+			case inData && line == ".":
+				inData = false
+				reply("250 OK")
+			case inData:
+				lines = append(lines, line)
+			case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(line, "MAIL FROM"), strings.HasPrefix(line, "RCPT TO"):
+				lines = append(lines, line)
+				reply("250 OK")
+			case line == "DATA":
+				inData = true
+				reply("354 Start mail input")
+			case line == "QUIT":
+				reply("221 Bye")
+				received <- lines
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	addr, received := fakeSMTPServer(t)
+	host, port, _ := net.SplitHostPort(addr)
+	mailer := NewSMTPMailer(host, port, "", "", "Blog <no-reply@example.com>")
+
+	err := mailer.Send(testMessage)
+
+	require.NoError(t, err)
+	lines := <-received
+	assert.Equal(t, "MAIL FROM:<no-reply@example.com>", lines[0])
+	assert.Equal(t, "RCPT TO:<john@example.com>", lines[1])
+	assert.Contains(t, lines, "To: john@example.com")
+}
+
+func TestSMTPMailer_Send_InvalidAddress(t *testing.T) {
+	mailer := NewSMTPMailer("localhost", "25", "", "", "not an address")
+
+	assert.Error(t, mailer.Send(testMessage))
+}
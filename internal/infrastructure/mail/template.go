@@ -0,0 +1,85 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+)
+
+// Cada plantilla se compone de nombre.idioma.txt, que también define el asunto
+// en el bloque "subject", y nombre.idioma.html
+//
+//go:embed templates
+var templateFS embed.FS
+
+type template struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Renderer genera el contenido de los emails a partir de las plantillas
+type Renderer struct {
+	templates map[string]template
+}
+
+// NewRenderer carga las plantillas embebidas en el binario
+func NewRenderer() (*Renderer, error) {
+	files, err := fs.Glob(templateFS, "templates/*.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]template, len(files))
+	for _, file := range files {
+		key := strings.TrimSuffix(path.Base(file), ".txt")
+		text, err := texttemplate.ParseFS(templateFS, file)
+		if err != nil {
+			return nil, err
+		}
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("la plantilla %s no define el asunto", key)
+		}
+		html, err := htmltemplate.ParseFS(templateFS, "templates/"+key+".html")
+		if err != nil {
+			return nil, err
+		}
+		templates[key] = template{text, html}
+	}
+	return &Renderer{templates}, nil
+}
+
+// Render genera el email name en el idioma locale, o en el idioma por defecto si
+// no hay plantilla para ese idioma. El destinatario lo completa quien lo envía
+func (r *Renderer) Render(name, locale string, data any) (Message, error) {
+	tmpl, ok := r.templates[name+"."+locale]
+	if !ok {
+		tmpl, ok = r.templates[name+"."+model.DefaultLocale]
+	}
+	if !ok {
+		return Message{}, fmt.Errorf("plantilla de email desconocida: %s", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.html.Execute(&html, data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,47 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_Render(t *testing.T) {
+	renderer, err := NewRenderer()
+	require.NoError(t, err)
+	data := struct{ Name, Link string }{"<John>", "https://blog.example.com/verify?token=a&b"}
+
+	tests := []struct {
+		name    string
+		locale  string
+		subject string
+		greets  string
+	}{
+		{"spanish", "es", "Verifica tu email", "Hola <John>:"},
+		{"english", "en", "Verify your email", "Hi <John>,"},
+		{"unknown locale falls back to spanish", "fr", "Verifica tu email", "Hola <John>:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := renderer.Render("verification", tt.locale, data)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.subject, msg.Subject)
+			assert.Contains(t, msg.Text, tt.greets)
+			assert.Contains(t, msg.Text, "https://blog.example.com/verify?token=a&b")
+			assert.Contains(t, msg.HTML, "&lt;John&gt;", "the html version must be escaped")
+			assert.Contains(t, msg.HTML, `href="https://blog.example.com/verify?token=a&amp;b"`)
+		})
+	}
+}
+
+func TestRenderer_Render_UnknownTemplate(t *testing.T) {
+	renderer, err := NewRenderer()
+	require.NoError(t, err)
+
+	_, err = renderer.Render("newsletter", "es", nil)
+
+	assert.Error(t, err)
+}
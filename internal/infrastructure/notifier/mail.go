@@ -0,0 +1,46 @@
+// Package notifier implementa el envío de avisos a los usuarios sobre su cuenta
+package notifier
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/infrastructure/mail"
+)
+
+// MailNotifier envía los avisos por email, en el idioma del usuario
+type MailNotifier struct {
+	mailer   mail.Mailer
+	renderer *mail.Renderer
+	baseURL  string
+}
+
+func NewMailNotifier(mailer mail.Mailer, renderer *mail.Renderer, baseURL string) *MailNotifier {
+	return &MailNotifier{mailer, renderer, strings.TrimSuffix(baseURL, "/")}
+}
+
+func (n *MailNotifier) SendVerification(user model.User, token string) error {
+	return n.send(user, "verification", n.link("/api/v1/auth/verify", token))
+}
+
+func (n *MailNotifier) SendPasswordReset(user model.User, token string) error {
+	return n.send(user, "password_reset", n.link("/reset-password", token))
+}
+
+func (n *MailNotifier) send(user model.User, template, link string) error {
+	msg, err := n.renderer.Render(template, user.Locale, struct {
+		Name string
+		Link string
+	}{user.Name, link})
+	if err != nil {
+		return err
+	}
+	msg.To = user.Email
+	return n.mailer.Send(msg)
+}
+
+// link construye la URL pública de path con el token como parámetro
+func (n *MailNotifier) link(path, token string) string {
+	return n.baseURL + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,44 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/internal/infrastructure/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNotifier(t *testing.T) (*MailNotifier, *mail.MemoryMailer) {
+	renderer, err := mail.NewRenderer()
+	require.NoError(t, err)
+	mailer := mail.NewMemoryMailer()
+	return NewMailNotifier(mailer, renderer, "https://blog.example.com/"), mailer
+}
+
+func TestMailNotifier_SendVerification(t *testing.T) {
+	n, mailer := newTestNotifier(t)
+
+	err := n.SendVerification(model.User{Name: "John", Email: "john@example.com", Locale: "es"}, "abc.def")
+
+	require.NoError(t, err)
+	messages := mailer.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "john@example.com", messages[0].To)
+	assert.Equal(t, "Verifica tu email", messages[0].Subject)
+	assert.Contains(t, messages[0].Text, "https://blog.example.com/api/v1/auth/verify?token=abc.def")
+	assert.Contains(t, messages[0].HTML, `href="https://blog.example.com/api/v1/auth/verify?token=abc.def"`)
+}
+
+func TestMailNotifier_SendPasswordReset(t *testing.T) {
+	n, mailer := newTestNotifier(t)
+
+	err := n.SendPasswordReset(model.User{Name: "Jane", Email: "jane@example.com", Locale: "en"}, "a+b")
+
+	require.NoError(t, err)
+	messages := mailer.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Reset your password", messages[0].Subject)
+	assert.Contains(t, messages[0].Text, "Hi Jane,")
+	assert.Contains(t, messages[0].Text, "https://blog.example.com/reset-password?token=a%2Bb")
+}
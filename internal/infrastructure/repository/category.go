@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type CategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{db}
+}
+
+func (r *CategoryRepository) GetAll() ([]model.Category, error) {
+	var categories []model.Category
+	err := r.db.Order("name ASC").Find(&categories).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseErrorWith(err, errors.ErrCategoryNotFound, nil)
+	}
+	return categories, nil
+}
+
+func (r *CategoryRepository) GetByID(id uint) (model.Category, error) {
+	var category model.Category
+	err := r.db.First(&category, id).Error
+	if err != nil {
+		return model.Category{}, errors.WrapDatabaseErrorWith(err, errors.ErrCategoryNotFound, nil)
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) GetBySlug(slug string) (model.Category, error) {
+	var category model.Category
+	err := r.db.Where("slug = ?", slug).First(&category).Error
+	if err != nil {
+		return model.Category{}, errors.WrapDatabaseErrorWith(err, errors.ErrCategoryNotFound, nil)
+	}
+	return category, nil
+}
+
+// GetDescendantIDs devuelve el ID de la categoría y los de todas sus subcategorías
+func (r *CategoryRepository) GetDescendantIDs(id uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Raw(`WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = ?
+			UNION ALL
+			SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id
+		)
+		SELECT id FROM tree`, id).Scan(&ids).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseErrorWith(err, errors.ErrCategoryNotFound, nil)
+	}
+	return ids, nil
+}
+
+func (r *CategoryRepository) Create(category model.Category) (model.Category, error) {
+	err := r.db.Create(&category).Error
+	if err != nil {
+		return model.Category{}, errors.WrapDatabaseErrorWith(err, errors.ErrCategoryNotFound, errors.ErrCategoryExists)
+	}
+	return category, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var categoryColumns = []string{"id", "name", "slug", "parent_id", "created_at", "updated_at"}
+
+func TestNewCategoryRepository(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCategoryRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, db, repo.db)
+}
+
+func TestCategoryRepository_GetAll(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCategoryRepository(db)
+
+	rows := sqlmock.NewRows(categoryColumns).
+		AddRow(1, "Backend", "backend", nil, time.Now(), time.Now()).
+		AddRow(2, "Go", "go", 1, time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT \* FROM "categories" ORDER BY name ASC`).WillReturnRows(rows)
+
+	categories, err := repo.GetAll()
+
+	assert.NoError(t, err)
+	assert.Len(t, categories, 2)
+	assert.Equal(t, uint(1), *categories[1].ParentID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_GetBySlug(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectQuery(`SELECT \* FROM "categories" WHERE slug = \$1`).WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := repo.GetBySlug("nada")
+
+	assert.ErrorIs(t, err, errors.ErrCategoryNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_GetDescendantIDs(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectQuery(`WITH RECURSIVE tree AS \(\s+SELECT id FROM categories WHERE id = \$1\s+UNION ALL\s+SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id\s+\)\s+SELECT id FROM tree`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(5))
+
+	ids, err := repo.GetDescendantIDs(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2, 5}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCategoryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "categories"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectCommit()
+
+	parentID := uint(1)
+	category, err := repo.Create(model.Category{Name: "Go", Slug: "go", ParentID: &parentID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), category.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db}
+}
+
+func (r *CommentRepository) GetByID(id uint) (model.Comment, error) {
+	var comment model.Comment
+	err := r.db.First(&comment, id).Error
+	if err != nil {
+		return model.Comment{}, errors.WrapDatabaseErrorWith(err, errors.ErrCommentNotFound, nil)
+	}
+	return comment, nil
+}
+
+// GetByPost devuelve los comentarios de la publicación con el estado indicado,
+// del más antiguo al más reciente
+func (r *CommentRepository) GetByPost(postID uint, status model.CommentStatus) ([]model.Comment, error) {
+	var comments []model.Comment
+	err := r.db.Where("post_id = ? AND status = ?", postID, status).Order("created_at ASC, id ASC").Find(&comments).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseErrorWith(err, errors.ErrCommentNotFound, nil)
+	}
+	return comments, nil
+}
+
+// GetPending devuelve la cola de moderación de todas las publicaciones
+func (r *CommentRepository) GetPending() ([]model.Comment, error) {
+	var comments []model.Comment
+	err := r.db.Where("status = ?", model.CommentStatusPending).Order("created_at ASC").Find(&comments).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseErrorWith(err, errors.ErrCommentNotFound, nil)
+	}
+	return comments, nil
+}
+
+// CountApprovedByAuthor cuenta los comentarios aprobados de un usuario
+func (r *CommentRepository) CountApprovedByAuthor(authorID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Comment{}).
+		Where("author_id = ? AND status = ?", authorID, model.CommentStatusApproved).
+		Count(&count).Error
+	if err != nil {
+		return 0, errors.WrapDatabaseErrorWith(err, errors.ErrCommentNotFound, nil)
+	}
+	return count, nil
+}
+
+func (r *CommentRepository) Create(comment model.Comment) (model.Comment, error) {
+	err := r.db.Create(&comment).Error
+	if err != nil {
+		return model.Comment{}, errors.WrapDatabaseErrorWith(err, errors.ErrCommentNotFound, nil)
+	}
+	return comment, nil
+}
+
+func (r *CommentRepository) Update(comment model.Comment) (model.Comment, error) {
+	err := r.db.Save(&comment).Error
+	if err != nil {
+		return model.Comment{}, errors.WrapDatabaseErrorWith(err, errors.ErrCommentNotFound, nil)
+	}
+	return comment, nil
+}
+
+// Delete elimina el comentario; sus respuestas se eliminan en cascada
+func (r *CommentRepository) Delete(id uint) error {
+	err := r.db.Delete(&model.Comment{}, id).Error
+	if err != nil {
+		return errors.WrapDatabaseErrorWith(err, errors.ErrCommentNotFound, nil)
+	}
+	return nil
+}
+
+// UpdateStatus cambia el estado de los comentarios indicados y devuelve cuántos
+// se modificaron
+func (r *CommentRepository) UpdateStatus(ids []uint, status model.CommentStatus) (int64, error) {
+	result := r.db.Model(&model.Comment{}).Where("id IN ?", ids).Update("status", status)
+	if result.Error != nil {
+		return 0, errors.WrapDatabaseErrorWith(result.Error, errors.ErrCommentNotFound, nil)
+	}
+	return result.RowsAffected, nil
+}
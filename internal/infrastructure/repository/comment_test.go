@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var commentColumns = []string{"id", "post_id", "parent_id", "author_id", "body", "status", "created_at", "updated_at"}
+
+func TestNewCommentRepository(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, db, repo.db)
+}
+
+func TestCommentRepository_GetByID(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "success - comment found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(commentColumns).AddRow(1, 1, nil, 2, "Hola", "approved", time.Now(), time.Now())
+				mock.ExpectQuery(`SELECT \* FROM "comments" WHERE "comments"."id" = \$1`).
+					WithArgs(1, 1).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "error - comment not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT \* FROM "comments"`).WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expectedError: errors.ErrCommentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewCommentRepository(db)
+			tt.setupMock(mock)
+
+			comment, err := repo.GetByID(1)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint(1), comment.ID)
+				assert.Nil(t, comment.ParentID)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCommentRepository_GetByPost(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentRepository(db)
+
+	rows := sqlmock.NewRows(commentColumns).
+		AddRow(1, 1, nil, 2, "Hola", "approved", time.Now(), time.Now()).
+		AddRow(2, 1, 1, 3, "Respuesta", "approved", time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT \* FROM "comments" WHERE post_id = \$1 AND status = \$2 ORDER BY created_at ASC, id ASC`).
+		WithArgs(1, model.CommentStatusApproved).
+		WillReturnRows(rows)
+
+	comments, err := repo.GetByPost(1, model.CommentStatusApproved)
+
+	assert.NoError(t, err)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, uint(1), *comments[1].ParentID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_GetPending(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentRepository(db)
+
+	rows := sqlmock.NewRows(commentColumns).AddRow(1, 1, nil, 2, "Hola", "pending", time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT \* FROM "comments" WHERE status = \$1 ORDER BY created_at ASC`).
+		WithArgs(model.CommentStatusPending).
+		WillReturnRows(rows)
+
+	comments, err := repo.GetPending()
+
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_CountApprovedByAuthor(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentRepository(db)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "comments" WHERE author_id = \$1 AND status = \$2`).
+		WithArgs(2, model.CommentStatusApproved).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := repo.CountApprovedByAuthor(2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "comments"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectCommit()
+
+	comment, err := repo.Create(model.Comment{PostID: 1, AuthorID: 2, Body: "Hola", Status: model.CommentStatusPending})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), comment.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_Delete(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "comments" WHERE "comments"."id" = \$1`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.Delete(1)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_UpdateStatus(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "comments" SET "status"=\$1,"updated_at"=\$2 WHERE id IN \(\$3,\$4\)$`).
+		WithArgs(model.CommentStatusSpam, sqlmock.AnyArg(), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	updated, err := repo.UpdateStatus([]uint{1, 2}, model.CommentStatusSpam)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type IdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewIdentityRepository(db *gorm.DB) *IdentityRepository {
+	return &IdentityRepository{db}
+}
+
+func (r *IdentityRepository) GetByProviderSubject(provider, subject string) (model.Identity, error) {
+	var identity model.Identity
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		return model.Identity{}, errors.WrapDatabaseErrorWith(err, errors.ErrIdentityNotFound, nil)
+	}
+	return identity, nil
+}
+
+func (r *IdentityRepository) Create(identity model.Identity) (model.Identity, error) {
+	err := r.db.Create(&identity).Error
+	if err != nil {
+		return model.Identity{}, errors.WrapDatabaseError(err)
+	}
+	return identity, nil
+}
+
+// CreateWithUser crea el usuario y la identidad vinculada a él. Si falla
+// cualquiera de los dos no se crea ninguno
+func (r *IdentityRepository) CreateWithUser(user model.User, identity model.Identity) (model.Identity, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(&identity).Error
+	})
+	if err != nil {
+		return model.Identity{}, errors.WrapDatabaseError(err)
+	}
+	identity.User = user
+	return identity, nil
+}
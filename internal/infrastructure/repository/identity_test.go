@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestIdentityRepository_GetByProviderSubject(t *testing.T) {
+	t.Run("success - identity found", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewIdentityRepository(db)
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "provider", "subject"}).AddRow(1, 2, "google", "sub-1")
+		mock.ExpectQuery(`SELECT \* FROM "identities" WHERE provider = \$1 AND subject = \$2`).
+			WithArgs("google", "sub-1", 1).
+			WillReturnRows(rows)
+
+		identity, err := repo.GetByProviderSubject("google", "sub-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), identity.UserID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error - identity not found", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewIdentityRepository(db)
+
+		mock.ExpectQuery(`SELECT \* FROM "identities"`).WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.GetByProviderSubject("google", "sub-1")
+
+		assert.ErrorIs(t, err, errors.ErrIdentityNotFound)
+	})
+}
+
+func TestIdentityRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewIdentityRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "identities"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	identity, err := repo.Create(model.Identity{UserID: 2, Provider: "google", Subject: "sub-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), identity.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdentityRepository_CreateWithUser(t *testing.T) {
+	t.Run("success - user and identity created", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewIdentityRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "users"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectQuery(`INSERT INTO "identities" \("user_id","provider","subject","email","created_at"\)`).
+			WithArgs(7, "google", "sub-1", "john@example.com", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		identity, err := repo.CreateWithUser(
+			model.User{Name: "John", Email: "john@example.com", Password: "hash"},
+			model.Identity{Provider: "google", Subject: "sub-1", Email: "john@example.com"},
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(7), identity.UserID)
+		assert.Equal(t, uint(7), identity.User.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error - user not created", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewIdentityRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "users"`).
+			WillReturnError(gorm.ErrDuplicatedKey)
+		mock.ExpectRollback()
+
+		_, err := repo.CreateWithUser(model.User{Email: "john@example.com"}, model.Identity{Provider: "google", Subject: "sub-1"})
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptRepository guarda los fallos en la base de datos, de modo que se
+// comparten entre instancias y se conservan entre reinicios
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db}
+}
+
+func (r *LoginAttemptRepository) Get(key string) (model.LoginAttempt, error) {
+	attempt := model.LoginAttempt{Key: key}
+	err := r.db.Where("key = ?", key).Limit(1).Find(&attempt).Error
+	if err != nil {
+		return model.LoginAttempt{}, errors.WrapDatabaseError(err)
+	}
+	return attempt, nil
+}
+
+// RecordFailure suma el fallo en una única sentencia para que los intentos
+// simultáneos no se pierdan
+func (r *LoginAttemptRepository) RecordFailure(key string, now time.Time, window time.Duration) (model.LoginAttempt, error) {
+	attempt := model.LoginAttempt{Key: key, Failures: 1, LastFailedAt: now}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"failures":       gorm.Expr("CASE WHEN login_attempts.last_failed_at > ? THEN login_attempts.failures + 1 ELSE 1 END", now.Add(-window)),
+			"last_failed_at": now,
+		}),
+	}, clause.Returning{}).Create(&attempt).Error
+	if err != nil {
+		return model.LoginAttempt{}, errors.WrapDatabaseError(err)
+	}
+	return attempt, nil
+}
+
+func (r *LoginAttemptRepository) Lock(key string, until time.Time) error {
+	err := r.db.Model(&model.LoginAttempt{}).Where("key = ?", key).Update("locked_until", until).Error
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepository) Reset(key string) error {
+	err := r.db.Where("key = ?", key).Delete(&model.LoginAttempt{}).Error
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
+
+// DeleteExpired elimina las claves sin fallos recientes ni bloqueo vigente, que
+// ya no afectan a los siguientes intentos
+func (r *LoginAttemptRepository) DeleteExpired(now time.Time, window time.Duration) (int64, error) {
+	result := r.db.
+		Where("last_failed_at <= ? AND (locked_until IS NULL OR locked_until <= ?)", now.Add(-window), now).
+		Delete(&model.LoginAttempt{})
+	if result.Error != nil {
+		return 0, errors.WrapDatabaseError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// MemoryLoginAttemptRepository guarda los fallos en memoria. Es suficiente con
+// una sola instancia de la API; los fallos se pierden al reiniciar
+type MemoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]model.LoginAttempt
+}
+
+func NewMemoryLoginAttemptRepository() *MemoryLoginAttemptRepository {
+	return &MemoryLoginAttemptRepository{attempts: make(map[string]model.LoginAttempt)}
+}
+
+func (r *MemoryLoginAttemptRepository) Get(key string) (model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if attempt, ok := r.attempts[key]; ok {
+		return attempt, nil
+	}
+	return model.LoginAttempt{Key: key}, nil
+}
+
+func (r *MemoryLoginAttemptRepository) RecordFailure(key string, now time.Time, window time.Duration) (model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt, ok := r.attempts[key]
+	if !ok || !attempt.LastFailedAt.After(now.Add(-window)) {
+		attempt = model.LoginAttempt{Key: key, LockedUntil: attempt.LockedUntil}
+	}
+	attempt.Failures++
+	attempt.LastFailedAt = now
+	r.attempts[key] = attempt
+	return attempt, nil
+}
+
+func (r *MemoryLoginAttemptRepository) Lock(key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if attempt, ok := r.attempts[key]; ok {
+		attempt.LockedUntil = &until
+		r.attempts[key] = attempt
+	}
+	return nil
+}
+
+func (r *MemoryLoginAttemptRepository) Reset(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.attempts, key)
+	return nil
+}
+
+func (r *MemoryLoginAttemptRepository) DeleteExpired(now time.Time, window time.Duration) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for key, attempt := range r.attempts {
+		if attempt.LastFailedAt.After(now.Add(-window)) || attempt.RetryAfter(now) > 0 {
+			continue
+		}
+		delete(r.attempts, key)
+		deleted++
+	}
+	return deleted, nil
+}
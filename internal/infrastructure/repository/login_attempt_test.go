@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptRepository_Get(t *testing.T) {
+	t.Run("success - stored failures", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewLoginAttemptRepository(db)
+
+		rows := sqlmock.NewRows([]string{"id", "key", "failures"}).AddRow(1, "email:john@example.com", 3)
+		mock.ExpectQuery(`SELECT \* FROM "login_attempts" WHERE key = \$1 LIMIT \$2`).
+			WithArgs("email:john@example.com", 1).
+			WillReturnRows(rows)
+
+		attempt, err := repo.Get("email:john@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempt.Failures)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success - unknown key has no failures", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewLoginAttemptRepository(db)
+
+		mock.ExpectQuery(`SELECT \* FROM "login_attempts" WHERE key = \$1`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "key", "failures"}))
+
+		attempt, err := repo.Get("ip:10.0.0.1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ip:10.0.0.1", attempt.Key)
+		assert.Zero(t, attempt.Failures)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLoginAttemptRepository_RecordFailure(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewLoginAttemptRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "login_attempts" .* ON CONFLICT \("key"\) DO UPDATE SET "failures"=CASE WHEN login_attempts.last_failed_at > \$\d+ THEN login_attempts.failures \+ 1 ELSE 1 END,"last_failed_at"=\$\d+ RETURNING \*`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key", "failures", "last_failed_at"}).
+			AddRow(1, "email:john@example.com", 4, now))
+	mock.ExpectCommit()
+
+	attempt, err := repo.RecordFailure("email:john@example.com", now, 15*time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, attempt.Failures)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptRepository_LockAndReset(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewLoginAttemptRepository(db)
+	until := time.Now().Add(time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "login_attempts" SET "locked_until"=\$1 WHERE key = \$2`).
+		WithArgs(until, "ip:10.0.0.1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.Lock("ip:10.0.0.1", until))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "login_attempts" WHERE key = \$1`).
+		WithArgs("ip:10.0.0.1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.Reset("ip:10.0.0.1"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "login_attempts"`).WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.Reset("ip:10.0.0.1"), errors.ErrDatabaseOperation)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptRepository_DeleteExpired(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewLoginAttemptRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "login_attempts" WHERE last_failed_at <= \$1 AND \(locked_until IS NULL OR locked_until <= \$2\)`).
+		WithArgs(now.Add(-15*time.Minute), now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	deleted, err := repo.DeleteExpired(now, 15*time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryLoginAttemptRepository(t *testing.T) {
+	repo := NewMemoryLoginAttemptRepository()
+	now := time.Now()
+	window := 15 * time.Minute
+
+	// Los fallos se suman dentro de la ventana
+	for i := 1; i <= 3; i++ {
+		attempt, err := repo.RecordFailure("email:john@example.com", now, window)
+		assert.NoError(t, err)
+		assert.Equal(t, i, attempt.Failures)
+	}
+
+	// Y se reinician si el último es anterior a la ventana
+	attempt, err := repo.RecordFailure("email:john@example.com", now.Add(window), window)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+
+	// El bloqueo se conserva hasta que se reinicia la clave
+	assert.NoError(t, repo.Lock("email:john@example.com", now.Add(time.Hour)))
+	attempt, err = repo.Get("email:john@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, attempt.RetryAfter(now))
+
+	assert.NoError(t, repo.Reset("email:john@example.com"))
+	attempt, err = repo.Get("email:john@example.com")
+	assert.NoError(t, err)
+	assert.Zero(t, attempt.Failures)
+	assert.Zero(t, attempt.RetryAfter(now))
+
+	// La purga solo elimina las claves sin fallos recientes ni bloqueo vigente
+	repo.RecordFailure("ip:old", now.Add(-time.Hour), window)
+	repo.RecordFailure("ip:locked", now.Add(-time.Hour), window)
+	repo.Lock("ip:locked", now.Add(time.Minute))
+	repo.RecordFailure("ip:recent", now, window)
+
+	deleted, err := repo.DeleteExpired(now, window)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	for key, remaining := range map[string]int{"ip:old": 0, "ip:locked": 1, "ip:recent": 1} {
+		attempt, _ := repo.Get(key)
+		assert.Equal(t, remaining, attempt.Failures, key)
+	}
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/UliVargas/blog-go/pkg/query"
+	"gorm.io/gorm"
+)
+
+// likeEscaper escapa los comodines de LIKE en los valores de los filtros. El
+// carácter de escape se declara en la consulta porque no todos los motores usan
+// la barra invertida por defecto
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// findPage aplica la especificación sobre db y devuelve una página de T. Las
+// columnas provienen de la lista blanca del esquema, por lo que se pueden
+// interpolar; los valores siempre se envían como parámetros. El ID se usa como
+// desempate para que el orden sea estable entre páginas. Solo se usa SQL
+// estándar, sin ILIKE, para no depender de PostgreSQL
+func findPage[T any](db *gorm.DB, spec query.Spec) (query.Page[T], error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return query.Page[T]{}, err
+	}
+	table := stmt.Schema.Table
+	column := func(name string) string {
+		return fmt.Sprintf("%s.%s", table, name)
+	}
+
+	for _, cond := range spec.Conditions {
+		switch cond.Operator {
+		case query.OpContains:
+			db = db.Where("LOWER("+column(cond.Column)+`) LIKE LOWER(?) ESCAPE '\'`, "%"+likeEscaper.Replace(fmt.Sprint(cond.Value))+"%")
+		case query.OpAfter:
+			db = db.Where(column(cond.Column)+" > ?", cond.Value)
+		case query.OpBefore:
+			db = db.Where(column(cond.Column)+" < ?", cond.Value)
+		default:
+			db = db.Where(column(cond.Column)+" = ?", cond.Value)
+		}
+	}
+
+	sortColumn, idColumn := column(spec.Sort.Column), column("id")
+	desc := spec.Desc
+	if spec.Cursor != nil {
+		// La página anterior se obtiene recorriendo el orden al revés
+		desc = desc != spec.Cursor.Backward
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		db = db.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", sortColumn, idColumn, op), spec.Cursor.Value, spec.Cursor.ID)
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	var items []T
+	err := db.Order(sortColumn + " " + direction).
+		Order(idColumn + " " + direction).
+		Limit(spec.Limit + 1).
+		Find(&items).Error
+	if err != nil {
+		return query.Page[T]{}, err
+	}
+
+	sortField := stmt.Schema.LookUpField(spec.Sort.Column)
+	idField := stmt.Schema.LookUpField("id")
+	return query.NewPage(items, spec, func(item T) (any, uint) {
+		value := reflect.ValueOf(&item).Elem()
+		sortValue, _ := sortField.ValueOf(context.Background(), value)
+		id, _ := idField.ValueOf(context.Background(), value)
+		return sortValue, uint(reflect.ValueOf(id).Uint())
+	}), nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type PasswordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetTokenRepository(db *gorm.DB) *PasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{db}
+}
+
+func (r *PasswordResetTokenRepository) Create(token model.PasswordResetToken) (model.PasswordResetToken, error) {
+	err := r.db.Create(&token).Error
+	if err != nil {
+		return model.PasswordResetToken{}, errors.WrapDatabaseError(err)
+	}
+	return token, nil
+}
+
+func (r *PasswordResetTokenRepository) GetByHash(hash string) (model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return model.PasswordResetToken{}, errors.WrapDatabaseErrorWith(err, errors.ErrInvalidResetToken, nil)
+	}
+	return token, nil
+}
+
+// MarkUsed marca el token como usado solo si no se había usado antes. Devuelve
+// false si otra petición lo usó primero
+func (r *PasswordResetTokenRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, errors.WrapDatabaseError(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateByUser marca como usados todos los tokens pendientes del usuario
+func (r *PasswordResetTokenRepository) InvalidateByUser(userID uint, usedAt time.Time) error {
+	err := r.db.Model(&model.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", usedAt).Error
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
+
+// ExistsCreatedSince indica si se generó algún token para el usuario después de since
+func (r *PasswordResetTokenRepository) ExistsCreatedSince(userID uint, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.PasswordResetToken{}).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Count(&count).Error
+	if err != nil {
+		return false, errors.WrapDatabaseError(err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPasswordResetTokenRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPasswordResetTokenRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "password_reset_tokens"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	token, err := repo.Create(model.PasswordResetToken{UserID: 1, TokenHash: "hash", ExpiresAt: time.Now()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), token.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPasswordResetTokenRepository_GetByHash(t *testing.T) {
+	t.Run("success - token found", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewPasswordResetTokenRepository(db)
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "token_hash"}).AddRow(1, 2, "hash")
+		mock.ExpectQuery(`SELECT \* FROM "password_reset_tokens" WHERE token_hash = \$1`).
+			WithArgs("hash", 1).
+			WillReturnRows(rows)
+
+		token, err := repo.GetByHash("hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), token.UserID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error - token not found", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewPasswordResetTokenRepository(db)
+
+		mock.ExpectQuery(`SELECT \* FROM "password_reset_tokens"`).WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.GetByHash("hash")
+
+		assert.ErrorIs(t, err, errors.ErrInvalidResetToken)
+	})
+}
+
+func TestPasswordResetTokenRepository_MarkUsed(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expected     bool
+	}{
+		{"success - token was unused", 1, true},
+		{"token already used", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPasswordResetTokenRepository(db)
+			usedAt := time.Now()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "password_reset_tokens" SET "used_at"=\$1 WHERE id = \$2 AND used_at IS NULL`).
+				WithArgs(usedAt, 1).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			mock.ExpectCommit()
+
+			marked, err := repo.MarkUsed(1, usedAt)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, marked)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPasswordResetTokenRepository_InvalidateByUser(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPasswordResetTokenRepository(db)
+	usedAt := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "password_reset_tokens" SET "used_at"=\$1 WHERE user_id = \$2 AND used_at IS NULL`).
+		WithArgs(usedAt, 1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := repo.InvalidateByUser(1, usedAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPasswordResetTokenRepository_ExistsCreatedSince(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int
+		expected bool
+	}{
+		{"recent token", 1, true},
+		{"no recent token", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPasswordResetTokenRepository(db)
+			since := time.Now().Add(-time.Minute)
+
+			mock.ExpectQuery(`SELECT count\(\*\) FROM "password_reset_tokens" WHERE user_id = \$1 AND created_at > \$2`).
+				WithArgs(1, since).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.count))
+
+			exists, err := repo.ExistsCreatedSince(1, since)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type PersonalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) *PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{db}
+}
+
+func (r *PersonalAccessTokenRepository) Create(token model.PersonalAccessToken) (model.PersonalAccessToken, error) {
+	err := r.db.Create(&token).Error
+	if err != nil {
+		return model.PersonalAccessToken{}, errors.WrapDatabaseError(err)
+	}
+	return token, nil
+}
+
+func (r *PersonalAccessTokenRepository) GetByHash(hash string) (model.PersonalAccessToken, error) {
+	var token model.PersonalAccessToken
+	err := r.db.Preload("User").Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return model.PersonalAccessToken{}, errors.WrapDatabaseErrorWith(err, errors.ErrInvalidAccessToken, nil)
+	}
+	return token, nil
+}
+
+// GetByUser devuelve los tokens no revocados del usuario, del más reciente al más antiguo
+func (r *PersonalAccessTokenRepository) GetByUser(userID uint) ([]model.PersonalAccessToken, error) {
+	var tokens []model.PersonalAccessToken
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).Order("created_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseError(err)
+	}
+	return tokens, nil
+}
+
+// Revoke revoca el token si pertenece al usuario y no estaba revocado. Devuelve
+// false en caso contrario
+func (r *PersonalAccessTokenRepository) Revoke(id, userID uint, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return false, errors.WrapDatabaseError(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// TouchLastUsed registra el último uso del token. Solo se escribe si el anterior
+// es más antiguo que precision, para no actualizar la fila en cada petición
+func (r *PersonalAccessTokenRepository) TouchLastUsed(id uint, usedAt time.Time, precision time.Duration) error {
+	err := r.db.Model(&model.PersonalAccessToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-precision)).
+		Update("last_used_at", usedAt).Error
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
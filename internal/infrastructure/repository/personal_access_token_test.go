@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPersonalAccessTokenRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPersonalAccessTokenRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "personal_access_tokens" \("user_id","name","token_hash","scopes","expires_at","last_used_at","revoked_at","created_at"\)`).
+		WithArgs(1, "ci", "hash", "posts:read posts:write", nil, nil, nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	token, err := repo.Create(model.PersonalAccessToken{
+		UserID:    1,
+		Name:      "ci",
+		TokenHash: "hash",
+		Scopes:    model.Scopes{model.ScopePostsRead, model.ScopePostsWrite},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), token.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPersonalAccessTokenRepository_GetByHash(t *testing.T) {
+	t.Run("success - token found with its user", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewPersonalAccessTokenRepository(db)
+
+		mock.ExpectQuery(`SELECT \* FROM "personal_access_tokens" WHERE token_hash = \$1`).
+			WithArgs("hash", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "token_hash", "scopes"}).AddRow(1, 2, "hash", "posts:read comments:write"))
+		mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1`).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(2, "editor"))
+
+		token, err := repo.GetByHash("hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Scopes{model.ScopePostsRead, model.ScopeCommentsWrite}, token.Scopes)
+		assert.Equal(t, model.RoleEditor, token.User.Role)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error - token not found", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewPersonalAccessTokenRepository(db)
+
+		mock.ExpectQuery(`SELECT \* FROM "personal_access_tokens"`).WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.GetByHash("hash")
+
+		assert.ErrorIs(t, err, errors.ErrInvalidAccessToken)
+	})
+}
+
+func TestPersonalAccessTokenRepository_GetByUser(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPersonalAccessTokenRepository(db)
+
+	mock.ExpectQuery(`SELECT \* FROM "personal_access_tokens" WHERE user_id = \$1 AND revoked_at IS NULL ORDER BY created_at DESC`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "scopes"}).AddRow(2, 1, "deploy", "posts:write").AddRow(1, 1, "ci", "posts:read"))
+
+	tokens, err := repo.GetByUser(1)
+
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+	assert.Equal(t, "deploy", tokens[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPersonalAccessTokenRepository_Revoke(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expected     bool
+	}{
+		{"success - token revoked", 1, true},
+		{"token of another user or already revoked", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPersonalAccessTokenRepository(db)
+			revokedAt := time.Now()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "personal_access_tokens" SET "revoked_at"=\$1 WHERE id = \$2 AND user_id = \$3 AND revoked_at IS NULL`).
+				WithArgs(revokedAt, 3, 1).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			mock.ExpectCommit()
+
+			revoked, err := repo.Revoke(3, 1, revokedAt)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, revoked)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPersonalAccessTokenRepository_TouchLastUsed(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPersonalAccessTokenRepository(db)
+	usedAt := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "personal_access_tokens" SET "last_used_at"=\$1 WHERE id = \$2 AND \(last_used_at IS NULL OR last_used_at < \$3\)`).
+		WithArgs(usedAt, 3, usedAt.Add(-time.Minute)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.TouchLastUsed(3, usedAt, time.Minute)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
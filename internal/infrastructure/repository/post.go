@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
+	"gorm.io/gorm"
+)
+
+type PostRepository struct {
+	db *gorm.DB
+}
+
+func NewPostRepository(db *gorm.DB) *PostRepository {
+	return &PostRepository{db}
+}
+
+// GetPublished devuelve una página de publicaciones publicadas según la especificación de consulta
+func (r *PostRepository) GetPublished(spec query.Spec) (query.Page[model.Post], error) {
+	db := r.db.Preload("Tags").Where("posts.status = ?", model.PostStatusPublished)
+	page, err := findPage[model.Post](db, spec)
+	if err != nil {
+		return query.Page[model.Post]{}, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	return page, nil
+}
+
+// GetPublishedByTag devuelve una página de las publicaciones publicadas que tienen la etiqueta
+func (r *PostRepository) GetPublishedByTag(tagID uint, spec query.Spec) (query.Page[model.Post], error) {
+	db := r.db.Preload("Tags").
+		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+		Where("post_tags.tag_id = ? AND posts.status = ?", tagID, model.PostStatusPublished)
+	page, err := findPage[model.Post](db, spec)
+	if err != nil {
+		return query.Page[model.Post]{}, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	return page, nil
+}
+
+// GetPublishedByCategories devuelve una página de las publicaciones publicadas de
+// cualquiera de las categorías
+func (r *PostRepository) GetPublishedByCategories(categoryIDs []uint, spec query.Spec) (query.Page[model.Post], error) {
+	db := r.db.Preload("Tags").
+		Where("posts.category_id IN ? AND posts.status = ?", categoryIDs, model.PostStatusPublished)
+	page, err := findPage[model.Post](db, spec)
+	if err != nil {
+		return query.Page[model.Post]{}, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	return page, nil
+}
+
+func (r *PostRepository) GetByAuthor(authorID uint) ([]model.Post, error) {
+	var posts []model.Post
+	err := r.db.Preload("Tags").Where("author_id = ?", authorID).Order("created_at DESC").Find(&posts).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	return posts, nil
+}
+
+func (r *PostRepository) GetByID(id uint) (model.Post, error) {
+	var post model.Post
+	err := r.db.Preload("Tags").First(&post, id).Error
+	if err != nil {
+		return model.Post{}, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	return post, nil
+}
+
+func (r *PostRepository) GetBySlug(slug string) (model.Post, error) {
+	var post model.Post
+	err := r.db.Preload("Tags").Where("slug = ?", slug).First(&post).Error
+	if err != nil {
+		return model.Post{}, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	return post, nil
+}
+
+// GetByPreviousSlug busca la publicación a la que perteneció un slug anterior
+func (r *PostRepository) GetByPreviousSlug(slug string) (model.Post, error) {
+	var post model.Post
+	err := r.db.Preload("Tags").Joins("JOIN post_slugs ON post_slugs.post_id = posts.id").
+		Where("post_slugs.slug = ?", slug).
+		First(&post).Error
+	if err != nil {
+		return model.Post{}, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	return post, nil
+}
+
+// SlugExists indica si el slug está en uso, actual o anterior, por otra publicación
+func (r *PostRepository) SlugExists(slug string, excludePostID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Post{}).Where("slug = ? AND id <> ?", slug, excludePostID).Count(&count).Error
+	if err != nil {
+		return false, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	err = r.db.Model(&model.PostSlug{}).Where("slug = ? AND post_id <> ?", slug, excludePostID).Count(&count).Error
+	if err != nil {
+		return false, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	return count > 0, nil
+}
+
+func (r *PostRepository) Create(post model.Post) (model.Post, error) {
+	err := r.db.Create(&post).Error
+	if err != nil {
+		return model.Post{}, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, errors.ErrSlugExists)
+	}
+	return post, nil
+}
+
+// Update guarda la publicación y, si el slug cambió, conserva el anterior en el historial.
+// Las etiquetas se reemplazan por las de la publicación recibida
+func (r *PostRepository) Update(post model.Post) (model.Post, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.Post
+		if err := tx.Select("id", "slug").First(&current, post.ID).Error; err != nil {
+			return err
+		}
+
+		if current.Slug != post.Slug {
+			if current.Slug != "" {
+				if err := tx.Create(&model.PostSlug{PostID: post.ID, Slug: current.Slug}).Error; err != nil {
+					return err
+				}
+			}
+			// Si la publicación recupera un slug anterior, deja de ser histórico
+			if err := tx.Where("post_id = ? AND slug = ?", post.ID, post.Slug).Delete(&model.PostSlug{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Omit("Tags").Save(&post).Error; err != nil {
+			return err
+		}
+
+		// nil conserva las etiquetas actuales; una lista vacía las elimina todas
+		if post.Tags != nil {
+			return tx.Model(&post).Association("Tags").Replace(post.Tags)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Post{}, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, errors.ErrSlugExists)
+	}
+	return post, nil
+}
+
+func (r *PostRepository) Delete(id uint) error {
+	err := r.db.Delete(&model.Post{}, id).Error
+	if err != nil {
+		return errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	return nil
+}
+
+// PublishDue publica las publicaciones programadas cuya fecha ya se cumplió
+func (r *PostRepository) PublishDue(now time.Time) (int64, error) {
+	result := r.db.Model(&model.Post{}).
+		Where("status = ? AND publish_at <= ?", model.PostStatusScheduled, now).
+		Updates(map[string]any{
+			"status":       model.PostStatusPublished,
+			"published_at": gorm.Expr("publish_at"),
+		})
+	if result.Error != nil {
+		return 0, errors.WrapDatabaseErrorWith(result.Error, errors.ErrPostNotFound, nil)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewPostRevisionRepository(db *gorm.DB) *PostRevisionRepository {
+	return &PostRevisionRepository{db}
+}
+
+func (r *PostRevisionRepository) GetByPost(postID uint) ([]model.PostRevision, error) {
+	var revisions []model.PostRevision
+	err := r.db.Where("post_id = ?", postID).Order("number DESC").Find(&revisions).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseErrorWith(err, errors.ErrRevisionNotFound, nil)
+	}
+	return revisions, nil
+}
+
+func (r *PostRevisionRepository) GetByNumber(postID uint, number int) (model.PostRevision, error) {
+	var revision model.PostRevision
+	err := r.db.Where("post_id = ? AND number = ?", postID, number).First(&revision).Error
+	if err != nil {
+		return model.PostRevision{}, errors.WrapDatabaseErrorWith(err, errors.ErrRevisionNotFound, nil)
+	}
+	return revision, nil
+}
+
+// Create guarda la revisión asignándole el siguiente número de la publicación
+func (r *PostRevisionRepository) Create(revision model.PostRevision) (model.PostRevision, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Bloquear la publicación para que dos ediciones simultáneas no reciban el mismo número
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&model.Post{}, revision.PostID).Error
+		if err != nil {
+			return err
+		}
+
+		var last int
+		err = tx.Model(&model.PostRevision{}).
+			Where("post_id = ?", revision.PostID).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+
+		revision.Number = last + 1
+		return tx.Create(&revision).Error
+	})
+	if err != nil {
+		return model.PostRevision{}, errors.WrapDatabaseErrorWith(err, errors.ErrPostNotFound, nil)
+	}
+	return revision, nil
+}
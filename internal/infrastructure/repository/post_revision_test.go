@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var revisionColumns = []string{"id", "post_id", "number", "title", "body", "editor_id", "created_at"}
+
+func TestNewPostRevisionRepository(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostRevisionRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, db, repo.db)
+}
+
+func TestPostRevisionRepository_GetByPost(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostRevisionRepository(db)
+
+	rows := sqlmock.NewRows(revisionColumns).
+		AddRow(2, 1, 2, "Title v2", "Body v2", 1, time.Now()).
+		AddRow(1, 1, 1, "Title", "Body", 1, time.Now())
+	mock.ExpectQuery(`SELECT \* FROM "post_revisions" WHERE post_id = \$1 ORDER BY number DESC`).
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	revisions, err := repo.GetByPost(1)
+
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Number)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRevisionRepository_GetByNumber(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "success - revision found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(revisionColumns).AddRow(1, 1, 1, "Title", "Body", 1, time.Now())
+				mock.ExpectQuery(`SELECT \* FROM "post_revisions" WHERE post_id = \$1 AND number = \$2`).
+					WithArgs(1, 1, 1).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "error - revision not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT \* FROM "post_revisions"`).WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expectedError: errors.ErrRevisionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPostRevisionRepository(db)
+			tt.setupMock(mock)
+
+			revision, err := repo.GetByNumber(1, 1)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, revision.Number)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostRevisionRepository_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(sqlmock.Sqlmock)
+		expectedNumber int
+		expectedError  error
+	}{
+		{
+			name: "success - assigns the next number",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT "id" FROM "posts" WHERE "posts"."id" = \$1 .* FOR UPDATE`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(`SELECT COALESCE\(MAX\(number\), 0\) FROM "post_revisions" WHERE post_id = \$1`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(4))
+				mock.ExpectQuery(`INSERT INTO "post_revisions"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+				mock.ExpectCommit()
+			},
+			expectedNumber: 5,
+		},
+		{
+			name: "error - post does not exist",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT "id" FROM "posts"`).WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectRollback()
+			},
+			expectedError: errors.ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPostRevisionRepository(db)
+			tt.setupMock(mock)
+
+			revision, err := repo.Create(model.PostRevision{PostID: 1, Title: "Title", Body: "Body", EditorID: 1})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedNumber, revision.Number)
+				assert.Equal(t, uint(10), revision.ID)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
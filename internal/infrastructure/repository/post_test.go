@@ -0,0 +1,597 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var postColumns = []string{"id", "title", "slug", "body", "excerpt", "status", "publish_at", "published_at", "author_id", "created_at", "updated_at"}
+
+func TestNewPostRepository(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, db, repo.db)
+}
+
+// expectTagPreload simula la carga de las etiquetas de publicaciones sin etiquetas
+func expectTagPreload(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT \* FROM "post_tags" WHERE "post_tags"."post_id" (=|IN)`).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+}
+
+func TestPostRepository_GetPublished(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedCount int
+		expectedError error
+	}{
+		{
+			name: "success - returns posts",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(postColumns).
+					AddRow(2, "Second", "second", "Body 2", "", "published", nil, time.Now(), 1, time.Now(), time.Now()).
+					AddRow(1, "First", "first", "Body 1", "", "published", nil, time.Now(), 1, time.Now(), time.Now())
+				mock.ExpectQuery(`SELECT \* FROM "posts" WHERE posts.status = \$1 ORDER BY posts.published_at DESC,posts.id DESC LIMIT \$2`).
+					WithArgs(model.PostStatusPublished, 21).
+					WillReturnRows(rows)
+				expectTagPreload(mock)
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "error - database error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT \* FROM "posts"`).WillReturnError(sql.ErrConnDone)
+			},
+			expectedError: errors.ErrDatabaseOperation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPostRepository(db)
+			tt.setupMock(mock)
+
+			page, err := repo.GetPublished(query.Spec{
+				Limit:   20,
+				SortKey: "-published_at",
+				Sort:    query.Field{Column: "published_at", Kind: query.KindTime},
+				Desc:    true,
+			})
+			posts := page.Data
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, posts)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, posts, tt.expectedCount)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostRepository_GetPublished_NextCursor(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	publishedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(postColumns).
+		AddRow(3, "Third", "third", "Body 3", "", "published", nil, publishedAt, 1, time.Now(), time.Now()).
+		AddRow(2, "Second", "second", "Body 2", "", "published", nil, publishedAt.Add(-time.Hour), 1, time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT \* FROM "posts" WHERE posts.status = \$1 ORDER BY posts.published_at DESC,posts.id DESC LIMIT \$2`).
+		WithArgs(model.PostStatusPublished, 2).
+		WillReturnRows(rows)
+	expectTagPreload(mock)
+
+	schema := query.Schema{
+		Sorts:       map[string]query.Field{"published_at": {Column: "published_at", Kind: query.KindTime}},
+		DefaultSort: "-published_at",
+	}
+	spec, err := schema.Parse(url.Values{"limit": {"1"}})
+	assert.NoError(t, err)
+
+	page, err := NewPostRepository(db).GetPublished(spec)
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Data, 1)
+	assert.Empty(t, page.PrevCursor)
+
+	next, err := schema.Parse(url.Values{"cursor": {page.NextCursor}})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), next.Cursor.ID)
+	assert.Equal(t, publishedAt, next.Cursor.Value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRepository_GetPublishedByTag(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostRepository(db)
+
+	rows := sqlmock.NewRows(postColumns).
+		AddRow(1, "Hola", "hola", "Body", "", "published", nil, time.Now(), 1, time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT "posts"."id",.* FROM "posts" JOIN post_tags ON post_tags.post_id = posts.id WHERE post_tags.tag_id = \$1 AND posts.status = \$2 ORDER BY posts.published_at DESC,posts.id DESC LIMIT \$3`).
+		WithArgs(3, model.PostStatusPublished, 11).
+		WillReturnRows(rows)
+	expectTagPreload(mock)
+
+	page, err := repo.GetPublishedByTag(3, query.Spec{Sort: query.Field{Column: "published_at"}, Desc: true, Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Data, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRepository_GetPublishedByCategories(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostRepository(db)
+
+	rows := sqlmock.NewRows(postColumns).
+		AddRow(1, "Hola", "hola", "Body", "", "published", nil, time.Now(), 1, time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT \* FROM "posts" WHERE posts.category_id IN \(\$1,\$2\) AND posts.status = \$3 ORDER BY posts.published_at DESC,posts.id DESC LIMIT \$4`).
+		WithArgs(1, 2, model.PostStatusPublished, 11).
+		WillReturnRows(rows)
+	expectTagPreload(mock)
+
+	page, err := repo.GetPublishedByCategories([]uint{1, 2}, query.Spec{Sort: query.Field{Column: "published_at"}, Desc: true, Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Data, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRepository_GetByID(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedPost  model.Post
+		expectedError error
+	}{
+		{
+			name: "success - post found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(postColumns).
+					AddRow(1, "Title", "title", "Body", "Excerpt", "draft", nil, nil, 7, time.Now(), time.Now())
+				mock.ExpectQuery(`SELECT \* FROM "posts" WHERE "posts"."id" = \$1`).WillReturnRows(rows)
+				expectTagPreload(mock)
+			},
+			expectedPost: model.Post{ID: 1, Title: "Title", Body: "Body", Excerpt: "Excerpt", AuthorID: 7},
+		},
+		{
+			name: "error - post not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT \* FROM "posts" WHERE "posts"."id" = \$1`).WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expectedError: errors.ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPostRepository(db)
+			tt.setupMock(mock)
+
+			post, err := repo.GetByID(1)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Equal(t, model.Post{}, post)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedPost.ID, post.ID)
+				assert.Equal(t, tt.expectedPost.Title, post.Title)
+				assert.Equal(t, tt.expectedPost.AuthorID, post.AuthorID)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostRepository_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedID    uint
+		expectedError error
+	}{
+		{
+			name: "success - post created",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "posts"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectCommit()
+			},
+			expectedID: 5,
+		},
+		{
+			name: "error - author does not exist",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "posts"`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			expectedError: errors.ErrDatabaseOperation,
+		},
+		{
+			name: "error - slug already in use",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "posts"`).
+					WillReturnError(fmt.Errorf(`ERROR: duplicate key value violates unique constraint "idx_posts_slug"`))
+				mock.ExpectRollback()
+			},
+			expectedError: errors.ErrSlugExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPostRepository(db)
+			tt.setupMock(mock)
+
+			post, err := repo.Create(model.Post{Title: "Title", Body: "Body", AuthorID: 1})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedID, post.ID)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostRepository_Update(t *testing.T) {
+	tests := []struct {
+		name          string
+		post          model.Post
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "success - same slug does not touch the history",
+			post: model.Post{ID: 1, Title: "Updated", Slug: "title", Body: "Body", AuthorID: 1},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT "id","slug" FROM "posts" WHERE "posts"."id" = \$1`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "slug"}).AddRow(1, "title"))
+				mock.ExpectExec(`UPDATE "posts" SET`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "success - changed slug is stored in the history",
+			post: model.Post{ID: 1, Title: "Updated", Slug: "updated", Body: "Body", AuthorID: 1},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT "id","slug" FROM "posts" WHERE "posts"."id" = \$1`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "slug"}).AddRow(1, "title"))
+				mock.ExpectQuery(`INSERT INTO "post_slugs"`).
+					WithArgs(1, "title", sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(`DELETE FROM "post_slugs" WHERE post_id = \$1 AND slug = \$2`).
+					WithArgs(1, "updated").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`UPDATE "posts" SET`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "success - empty tag list clears the tags",
+			post: model.Post{ID: 1, Title: "Updated", Slug: "title", Body: "Body", AuthorID: 1, Tags: []model.Tag{}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT "id","slug" FROM "posts" WHERE "posts"."id" = \$1`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "slug"}).AddRow(1, "title"))
+				mock.ExpectExec(`UPDATE "posts" SET`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`UPDATE "posts" SET "updated_at"=\$1 WHERE "id" = \$2`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`DELETE FROM "post_tags" WHERE "post_tags"."post_id" = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error - post not found",
+			post: model.Post{ID: 9, Title: "Updated", Slug: "updated"},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT "id","slug" FROM "posts"`).WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectRollback()
+			},
+			expectedError: errors.ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPostRepository(db)
+			tt.setupMock(mock)
+
+			post, err := repo.Update(tt.post)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.post.Slug, post.Slug)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostRepository_GetBySlug(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostRepository(db)
+
+	rows := sqlmock.NewRows(postColumns).
+		AddRow(1, "Hola", "hola", "Body", "", "published", nil, time.Now(), 1, time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT \* FROM "posts" WHERE slug = \$1`).WithArgs("hola", 1).WillReturnRows(rows)
+	expectTagPreload(mock)
+
+	post, err := repo.GetBySlug("hola")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hola", post.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRepository_GetByPreviousSlug(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "success - previous slug found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(postColumns).
+					AddRow(1, "Hola mundo", "hola-mundo", "Body", "", "published", nil, time.Now(), 1, time.Now(), time.Now())
+				mock.ExpectQuery(`SELECT "posts"."id",.* FROM "posts" JOIN post_slugs ON post_slugs.post_id = posts.id WHERE post_slugs.slug = \$1`).
+					WithArgs("hola", 1).
+					WillReturnRows(rows)
+				expectTagPreload(mock)
+			},
+		},
+		{
+			name: "error - unknown slug",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`JOIN post_slugs`).WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expectedError: errors.ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPostRepository(db)
+			tt.setupMock(mock)
+
+			post, err := repo.GetByPreviousSlug("hola")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "hola-mundo", post.Slug)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostRepository_SlugExists(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		expected  bool
+	}{
+		{
+			name: "used by another post",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "posts" WHERE slug = \$1 AND id <> \$2`).
+					WithArgs("hola", 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			expected: true,
+		},
+		{
+			name: "used in the history of another post",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "posts"`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "post_slugs" WHERE slug = \$1 AND post_id <> \$2`).
+					WithArgs("hola", 3).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			expected: true,
+		},
+		{
+			name: "free slug",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "posts"`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "post_slugs"`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPostRepository(db)
+			tt.setupMock(mock)
+
+			exists, err := repo.SlugExists("hola", 3)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, exists)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostRepository_Delete(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "success - post deleted",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`DELETE FROM "posts" WHERE "posts"."id" = \$1`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error - database error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`DELETE FROM "posts"`).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedError: errors.ErrDatabaseOperation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPostRepository(db)
+			tt.setupMock(mock)
+
+			err := repo.Delete(1)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostRepository_GetByAuthor(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPostRepository(db)
+
+	rows := sqlmock.NewRows(postColumns).
+		AddRow(1, "Draft", "draft", "Body", "", "draft", nil, nil, 7, time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT \* FROM "posts" WHERE author_id = \$1 ORDER BY created_at DESC`).
+		WithArgs(7).
+		WillReturnRows(rows)
+	expectTagPreload(mock)
+
+	posts, err := repo.GetByAuthor(7)
+
+	assert.NoError(t, err)
+	assert.Len(t, posts, 1)
+	assert.Equal(t, model.PostStatusDraft, posts[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRepository_PublishDue(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedCount int64
+		expectedError error
+	}{
+		{
+			name: "success - publishes due posts",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "posts" SET "published_at"=publish_at,"status"=\$1,"updated_at"=\$2 WHERE status = \$3 AND publish_at <= \$4`).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "error - database error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "posts"`).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedError: errors.ErrDatabaseOperation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewPostRepository(db)
+			tt.setupMock(mock)
+
+			count, err := repo.PublishDue(time.Now())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedCount, count)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
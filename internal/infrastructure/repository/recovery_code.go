@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db}
+}
+
+// Replace sustituye todos los códigos del usuario por los indicados
+func (r *RecoveryCodeRepository) Replace(userID uint, codes []model.RecoveryCode) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
+
+// Use marca como usado el código del usuario con el hash indicado. Devuelve false
+// si no existe o ya se había usado
+func (r *RecoveryCodeRepository) Use(userID uint, hash string, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, errors.WrapDatabaseError(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *RecoveryCodeRepository) DeleteByUser(userID uint) error {
+	err := r.db.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
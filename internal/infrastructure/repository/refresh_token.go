@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db}
+}
+
+func (r *RefreshTokenRepository) Create(token model.RefreshToken) (model.RefreshToken, error) {
+	err := r.db.Create(&token).Error
+	if err != nil {
+		return model.RefreshToken{}, errors.WrapDatabaseError(err)
+	}
+	return token, nil
+}
+
+func (r *RefreshTokenRepository) GetByHash(hash string) (model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return model.RefreshToken{}, errors.WrapDatabaseErrorWith(err, errors.ErrInvalidRefreshToken, nil)
+	}
+	return token, nil
+}
+
+// MarkUsed marca el token como canjeado solo si seguía activo. Devuelve false si
+// otra petición lo canjeó o revocó antes, lo que permite detectar la reutilización
+// incluso con peticiones concurrentes
+func (r *RefreshTokenRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, errors.WrapDatabaseError(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeFamily revoca todos los tokens de la familia que sigan sin revocar
+func (r *RefreshTokenRepository) RevokeFamily(familyID string, revokedAt time.Time) error {
+	err := r.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", revokedAt).Error
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
+
+// RevokeByUser revoca todos los tokens del usuario que sigan sin revocar, lo que
+// cierra todas sus sesiones
+func (r *RefreshTokenRepository) RevokeByUser(userID uint, revokedAt time.Time) error {
+	err := r.db.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRefreshTokenRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRefreshTokenRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "refresh_tokens"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	token, err := repo.Create(model.RefreshToken{UserID: 1, FamilyID: "family", TokenHash: "hash", ExpiresAt: time.Now()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), token.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepository_GetByHash(t *testing.T) {
+	t.Run("success - token found", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewRefreshTokenRepository(db)
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "family_id", "token_hash"}).AddRow(1, 2, "family", "hash")
+		mock.ExpectQuery(`SELECT \* FROM "refresh_tokens" WHERE token_hash = \$1`).
+			WithArgs("hash", 1).
+			WillReturnRows(rows)
+
+		token, err := repo.GetByHash("hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), token.UserID)
+		assert.Equal(t, "family", token.FamilyID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error - token not found", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewRefreshTokenRepository(db)
+
+		mock.ExpectQuery(`SELECT \* FROM "refresh_tokens"`).WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.GetByHash("hash")
+
+		assert.ErrorIs(t, err, errors.ErrInvalidRefreshToken)
+	})
+}
+
+func TestRefreshTokenRepository_MarkUsed(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expected     bool
+	}{
+		{"success - token was active", 1, true},
+		{"token already used or revoked", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewRefreshTokenRepository(db)
+			usedAt := time.Now()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "refresh_tokens" SET "used_at"=\$1 WHERE id = \$2 AND used_at IS NULL AND revoked_at IS NULL`).
+				WithArgs(usedAt, 1).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			mock.ExpectCommit()
+
+			marked, err := repo.MarkUsed(1, usedAt)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, marked)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenRepository_RevokeFamily(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRefreshTokenRepository(db)
+	revokedAt := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "refresh_tokens" SET "revoked_at"=\$1 WHERE family_id = \$2 AND revoked_at IS NULL`).
+		WithArgs(revokedAt, "family").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	err := repo.RevokeFamily("family", revokedAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepository_RevokeByUser(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRefreshTokenRepository(db)
+	revokedAt := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "refresh_tokens" SET "revoked_at"=\$1 WHERE user_id = \$2 AND revoked_at IS NULL`).
+		WithArgs(revokedAt, 1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := repo.RevokeByUser(1, revokedAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
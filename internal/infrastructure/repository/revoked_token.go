@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevokedTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRevokedTokenRepository(db *gorm.DB) *RevokedTokenRepository {
+	return &RevokedTokenRepository{db}
+}
+
+// Create registra la revocación. Revocar dos veces el mismo token no es un error
+func (r *RevokedTokenRepository) Create(token model.RevokedToken) error {
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&token).Error
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
+
+func (r *RevokedTokenRepository) IsRevoked(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	if err != nil {
+		return false, errors.WrapDatabaseError(err)
+	}
+	return count > 0, nil
+}
+
+// GetActive devuelve las revocaciones de tokens que todavía no expiraron
+func (r *RevokedTokenRepository) GetActive(now time.Time) ([]model.RevokedToken, error) {
+	var tokens []model.RevokedToken
+	err := r.db.Where("expires_at > ?", now).Find(&tokens).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseError(err)
+	}
+	return tokens, nil
+}
+
+// GetCreatedSince devuelve las revocaciones registradas después de since que
+// todavía no expiraron
+func (r *RevokedTokenRepository) GetCreatedSince(since, now time.Time) ([]model.RevokedToken, error) {
+	var tokens []model.RevokedToken
+	err := r.db.Where("created_at > ? AND expires_at > ?", since, now).Find(&tokens).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseError(err)
+	}
+	return tokens, nil
+}
+
+// DeleteExpired elimina las revocaciones de tokens que ya expiraron, ya que esos
+// tokens se rechazan de todos modos por su claim exp
+func (r *RevokedTokenRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&model.RevokedToken{})
+	if result.Error != nil {
+		return 0, errors.WrapDatabaseError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// cacheRefreshOverlap es el margen con el que Refresh vuelve a leer las
+// revocaciones anteriores a la última recarga, para no perder las registradas
+// por instancias con el reloj algo atrasado o cuya transacción confirmó tarde
+const cacheRefreshOverlap = time.Minute
+
+// CachedRevokedTokenRepository mantiene en memoria las revocaciones vigentes
+// para que el middleware de autenticación no consulte la base de datos en cada
+// petición. La base de datos conserva las revocaciones entre reinicios: la caché
+// se precarga con Warm al iniciar y cada revocación se escribe en ambas. Las
+// revocaciones de otras instancias de la API se incorporan con Refresh, que debe
+// llamarse periódicamente
+type CachedRevokedTokenRepository struct {
+	repo     *RevokedTokenRepository
+	mu       sync.RWMutex
+	revoked  map[string]time.Time
+	syncedAt time.Time
+}
+
+func NewCachedRevokedTokenRepository(repo *RevokedTokenRepository) *CachedRevokedTokenRepository {
+	return &CachedRevokedTokenRepository{repo: repo, revoked: make(map[string]time.Time)}
+}
+
+// Warm carga en la caché las revocaciones que siguen vigentes
+func (r *CachedRevokedTokenRepository) Warm(now time.Time) error {
+	tokens, err := r.repo.GetActive(now)
+	if err != nil {
+		return err
+	}
+	r.store(tokens, now)
+	return nil
+}
+
+// Refresh carga en la caché las revocaciones registradas desde la recarga
+// anterior, incluidas las de otras instancias
+func (r *CachedRevokedTokenRepository) Refresh(now time.Time) error {
+	r.mu.RLock()
+	since := r.syncedAt.Add(-cacheRefreshOverlap)
+	r.mu.RUnlock()
+
+	tokens, err := r.repo.GetCreatedSince(since, now)
+	if err != nil {
+		return err
+	}
+	r.store(tokens, now)
+	return nil
+}
+
+// store guarda las revocaciones en la caché y anota el momento de la recarga
+func (r *CachedRevokedTokenRepository) store(tokens []model.RevokedToken, syncedAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range tokens {
+		r.revoked[token.JTI] = token.ExpiresAt
+	}
+	r.syncedAt = syncedAt
+}
+
+func (r *CachedRevokedTokenRepository) Create(token model.RevokedToken) error {
+	if err := r.repo.Create(token); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revoked[token.JTI] = token.ExpiresAt
+	return nil
+}
+
+func (r *CachedRevokedTokenRepository) IsRevoked(jti string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.revoked[jti]
+	return ok, nil
+}
+
+func (r *CachedRevokedTokenRepository) DeleteExpired(now time.Time) (int64, error) {
+	deleted, err := r.repo.DeleteExpired(now)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for jti, expiresAt := range r.revoked {
+		if !expiresAt.After(now) {
+			delete(r.revoked, jti)
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevokedTokenRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRevokedTokenRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "revoked_tokens" .* ON CONFLICT DO NOTHING`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := repo.Create(model.RevokedToken{JTI: "jti-1", UserID: 1, ExpiresAt: time.Now()})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevokedTokenRepository_IsRevoked(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRevokedTokenRepository(db)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "revoked_tokens" WHERE jti = \$1`).
+		WithArgs("jti-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	revoked, err := repo.IsRevoked("jti-1")
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevokedTokenRepository_DeleteExpired(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRevokedTokenRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "revoked_tokens" WHERE expires_at <= \$1`).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	deleted, err := repo.DeleteExpired(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedRevokedTokenRepository(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCachedRevokedTokenRepository(NewRevokedTokenRepository(db))
+	now := time.Now()
+
+	// Warm carga las revocaciones vigentes
+	mock.ExpectQuery(`SELECT \* FROM "revoked_tokens" WHERE expires_at > \$1`).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "jti", "user_id", "expires_at"}).
+			AddRow(1, "warm", 1, now.Add(time.Minute)))
+	assert.NoError(t, repo.Warm(now))
+
+	// Create escribe en la base de datos y en la caché
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "revoked_tokens"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.Create(model.RevokedToken{JTI: "new", UserID: 1, ExpiresAt: now.Add(time.Hour)}))
+
+	// Las consultas se resuelven sin acceder a la base de datos
+	for jti, expected := range map[string]bool{"warm": true, "new": true, "unknown": false} {
+		revoked, err := repo.IsRevoked(jti)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, revoked, jti)
+	}
+
+	// La purga elimina de la caché las revocaciones expiradas
+	later := now.Add(30 * time.Minute)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "revoked_tokens"`).
+		WithArgs(later).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	deleted, err := repo.DeleteExpired(later)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	revoked, _ := repo.IsRevoked("warm")
+	assert.False(t, revoked)
+	revoked, _ = repo.IsRevoked("new")
+	assert.True(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedRevokedTokenRepository_Refresh(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCachedRevokedTokenRepository(NewRevokedTokenRepository(db))
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT \* FROM "revoked_tokens" WHERE expires_at > \$1`).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "jti", "user_id", "expires_at"}))
+	assert.NoError(t, repo.Warm(now))
+
+	// Una revocación registrada por otra instancia se ve tras la recarga
+	later := now.Add(10 * time.Second)
+	mock.ExpectQuery(`SELECT \* FROM "revoked_tokens" WHERE created_at > \$1 AND expires_at > \$2`).
+		WithArgs(now.Add(-cacheRefreshOverlap), later).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "jti", "user_id", "expires_at"}).
+			AddRow(1, "remote", 1, later.Add(time.Minute)))
+
+	revoked, _ := repo.IsRevoked("remote")
+	assert.False(t, revoked)
+	assert.NoError(t, repo.Refresh(later))
+	revoked, _ = repo.IsRevoked("remote")
+	assert.True(t, revoked)
+
+	// La siguiente recarga parte de la anterior
+	mock.ExpectQuery(`SELECT \* FROM "revoked_tokens" WHERE created_at > \$1 AND expires_at > \$2`).
+		WithArgs(later.Add(-cacheRefreshOverlap), later.Add(10*time.Second)).
+		WillReturnError(sqlmock.ErrCancelled)
+	assert.Error(t, repo.Refresh(later.Add(10*time.Second)))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
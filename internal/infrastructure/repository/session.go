@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db}
+}
+
+func (r *SessionRepository) Create(session model.Session) (model.Session, error) {
+	err := r.db.Create(&session).Error
+	if err != nil {
+		return model.Session{}, errors.WrapDatabaseError(err)
+	}
+	return session, nil
+}
+
+func (r *SessionRepository) GetByFamily(familyID string) (model.Session, error) {
+	var session model.Session
+	err := r.db.Where("family_id = ?", familyID).First(&session).Error
+	if err != nil {
+		return model.Session{}, errors.WrapDatabaseErrorWith(err, errors.ErrSessionNotFound, nil)
+	}
+	return session, nil
+}
+
+// GetActiveByUser devuelve las sesiones del usuario que siguen abiertas. Una
+// sesión cuya familia de tokens de renovación fue revocada o expiró ya no se
+// puede renovar, por lo que no se muestra aunque no se haya revocado
+func (r *SessionRepository) GetActiveByUser(userID uint, now time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Where("EXISTS (?)", r.db.Model(&model.RefreshToken{}).
+			Select("1").
+			Where("refresh_tokens.family_id = sessions.family_id AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", now)).
+		Order("last_seen_at DESC, id DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseError(err)
+	}
+	return sessions, nil
+}
+
+// TouchLastSeen registra la última actividad de la sesión y el dispositivo
+// desde el que se produjo
+func (r *SessionRepository) TouchLastSeen(id uint, ip, userAgent string, seenAt time.Time) error {
+	err := r.db.Model(&model.Session{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"ip": ip, "user_agent": userAgent, "last_seen_at": seenAt}).Error
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
+
+// Revoke revoca la sesión solo si pertenece al usuario y seguía sin revocar,
+// junto con su familia de tokens de renovación
+func (r *SessionRepository) Revoke(id, userID uint, revokedAt time.Time) (bool, error) {
+	var sessions []model.Session
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&sessions).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "family_id"}}}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+			Update("revoked_at", revokedAt).Error
+		if err != nil {
+			return err
+		}
+		return revokeFamilies(tx, sessions, revokedAt)
+	})
+	if err != nil {
+		return false, errors.WrapDatabaseError(err)
+	}
+	return len(sessions) == 1, nil
+}
+
+// RevokeByUser revoca todas las sesiones del usuario salvo exceptID, junto con
+// sus familias de tokens de renovación. Con exceptID 0 se revocan todas
+func (r *SessionRepository) RevokeByUser(userID, exceptID uint, revokedAt time.Time) ([]uint, error) {
+	var sessions []model.Session
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&sessions).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "family_id"}}}).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, exceptID).
+			Update("revoked_at", revokedAt).Error
+		if err != nil {
+			return err
+		}
+		return revokeFamilies(tx, sessions, revokedAt)
+	})
+	if err != nil {
+		return nil, errors.WrapDatabaseError(err)
+	}
+
+	ids := make([]uint, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID
+	}
+	return ids, nil
+}
+
+// revokeFamilies revoca los tokens de renovación de las sesiones. Sin esto, un
+// token de una sesión revocada seguiría siendo válido una vez que Purge
+// eliminara la sesión
+func revokeFamilies(tx *gorm.DB, sessions []model.Session, revokedAt time.Time) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+	families := make([]string, len(sessions))
+	for i, session := range sessions {
+		families[i] = session.FamilyID
+	}
+	return tx.Model(&model.RefreshToken{}).
+		Where("family_id IN ? AND revoked_at IS NULL", families).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *SessionRepository) IsRevoked(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Session{}).Where("id = ? AND revoked_at IS NOT NULL", id).Count(&count).Error
+	if err != nil {
+		return false, errors.WrapDatabaseError(err)
+	}
+	return count > 0, nil
+}
+
+// GetRevokedSince devuelve las sesiones revocadas después de since
+func (r *SessionRepository) GetRevokedSince(since time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.Where("revoked_at > ?", since).Find(&sessions).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseError(err)
+	}
+	return sessions, nil
+}
+
+// DeleteInactive elimina las sesiones sin actividad desde lastSeenBefore y las
+// revocadas antes de revokedBefore. Los tokens de esas sesiones ya expiraron,
+// por lo que no es necesario conservarlas
+func (r *SessionRepository) DeleteInactive(lastSeenBefore, revokedBefore time.Time) (int64, error) {
+	result := r.db.Where("last_seen_at < ? OR revoked_at < ?", lastSeenBefore, revokedBefore).Delete(&model.Session{})
+	if result.Error != nil {
+		return 0, errors.WrapDatabaseError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// CachedSessionRepository mantiene en memoria las sesiones revocadas recientemente
+// para que el middleware de autenticación no consulte la base de datos en cada
+// petición. Igual que CachedRevokedTokenRepository, la caché se precarga con
+// Warm al iniciar, cada revocación se escribe en la base de datos y en memoria y
+// las de otras instancias se incorporan llamando periódicamente a Refresh
+type CachedSessionRepository struct {
+	repo     *SessionRepository
+	mu       sync.RWMutex
+	revoked  map[uint]time.Time
+	syncedAt time.Time
+}
+
+func NewCachedSessionRepository(repo *SessionRepository) *CachedSessionRepository {
+	return &CachedSessionRepository{repo: repo, revoked: make(map[uint]time.Time)}
+}
+
+// Warm carga en la caché las sesiones revocadas después de since, cuyos tokens
+// de acceso pueden seguir sin expirar. now es el momento de la carga, desde el
+// que continúa Refresh
+func (r *CachedSessionRepository) Warm(since, now time.Time) error {
+	sessions, err := r.repo.GetRevokedSince(since)
+	if err != nil {
+		return err
+	}
+	r.store(sessions, now)
+	return nil
+}
+
+// Refresh carga en la caché las sesiones revocadas desde la recarga anterior,
+// incluidas las que revocaron otras instancias
+func (r *CachedSessionRepository) Refresh(now time.Time) error {
+	r.mu.RLock()
+	since := r.syncedAt.Add(-cacheRefreshOverlap)
+	r.mu.RUnlock()
+
+	sessions, err := r.repo.GetRevokedSince(since)
+	if err != nil {
+		return err
+	}
+	r.store(sessions, now)
+	return nil
+}
+
+// store guarda las sesiones revocadas en la caché y anota el momento de la recarga
+func (r *CachedSessionRepository) store(sessions []model.Session, syncedAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, session := range sessions {
+		r.revoked[session.ID] = *session.RevokedAt
+	}
+	r.syncedAt = syncedAt
+}
+
+func (r *CachedSessionRepository) Create(session model.Session) (model.Session, error) {
+	return r.repo.Create(session)
+}
+
+func (r *CachedSessionRepository) GetByFamily(familyID string) (model.Session, error) {
+	return r.repo.GetByFamily(familyID)
+}
+
+func (r *CachedSessionRepository) GetActiveByUser(userID uint, now time.Time) ([]model.Session, error) {
+	return r.repo.GetActiveByUser(userID, now)
+}
+
+func (r *CachedSessionRepository) TouchLastSeen(id uint, ip, userAgent string, seenAt time.Time) error {
+	return r.repo.TouchLastSeen(id, ip, userAgent, seenAt)
+}
+
+func (r *CachedSessionRepository) Revoke(id, userID uint, revokedAt time.Time) (bool, error) {
+	revoked, err := r.repo.Revoke(id, userID, revokedAt)
+	if err != nil || !revoked {
+		return revoked, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revoked[id] = revokedAt
+	return true, nil
+}
+
+func (r *CachedSessionRepository) RevokeByUser(userID, exceptID uint, revokedAt time.Time) ([]uint, error) {
+	ids, err := r.repo.RevokeByUser(userID, exceptID, revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		r.revoked[id] = revokedAt
+	}
+	return ids, nil
+}
+
+func (r *CachedSessionRepository) IsRevoked(id uint) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.revoked[id]
+	return ok, nil
+}
+
+func (r *CachedSessionRepository) DeleteInactive(lastSeenBefore, revokedBefore time.Time) (int64, error) {
+	deleted, err := r.repo.DeleteInactive(lastSeenBefore, revokedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, revokedAt := range r.revoked {
+		if revokedAt.Before(revokedBefore) {
+			delete(r.revoked, id)
+		}
+	}
+	return deleted, nil
+}
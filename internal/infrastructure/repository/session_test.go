@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSessionRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSessionRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "sessions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	session, err := repo.Create(model.Session{UserID: 1, FamilyID: "family", IP: "10.0.0.1", LastSeenAt: time.Now()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), session.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_GetByFamily(t *testing.T) {
+	t.Run("success - session found", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewSessionRepository(db)
+
+		mock.ExpectQuery(`SELECT \* FROM "sessions" WHERE family_id = \$1`).
+			WithArgs("family", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "family_id"}).AddRow(3, 1, "family"))
+
+		session, err := repo.GetByFamily("family")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), session.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error - session not found", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewSessionRepository(db)
+
+		mock.ExpectQuery(`SELECT \* FROM "sessions"`).WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.GetByFamily("family")
+
+		assert.ErrorIs(t, err, errors.ErrSessionNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSessionRepository_GetActiveByUser(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSessionRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT \* FROM "sessions" WHERE \(user_id = \$1 AND revoked_at IS NULL\) AND EXISTS \(SELECT 1 FROM "refresh_tokens" WHERE refresh_tokens.family_id = sessions.family_id AND used_at IS NULL AND revoked_at IS NULL AND expires_at > \$2\) ORDER BY last_seen_at DESC, id DESC`).
+		WithArgs(1, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "user_agent"}).
+			AddRow(2, 1, "Firefox").
+			AddRow(1, 1, "curl"))
+
+	sessions, err := repo.GetActiveByUser(1, now)
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, "Firefox", sessions[0].UserAgent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_TouchLastSeen(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSessionRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "sessions" SET "ip"=\$1,"last_seen_at"=\$2,"user_agent"=\$3 WHERE id = \$4`).
+		WithArgs("10.0.0.2", now, "Firefox", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.TouchLastSeen(1, "10.0.0.2", "Firefox", now)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_Revoke(t *testing.T) {
+	t.Run("success - session revoked", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewSessionRepository(db)
+		now := time.Now()
+
+		// La sesión y su familia de tokens se revocan en la misma transacción
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE "sessions" SET "revoked_at"=\$1 WHERE id = \$2 AND user_id = \$3 AND revoked_at IS NULL RETURNING "id","family_id"`).
+			WithArgs(now, 2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "family_id"}).AddRow(2, "family"))
+		mock.ExpectExec(`UPDATE "refresh_tokens" SET "revoked_at"=\$1 WHERE family_id IN \(\$2\) AND revoked_at IS NULL`).
+			WithArgs(now, "family").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		revoked, err := repo.Revoke(2, 1, now)
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not revoked - session of another user or already revoked", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewSessionRepository(db)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE "sessions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "family_id"}))
+		mock.ExpectCommit()
+
+		revoked, err := repo.Revoke(2, 1, now)
+
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error - tokens cannot be revoked", func(t *testing.T) {
+		db, mock, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		repo := NewSessionRepository(db)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE "sessions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "family_id"}).AddRow(2, "family"))
+		mock.ExpectExec(`UPDATE "refresh_tokens"`).
+			WillReturnError(sqlmock.ErrCancelled)
+		mock.ExpectRollback()
+
+		revoked, err := repo.Revoke(2, 1, now)
+
+		assert.Error(t, err)
+		assert.False(t, revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSessionRepository_RevokeByUser(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSessionRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE "sessions" SET "revoked_at"=\$1 WHERE user_id = \$2 AND id <> \$3 AND revoked_at IS NULL RETURNING "id","family_id"`).
+		WithArgs(now, 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "family_id"}).AddRow(3, "family-3").AddRow(4, "family-4"))
+	mock.ExpectExec(`UPDATE "refresh_tokens" SET "revoked_at"=\$1 WHERE family_id IN \(\$2,\$3\) AND revoked_at IS NULL`).
+		WithArgs(now, "family-3", "family-4").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	ids, err := repo.RevokeByUser(1, 2, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{3, 4}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_IsRevoked(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSessionRepository(db)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "sessions" WHERE id = \$1 AND revoked_at IS NOT NULL`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	revoked, err := repo.IsRevoked(1)
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_DeleteInactive(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSessionRepository(db)
+	lastSeenBefore := time.Now().Add(-time.Hour)
+	revokedBefore := time.Now().Add(-time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "sessions" WHERE last_seen_at < \$1 OR revoked_at < \$2`).
+		WithArgs(lastSeenBefore, revokedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	deleted, err := repo.DeleteInactive(lastSeenBefore, revokedBefore)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedSessionRepository(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCachedSessionRepository(NewSessionRepository(db))
+	now := time.Now()
+	since := now.Add(-15 * time.Minute)
+
+	// Warm carga las sesiones revocadas recientemente
+	mock.ExpectQuery(`SELECT \* FROM "sessions" WHERE revoked_at > \$1`).
+		WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "revoked_at"}).
+			AddRow(1, 1, now.Add(-time.Minute)))
+	assert.NoError(t, repo.Warm(since, now))
+
+	// Las revocaciones se escriben en la base de datos y en la caché
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE "sessions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "family_id"}).AddRow(2, "family-2"))
+	mock.ExpectExec(`UPDATE "refresh_tokens"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	revoked, err := repo.Revoke(2, 1, now)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE "sessions" .* RETURNING "id","family_id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "family_id"}).AddRow(3, "family-3"))
+	mock.ExpectExec(`UPDATE "refresh_tokens"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	ids, err := repo.RevokeByUser(1, 4, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{3}, ids)
+
+	// Las consultas se resuelven sin acceder a la base de datos
+	for id, expected := range map[uint]bool{1: true, 2: true, 3: true, 4: false} {
+		revoked, err := repo.IsRevoked(id)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, revoked, id)
+	}
+
+	// La purga elimina de la caché las revocaciones anteriores a revokedBefore
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "sessions"`).
+		WithArgs(since, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	deleted, err := repo.DeleteInactive(since, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	revoked, _ = repo.IsRevoked(1)
+	assert.False(t, revoked)
+	revoked, _ = repo.IsRevoked(2)
+	assert.True(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedSessionRepository_Refresh(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCachedSessionRepository(NewSessionRepository(db))
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT \* FROM "sessions" WHERE revoked_at > \$1`).
+		WithArgs(now.Add(-15 * time.Minute)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "revoked_at"}))
+	assert.NoError(t, repo.Warm(now.Add(-15*time.Minute), now))
+
+	// Una sesión revocada por otra instancia se ve tras la recarga
+	later := now.Add(10 * time.Second)
+	mock.ExpectQuery(`SELECT \* FROM "sessions" WHERE revoked_at > \$1`).
+		WithArgs(now.Add(-cacheRefreshOverlap)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "revoked_at"}).
+			AddRow(5, 1, later))
+
+	revoked, _ := repo.IsRevoked(5)
+	assert.False(t, revoked)
+	assert.NoError(t, repo.Refresh(later))
+	revoked, _ = repo.IsRevoked(5)
+	assert.True(t, revoked)
+
+	// La siguiente recarga parte de la anterior
+	mock.ExpectQuery(`SELECT \* FROM "sessions" WHERE revoked_at > \$1`).
+		WithArgs(later.Add(-cacheRefreshOverlap)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "revoked_at"}))
+	assert.NoError(t, repo.Refresh(later.Add(10*time.Second)))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
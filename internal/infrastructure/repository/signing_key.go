@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type SigningKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) *SigningKeyRepository {
+	return &SigningKeyRepository{db}
+}
+
+func (r *SigningKeyRepository) Create(key model.SigningKey) (model.SigningKey, error) {
+	if err := r.db.Create(&key).Error; err != nil {
+		return model.SigningKey{}, errors.WrapDatabaseError(err)
+	}
+	return key, nil
+}
+
+func (r *SigningKeyRepository) GetValid(now time.Time) ([]model.SigningKey, error) {
+	var keys []model.SigningKey
+	err := r.db.Where("retires_at IS NULL OR retires_at > ?", now).Order("created_at ASC, id ASC").Find(&keys).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseError(err)
+	}
+	return keys, nil
+}
+
+// Retire fija el retiro de las claves indicadas. Las claves ya retiradas
+// conservan su fecha, para que rotar varias veces no alargue su vigencia
+func (r *SigningKeyRepository) Retire(ids []uint, retiresAt time.Time) error {
+	err := r.db.Model(&model.SigningKey{}).
+		Where("id IN ? AND retires_at IS NULL", ids).
+		Update("retires_at", retiresAt).Error
+	if err != nil {
+		return errors.WrapDatabaseError(err)
+	}
+	return nil
+}
+
+// DeleteRetired elimina las claves cuyo retiro ya pasó: los tokens firmados con
+// ellas ya expiraron
+func (r *SigningKeyRepository) DeleteRetired(now time.Time) (int64, error) {
+	result := r.db.Where("retires_at <= ?", now).Delete(&model.SigningKey{})
+	if result.Error != nil {
+		return 0, errors.WrapDatabaseError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
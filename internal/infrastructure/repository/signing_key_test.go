@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigningKeyRepository_Create(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSigningKeyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "signing_keys"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	key, err := repo.Create(model.SigningKey{KID: "kid-1", Algorithm: "RS256", PrivateKey: []byte("encrypted")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), key.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSigningKeyRepository_GetValid(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSigningKeyRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT \* FROM "signing_keys" WHERE retires_at IS NULL OR retires_at > \$1 ORDER BY created_at ASC, id ASC`).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kid", "algorithm", "retires_at"}).
+			AddRow(1, "old", "RS256", now.Add(time.Minute)).
+			AddRow(2, "new", "RS256", nil))
+
+	keys, err := repo.GetValid(now)
+
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "new", keys[1].KID)
+	assert.Nil(t, keys[1].RetiresAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSigningKeyRepository_Retire(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSigningKeyRepository(db)
+	retiresAt := time.Now().Add(time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "signing_keys" SET "retires_at"=\$1 WHERE id IN \(\$2,\$3\) AND retires_at IS NULL`).
+		WithArgs(retiresAt, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := repo.Retire([]uint{1, 2}, retiresAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSigningKeyRepository_DeleteRetired(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSigningKeyRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "signing_keys" WHERE retires_at <= \$1`).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deleted, err := repo.DeleteRetired(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
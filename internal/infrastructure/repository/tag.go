@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db}
+}
+
+func (r *TagRepository) GetByID(id uint) (model.Tag, error) {
+	var tag model.Tag
+	err := r.db.First(&tag, id).Error
+	if err != nil {
+		return model.Tag{}, errors.WrapDatabaseErrorWith(err, errors.ErrTagNotFound, nil)
+	}
+	return tag, nil
+}
+
+// GetBySlug busca la etiqueta por su slug o por uno de sus alias
+func (r *TagRepository) GetBySlug(slug string) (model.Tag, error) {
+	tag, err := findTagBySlug(r.db, slug)
+	if err != nil {
+		return model.Tag{}, errors.WrapDatabaseErrorWith(err, errors.ErrTagNotFound, nil)
+	}
+	return tag, nil
+}
+
+// GetCloud devuelve las etiquetas usadas por publicaciones publicadas con su
+// número de publicaciones, de la más usada a la menos usada
+func (r *TagRepository) GetCloud() ([]model.TagCount, error) {
+	var cloud []model.TagCount
+	err := r.db.Model(&model.Tag{}).
+		Select("tags.id, tags.name, tags.slug, COUNT(posts.id) AS count").
+		Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins("JOIN posts ON posts.id = post_tags.post_id AND posts.status = ?", model.PostStatusPublished).
+		Group("tags.id, tags.name, tags.slug").
+		Order("count DESC, tags.name ASC").
+		Scan(&cloud).Error
+	if err != nil {
+		return nil, errors.WrapDatabaseErrorWith(err, errors.ErrTagNotFound, nil)
+	}
+	return cloud, nil
+}
+
+// SlugInUse indica si el slug pertenece, como slug o como alias, a otra etiqueta
+func (r *TagRepository) SlugInUse(slug string, excludeTagID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Tag{}).Where("slug = ? AND id <> ?", slug, excludeTagID).Count(&count).Error
+	if err != nil {
+		return false, errors.WrapDatabaseErrorWith(err, errors.ErrTagNotFound, nil)
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	err = r.db.Model(&model.TagAlias{}).Where("slug = ? AND tag_id <> ?", slug, excludeTagID).Count(&count).Error
+	if err != nil {
+		return false, errors.WrapDatabaseErrorWith(err, errors.ErrTagNotFound, nil)
+	}
+	return count > 0, nil
+}
+
+// FindOrCreate resuelve cada etiqueta por su slug o alias y crea las que no existen
+func (r *TagRepository) FindOrCreate(tags []model.Tag) ([]model.Tag, error) {
+	resolved := make([]model.Tag, 0, len(tags))
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, tag := range tags {
+			existing, err := findTagBySlug(tx, tag.Slug)
+			if err == nil {
+				resolved = append(resolved, existing)
+				continue
+			}
+			if err != gorm.ErrRecordNotFound {
+				return err
+			}
+
+			// Otra petición puede haber creado la etiqueta al mismo tiempo
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tag).Error; err != nil {
+				return err
+			}
+			if tag.ID == 0 {
+				if err := tx.Where("slug = ?", tag.Slug).First(&tag).Error; err != nil {
+					return err
+				}
+			}
+			resolved = append(resolved, tag)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WrapDatabaseErrorWith(err, errors.ErrTagNotFound, nil)
+	}
+	return resolved, nil
+}
+
+// Rename guarda el nuevo nombre y slug de la etiqueta; el slug anterior se conserva como alias
+func (r *TagRepository) Rename(tag model.Tag) (model.Tag, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.Tag
+		if err := tx.First(&current, tag.ID).Error; err != nil {
+			return err
+		}
+
+		if current.Slug != tag.Slug {
+			if err := tx.Create(&model.TagAlias{TagID: tag.ID, Slug: current.Slug}).Error; err != nil {
+				return err
+			}
+			// Si la etiqueta recupera un alias como slug, deja de ser alias
+			if err := tx.Where("tag_id = ? AND slug = ?", tag.ID, tag.Slug).Delete(&model.TagAlias{}).Error; err != nil {
+				return err
+			}
+		}
+
+		tag.CreatedAt = current.CreatedAt
+		return tx.Save(&tag).Error
+	})
+	if err != nil {
+		return model.Tag{}, errors.WrapDatabaseErrorWith(err, errors.ErrTagNotFound, errors.ErrTagExists)
+	}
+	return tag, nil
+}
+
+// Merge mueve todas las publicaciones y alias de la etiqueta de origen a la de
+// destino y elimina la de origen, cuyo slug pasa a ser un alias del destino.
+// Todo ocurre en una única transacción
+func (r *TagRepository) Merge(sourceID uint, targetID uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var source, target model.Tag
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&source, sourceID).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&target, targetID).Error; err != nil {
+			return err
+		}
+
+		// Las publicaciones que ya tienen ambas etiquetas conservan una sola asociación
+		err := tx.Exec(`INSERT INTO post_tags (post_id, tag_id)
+			SELECT post_id, ? FROM post_tags WHERE tag_id = ?
+			ON CONFLICT DO NOTHING`, target.ID, source.ID).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM post_tags WHERE tag_id = ?", source.ID).Error; err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.TagAlias{}).Where("tag_id = ?", source.ID).Update("tag_id", target.ID).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&source).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.TagAlias{TagID: target.ID, Slug: source.Slug}).Error
+	})
+	if err != nil {
+		return errors.WrapDatabaseErrorWith(err, errors.ErrTagNotFound, errors.ErrTagExists)
+	}
+	return nil
+}
+
+func (r *TagRepository) CreateAlias(alias model.TagAlias) (model.TagAlias, error) {
+	err := r.db.Create(&alias).Error
+	if err != nil {
+		return model.TagAlias{}, errors.WrapDatabaseErrorWith(err, errors.ErrTagNotFound, errors.ErrTagExists)
+	}
+	return alias, nil
+}
+
+// findTagBySlug busca primero en los slugs de las etiquetas y después en sus alias
+func findTagBySlug(db *gorm.DB, slug string) (model.Tag, error) {
+	var tag model.Tag
+	err := db.Where("slug = ?", slug).First(&tag).Error
+	if err != gorm.ErrRecordNotFound {
+		return tag, err
+	}
+
+	err = db.Joins("JOIN tag_aliases ON tag_aliases.tag_id = tags.id").
+		Where("tag_aliases.slug = ?", slug).
+		First(&tag).Error
+	return tag, err
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var tagColumns = []string{"id", "name", "slug", "created_at"}
+
+func TestNewTagRepository(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, db, repo.db)
+}
+
+func TestTagRepository_GetBySlug(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedID    uint
+		expectedError error
+	}{
+		{
+			name: "success - current slug",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT \* FROM "tags" WHERE slug = \$1`).
+					WithArgs("go", 1).
+					WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(1, "Go", "go", time.Now()))
+			},
+			expectedID: 1,
+		},
+		{
+			name: "success - alias",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT \* FROM "tags" WHERE slug = \$1`).WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectQuery(`SELECT "tags"."id",.* FROM "tags" JOIN tag_aliases ON tag_aliases.tag_id = tags.id WHERE tag_aliases.slug = \$1`).
+					WithArgs("go", 1).
+					WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(2, "Golang", "golang", time.Now()))
+			},
+			expectedID: 2,
+		},
+		{
+			name: "error - unknown slug",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT \* FROM "tags"`).WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectQuery(`SELECT "tags"."id",.* FROM "tags" JOIN tag_aliases`).WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expectedError: errors.ErrTagNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewTagRepository(db)
+			tt.setupMock(mock)
+
+			tag, err := repo.GetBySlug("go")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedID, tag.ID)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTagRepository_GetCloud(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "slug", "count"}).
+		AddRow(1, "Go", "go", 5).
+		AddRow(2, "SQL", "sql", 2)
+	mock.ExpectQuery(`SELECT tags.id, tags.name, tags.slug, COUNT\(posts.id\) AS count FROM "tags" JOIN post_tags ON post_tags.tag_id = tags.id JOIN posts ON posts.id = post_tags.post_id AND posts.status = \$1 GROUP BY tags.id, tags.name, tags.slug ORDER BY count DESC, tags.name ASC`).
+		WithArgs(model.PostStatusPublished).
+		WillReturnRows(rows)
+
+	cloud, err := repo.GetCloud()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TagCount{
+		{ID: 1, Name: "Go", Slug: "go", Count: 5},
+		{ID: 2, Name: "SQL", Slug: "sql", Count: 2},
+	}, cloud)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_SlugInUse(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "tags" WHERE slug = \$1 AND id <> \$2`).
+		WithArgs("go", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "tag_aliases" WHERE slug = \$1 AND tag_id <> \$2`).
+		WithArgs("go", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	inUse, err := repo.SlugInUse("go", 3)
+
+	assert.NoError(t, err)
+	assert.True(t, inUse)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_FindOrCreate(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectBegin()
+	// "go" ya existe
+	mock.ExpectQuery(`SELECT \* FROM "tags" WHERE slug = \$1`).
+		WithArgs("go", 1).
+		WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(1, "Go", "go", time.Now()))
+	// "testing" no existe y se crea
+	mock.ExpectQuery(`SELECT \* FROM "tags" WHERE slug = \$1`).
+		WithArgs("testing", 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectQuery(`SELECT "tags"."id",.* FROM "tags" JOIN tag_aliases`).
+		WithArgs("testing", 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectQuery(`INSERT INTO "tags" .* ON CONFLICT DO NOTHING RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectCommit()
+
+	tags, err := repo.FindOrCreate([]model.Tag{
+		{Name: "Go", Slug: "go"},
+		{Name: "Testing", Slug: "testing"},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, uint(1), tags[0].ID)
+	assert.Equal(t, uint(7), tags[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_Rename(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "tags" WHERE "tags"."id" = \$1`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(1, "Golang", "golang", time.Now()))
+	mock.ExpectQuery(`INSERT INTO "tag_aliases"`).
+		WithArgs(1, "golang", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(`DELETE FROM "tag_aliases" WHERE tag_id = \$1 AND slug = \$2`).
+		WithArgs(1, "go").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE "tags" SET "name"=\$1,"slug"=\$2,"created_at"=\$3 WHERE "id" = \$4`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tag, err := repo.Rename(model.Tag{ID: 1, Name: "Go", Slug: "go"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "go", tag.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_Merge(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "success - associations moved in one transaction",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT \* FROM "tags" WHERE "tags"."id" = \$1 .*FOR UPDATE`).
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(2, "Golang", "golang", time.Now()))
+				mock.ExpectQuery(`SELECT \* FROM "tags" WHERE "tags"."id" = \$1 .*FOR UPDATE`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(1, "Go", "go", time.Now()))
+				mock.ExpectExec(`INSERT INTO post_tags \(post_id, tag_id\)\s+SELECT post_id, \$1 FROM post_tags WHERE tag_id = \$2\s+ON CONFLICT DO NOTHING`).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(`DELETE FROM post_tags WHERE tag_id = \$1`).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectExec(`UPDATE "tag_aliases" SET "tag_id"=\$1 WHERE tag_id = \$2`).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM "tags" WHERE "tags"."id" = \$1`).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`INSERT INTO "tag_aliases"`).
+					WithArgs(1, "golang", sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error - rolls back when a step fails",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT \* FROM "tags"`).
+					WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(2, "Golang", "golang", time.Now()))
+				mock.ExpectQuery(`SELECT \* FROM "tags"`).
+					WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(1, "Go", "go", time.Now()))
+				mock.ExpectExec(`INSERT INTO post_tags`).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedError: errors.ErrDatabaseOperation,
+		},
+		{
+			name: "error - target tag not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT \* FROM "tags"`).
+					WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(2, "Golang", "golang", time.Now()))
+				mock.ExpectQuery(`SELECT \* FROM "tags"`).WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectRollback()
+			},
+			expectedError: errors.ErrTagNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewTagRepository(db)
+			tt.setupMock(mock)
+
+			err := repo.Merge(2, 1)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTagRepository_CreateAlias(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "success - alias created",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "tag_aliases"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error - slug already in use",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "tag_aliases"`).
+					WillReturnError(fmt.Errorf(`ERROR: duplicate key value violates unique constraint "idx_tag_aliases_slug"`))
+				mock.ExpectRollback()
+			},
+			expectedError: errors.ErrTagExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewTagRepository(db)
+			tt.setupMock(mock)
+
+			_, err := repo.CreateAlias(model.TagAlias{TagID: 1, Slug: "golang"})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"sync"
+	"time"
+
 	"github.com/UliVargas/blog-go/internal/domain/model"
 	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
 	"gorm.io/gorm"
 )
 
@@ -14,13 +18,13 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db}
 }
 
-func (r *UserRepository) GetAll() ([]model.User, error) {
-	var users []model.User
-	err := r.db.Find(&users).Error
+// GetAll devuelve una página de usuarios según la especificación de consulta
+func (r *UserRepository) GetAll(spec query.Spec) (query.Page[model.User], error) {
+	page, err := findPage[model.User](r.db, spec)
 	if err != nil {
-		return nil, errors.WrapDatabaseError(err)
+		return query.Page[model.User]{}, errors.WrapDatabaseError(err)
 	}
-	return users, nil
+	return page, nil
 }
 
 func (r *UserRepository) GetByID(id uint) (model.User, error) {
@@ -41,12 +45,12 @@ func (r *UserRepository) GetByEmail(email string) (model.User, error) {
 	return user, nil
 }
 
-func (r *UserRepository) Create(user model.User) error {
+func (r *UserRepository) Create(user model.User) (model.User, error) {
 	err := r.db.Create(&user).Error
 	if err != nil {
-		return errors.WrapDatabaseError(err)
+		return model.User{}, errors.WrapDatabaseError(err)
 	}
-	return nil
+	return user, nil
 }
 
 func (r *UserRepository) Update(user model.User) (model.User, error) {
@@ -57,6 +61,46 @@ func (r *UserRepository) Update(user model.User) (model.User, error) {
 	return user, nil
 }
 
+// UpdateFields actualiza solo las columnas indicadas, a diferencia de Update que
+// guarda el registro completo, y devuelve el usuario actualizado
+func (r *UserRepository) UpdateFields(id uint, fields map[string]any) (model.User, error) {
+	result := r.db.Model(&model.User{}).Where("id = ?", id).Updates(fields)
+	if result.Error != nil {
+		return model.User{}, errors.WrapDatabaseError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return model.User{}, errors.ErrUserNotFound
+	}
+	return r.GetByID(id)
+}
+
+// AdvanceTOTPStep registra step como el paso del último código TOTP aceptado
+// solo si es posterior al anterior. Devuelve false si el código ya se usó, lo que
+// impide reutilizarlo aunque dos peticiones lo presenten a la vez
+func (r *UserRepository) AdvanceTOTPStep(id uint, step int64) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, errors.WrapDatabaseError(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReplacePasswordHash reemplaza el hash de la contraseña por otro de la misma
+// contraseña solo si sigue siendo current. No modifica password_changed_at, por
+// lo que los tokens emitidos siguen siendo válidos, y devuelve false si la
+// contraseña cambió mientras tanto
+func (r *UserRepository) ReplacePasswordHash(id uint, current, replacement string) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND password = ?", id, current).
+		Update("password", replacement)
+	if result.Error != nil {
+		return false, errors.WrapDatabaseError(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
 func (r *UserRepository) Delete(id uint) error {
 	err := r.db.Delete(&model.User{}, id).Error
 	if err != nil {
@@ -64,3 +108,86 @@ func (r *UserRepository) Delete(id uint) error {
 	}
 	return nil
 }
+
+// CachedUserRepository mantiene en memoria el último cambio de contraseña de
+// cada usuario consultado, para que el middleware de autenticación compruebe los
+// tokens sin acceder a la base de datos en cada petición. La caché se actualiza
+// en cada escritura que pasa por este repositorio y cada entrada se vuelve a leer
+// pasado ttl, para ver los cambios y las eliminaciones de otras instancias
+type CachedUserRepository struct {
+	*UserRepository
+	ttl             time.Duration
+	now             func() time.Time
+	mu              sync.RWMutex
+	passwordChanges map[uint]cachedPasswordChange
+}
+
+// cachedPasswordChange es el último cambio de contraseña de un usuario y el
+// momento en que se leyó
+type cachedPasswordChange struct {
+	changedAt time.Time
+	loadedAt  time.Time
+}
+
+func NewCachedUserRepository(repo *UserRepository, ttl time.Duration) *CachedUserRepository {
+	return &CachedUserRepository{UserRepository: repo, ttl: ttl, now: time.Now, passwordChanges: make(map[uint]cachedPasswordChange)}
+}
+
+// PasswordChangedAt devuelve el último cambio de contraseña del usuario, o el
+// instante cero si nunca la cambió
+func (r *CachedUserRepository) PasswordChangedAt(userID uint) (time.Time, error) {
+	r.mu.RLock()
+	cached, ok := r.passwordChanges[userID]
+	r.mu.RUnlock()
+	if ok && r.now().Sub(cached.loadedAt) < r.ttl {
+		return cached.changedAt, nil
+	}
+
+	user, err := r.UserRepository.GetByID(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return r.remember(user), nil
+}
+
+func (r *CachedUserRepository) Update(user model.User) (model.User, error) {
+	updated, err := r.UserRepository.Update(user)
+	if err != nil {
+		return model.User{}, err
+	}
+	r.remember(updated)
+	return updated, nil
+}
+
+func (r *CachedUserRepository) UpdateFields(id uint, fields map[string]any) (model.User, error) {
+	updated, err := r.UserRepository.UpdateFields(id, fields)
+	if err != nil {
+		return model.User{}, err
+	}
+	r.remember(updated)
+	return updated, nil
+}
+
+func (r *CachedUserRepository) Delete(id uint) error {
+	if err := r.UserRepository.Delete(id); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.passwordChanges, id)
+	return nil
+}
+
+// remember guarda en la caché el último cambio de contraseña del usuario
+func (r *CachedUserRepository) remember(user model.User) time.Time {
+	var changedAt time.Time
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.passwordChanges[user.ID] = cachedPasswordChange{changedAt: changedAt, loadedAt: r.now()}
+	return changedAt
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/UliVargas/blog-go/internal/domain/model"
 	"github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
@@ -44,29 +45,70 @@ func TestNewUserRepository(t *testing.T) {
 }
 
 func TestUserRepository_GetAll(t *testing.T) {
+	sortByCreatedAt := query.Spec{
+		Limit:   2,
+		SortKey: "created_at",
+		Sort:    query.Field{Column: "created_at", Kind: query.KindTime},
+	}
+	cursorTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name          string
+		spec          query.Spec
 		setupMock     func(sqlmock.Sqlmock)
 		expectedUsers []model.User
+		expectNext    bool
+		expectPrev    bool
 		expectedError error
 	}{
 		{
-			name: "success - returns users",
+			name: "success - first page with more results",
+			spec: sortByCreatedAt,
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "created_at", "updated_at"}).
 					AddRow(1, "John Doe", "john@example.com", "password123", time.Now(), time.Now()).
-					AddRow(2, "Jane Smith", "jane@example.com", "password456", time.Now(), time.Now())
+					AddRow(2, "Jane Smith", "jane@example.com", "password456", time.Now(), time.Now()).
+					AddRow(3, "Jim Beam", "jim@example.com", "password789", time.Now(), time.Now())
 
-				mock.ExpectQuery(`SELECT \* FROM "users"`).WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT \* FROM "users" ORDER BY users.created_at ASC,users.id ASC LIMIT \$1`).
+					WithArgs(3).
+					WillReturnRows(rows)
 			},
 			expectedUsers: []model.User{
 				{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "password123"},
 				{ID: 2, Name: "Jane Smith", Email: "jane@example.com", Password: "password456"},
 			},
-			expectedError: nil,
+			expectNext: true,
+		},
+		{
+			name: "success - filters and cursor",
+			spec: query.Spec{
+				Limit:   2,
+				SortKey: "-created_at",
+				Sort:    query.Field{Column: "created_at", Kind: query.KindTime},
+				Desc:    true,
+				Cursor:  &query.Cursor{Value: cursorTime, ID: 5},
+				Conditions: []query.Condition{
+					{Column: "name", Operator: query.OpContains, Value: "50%"},
+					{Column: "created_at", Operator: query.OpAfter, Value: cursorTime.AddDate(0, -1, 0)},
+				},
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "created_at", "updated_at"}).
+					AddRow(4, "John Doe", "john@example.com", "password123", time.Now(), time.Now())
+
+				mock.ExpectQuery(`SELECT \* FROM "users" WHERE LOWER\(users.name\) LIKE LOWER\(\$1\) ESCAPE '\\' AND users.created_at > \$2 AND \(users.created_at, users.id\) < \(\$3, \$4\) ORDER BY users.created_at DESC,users.id DESC LIMIT \$5`).
+					WithArgs(`%50\%%`, cursorTime.AddDate(0, -1, 0), cursorTime, 5, 3).
+					WillReturnRows(rows)
+			},
+			expectedUsers: []model.User{
+				{ID: 4, Name: "John Doe", Email: "john@example.com", Password: "password123"},
+			},
+			expectPrev: true,
 		},
 		{
 			name: "success - empty result",
+			spec: sortByCreatedAt,
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "created_at", "updated_at"})
 				mock.ExpectQuery(`SELECT \* FROM "users"`).WillReturnRows(rows)
@@ -76,6 +118,7 @@ func TestUserRepository_GetAll(t *testing.T) {
 		},
 		{
 			name: "error - database error",
+			spec: sortByCreatedAt,
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(`SELECT \* FROM "users"`).WillReturnError(sql.ErrConnDone)
 			},
@@ -92,7 +135,8 @@ func TestUserRepository_GetAll(t *testing.T) {
 			repo := NewUserRepository(db)
 			tt.setupMock(mock)
 
-			users, err := repo.GetAll()
+			page, err := repo.GetAll(tt.spec)
+			users := page.Data
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -107,6 +151,8 @@ func TestUserRepository_GetAll(t *testing.T) {
 					assert.Equal(t, expectedUser.Email, users[i].Email)
 					assert.Equal(t, expectedUser.Password, users[i].Password)
 				}
+				assert.Equal(t, tt.expectNext, page.NextCursor != "")
+				assert.Equal(t, tt.expectPrev, page.PrevCursor != "")
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -276,13 +322,14 @@ func TestUserRepository_Create(t *testing.T) {
 			repo := NewUserRepository(db)
 			tt.setupMock(mock)
 
-			err := repo.Create(tt.user)
+			user, err := repo.Create(tt.user)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.expectedError)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, uint(1), user.ID)
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -349,6 +396,70 @@ func TestUserRepository_Update(t *testing.T) {
 	}
 }
 
+func TestUserRepository_UpdateFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "success - only given columns are updated",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "users" SET "bio"=\$1,"updated_at"=\$2 WHERE id = \$3`).
+					WithArgs("Gopher", sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				rows := sqlmock.NewRows([]string{"id", "name", "email", "bio"}).
+					AddRow(1, "John Doe", "john@example.com", "Gopher")
+				mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1`).
+					WithArgs(1, 1).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "error - user not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "users" SET`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectedError: errors.ErrUserNotFound,
+		},
+		{
+			name: "error - database error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "users" SET`).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedError: errors.ErrDatabaseOperation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewUserRepository(db)
+			tt.setupMock(mock)
+
+			user, err := repo.UpdateFields(1, map[string]any{"bio": "Gopher"})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint(1), user.ID)
+				assert.Equal(t, "Gopher", user.Bio)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestUserRepository_Delete(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -398,4 +509,149 @@ func TestUserRepository_Delete(t *testing.T) {
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
-}
\ No newline at end of file
+}
+func TestCachedUserRepository_PasswordChangedAt(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCachedUserRepository(NewUserRepository(db), time.Hour)
+	changedAt := time.Now().Truncate(time.Second)
+
+	// La primera consulta lee el usuario y las siguientes usan la caché
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "john@example.com"))
+	for range 2 {
+		got, err := repo.PasswordChangedAt(1)
+		assert.NoError(t, err)
+		assert.True(t, got.IsZero())
+	}
+
+	// Cambiar la contraseña actualiza la caché
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_changed_at"}).AddRow(1, "john@example.com", changedAt))
+	_, err := repo.UpdateFields(1, map[string]any{"password": "hash", "password_changed_at": changedAt})
+	require.NoError(t, err)
+
+	got, err := repo.PasswordChangedAt(1)
+	assert.NoError(t, err)
+	assert.True(t, changedAt.Equal(got))
+
+	// Eliminar el usuario lo quita de la caché
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, repo.Delete(1))
+
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1`).
+		WithArgs(1, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	_, err = repo.PasswordChangedAt(1)
+	assert.ErrorIs(t, err, errors.ErrUserNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedUserRepository_PasswordChangedAt_Expires(t *testing.T) {
+	db, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCachedUserRepository(NewUserRepository(db), time.Minute)
+	now := time.Now()
+	repo.now = func() time.Time { return now }
+	changedAt := now.Truncate(time.Second)
+
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "john@example.com"))
+	got, err := repo.PasswordChangedAt(1)
+	assert.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	// Pasado el ttl se ve el cambio de contraseña hecho por otra instancia
+	now = now.Add(time.Minute)
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_changed_at"}).AddRow(1, "john@example.com", changedAt))
+	got, err = repo.PasswordChangedAt(1)
+	assert.NoError(t, err)
+	assert.True(t, changedAt.Equal(got))
+
+	// Y también su eliminación
+	now = now.Add(time.Minute)
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1`).
+		WithArgs(1, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	_, err = repo.PasswordChangedAt(1)
+	assert.ErrorIs(t, err, errors.ErrUserNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_AdvanceTOTPStep(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expected     bool
+	}{
+		{"success - newer step", 1, true},
+		{"step already used", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewUserRepository(db)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "users" SET "totp_last_step"=\$1,"updated_at"=\$2 WHERE id = \$3 AND totp_last_step < \$4`).
+				WithArgs(int64(42), sqlmock.AnyArg(), 1, int64(42)).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			mock.ExpectCommit()
+
+			advanced, err := repo.AdvanceTOTPStep(1, 42)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, advanced)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_ReplacePasswordHash(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expected     bool
+	}{
+		{"success - hash replaced", 1, true},
+		{"password changed meanwhile", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			repo := NewUserRepository(db)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "users" SET "password"=\$1,"updated_at"=\$2 WHERE id = \$3 AND password = \$4`).
+				WithArgs("new-hash", sqlmock.AnyArg(), 1, "old-hash").
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			mock.ExpectCommit()
+
+			replaced, err := repo.ReplacePasswordHash(1, "old-hash", "new-hash")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, replaced)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job representa una tarea que se ejecuta periódicamente en segundo plano
+type Job func(ctx context.Context) error
+
+// Every ejecuta job inmediatamente y luego cada interval hasta que ctx se cancele.
+// Los errores se registran en el log sin detener el planificador
+func Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil {
+			log.Printf("Error en la tarea programada %s: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
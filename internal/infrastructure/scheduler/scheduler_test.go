@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	t.Run("runs the job until the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var runs atomic.Int32
+
+		done := make(chan struct{})
+		go func() {
+			Every(ctx, "test", 5*time.Millisecond, func(ctx context.Context) error {
+				if runs.Add(1) == 3 {
+					cancel()
+				}
+				return nil
+			})
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("el planificador no se detuvo al cancelar el contexto")
+		}
+		assert.GreaterOrEqual(t, runs.Load(), int32(3))
+	})
+
+	t.Run("keeps running after a job error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var runs atomic.Int32
+
+		Every(ctx, "failing", time.Millisecond, func(ctx context.Context) error {
+			if runs.Add(1) == 2 {
+				cancel()
+			}
+			return errors.New("job failed")
+		})
+
+		assert.Equal(t, int32(2), runs.Load())
+	})
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	services "github.com/UliVargas/blog-go/internal/application/service"
@@ -30,16 +32,122 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authService.Login(req.Email, req.Password)
+	result, err := h.authService.Login(req.Email, req.Password, clientInfo(c))
 	if err != nil {
 		utils.HandleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.LoginResponse{
-		Message: "Inicio de sesión exitoso",
-		Token:   token,
-	})
+	respondLogin(c, result)
+}
+
+// respondLogin responde con los tokens de un inicio de sesión o, si el usuario
+// tiene activada la verificación en dos pasos, con el desafío
+func respondLogin(c *gin.Context, result dto.LoginResult) {
+	if result.Challenge != nil {
+		c.JSON(http.StatusOK, dto.NewTwoFactorChallengeResponse(*result.Challenge))
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewLoginResponse("Inicio de sesión exitoso", result.Tokens))
+}
+
+// VerifyTwoFactor completa el inicio de sesión con el desafío devuelto por Login
+// y un código TOTP o de recuperación
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req dto.VerifyTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	tokens, err := h.authService.VerifyTwoFactor(req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewLoginResponse("Inicio de sesión exitoso", tokens))
+}
+
+// Refresh rota el token de renovación y devuelve un nuevo par de tokens
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	tokens, err := h.authService.Refresh(req.RefreshToken, clientInfo(c))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewLoginResponse("Token renovado", tokens))
+}
+
+// Logout revoca el token de acceso y la sesión de la petición y, si se envía en
+// el cuerpo, el token de renovación de la sesión
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	// El cuerpo es opcional
+	var req dto.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.HandleBadRequest(c, "Datos inválidos")
+		return
+	}
+
+	jti, expiresAt := getTokenID(c)
+	if err := h.authService.Logout(userID, getSessionID(c), jti, expiresAt, req.RefreshToken); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sesión cerrada exitosamente"})
+}
+
+// ChangePassword reemplaza la contraseña del usuario autenticado y devuelve un
+// nuevo par de tokens, ya que los anteriores dejan de ser válidos
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	tokens, err := h.authService.ChangePassword(userID, req.CurrentPassword, req.NewPassword, clientInfo(c))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewLoginResponse("Contraseña actualizada exitosamente", tokens))
 }
 
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -6,25 +6,60 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	services "github.com/UliVargas/blog-go/internal/application/service"
 	"github.com/UliVargas/blog-go/internal/domain/dto"
 	"github.com/UliVargas/blog-go/internal/domain/model"
 	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
 // MockAuthService mocks the AuthService for handler testing
 type MockAuthService struct {
-	LoginFunc    func(email, password string) (string, error)
+	LoginFunc    func(email, password string, client dto.ClientInfo) (dto.LoginResult, error)
+	RefreshFunc  func(refreshToken string, client dto.ClientInfo) (dto.TokenPair, error)
+	LogoutFunc   func(userID, sessionID uint, jti string, expiresAt time.Time, refreshToken string) error
 	RegisterFunc func(user model.User) error
+
+	ChangePasswordFunc  func(userID uint, currentPassword, newPassword string, client dto.ClientInfo) (dto.TokenPair, error)
+	VerifyTwoFactorFunc func(challengeToken, code string, client dto.ClientInfo) (dto.TokenPair, error)
 }
 
-func (m *MockAuthService) Login(email, password string) (string, error) {
+func (m *MockAuthService) Login(email, password string, client dto.ClientInfo) (dto.LoginResult, error) {
 	if m.LoginFunc != nil {
-		return m.LoginFunc(email, password)
+		return m.LoginFunc(email, password, client)
+	}
+	return dto.LoginResult{Tokens: dto.TokenPair{AccessToken: "mock-token"}}, nil
+}
+
+func (m *MockAuthService) VerifyTwoFactor(challengeToken, code string, client dto.ClientInfo) (dto.TokenPair, error) {
+	if m.VerifyTwoFactorFunc != nil {
+		return m.VerifyTwoFactorFunc(challengeToken, code, client)
 	}
-	return "mock-token", nil
+	return dto.TokenPair{AccessToken: "mock-token"}, nil
+}
+
+func (m *MockAuthService) Refresh(refreshToken string, client dto.ClientInfo) (dto.TokenPair, error) {
+	if m.RefreshFunc != nil {
+		return m.RefreshFunc(refreshToken, client)
+	}
+	return dto.TokenPair{AccessToken: "mock-token"}, nil
+}
+
+func (m *MockAuthService) Logout(userID, sessionID uint, jti string, expiresAt time.Time, refreshToken string) error {
+	if m.LogoutFunc != nil {
+		return m.LogoutFunc(userID, sessionID, jti, expiresAt, refreshToken)
+	}
+	return nil
+}
+
+func (m *MockAuthService) ChangePassword(userID uint, currentPassword, newPassword string, client dto.ClientInfo) (dto.TokenPair, error) {
+	if m.ChangePasswordFunc != nil {
+		return m.ChangePasswordFunc(userID, currentPassword, newPassword, client)
+	}
+	return dto.TokenPair{AccessToken: "mock-token"}, nil
 }
 
 func (m *MockAuthService) Register(user model.User) error {
@@ -64,12 +99,27 @@ func TestAuthHandler_Login(t *testing.T) {
 				Password: "password123",
 			},
 			mockSetup: func(m *MockAuthService) {
-				m.LoginFunc = func(email, password string) (string, error) {
-					return "jwt-token-123", nil
+				m.LoginFunc = func(email, password string, client dto.ClientInfo) (dto.LoginResult, error) {
+					assert.Equal(t, dto.ClientInfo{IP: "10.0.0.1", UserAgent: "Firefox"}, client)
+					return dto.LoginResult{Tokens: dto.TokenPair{AccessToken: "jwt-token-123", RefreshToken: "refresh-123", ExpiresIn: 15 * time.Minute}}, nil
 				}
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"message":"Inicio de sesión exitoso","token":"jwt-token-123"}`,
+			expectedBody:   `{"message":"Inicio de sesión exitoso","token":"jwt-token-123","refresh_token":"refresh-123","token_type":"Bearer","expires_in":900}`,
+		},
+		{
+			name: "success - two-factor challenge",
+			requestBody: dto.LoginRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.LoginFunc = func(email, password string, client dto.ClientInfo) (dto.LoginResult, error) {
+					return dto.LoginResult{Challenge: &dto.TwoFactorChallenge{Token: "challenge-123", ExpiresIn: 5 * time.Minute}}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Introduce el código de verificación en dos pasos","two_factor_required":true,"challenge_token":"challenge-123","expires_in":300}`,
 		},
 		{
 			name: "error - invalid credentials",
@@ -78,13 +128,27 @@ func TestAuthHandler_Login(t *testing.T) {
 				Password: "wrongpassword",
 			},
 			mockSetup: func(m *MockAuthService) {
-				m.LoginFunc = func(email, password string) (string, error) {
-					return "", appErrors.ErrInvalidCredentials
+				m.LoginFunc = func(email, password string, client dto.ClientInfo) (dto.LoginResult, error) {
+					return dto.LoginResult{}, appErrors.ErrInvalidCredentials
 				}
 			},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   `{"error":"Credenciales inválidas"}`,
 		},
+		{
+			name: "error - too many attempts",
+			requestBody: dto.LoginRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(m *MockAuthService) {
+				m.LoginFunc = func(email, password string, client dto.ClientInfo) (dto.LoginResult, error) {
+					return dto.LoginResult{}, appErrors.NewRetryAfterError(appErrors.ErrTooManyLoginAttempts, 30*time.Second)
+				}
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   `{"error":"Demasiados intentos de inicio de sesión; inténtalo más tarde"}`,
+		},
 		{
 			name:        "error - invalid JSON",
 			requestBody: `{"email":"invalid-json"`,
@@ -135,6 +199,342 @@ func TestAuthHandler_Login(t *testing.T) {
 
 			req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
 			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("User-Agent", "Firefox")
+			req.RemoteAddr = "10.0.0.1:54321"
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuthHandler_VerifyTwoFactor(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "success - tokens issued",
+			requestBody: dto.VerifyTwoFactorRequest{ChallengeToken: "challenge-123", Code: "123456"},
+			mockSetup: func(m *MockAuthService) {
+				m.VerifyTwoFactorFunc = func(challengeToken, code string, client dto.ClientInfo) (dto.TokenPair, error) {
+					assert.Equal(t, "challenge-123", challengeToken)
+					assert.Equal(t, "123456", code)
+					assert.Equal(t, "10.0.0.1", client.IP)
+					return dto.TokenPair{AccessToken: "jwt-token-123", RefreshToken: "refresh-123", ExpiresIn: 15 * time.Minute}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Inicio de sesión exitoso","token":"jwt-token-123","refresh_token":"refresh-123","token_type":"Bearer","expires_in":900}`,
+		},
+		{
+			name:        "error - invalid code",
+			requestBody: dto.VerifyTwoFactorRequest{ChallengeToken: "challenge-123", Code: "000000"},
+			mockSetup: func(m *MockAuthService) {
+				m.VerifyTwoFactorFunc = func(challengeToken, code string, client dto.ClientInfo) (dto.TokenPair, error) {
+					return dto.TokenPair{}, appErrors.ErrInvalidTwoFactorCode
+				}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Código de verificación inválido"}`,
+		},
+		{
+			name:        "error - expired challenge",
+			requestBody: dto.VerifyTwoFactorRequest{ChallengeToken: "expired", Code: "123456"},
+			mockSetup: func(m *MockAuthService) {
+				m.VerifyTwoFactorFunc = func(challengeToken, code string, client dto.ClientInfo) (dto.TokenPair, error) {
+					return dto.TokenPair{}, appErrors.ErrInvalidTwoFactorChallenge
+				}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"La verificación en dos pasos expiró; inicia sesión de nuevo"}`,
+		},
+		{
+			name:           "error - missing code",
+			requestBody:    dto.VerifyTwoFactorRequest{ChallengeToken: "challenge-123"},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"code":"Este campo es obligatorio"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authHandler, mockService := NewAuthHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.POST("/2fa/verify", authHandler.VerifyTwoFactor)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/2fa/verify", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.RemoteAddr = "10.0.0.1:54321"
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockSetup      func(*MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "success - tokens rotated",
+			requestBody: dto.RefreshRequest{RefreshToken: "refresh-123"},
+			mockSetup: func(m *MockAuthService) {
+				m.RefreshFunc = func(refreshToken string, client dto.ClientInfo) (dto.TokenPair, error) {
+					assert.Equal(t, "refresh-123", refreshToken)
+					return dto.TokenPair{AccessToken: "jwt-token-456", RefreshToken: "refresh-456", ExpiresIn: 15 * time.Minute}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Token renovado","token":"jwt-token-456","refresh_token":"refresh-456","token_type":"Bearer","expires_in":900}`,
+		},
+		{
+			name:        "error - invalid refresh token",
+			requestBody: dto.RefreshRequest{RefreshToken: "expired"},
+			mockSetup: func(m *MockAuthService) {
+				m.RefreshFunc = func(refreshToken string, client dto.ClientInfo) (dto.TokenPair, error) {
+					return dto.TokenPair{}, appErrors.ErrInvalidRefreshToken
+				}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Token de renovación inválido o expirado"}`,
+		},
+		{
+			name:        "error - reused refresh token",
+			requestBody: dto.RefreshRequest{RefreshToken: "used"},
+			mockSetup: func(m *MockAuthService) {
+				m.RefreshFunc = func(refreshToken string, client dto.ClientInfo) (dto.TokenPair, error) {
+					return dto.TokenPair{}, appErrors.ErrRefreshTokenReused
+				}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Token de renovación reutilizado; se cerraron las sesiones asociadas"}`,
+		},
+		{
+			name:           "error - invalid JSON",
+			requestBody:    `{"refresh_token":`,
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos inválidos"}`,
+		},
+		{
+			name:           "error - missing refresh token",
+			requestBody:    dto.RefreshRequest{},
+			mockSetup:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"refreshtoken":"Este campo es obligatorio"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authHandler, mockService := NewAuthHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.POST("/refresh", authHandler.Refresh)
+
+			var body []byte
+			if str, ok := tt.requestBody.(string); ok {
+				body = []byte(str)
+			} else {
+				body, _ = json.Marshal(tt.requestBody)
+			}
+
+			req, _ := http.NewRequest("POST", "/refresh", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	expiresAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	withToken := func(c *gin.Context) {
+		c.Set("session_id", uint(4))
+		c.Set("token_id", "jti-1")
+		c.Set("token_expires_at", expiresAt)
+		c.Next()
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		authenticated  bool
+		mockSetup      func(*testing.T, *MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:          "success - without body",
+			authenticated: true,
+			mockSetup: func(t *testing.T, m *MockAuthService) {
+				m.LogoutFunc = func(userID, sessionID uint, jti string, exp time.Time, refreshToken string) error {
+					assert.Equal(t, uint(1), userID)
+					assert.Equal(t, uint(4), sessionID)
+					assert.Equal(t, "jti-1", jti)
+					assert.Equal(t, expiresAt, exp)
+					assert.Empty(t, refreshToken)
+					return nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Sesión cerrada exitosamente"}`,
+		},
+		{
+			name:          "success - with refresh token",
+			requestBody:   `{"refresh_token":"refresh-123"}`,
+			authenticated: true,
+			mockSetup: func(t *testing.T, m *MockAuthService) {
+				m.LogoutFunc = func(userID, sessionID uint, jti string, exp time.Time, refreshToken string) error {
+					assert.Equal(t, "refresh-123", refreshToken)
+					return nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Sesión cerrada exitosamente"}`,
+		},
+		{
+			name:           "error - invalid JSON",
+			requestBody:    `{"refresh_token":`,
+			authenticated:  true,
+			mockSetup:      func(t *testing.T, m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos inválidos"}`,
+		},
+		{
+			name:           "error - unauthenticated",
+			mockSetup:      func(t *testing.T, m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"No autorizado"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authHandler, mockService := NewAuthHandlerWithMock()
+			tt.mockSetup(t, mockService)
+
+			router := setupRouter()
+			if tt.authenticated {
+				router.POST("/logout", withUserID(1), withToken, authHandler.Logout)
+			} else {
+				router.POST("/logout", authHandler.Logout)
+			}
+
+			req, _ := http.NewRequest("POST", "/logout", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		authenticated  bool
+		mockSetup      func(*testing.T, *MockAuthService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:          "success - new tokens issued",
+			requestBody:   `{"current_password":"old-password","new_password":"new-password"}`,
+			authenticated: true,
+			mockSetup: func(t *testing.T, m *MockAuthService) {
+				m.ChangePasswordFunc = func(userID uint, currentPassword, newPassword string, client dto.ClientInfo) (dto.TokenPair, error) {
+					assert.Equal(t, uint(1), userID)
+					assert.Equal(t, "old-password", currentPassword)
+					assert.Equal(t, "new-password", newPassword)
+					return dto.TokenPair{AccessToken: "jwt-token-456", RefreshToken: "refresh-456", ExpiresIn: 15 * time.Minute}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Contraseña actualizada exitosamente","token":"jwt-token-456","refresh_token":"refresh-456","token_type":"Bearer","expires_in":900}`,
+		},
+		{
+			name:          "error - incorrect current password",
+			requestBody:   `{"current_password":"wrong","new_password":"new-password"}`,
+			authenticated: true,
+			mockSetup: func(t *testing.T, m *MockAuthService) {
+				m.ChangePasswordFunc = func(userID uint, currentPassword, newPassword string, client dto.ClientInfo) (dto.TokenPair, error) {
+					return dto.TokenPair{}, appErrors.ErrIncorrectPassword
+				}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"La contraseña actual es incorrecta"}`,
+		},
+		{
+			name:           "error - short new password",
+			requestBody:    `{"current_password":"old-password","new_password":"123"}`,
+			authenticated:  true,
+			mockSetup:      func(t *testing.T, m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"newpassword":"Debe tener al menos 8 caracteres"}}`,
+		},
+		{
+			name:           "error - new password equal to the current one",
+			requestBody:    `{"current_password":"old-password","new_password":"old-password"}`,
+			authenticated:  true,
+			mockSetup:      func(t *testing.T, m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"newpassword":"Debe ser distinto de currentpassword"}}`,
+		},
+		{
+			name:           "error - invalid JSON",
+			requestBody:    `{"current_password":`,
+			authenticated:  true,
+			mockSetup:      func(t *testing.T, m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos inválidos"}`,
+		},
+		{
+			name:           "error - unauthenticated",
+			mockSetup:      func(t *testing.T, m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"No autorizado"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authHandler, mockService := NewAuthHandlerWithMock()
+			tt.mockSetup(t, mockService)
+
+			router := setupRouter()
+			if tt.authenticated {
+				router.POST("/me/password", withUserID(1), authHandler.ChangePassword)
+			} else {
+				router.POST("/me/password", authHandler.ChangePassword)
+			}
+
+			req, _ := http.NewRequest("POST", "/me/password", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"net/http"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentService domainService.CommentServiceInterface
+}
+
+func NewCommentHandler(commentService *services.CommentService) *CommentHandler {
+	return &CommentHandler{commentService}
+}
+
+// GetByPost devuelve los comentarios aprobados de la publicación en forma de árbol
+func (h *CommentHandler) GetByPost(c *gin.Context) {
+	postID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var query dto.CommentTreeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleBadRequest(c, "Parámetros inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&query); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	viewerID, _ := getUserID(c)
+	comments, err := h.commentService.GetTree(postID, viewerID, query.Depth)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, comments)
+}
+
+func (h *CommentHandler) Create(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	postID, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	comment, err := h.commentService.Create(req.ToComment(postID, userID))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.NewCommentResponse(comment))
+}
+
+func (h *CommentHandler) Update(c *gin.Context) {
+	principal, err := getPrincipal(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	comment, err := h.commentService.Update(id, req.Body, principal)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewCommentResponse(comment))
+}
+
+func (h *CommentHandler) Delete(c *gin.Context) {
+	principal, err := getPrincipal(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	if err := h.commentService.Delete(id, principal); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	utils.SendNoContent(c)
+}
+
+// GetPending devuelve la cola de moderación
+func (h *CommentHandler) GetPending(c *gin.Context) {
+	comments, err := h.commentService.GetPending()
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewCommentResponses(comments))
+}
+
+// Moderate aprueba, rechaza o marca como spam varios comentarios a la vez
+func (h *CommentHandler) Moderate(c *gin.Context) {
+	var req dto.ModerateCommentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	updated, err := h.commentService.Moderate(req.IDs, model.CommentStatus(req.Status))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ModerateCommentsResponse{Updated: updated})
+}
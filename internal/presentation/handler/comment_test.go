@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockCommentService mocks the CommentService for handler testing
+type MockCommentService struct {
+	GetTreeFunc    func(postID uint, viewerID uint, depth int) ([]dto.CommentNode, error)
+	GetPendingFunc func() ([]model.Comment, error)
+	CreateFunc     func(comment model.Comment) (model.Comment, error)
+	UpdateFunc     func(id uint, body string, principal model.Principal) (model.Comment, error)
+	DeleteFunc     func(id uint, principal model.Principal) error
+	ModerateFunc   func(ids []uint, status model.CommentStatus) (int64, error)
+}
+
+func (m *MockCommentService) GetTree(postID uint, viewerID uint, depth int) ([]dto.CommentNode, error) {
+	if m.GetTreeFunc != nil {
+		return m.GetTreeFunc(postID, viewerID, depth)
+	}
+	return []dto.CommentNode{}, nil
+}
+
+func (m *MockCommentService) GetPending() ([]model.Comment, error) {
+	if m.GetPendingFunc != nil {
+		return m.GetPendingFunc()
+	}
+	return []model.Comment{}, nil
+}
+
+func (m *MockCommentService) Create(comment model.Comment) (model.Comment, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(comment)
+	}
+	return comment, nil
+}
+
+func (m *MockCommentService) Update(id uint, body string, principal model.Principal) (model.Comment, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, body, principal)
+	}
+	return model.Comment{ID: id, Body: body}, nil
+}
+
+func (m *MockCommentService) Delete(id uint, principal model.Principal) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, principal)
+	}
+	return nil
+}
+
+func (m *MockCommentService) Moderate(ids []uint, status model.CommentStatus) (int64, error) {
+	if m.ModerateFunc != nil {
+		return m.ModerateFunc(ids, status)
+	}
+	return 0, nil
+}
+
+// NewCommentHandlerWithMock creates a CommentHandler with a mock service for testing
+func NewCommentHandlerWithMock() (*CommentHandler, *MockCommentService) {
+	mockService := &MockCommentService{}
+	commentHandler := &CommentHandler{commentService: mockService}
+	return commentHandler, mockService
+}
+
+func TestNewCommentHandler(t *testing.T) {
+	mockService := &services.CommentService{}
+	commentHandler := NewCommentHandler(mockService)
+
+	assert.NotNil(t, commentHandler)
+	assert.Equal(t, mockService, commentHandler.commentService)
+}
+
+func TestCommentHandler_GetByPost(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockCommentService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "success - tree with depth",
+			query: "?depth=2",
+			mockSetup: func(m *MockCommentService) {
+				m.GetTreeFunc = func(postID uint, viewerID uint, depth int) ([]dto.CommentNode, error) {
+					assert.Equal(t, uint(1), postID)
+					assert.Equal(t, 2, depth)
+					return []dto.CommentNode{
+						{CommentResponse: dto.CommentResponse{ID: 1, PostID: 1, AuthorID: 2, Body: "Hola", Status: model.CommentStatusApproved}, Replies: []dto.CommentNode{}, MoreReplies: true},
+					}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"id":1,"post_id":1,"parent_id":null,"author_id":2,"body":"Hola","status":"approved","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","replies":[],"more_replies":true}]`,
+		},
+		{
+			name:           "error - depth above the limit",
+			query:          "?depth=50",
+			mockSetup:      func(m *MockCommentService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"depth":"No puede ser mayor que 10"}}`,
+		},
+		{
+			name:  "error - post not found",
+			query: "",
+			mockSetup: func(m *MockCommentService) {
+				m.GetTreeFunc = func(postID uint, viewerID uint, depth int) ([]dto.CommentNode, error) {
+					return nil, appErrors.ErrPostNotFound
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Publicación no encontrada"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentHandler, mockService := NewCommentHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.GET("/posts/:id/comments", commentHandler.GetByPost)
+
+			req, _ := http.NewRequest("GET", "/posts/1/comments"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestCommentHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		authenticated  bool
+		mockSetup      func(*MockCommentService)
+		expectedStatus int
+	}{
+		{
+			name:          "success - reply created",
+			requestBody:   dto.CreateCommentRequest{Body: "Hola", ParentID: func() *uint { id := uint(3); return &id }()},
+			authenticated: true,
+			mockSetup: func(m *MockCommentService) {
+				m.CreateFunc = func(comment model.Comment) (model.Comment, error) {
+					assert.Equal(t, uint(1), comment.PostID)
+					assert.Equal(t, uint(7), comment.AuthorID)
+					assert.Equal(t, uint(3), *comment.ParentID)
+					comment.Status = model.CommentStatusPending
+					return comment, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "error - empty body",
+			requestBody:    dto.CreateCommentRequest{},
+			authenticated:  true,
+			mockSetup:      func(m *MockCommentService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:          "error - invalid parent",
+			requestBody:   dto.CreateCommentRequest{Body: "Hola"},
+			authenticated: true,
+			mockSetup: func(m *MockCommentService) {
+				m.CreateFunc = func(comment model.Comment) (model.Comment, error) {
+					return model.Comment{}, appErrors.ErrInvalidParentComment
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "error - no user in context",
+			requestBody:    dto.CreateCommentRequest{Body: "Hola"},
+			authenticated:  false,
+			mockSetup:      func(m *MockCommentService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentHandler, mockService := NewCommentHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			if tt.authenticated {
+				router.Use(withUserID(7))
+			}
+			router.POST("/posts/:id/comments", commentHandler.Create)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/posts/1/comments", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestCommentHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockCommentService)
+		expectedStatus int
+	}{
+		{
+			name:           "success - comment updated",
+			mockSetup:      func(m *MockCommentService) {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "error - edit window expired",
+			mockSetup: func(m *MockCommentService) {
+				m.UpdateFunc = func(id uint, body string, principal model.Principal) (model.Comment, error) {
+					return model.Comment{}, appErrors.ErrCommentEditExpired
+				}
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentHandler, mockService := NewCommentHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.Use(withUserID(7))
+			router.PUT("/comments/:id", commentHandler.Update)
+
+			body, _ := json.Marshal(dto.UpdateCommentRequest{Body: "Editado"})
+			req, _ := http.NewRequest("PUT", "/comments/1", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestCommentHandler_Delete(t *testing.T) {
+	commentHandler, mockService := NewCommentHandlerWithMock()
+	mockService.DeleteFunc = func(id uint, principal model.Principal) error {
+		assert.Equal(t, uint(1), id)
+		assert.Equal(t, model.Principal{UserID: 7}, principal)
+		return nil
+	}
+
+	router := setupRouter()
+	router.Use(withUserID(7))
+	router.DELETE("/comments/:id", commentHandler.Delete)
+
+	req, _ := http.NewRequest("DELETE", "/comments/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestCommentHandler_Moderate(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "success - comments approved",
+			requestBody:    dto.ModerateCommentsRequest{IDs: []uint{1, 2}, Status: "approved"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"updated":2}`,
+		},
+		{
+			name:           "error - pending is not a moderation result",
+			requestBody:    dto.ModerateCommentsRequest{IDs: []uint{1}, Status: "pending"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"status":"Debe ser uno de: approved, rejected, spam"}}`,
+		},
+		{
+			name:           "error - no ids",
+			requestBody:    dto.ModerateCommentsRequest{Status: "spam"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"ids":"Este campo es obligatorio"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentHandler, mockService := NewCommentHandlerWithMock()
+			mockService.ModerateFunc = func(ids []uint, status model.CommentStatus) (int64, error) {
+				return int64(len(ids)), nil
+			}
+
+			router := setupRouter()
+			router.POST("/comments/moderate", commentHandler.Moderate)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/comments/moderate", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// getUserID obtiene el ID del usuario autenticado que AuthMiddleware guarda en el contexto
+func getUserID(c *gin.Context) (uint, error) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return 0, appErrors.ErrUnauthorized
+	}
+	userID, ok := value.(uint)
+	if !ok || userID == 0 {
+		return 0, appErrors.ErrUnauthorized
+	}
+	return userID, nil
+}
+
+// getRole obtiene el rol del usuario autenticado, o "" si la petición es anónima
+func getRole(c *gin.Context) model.Role {
+	role, _ := c.Value("role").(model.Role)
+	return role
+}
+
+// getPrincipal obtiene el usuario autenticado y su rol para las comprobaciones
+// de autorización de los servicios
+func getPrincipal(c *gin.Context) (model.Principal, error) {
+	userID, err := getUserID(c)
+	if err != nil {
+		return model.Principal{}, err
+	}
+	return model.Principal{UserID: userID, Role: getRole(c)}, nil
+}
+
+// getTokenID obtiene el jti y la expiración del token de acceso de la petición
+func getTokenID(c *gin.Context) (string, time.Time) {
+	jti := c.GetString("token_id")
+	expiresAt := c.GetTime("token_expires_at")
+	return jti, expiresAt
+}
+
+// getSessionID obtiene la sesión del token de acceso de la petición, o 0 si el
+// token no la incluye
+func getSessionID(c *gin.Context) uint {
+	return c.GetUint("session_id")
+}
+
+// maxUserAgentLength es la longitud máxima del user agent que se guarda en la sesión
+const maxUserAgentLength = 512
+
+// clientInfo obtiene el dispositivo desde el que se hace la petición
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	userAgent := c.Request.UserAgent()
+	if runes := []rune(userAgent); len(runes) > maxUserAgentLength {
+		userAgent = string(runes[:maxUserAgentLength])
+	}
+	return dto.ClientInfo{IP: c.ClientIP(), UserAgent: userAgent}
+}
+
+// parseIDParam convierte un parámetro de ruta en un ID numérico
+func parseIDParam(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		return 0, appErrors.ErrInvalidID
+	}
+	return uint(id), nil
+}
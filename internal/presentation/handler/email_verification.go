@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type EmailVerificationHandler struct {
+	verificationService domainService.EmailVerificationServiceInterface
+}
+
+func NewEmailVerificationHandler(verificationService *services.EmailVerificationService) *EmailVerificationHandler {
+	return &EmailVerificationHandler{verificationService}
+}
+
+// Verify marca como verificado el email del enlace recibido en ?token=
+func (h *EmailVerificationHandler) Verify(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		utils.HandleBadRequest(c, "El token de verificación es obligatorio")
+		return
+	}
+
+	if err := h.verificationService.Verify(token); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verificado exitosamente"})
+}
+
+// Resend envía un nuevo enlace de verificación. La respuesta es la misma exista
+// o no la cuenta
+func (h *EmailVerificationHandler) Resend(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	if err := h.verificationService.Resend(req.Email); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Si la cuenta existe y no está verificada, recibirás un nuevo enlace"})
+}
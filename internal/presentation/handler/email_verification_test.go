@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockEmailVerificationService mocks the EmailVerificationService for handler testing
+type MockEmailVerificationService struct {
+	VerifyFunc func(token string) error
+	ResendFunc func(email string) error
+}
+
+func (m *MockEmailVerificationService) Send(user model.User) error {
+	return nil
+}
+
+func (m *MockEmailVerificationService) Verify(token string) error {
+	if m.VerifyFunc != nil {
+		return m.VerifyFunc(token)
+	}
+	return nil
+}
+
+func (m *MockEmailVerificationService) Resend(email string) error {
+	if m.ResendFunc != nil {
+		return m.ResendFunc(email)
+	}
+	return nil
+}
+
+func TestNewEmailVerificationHandler(t *testing.T) {
+	mockService := &services.EmailVerificationService{}
+	verificationHandler := NewEmailVerificationHandler(mockService)
+
+	assert.NotNil(t, verificationHandler)
+	assert.Equal(t, mockService, verificationHandler.verificationService)
+}
+
+func TestEmailVerificationHandler_Verify(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		verifyFunc     func(token string) error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success - email verified",
+			url:  "/verify?token=abc.def",
+			verifyFunc: func(token string) error {
+				assert.Equal(t, "abc.def", token)
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Email verificado exitosamente"}`,
+		},
+		{
+			name:           "error - missing token",
+			url:            "/verify",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"El token de verificación es obligatorio"}`,
+		},
+		{
+			name: "error - invalid token",
+			url:  "/verify?token=used",
+			verifyFunc: func(token string) error {
+				return appErrors.ErrInvalidVerificationToken
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Token de verificación inválido o expirado"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verificationHandler := &EmailVerificationHandler{&MockEmailVerificationService{VerifyFunc: tt.verifyFunc}}
+			router := setupRouter()
+			router.GET("/verify", verificationHandler.Verify)
+
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestEmailVerificationHandler_Resend(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		resendFunc     func(email string) error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "success - same response for any account",
+			requestBody: dto.ResendVerificationRequest{Email: "john@example.com"},
+			resendFunc: func(email string) error {
+				assert.Equal(t, "john@example.com", email)
+				return nil
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"message":"Si la cuenta existe y no está verificada, recibirás un nuevo enlace"}`,
+		},
+		{
+			name:           "error - invalid email",
+			requestBody:    dto.ResendVerificationRequest{Email: "not-an-email"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"email":"Debe ser un email válido"}}`,
+		},
+		{
+			name:           "error - invalid JSON",
+			requestBody:    `{"email":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos inválidos"}`,
+		},
+		{
+			name:        "error - database failure",
+			requestBody: dto.ResendVerificationRequest{Email: "john@example.com"},
+			resendFunc: func(email string) error {
+				return appErrors.ErrDatabaseConnection
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verificationHandler := &EmailVerificationHandler{&MockEmailVerificationService{ResendFunc: tt.resendFunc}}
+			router := setupRouter()
+			router.POST("/verify/resend", verificationHandler.Resend)
+
+			var body []byte
+			if str, ok := tt.requestBody.(string); ok {
+				body = []byte(str)
+			} else {
+				body, _ = json.Marshal(tt.requestBody)
+			}
+			req, _ := http.NewRequest(http.MethodPost, "/verify/resend", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type LoginThrottleHandler struct {
+	throttle domainService.LoginThrottleInterface
+}
+
+func NewLoginThrottleHandler(throttle *services.LoginThrottle) *LoginThrottleHandler {
+	return &LoginThrottleHandler{throttle}
+}
+
+// Unlock levanta el bloqueo por intentos fallidos de la cuenta del usuario :id
+func (h *LoginThrottleHandler) Unlock(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	if err := h.throttle.Unlock(id); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cuenta desbloqueada exitosamente"})
+}
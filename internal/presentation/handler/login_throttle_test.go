@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockLoginThrottle mocks the LoginThrottle for handler testing
+type MockLoginThrottle struct {
+	UnlockFunc func(userID uint) error
+}
+
+func (m *MockLoginThrottle) Check(email, ip string, now time.Time) error {
+	return nil
+}
+
+func (m *MockLoginThrottle) Fail(email, ip string, now time.Time) error {
+	return nil
+}
+
+func (m *MockLoginThrottle) Succeed(email string) error {
+	return nil
+}
+
+func (m *MockLoginThrottle) Unlock(userID uint) error {
+	if m.UnlockFunc != nil {
+		return m.UnlockFunc(userID)
+	}
+	return nil
+}
+
+func TestNewLoginThrottleHandler(t *testing.T) {
+	throttle := &services.LoginThrottle{}
+	loginThrottleHandler := NewLoginThrottleHandler(throttle)
+
+	assert.NotNil(t, loginThrottleHandler)
+	assert.Equal(t, throttle, loginThrottleHandler.throttle)
+}
+
+func TestLoginThrottleHandler_Unlock(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		unlockFunc     func(userID uint) error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success - account unlocked",
+			path: "/users/5/unlock",
+			unlockFunc: func(userID uint) error {
+				assert.Equal(t, uint(5), userID)
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Cuenta desbloqueada exitosamente"}`,
+		},
+		{
+			name: "error - user not found",
+			path: "/users/5/unlock",
+			unlockFunc: func(userID uint) error {
+				return appErrors.ErrUserNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Usuario no encontrado"}`,
+		},
+		{
+			name:           "error - invalid id",
+			path:           "/users/abc/unlock",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"ID inválido"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loginThrottleHandler := &LoginThrottleHandler{&MockLoginThrottle{UnlockFunc: tt.unlockFunc}}
+			router := setupRouter()
+			router.POST("/users/:id/unlock", loginThrottleHandler.Unlock)
+
+			req, _ := http.NewRequest(http.MethodPost, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// oidcStateCookie guarda el estado del inicio de sesión externo en el navegador
+// mientras el usuario está en la página del proveedor
+const oidcStateCookie = "oidc_state"
+
+// OIDCHandler gestiona el inicio de sesión con proveedores externos.
+// secureCookies se decide a partir de APPURL y no de la petición, porque
+// cualquier cliente puede enviar X-Forwarded-Proto
+type OIDCHandler struct {
+	oidcService   domainService.OIDCServiceInterface
+	secureCookies bool
+}
+
+func NewOIDCHandler(oidcService *services.OIDCService, secureCookies bool) *OIDCHandler {
+	return &OIDCHandler{oidcService, secureCookies}
+}
+
+// Providers lista los proveedores con los que se puede iniciar sesión
+func (h *OIDCHandler) Providers(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.OIDCProvidersResponse{Providers: h.oidcService.Providers()})
+}
+
+// Begin redirige al usuario a la página de inicio de sesión del proveedor
+func (h *OIDCHandler) Begin(c *gin.Context) {
+	authorization, err := h.oidcService.Begin(c.Param("provider"))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	// La cookie se limita a esta ruta y la del callback, que cuelga de ella. Lax
+	// permite enviarla en la redirección de vuelta desde el proveedor
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, authorization.State, int(authorization.ExpiresIn.Seconds()), c.Request.URL.Path, "", h.secureCookies, true)
+	c.Redirect(http.StatusFound, authorization.URL)
+}
+
+// Callback completa el inicio de sesión cuando el proveedor redirige de vuelta
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	state, _ := c.Cookie(oidcStateCookie)
+	// El estado es de un solo uso
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, "", -1, strings.TrimSuffix(c.Request.URL.Path, "/callback"), "", h.secureCookies, true)
+
+	// El proveedor informa así de que el usuario canceló o no pudo iniciar sesión
+	if c.Query("error") != "" {
+		utils.HandleError(c, appErrors.ErrOIDCAuthenticationFailed)
+		return
+	}
+
+	result, err := h.oidcService.Callback(c.Param("provider"), c.Query("code"), c.Query("state"), state, clientInfo(c))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	respondLogin(c, result)
+}
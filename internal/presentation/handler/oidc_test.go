@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOIDCService mocks the OIDCService for handler testing
+type MockOIDCService struct {
+	ProvidersFunc func() []string
+	BeginFunc     func(provider string) (dto.OIDCAuthorization, error)
+	CallbackFunc  func(provider, code, state, stateCookie string, client dto.ClientInfo) (dto.LoginResult, error)
+}
+
+func (m *MockOIDCService) Providers() []string {
+	if m.ProvidersFunc != nil {
+		return m.ProvidersFunc()
+	}
+	return nil
+}
+
+func (m *MockOIDCService) Begin(provider string) (dto.OIDCAuthorization, error) {
+	if m.BeginFunc != nil {
+		return m.BeginFunc(provider)
+	}
+	return dto.OIDCAuthorization{}, nil
+}
+
+func (m *MockOIDCService) Callback(provider, code, state, stateCookie string, client dto.ClientInfo) (dto.LoginResult, error) {
+	if m.CallbackFunc != nil {
+		return m.CallbackFunc(provider, code, state, stateCookie, client)
+	}
+	return dto.LoginResult{}, nil
+}
+
+func TestNewOIDCHandler(t *testing.T) {
+	mockService := &services.OIDCService{}
+	oidcHandler := NewOIDCHandler(mockService, true)
+
+	assert.NotNil(t, oidcHandler)
+	assert.Equal(t, mockService, oidcHandler.oidcService)
+	assert.True(t, oidcHandler.secureCookies)
+}
+
+func TestOIDCHandler_Providers(t *testing.T) {
+	oidcHandler := &OIDCHandler{oidcService: &MockOIDCService{ProvidersFunc: func() []string {
+		return []string{"gitlab", "google"}
+	}}}
+	router := setupRouter()
+	router.GET("/auth/oidc", oidcHandler.Providers)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth/oidc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"providers":["gitlab","google"]}`, w.Body.String())
+}
+
+func TestOIDCHandler_Begin(t *testing.T) {
+	t.Run("success - redirect with state cookie", func(t *testing.T) {
+		oidcHandler := &OIDCHandler{oidcService: &MockOIDCService{BeginFunc: func(provider string) (dto.OIDCAuthorization, error) {
+			assert.Equal(t, "google", provider)
+			return dto.OIDCAuthorization{URL: "https://accounts.example.com/authorize?state=abc", State: "signed-state", ExpiresIn: 10 * time.Minute}, nil
+		}}}
+		router := setupRouter()
+		router.GET("/auth/oidc/:provider", oidcHandler.Begin)
+
+		req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/google", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "https://accounts.example.com/authorize?state=abc", w.Header().Get("Location"))
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, oidcStateCookie, cookies[0].Name)
+		assert.Equal(t, "signed-state", cookies[0].Value)
+		assert.Equal(t, "/auth/oidc/google", cookies[0].Path)
+		assert.Equal(t, 600, cookies[0].MaxAge)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	})
+
+	t.Run("success - Secure follows the configuration, not the request headers", func(t *testing.T) {
+		for _, secure := range []bool{true, false} {
+			oidcHandler := &OIDCHandler{oidcService: &MockOIDCService{BeginFunc: func(provider string) (dto.OIDCAuthorization, error) {
+				return dto.OIDCAuthorization{URL: "https://accounts.example.com/authorize", State: "signed-state", ExpiresIn: time.Minute}, nil
+			}}, secureCookies: secure}
+			router := setupRouter()
+			router.GET("/auth/oidc/:provider", oidcHandler.Begin)
+
+			req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/google", nil)
+			req.Header.Set("X-Forwarded-Proto", "https")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			cookies := w.Result().Cookies()
+			require.Len(t, cookies, 1)
+			assert.Equal(t, secure, cookies[0].Secure)
+		}
+	})
+
+	t.Run("error - unknown provider", func(t *testing.T) {
+		oidcHandler := &OIDCHandler{oidcService: &MockOIDCService{BeginFunc: func(provider string) (dto.OIDCAuthorization, error) {
+			return dto.OIDCAuthorization{}, appErrors.ErrOIDCProviderNotFound
+		}}}
+		router := setupRouter()
+		router.GET("/auth/oidc/:provider", oidcHandler.Begin)
+
+		req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/other", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error":"Proveedor de inicio de sesión desconocido"}`, w.Body.String())
+	})
+}
+
+func TestOIDCHandler_Callback(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		callbackFunc   func(provider, code, state, stateCookie string, client dto.ClientInfo) (dto.LoginResult, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "success - tokens issued",
+			query: "?code=code-123&state=state-123",
+			callbackFunc: func(provider, code, state, stateCookie string, client dto.ClientInfo) (dto.LoginResult, error) {
+				assert.Equal(t, "google", provider)
+				assert.Equal(t, "code-123", code)
+				assert.Equal(t, "state-123", state)
+				assert.Equal(t, "signed-state", stateCookie)
+				return dto.LoginResult{Tokens: dto.TokenPair{AccessToken: "jwt-token-123", RefreshToken: "refresh-123", ExpiresIn: 15 * time.Minute}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Inicio de sesión exitoso","token":"jwt-token-123","refresh_token":"refresh-123","token_type":"Bearer","expires_in":900}`,
+		},
+		{
+			name:  "success - two-factor challenge",
+			query: "?code=code-123&state=state-123",
+			callbackFunc: func(provider, code, state, stateCookie string, client dto.ClientInfo) (dto.LoginResult, error) {
+				return dto.LoginResult{Challenge: &dto.TwoFactorChallenge{Token: "challenge-123", ExpiresIn: 5 * time.Minute}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Introduce el código de verificación en dos pasos","two_factor_required":true,"challenge_token":"challenge-123","expires_in":300}`,
+		},
+		{
+			name:  "error - invalid state",
+			query: "?code=code-123&state=other",
+			callbackFunc: func(provider, code, state, stateCookie string, client dto.ClientInfo) (dto.LoginResult, error) {
+				return dto.LoginResult{}, appErrors.ErrInvalidOIDCState
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"La solicitud de inicio de sesión expiró o no es válida; inténtalo de nuevo"}`,
+		},
+		{
+			name:  "error - cancelled at the provider",
+			query: "?error=access_denied&state=state-123",
+			callbackFunc: func(provider, code, state, stateCookie string, client dto.ClientInfo) (dto.LoginResult, error) {
+				t.Fatal("the service must not be called")
+				return dto.LoginResult{}, nil
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"No se pudo iniciar sesión con el proveedor"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oidcHandler := &OIDCHandler{oidcService: &MockOIDCService{CallbackFunc: tt.callbackFunc}}
+			router := setupRouter()
+			router.GET("/auth/oidc/:provider/callback", oidcHandler.Callback)
+
+			req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/google/callback"+tt.query, nil)
+			req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: "signed-state"})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			cookies := w.Result().Cookies()
+			require.Len(t, cookies, 1)
+			assert.Equal(t, "/auth/oidc/google", cookies[0].Path)
+			assert.Less(t, cookies[0].MaxAge, 0)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordResetHandler struct {
+	passwordResetService domainService.PasswordResetServiceInterface
+}
+
+func NewPasswordResetHandler(passwordResetService *services.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{passwordResetService}
+}
+
+// Forgot envía un enlace para restablecer la contraseña. La respuesta es la misma
+// esté o no registrado el email
+func (h *PasswordResetHandler) Forgot(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	if err := h.passwordResetService.Forgot(req.Email); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Si el email está registrado, recibirás un enlace para restablecer tu contraseña"})
+}
+
+// Reset establece una nueva contraseña y cierra todas las sesiones del usuario
+func (h *PasswordResetHandler) Reset(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	if err := h.passwordResetService.Reset(req.Token, req.Password); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contraseña restablecida exitosamente"})
+}
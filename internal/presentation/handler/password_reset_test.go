@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockPasswordResetService mocks the PasswordResetService for handler testing
+type MockPasswordResetService struct {
+	ForgotFunc func(email string) error
+	ResetFunc  func(token, password string) error
+}
+
+func (m *MockPasswordResetService) Forgot(email string) error {
+	if m.ForgotFunc != nil {
+		return m.ForgotFunc(email)
+	}
+	return nil
+}
+
+func (m *MockPasswordResetService) Reset(token, password string) error {
+	if m.ResetFunc != nil {
+		return m.ResetFunc(token, password)
+	}
+	return nil
+}
+
+func TestNewPasswordResetHandler(t *testing.T) {
+	mockService := &services.PasswordResetService{}
+	passwordResetHandler := NewPasswordResetHandler(mockService)
+
+	assert.NotNil(t, passwordResetHandler)
+	assert.Equal(t, mockService, passwordResetHandler.passwordResetService)
+}
+
+func TestPasswordResetHandler_Forgot(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		forgotFunc     func(email string) error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "success - same response for any email",
+			requestBody: dto.ForgotPasswordRequest{Email: "john@example.com"},
+			forgotFunc: func(email string) error {
+				assert.Equal(t, "john@example.com", email)
+				return nil
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"message":"Si el email está registrado, recibirás un enlace para restablecer tu contraseña"}`,
+		},
+		{
+			name:           "error - invalid email",
+			requestBody:    dto.ForgotPasswordRequest{Email: "john"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"email":"Debe ser un email válido"}}`,
+		},
+		{
+			name:           "error - invalid JSON",
+			requestBody:    `{"email":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos inválidos"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passwordResetHandler := &PasswordResetHandler{&MockPasswordResetService{ForgotFunc: tt.forgotFunc}}
+			router := setupRouter()
+			router.POST("/forgot-password", passwordResetHandler.Forgot)
+
+			var body []byte
+			if str, ok := tt.requestBody.(string); ok {
+				body = []byte(str)
+			} else {
+				body, _ = json.Marshal(tt.requestBody)
+			}
+			req, _ := http.NewRequest(http.MethodPost, "/forgot-password", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestPasswordResetHandler_Reset(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		resetFunc      func(token, password string) error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "success - password reset",
+			requestBody: dto.ResetPasswordRequest{Token: "reset-token", Password: "new-password"},
+			resetFunc: func(token, password string) error {
+				assert.Equal(t, "reset-token", token)
+				assert.Equal(t, "new-password", password)
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Contraseña restablecida exitosamente"}`,
+		},
+		{
+			name:        "error - invalid token",
+			requestBody: dto.ResetPasswordRequest{Token: "used", Password: "new-password"},
+			resetFunc: func(token, password string) error {
+				return appErrors.ErrInvalidResetToken
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Token de restablecimiento inválido o expirado"}`,
+		},
+		{
+			name:           "error - short password",
+			requestBody:    dto.ResetPasswordRequest{Token: "reset-token", Password: "123"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"password":"Debe tener al menos 6 caracteres"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passwordResetHandler := &PasswordResetHandler{&MockPasswordResetService{ResetFunc: tt.resetFunc}}
+			router := setupRouter()
+			router.POST("/reset-password", passwordResetHandler.Reset)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/reset-password", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
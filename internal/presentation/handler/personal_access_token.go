@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalAccessTokenHandler struct {
+	tokenService domainService.PersonalAccessTokenServiceInterface
+}
+
+func NewPersonalAccessTokenHandler(tokenService *services.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{tokenService}
+}
+
+// Create genera un token de acceso personal para el usuario autenticado. El
+// token solo se muestra en esta respuesta
+func (h *PersonalAccessTokenHandler) Create(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	accessToken, token, err := h.tokenService.Create(userID, req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreatedAccessTokenResponse{
+		Message:     "Token creado. Guárdalo en un lugar seguro: no se volverá a mostrar",
+		Token:       token,
+		AccessToken: dto.NewAccessTokenResponse(accessToken),
+	})
+}
+
+// List devuelve los tokens activos del usuario autenticado, sin su valor
+func (h *PersonalAccessTokenHandler) List(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	tokens, err := h.tokenService.List(userID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAccessTokenResponses(tokens))
+}
+
+// Revoke revoca un token del usuario autenticado
+func (h *PersonalAccessTokenHandler) Revoke(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	if err := h.tokenService.Revoke(userID, id); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token revocado"})
+}
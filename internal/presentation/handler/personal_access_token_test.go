@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockPersonalAccessTokenService mocks the PersonalAccessTokenService for handler testing
+type MockPersonalAccessTokenService struct {
+	CreateFunc func(userID uint, req dto.CreateAccessTokenRequest) (model.PersonalAccessToken, string, error)
+	ListFunc   func(userID uint) ([]model.PersonalAccessToken, error)
+	RevokeFunc func(userID, id uint) error
+}
+
+func (m *MockPersonalAccessTokenService) Create(userID uint, req dto.CreateAccessTokenRequest) (model.PersonalAccessToken, string, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(userID, req)
+	}
+	return model.PersonalAccessToken{}, "", nil
+}
+
+func (m *MockPersonalAccessTokenService) List(userID uint) ([]model.PersonalAccessToken, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockPersonalAccessTokenService) Revoke(userID, id uint) error {
+	if m.RevokeFunc != nil {
+		return m.RevokeFunc(userID, id)
+	}
+	return nil
+}
+
+func (m *MockPersonalAccessTokenService) Authenticate(token string) (model.PersonalAccessToken, error) {
+	return model.PersonalAccessToken{}, nil
+}
+
+func TestNewPersonalAccessTokenHandler(t *testing.T) {
+	mockService := &services.PersonalAccessTokenService{}
+	tokenHandler := NewPersonalAccessTokenHandler(mockService)
+
+	assert.NotNil(t, tokenHandler)
+	assert.Equal(t, mockService, tokenHandler.tokenService)
+}
+
+func TestPersonalAccessTokenHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		createFunc     func(userID uint, req dto.CreateAccessTokenRequest) (model.PersonalAccessToken, string, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "success - token shown once",
+			requestBody: `{"name":"CI","scopes":["posts:read","posts:write"]}`,
+			createFunc: func(userID uint, req dto.CreateAccessTokenRequest) (model.PersonalAccessToken, string, error) {
+				assert.Equal(t, uint(1), userID)
+				assert.Equal(t, "CI", req.Name)
+				assert.Equal(t, []string{"posts:read", "posts:write"}, req.Scopes)
+				assert.Nil(t, req.ExpiresAt)
+				return model.PersonalAccessToken{ID: 3, UserID: 1, Name: "CI", Scopes: model.Scopes{model.ScopePostsRead, model.ScopePostsWrite}}, "blog_pat_secret", nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"message":"Token creado. Guárdalo en un lugar seguro: no se volverá a mostrar","token":"blog_pat_secret","access_token":{"id":3,"user_id":1,"name":"CI","scopes":["posts:read","posts:write"],"expires_at":null,"last_used_at":null,"revoked_at":null,"created_at":"0001-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:        "error - unknown scope",
+			requestBody: `{"name":"CI","scopes":["posts:admin"]}`,
+			createFunc: func(userID uint, req dto.CreateAccessTokenRequest) (model.PersonalAccessToken, string, error) {
+				return model.PersonalAccessToken{}, "", appErrors.ErrInvalidScope
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Scope desconocido"}`,
+		},
+		{
+			name:           "error - no scopes",
+			requestBody:    `{"name":"CI","scopes":[]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"scopes":"Debe tener al menos 1 elementos"}}`,
+		},
+		{
+			name:           "error - invalid JSON",
+			requestBody:    `{"name":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos inválidos"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenHandler := &PersonalAccessTokenHandler{&MockPersonalAccessTokenService{CreateFunc: tt.createFunc}}
+			router := setupRouter()
+			router.POST("/me/tokens", withUserID(1), tokenHandler.Create)
+
+			req, _ := http.NewRequest(http.MethodPost, "/me/tokens", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestPersonalAccessTokenHandler_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticated  bool
+		listFunc       func(userID uint) ([]model.PersonalAccessToken, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:          "success - tokens listed without their value",
+			authenticated: true,
+			listFunc: func(userID uint) ([]model.PersonalAccessToken, error) {
+				assert.Equal(t, uint(1), userID)
+				return []model.PersonalAccessToken{{ID: 3, UserID: 1, Name: "CI", TokenHash: "hash", Scopes: model.Scopes{model.ScopePostsRead}}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"id":3,"user_id":1,"name":"CI","scopes":["posts:read"],"expires_at":null,"last_used_at":null,"revoked_at":null,"created_at":"0001-01-01T00:00:00Z"}]`,
+		},
+		{
+			name:           "error - unauthenticated",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"No autorizado"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenHandler := &PersonalAccessTokenHandler{&MockPersonalAccessTokenService{ListFunc: tt.listFunc}}
+			router := setupRouter()
+			if tt.authenticated {
+				router.Use(withUserID(1))
+			}
+			router.GET("/me/tokens", tokenHandler.List)
+
+			req, _ := http.NewRequest(http.MethodGet, "/me/tokens", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestPersonalAccessTokenHandler_Revoke(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		revokeFunc     func(userID, id uint) error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success - token revoked",
+			id:   "3",
+			revokeFunc: func(userID, id uint) error {
+				assert.Equal(t, uint(1), userID)
+				assert.Equal(t, uint(3), id)
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Token revocado"}`,
+		},
+		{
+			name: "error - unknown token",
+			id:   "9",
+			revokeFunc: func(userID, id uint) error {
+				return appErrors.ErrAccessTokenNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Token de acceso no encontrado"}`,
+		},
+		{
+			name:           "error - invalid id",
+			id:             "abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"ID inválido"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenHandler := &PersonalAccessTokenHandler{&MockPersonalAccessTokenService{RevokeFunc: tt.revokeFunc}}
+			router := setupRouter()
+			router.DELETE("/me/tokens/:id", withUserID(1), tokenHandler.Revoke)
+
+			req, _ := http.NewRequest(http.MethodDelete, "/me/tokens/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
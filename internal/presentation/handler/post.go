@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PostHandler struct {
+	postService domainService.PostServiceInterface
+}
+
+func NewPostHandler(postService *services.PostService) *PostHandler {
+	return &PostHandler{postService}
+}
+
+// GetAll lista las publicaciones publicadas paginadas por cursor
+func (h *PostHandler) GetAll(c *gin.Context) {
+	spec, err := dto.PostListSchema.Parse(c.Request.URL.Query())
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	page, err := h.postService.GetPublished(spec)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	utils.SetPaginationLinks(c, page.NextCursor, page.PrevCursor)
+	c.JSON(http.StatusOK, dto.NewPostPage(page))
+}
+
+// GetByTag lista las publicaciones publicadas con la etiqueta indicada, paginadas por cursor
+func (h *PostHandler) GetByTag(c *gin.Context) {
+	spec, err := dto.PostListSchema.Parse(c.Request.URL.Query())
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	page, err := h.postService.GetPublishedByTag(c.Param("slug"), spec)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	utils.SetPaginationLinks(c, page.NextCursor, page.PrevCursor)
+	c.JSON(http.StatusOK, dto.NewPostPage(page))
+}
+
+// GetByCategory lista las publicaciones publicadas de la categoría y sus
+// subcategorías, paginadas por cursor
+func (h *PostHandler) GetByCategory(c *gin.Context) {
+	spec, err := dto.PostListSchema.Parse(c.Request.URL.Query())
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	page, err := h.postService.GetPublishedByCategory(c.Param("slug"), spec)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	utils.SetPaginationLinks(c, page.NextCursor, page.PrevCursor)
+	c.JSON(http.StatusOK, dto.NewPostPage(page))
+}
+
+// GetMine lista todas las publicaciones del usuario autenticado, incluidos sus borradores
+func (h *PostHandler) GetMine(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	posts, err := h.postService.GetByAuthor(userID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPostResponses(posts))
+}
+
+func (h *PostHandler) GetByID(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	// El usuario es opcional: los autores pueden ver sus propios borradores
+	viewerID, _ := getUserID(c)
+	post, err := h.postService.GetByID(id, viewerID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPostResponse(post))
+}
+
+// GetBySlug devuelve la publicación por su slug; los slugs anteriores
+// responden con una redirección permanente al slug actual
+func (h *PostHandler) GetBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	viewerID, _ := getUserID(c)
+
+	post, moved, err := h.postService.GetBySlug(slug, viewerID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	if moved {
+		location := strings.TrimSuffix(c.Request.URL.Path, slug) + post.Slug
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPostResponse(post))
+}
+
+func (h *PostHandler) Create(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.CreatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	post, err := h.postService.Create(req.ToPost(userID))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.NewPostResponse(post))
+}
+
+func (h *PostHandler) Update(c *gin.Context) {
+	principal, err := getPrincipal(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.UpdatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	post, err := h.postService.Update(req.ToPost(id), principal)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPostResponse(post))
+}
+
+func (h *PostHandler) Delete(c *gin.Context) {
+	principal, err := getPrincipal(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	if err := h.postService.Delete(id, principal); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	utils.SendNoContent(c)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *PostHandler) GetRevisions(c *gin.Context) {
+	principal, err := getPrincipal(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	revisions, err := h.postService.GetRevisions(id, principal)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPostRevisionResponses(revisions))
+}
+
+func (h *PostHandler) DiffRevisions(c *gin.Context) {
+	principal, err := getPrincipal(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var query dto.RevisionDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleBadRequest(c, "Parámetros inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&query); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	result, err := h.postService.DiffRevisions(id, query.From, query.To, query.Mode, principal)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *PostHandler) RestoreRevision(c *gin.Context) {
+	principal, err := getPrincipal(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || number < 1 {
+		utils.HandleError(c, appErrors.ErrInvalidID)
+		return
+	}
+
+	post, err := h.postService.RestoreRevision(id, number, principal)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPostResponse(post))
+}
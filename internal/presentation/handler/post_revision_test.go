@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupRevisionRouter registra las rutas de revisiones igual que main.go
+func setupRevisionRouter(postHandler *PostHandler) *gin.Engine {
+	router := setupRouter()
+	router.Use(withUserID(7))
+	router.GET("/posts/:id/revisions", postHandler.GetRevisions)
+	router.GET("/posts/:id/revisions/diff", postHandler.DiffRevisions)
+	router.POST("/posts/:id/revisions/:rev/restore", postHandler.RestoreRevision)
+	return router
+}
+
+func TestPostHandler_GetRevisions(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockPostService)
+		expectedStatus int
+	}{
+		{
+			name: "success - revisions listed",
+			mockSetup: func(m *MockPostService) {
+				m.GetRevisionsFunc = func(postID uint, principal model.Principal) ([]model.PostRevision, error) {
+					return []model.PostRevision{{PostID: postID, Number: 1, EditorID: principal.UserID}}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "error - not the author",
+			mockSetup: func(m *MockPostService) {
+				m.GetRevisionsFunc = func(postID uint, principal model.Principal) ([]model.PostRevision, error) {
+					return nil, appErrors.ErrNotPostAuthor
+				}
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postHandler, mockService := NewPostHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			req, _ := http.NewRequest("GET", "/posts/1/revisions", nil)
+			w := httptest.NewRecorder()
+			setupRevisionRouter(postHandler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPostHandler_DiffRevisions(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedMode   string
+	}{
+		{name: "success - unified", query: "?from=1&to=2", expectedStatus: http.StatusOK, expectedMode: ""},
+		{name: "success - word", query: "?from=1&to=2&mode=word", expectedStatus: http.StatusOK, expectedMode: "word"},
+		{name: "error - missing revisions", query: "?from=1", expectedStatus: http.StatusBadRequest},
+		{name: "error - invalid mode", query: "?from=1&to=2&mode=html", expectedStatus: http.StatusBadRequest},
+		{name: "error - non numeric revision", query: "?from=a&to=2", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postHandler, mockService := NewPostHandlerWithMock()
+			mockService.DiffRevisionsFunc = func(postID uint, from, to int, mode string, principal model.Principal) (dto.RevisionDiffResponse, error) {
+				assert.Equal(t, tt.expectedMode, mode)
+				return dto.RevisionDiffResponse{From: from, To: to, Mode: "unified"}, nil
+			}
+
+			req, _ := http.NewRequest("GET", "/posts/1/revisions/diff"+tt.query, nil)
+			w := httptest.NewRecorder()
+			setupRevisionRouter(postHandler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPostHandler_RestoreRevision(t *testing.T) {
+	tests := []struct {
+		name           string
+		rev            string
+		mockSetup      func(*MockPostService)
+		expectedStatus int
+	}{
+		{
+			name: "success - revision restored",
+			rev:  "2",
+			mockSetup: func(m *MockPostService) {
+				m.RestoreRevisionFunc = func(postID uint, number int, principal model.Principal) (model.Post, error) {
+					assert.Equal(t, 2, number)
+					return model.Post{ID: postID, Title: "Restored"}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "error - invalid revision number",
+			rev:            "abc",
+			mockSetup:      func(m *MockPostService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - revision not found",
+			rev:  "9",
+			mockSetup: func(m *MockPostService) {
+				m.RestoreRevisionFunc = func(postID uint, number int, principal model.Principal) (model.Post, error) {
+					return model.Post{}, appErrors.ErrRevisionNotFound
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postHandler, mockService := NewPostHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			req, _ := http.NewRequest("POST", "/posts/1/revisions/"+tt.rev+"/restore", nil)
+			w := httptest.NewRecorder()
+			setupRevisionRouter(postHandler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,516 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockPostService mocks the PostService for handler testing
+type MockPostService struct {
+	GetPublishedFunc           func(spec query.Spec) (query.Page[model.Post], error)
+	GetPublishedByTagFunc      func(slug string, spec query.Spec) (query.Page[model.Post], error)
+	GetPublishedByCategoryFunc func(slug string, spec query.Spec) (query.Page[model.Post], error)
+	GetByAuthorFunc            func(authorID uint) ([]model.Post, error)
+	GetByIDFunc                func(id uint, viewerID uint) (model.Post, error)
+	GetBySlugFunc              func(slug string, viewerID uint) (model.Post, bool, error)
+	CreateFunc                 func(post model.Post) (model.Post, error)
+	UpdateFunc                 func(post model.Post, principal model.Principal) (model.Post, error)
+	DeleteFunc                 func(id uint, principal model.Principal) error
+
+	GetRevisionsFunc    func(postID uint, principal model.Principal) ([]model.PostRevision, error)
+	DiffRevisionsFunc   func(postID uint, from, to int, mode string, principal model.Principal) (dto.RevisionDiffResponse, error)
+	RestoreRevisionFunc func(postID uint, number int, principal model.Principal) (model.Post, error)
+}
+
+func (m *MockPostService) GetPublished(spec query.Spec) (query.Page[model.Post], error) {
+	if m.GetPublishedFunc != nil {
+		return m.GetPublishedFunc(spec)
+	}
+	return query.Page[model.Post]{Data: []model.Post{}}, nil
+}
+
+func (m *MockPostService) GetPublishedByTag(slug string, spec query.Spec) (query.Page[model.Post], error) {
+	if m.GetPublishedByTagFunc != nil {
+		return m.GetPublishedByTagFunc(slug, spec)
+	}
+	return query.Page[model.Post]{Data: []model.Post{}}, nil
+}
+
+func (m *MockPostService) GetPublishedByCategory(slug string, spec query.Spec) (query.Page[model.Post], error) {
+	if m.GetPublishedByCategoryFunc != nil {
+		return m.GetPublishedByCategoryFunc(slug, spec)
+	}
+	return query.Page[model.Post]{Data: []model.Post{}}, nil
+}
+
+func (m *MockPostService) GetByAuthor(authorID uint) ([]model.Post, error) {
+	if m.GetByAuthorFunc != nil {
+		return m.GetByAuthorFunc(authorID)
+	}
+	return []model.Post{}, nil
+}
+
+func (m *MockPostService) GetByID(id uint, viewerID uint) (model.Post, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(id, viewerID)
+	}
+	return model.Post{}, nil
+}
+
+func (m *MockPostService) GetBySlug(slug string, viewerID uint) (model.Post, bool, error) {
+	if m.GetBySlugFunc != nil {
+		return m.GetBySlugFunc(slug, viewerID)
+	}
+	return model.Post{}, false, nil
+}
+
+func (m *MockPostService) Create(post model.Post) (model.Post, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(post)
+	}
+	return post, nil
+}
+
+func (m *MockPostService) Update(post model.Post, principal model.Principal) (model.Post, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(post, principal)
+	}
+	return post, nil
+}
+
+func (m *MockPostService) Delete(id uint, principal model.Principal) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, principal)
+	}
+	return nil
+}
+
+func (m *MockPostService) GetRevisions(postID uint, principal model.Principal) ([]model.PostRevision, error) {
+	if m.GetRevisionsFunc != nil {
+		return m.GetRevisionsFunc(postID, principal)
+	}
+	return []model.PostRevision{}, nil
+}
+
+func (m *MockPostService) DiffRevisions(postID uint, from, to int, mode string, principal model.Principal) (dto.RevisionDiffResponse, error) {
+	if m.DiffRevisionsFunc != nil {
+		return m.DiffRevisionsFunc(postID, from, to, mode, principal)
+	}
+	return dto.RevisionDiffResponse{}, nil
+}
+
+func (m *MockPostService) RestoreRevision(postID uint, number int, principal model.Principal) (model.Post, error) {
+	if m.RestoreRevisionFunc != nil {
+		return m.RestoreRevisionFunc(postID, number, principal)
+	}
+	return model.Post{}, nil
+}
+
+// NewPostHandlerWithMock creates a PostHandler with a mock service for testing
+func NewPostHandlerWithMock() (*PostHandler, *MockPostService) {
+	mockService := &MockPostService{}
+	postHandler := &PostHandler{postService: mockService}
+	return postHandler, mockService
+}
+
+// withUserID simula el contexto que deja AuthMiddleware
+func withUserID(userID uint) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	}
+}
+
+func TestNewPostHandler(t *testing.T) {
+	mockService := &services.PostService{}
+	postHandler := NewPostHandler(mockService)
+
+	assert.NotNil(t, postHandler)
+	assert.Equal(t, mockService, postHandler.postService)
+}
+
+func TestPostHandler_GetAll(t *testing.T) {
+	t.Run("success - returns page with links", func(t *testing.T) {
+		handler, mockService := NewPostHandlerWithMock()
+		mockService.GetPublishedFunc = func(spec query.Spec) (query.Page[model.Post], error) {
+			assert.Equal(t, "-published_at", spec.SortKey)
+			assert.Equal(t, 5, spec.Limit)
+			return query.Page[model.Post]{
+				Data:       []model.Post{{ID: 2, Title: "Second"}},
+				NextCursor: "next",
+				PrevCursor: "prev",
+			}, nil
+		}
+		router := setupRouter()
+		router.GET("/posts", handler.GetAll)
+
+		req, _ := http.NewRequest("GET", "/posts?limit=5", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `</posts?cursor=next&limit=5>; rel="next", </posts?cursor=prev&limit=5>; rel="prev"`, w.Header().Get("Link"))
+		var page query.Page[model.Post]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Len(t, page.Data, 1)
+		assert.Equal(t, "next", page.NextCursor)
+		assert.Equal(t, "prev", page.PrevCursor)
+	})
+
+	t.Run("error - invalid filter", func(t *testing.T) {
+		handler, _ := NewPostHandlerWithMock()
+		router := setupRouter()
+		router.GET("/posts", handler.GetAll)
+
+		req, _ := http.NewRequest("GET", "/posts?author_id=abc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Valor inválido para el filtro 'author_id'"}`, w.Body.String())
+	})
+}
+
+func TestPostHandler_GetByID(t *testing.T) {
+	tests := []struct {
+		name           string
+		urlParam       string
+		mockSetup      func(*MockPostService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "success - post found",
+			urlParam: "1",
+			mockSetup: func(m *MockPostService) {
+				m.GetByIDFunc = func(id uint, viewerID uint) (model.Post, error) {
+					return model.Post{ID: 1, Title: "Title", Body: "Body", BodyHTML: "<p>Body</p>\n", TOC: []model.TOCEntry{}, WordCount: 1, ReadingTime: 1, Status: model.PostStatusPublished, AuthorID: 3, Tags: []model.Tag{{ID: 2, Name: "Go", Slug: "go"}}}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":1,"title":"Title","slug":"","body":"Body","body_html":"<p>Body</p>\n","toc":[],"word_count":1,"reading_time_minutes":1,"excerpt":"","status":"published","author_id":3,"category_id":null,"tags":[{"id":2,"name":"Go","slug":"go","created_at":"0001-01-01T00:00:00Z"}],"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:           "error - invalid ID format",
+			urlParam:       "abc",
+			mockSetup:      func(m *MockPostService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"ID inválido"}`,
+		},
+		{
+			name:     "error - post not found",
+			urlParam: "99",
+			mockSetup: func(m *MockPostService) {
+				m.GetByIDFunc = func(id uint, viewerID uint) (model.Post, error) {
+					return model.Post{}, appErrors.ErrPostNotFound
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Publicación no encontrada"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postHandler, mockService := NewPostHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.GET("/posts/:id", postHandler.GetByID)
+
+			req, _ := http.NewRequest("GET", "/posts/"+tt.urlParam, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestPostHandler_GetByID_PassesViewer(t *testing.T) {
+	postHandler, mockService := NewPostHandlerWithMock()
+	var receivedViewer uint
+	mockService.GetByIDFunc = func(id uint, viewerID uint) (model.Post, error) {
+		receivedViewer = viewerID
+		return model.Post{ID: id, Status: model.PostStatusDraft, AuthorID: viewerID}, nil
+	}
+
+	router := setupRouter()
+	router.Use(withUserID(7))
+	router.GET("/posts/:id", postHandler.GetByID)
+
+	req, _ := http.NewRequest("GET", "/posts/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uint(7), receivedViewer)
+}
+
+func TestPostHandler_GetBySlug(t *testing.T) {
+	tests := []struct {
+		name             string
+		slug             string
+		mockSetup        func(*MockPostService)
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			name: "success - current slug",
+			slug: "hola-mundo",
+			mockSetup: func(m *MockPostService) {
+				m.GetBySlugFunc = func(slug string, viewerID uint) (model.Post, bool, error) {
+					return model.Post{ID: 1, Slug: "hola-mundo", Status: model.PostStatusPublished}, false, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "redirect - previous slug",
+			slug: "hola",
+			mockSetup: func(m *MockPostService) {
+				m.GetBySlugFunc = func(slug string, viewerID uint) (model.Post, bool, error) {
+					return model.Post{ID: 1, Slug: "hola-mundo", Status: model.PostStatusPublished}, true, nil
+				}
+			},
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/posts/by-slug/hola-mundo",
+		},
+		{
+			name: "error - unknown slug",
+			slug: "nada",
+			mockSetup: func(m *MockPostService) {
+				m.GetBySlugFunc = func(slug string, viewerID uint) (model.Post, bool, error) {
+					return model.Post{}, false, appErrors.ErrPostNotFound
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postHandler, mockService := NewPostHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.GET("/posts/by-slug/:slug", postHandler.GetBySlug)
+
+			req, _ := http.NewRequest("GET", "/posts/by-slug/"+tt.slug, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestPostHandler_GetMine(t *testing.T) {
+	postHandler, mockService := NewPostHandlerWithMock()
+	mockService.GetByAuthorFunc = func(authorID uint) ([]model.Post, error) {
+		return []model.Post{
+			{ID: 1, Status: model.PostStatusDraft, AuthorID: authorID},
+			{ID: 2, Status: model.PostStatusPublished, AuthorID: authorID},
+		}, nil
+	}
+
+	router := setupRouter()
+	router.Use(withUserID(7))
+	router.GET("/posts/mine", postHandler.GetMine)
+
+	req, _ := http.NewRequest("GET", "/posts/mine", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var posts []model.Post
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &posts))
+	assert.Len(t, posts, 2)
+	assert.Equal(t, model.PostStatusDraft, posts[0].Status)
+}
+
+func TestPostHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		authenticated  bool
+		mockSetup      func(*MockPostService)
+		expectedStatus int
+	}{
+		{
+			name:          "success - post created for the current user",
+			requestBody:   dto.CreatePostRequest{Title: "My post", Body: "Content"},
+			authenticated: true,
+			mockSetup: func(m *MockPostService) {
+				m.CreateFunc = func(post model.Post) (model.Post, error) {
+					assert.Equal(t, uint(7), post.AuthorID)
+					post.ID = 1
+					return post, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "error - missing title",
+			requestBody:    dto.CreatePostRequest{Body: "Content"},
+			authenticated:  true,
+			mockSetup:      func(m *MockPostService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "error - no user in context",
+			requestBody:    dto.CreatePostRequest{Title: "My post", Body: "Content"},
+			authenticated:  false,
+			mockSetup:      func(m *MockPostService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postHandler, mockService := NewPostHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			if tt.authenticated {
+				router.Use(withUserID(7))
+			}
+			router.POST("/posts", postHandler.Create)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/posts", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPostHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockPostService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success - post updated",
+			mockSetup: func(m *MockPostService) {
+				m.UpdateFunc = func(post model.Post, principal model.Principal) (model.Post, error) {
+					post.AuthorID = principal.UserID
+					return post, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "error - not the author",
+			mockSetup: func(m *MockPostService) {
+				m.UpdateFunc = func(post model.Post, principal model.Principal) (model.Post, error) {
+					return model.Post{}, appErrors.ErrNotPostAuthor
+				}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"Solo el autor puede modificar la publicación"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postHandler, mockService := NewPostHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.Use(withUserID(7))
+			router.PUT("/posts/:id", postHandler.Update)
+
+			body, _ := json.Marshal(dto.UpdatePostRequest{Title: "Updated", Body: "Content"})
+			req, _ := http.NewRequest("PUT", "/posts/1", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPostHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockPostService)
+		expectedStatus int
+	}{
+		{
+			name: "success - post deleted",
+			mockSetup: func(m *MockPostService) {
+				m.DeleteFunc = func(id uint, principal model.Principal) error {
+					return nil
+				}
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "error - not the author",
+			mockSetup: func(m *MockPostService) {
+				m.DeleteFunc = func(id uint, principal model.Principal) error {
+					return appErrors.ErrNotPostAuthor
+				}
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postHandler, mockService := NewPostHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.Use(withUserID(7))
+			router.DELETE("/posts/:id", postHandler.Delete)
+
+			req, _ := http.NewRequest("DELETE", "/posts/1", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPostHandler_Update_PassesPrincipal(t *testing.T) {
+	postHandler, mockService := NewPostHandlerWithMock()
+	mockService.UpdateFunc = func(post model.Post, principal model.Principal) (model.Post, error) {
+		assert.Equal(t, model.Principal{UserID: 3, Role: model.RoleEditor}, principal)
+		return post, nil
+	}
+
+	router := setupRouter()
+	router.PUT("/posts/:id", withUserID(3), withRole(model.RoleEditor), postHandler.Update)
+
+	req, _ := http.NewRequest("PUT", "/posts/1", bytes.NewBufferString(`{"title":"Título editado","body":"Contenido editado"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,15 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+// NewRouter crea el router de la API. La IP del cliente, que se usa para limitar
+// los inicios de sesión fallidos y se guarda en las sesiones, solo se toma de
+// X-Forwarded-For o X-Real-IP si la petición llega desde uno de trustedProxies;
+// sin proxies de confianza se usa siempre la dirección de la conexión
+func NewRouter(trustedProxies []string) (*gin.Engine, error) {
+	router := gin.Default()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
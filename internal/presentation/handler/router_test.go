@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRouter_ClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		forwardedFor   []string
+		expectedIPs    map[string]int
+	}{
+		{
+			name:         "spoofed X-Forwarded-For does not reset the IP counter",
+			forwardedFor: []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"},
+			expectedIPs:  map[string]int{"10.0.0.1": 3},
+		},
+		{
+			name:           "trusted proxy forwards the client IP",
+			trustedProxies: []string{"10.0.0.0/8"},
+			forwardedFor:   []string{"1.1.1.1", "1.1.1.1"},
+			expectedIPs:    map[string]int{"1.1.1.1": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The mock counts failed logins per client IP like the login throttle
+			failures := map[string]int{}
+			authHandler, mockService := NewAuthHandlerWithMock()
+			mockService.LoginFunc = func(email, password string, client dto.ClientInfo) (dto.LoginResult, error) {
+				failures[client.IP]++
+				return dto.LoginResult{}, appErrors.ErrInvalidCredentials
+			}
+
+			router, err := NewRouter(tt.trustedProxies)
+			require.NoError(t, err)
+			router.POST("/login", authHandler.Login)
+
+			for _, forwardedFor := range tt.forwardedFor {
+				req, _ := http.NewRequest("POST", "/login", bytes.NewBufferString(`{"email":"test@example.com","password":"password123"}`))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				req.RemoteAddr = "10.0.0.1:54321"
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusUnauthorized, w.Code)
+			}
+
+			assert.Equal(t, tt.expectedIPs, failures)
+		})
+	}
+}
+
+func TestNewRouter_InvalidProxy(t *testing.T) {
+	_, err := NewRouter([]string{"not-an-ip"})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	sessionService domainService.SessionServiceInterface
+}
+
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService}
+}
+
+// List devuelve las sesiones activas del usuario autenticado
+func (h *SessionHandler) List(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	sessions, err := h.sessionService.List(userID, getSessionID(c))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// Revoke cierra una sesión del usuario autenticado, que puede ser la actual
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	if err := h.sessionService.Revoke(userID, id); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sesión cerrada"})
+}
+
+// RevokeOthers cierra todas las sesiones del usuario autenticado salvo la actual
+func (h *SessionHandler) RevokeOthers(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeOthers(userID, getSessionID(c))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RevokeSessionsResponse{Message: "Sesiones cerradas", Revoked: revoked})
+}
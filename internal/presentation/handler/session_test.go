@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockSessionService mocks the SessionService for handler testing
+type MockSessionService struct {
+	ListFunc         func(userID, currentID uint) ([]dto.SessionResponse, error)
+	RevokeFunc       func(userID, id uint) error
+	RevokeOthersFunc func(userID, currentID uint) (int, error)
+}
+
+func (m *MockSessionService) List(userID, currentID uint) ([]dto.SessionResponse, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(userID, currentID)
+	}
+	return nil, nil
+}
+
+func (m *MockSessionService) Revoke(userID, id uint) error {
+	if m.RevokeFunc != nil {
+		return m.RevokeFunc(userID, id)
+	}
+	return nil
+}
+
+func (m *MockSessionService) RevokeOthers(userID, currentID uint) (int, error) {
+	if m.RevokeOthersFunc != nil {
+		return m.RevokeOthersFunc(userID, currentID)
+	}
+	return 0, nil
+}
+
+// withSessionID simulates the session that AuthMiddleware reads from the token
+func withSessionID(sessionID uint) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("session_id", sessionID)
+		c.Next()
+	}
+}
+
+func TestNewSessionHandler(t *testing.T) {
+	mockService := &services.SessionService{}
+	sessionHandler := NewSessionHandler(mockService)
+
+	assert.NotNil(t, sessionHandler)
+	assert.Equal(t, mockService, sessionHandler.sessionService)
+}
+
+func TestSessionHandler_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticated  bool
+		listFunc       func(userID, currentID uint) ([]dto.SessionResponse, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:          "success - current session is marked",
+			authenticated: true,
+			listFunc: func(userID, currentID uint) ([]dto.SessionResponse, error) {
+				assert.Equal(t, uint(1), userID)
+				assert.Equal(t, uint(2), currentID)
+				return []dto.SessionResponse{{Session: model.Session{ID: 2, UserID: 1, FamilyID: "family", UserAgent: "Firefox", IP: "10.0.0.1"}, Current: true}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"id":2,"user_agent":"Firefox","ip":"10.0.0.1","last_seen_at":"0001-01-01T00:00:00Z","created_at":"0001-01-01T00:00:00Z","current":true}]`,
+		},
+		{
+			name:           "error - unauthenticated",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"No autorizado"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionHandler := &SessionHandler{&MockSessionService{ListFunc: tt.listFunc}}
+			router := setupRouter()
+			if tt.authenticated {
+				router.Use(withUserID(1), withSessionID(2))
+			}
+			router.GET("/me/sessions", sessionHandler.List)
+
+			req, _ := http.NewRequest(http.MethodGet, "/me/sessions", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestSessionHandler_Revoke(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		revokeFunc     func(userID, id uint) error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success - session revoked",
+			id:   "3",
+			revokeFunc: func(userID, id uint) error {
+				assert.Equal(t, uint(1), userID)
+				assert.Equal(t, uint(3), id)
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Sesión cerrada"}`,
+		},
+		{
+			name: "error - unknown session",
+			id:   "9",
+			revokeFunc: func(userID, id uint) error {
+				return appErrors.ErrSessionNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Sesión no encontrada"}`,
+		},
+		{
+			name:           "error - invalid id",
+			id:             "abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"ID inválido"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionHandler := &SessionHandler{&MockSessionService{RevokeFunc: tt.revokeFunc}}
+			router := setupRouter()
+			router.DELETE("/me/sessions/:id", withUserID(1), sessionHandler.Revoke)
+
+			req, _ := http.NewRequest(http.MethodDelete, "/me/sessions/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestSessionHandler_RevokeOthers(t *testing.T) {
+	sessionHandler := &SessionHandler{&MockSessionService{RevokeOthersFunc: func(userID, currentID uint) (int, error) {
+		assert.Equal(t, uint(1), userID)
+		assert.Equal(t, uint(2), currentID)
+		return 3, nil
+	}}}
+	router := setupRouter()
+	router.DELETE("/me/sessions", withUserID(1), withSessionID(2), sessionHandler.RevokeOthers)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/me/sessions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message":"Sesiones cerradas","revoked":3}`, w.Body.String())
+}
+
+func TestClientInfo_TruncatesUserAgent(t *testing.T) {
+	var client dto.ClientInfo
+	router := setupRouter()
+	router.GET("/", func(c *gin.Context) { client = clientInfo(c) })
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", strings.Repeat("ñ", maxUserAgentLength+10))
+	req.RemoteAddr = "10.0.0.1:54321"
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "10.0.0.1", client.IP)
+	assert.Equal(t, strings.Repeat("ñ", maxUserAgentLength), client.UserAgent)
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	"github.com/gin-gonic/gin"
+)
+
+// jwksCacheControl permite a otros servicios cachear las claves públicas. Al
+// encontrar un kid desconocido deben volver a pedirlas
+const jwksCacheControl = "public, max-age=300"
+
+type SigningKeyHandler struct {
+	signingKeyService domainService.SigningKeyServiceInterface
+}
+
+func NewSigningKeyHandler(signingKeyService *services.SigningKeyService) *SigningKeyHandler {
+	return &SigningKeyHandler{signingKeyService}
+}
+
+// JWKS publica las claves públicas con las que se verifican los tokens de acceso
+func (h *SigningKeyHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", jwksCacheControl)
+	c.JSON(http.StatusOK, h.signingKeyService.JWKS())
+}
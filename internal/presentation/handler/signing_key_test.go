@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/pkg/jwtkeys"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockSigningKeyService mocks the SigningKeyService for handler testing
+type MockSigningKeyService struct {
+	JWKSFunc func() jwtkeys.JWKS
+}
+
+func (m *MockSigningKeyService) Sign(claims map[string]any) (string, error) {
+	return "", nil
+}
+
+func (m *MockSigningKeyService) Rotate(now time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *MockSigningKeyService) JWKS() jwtkeys.JWKS {
+	if m.JWKSFunc != nil {
+		return m.JWKSFunc()
+	}
+	return jwtkeys.JWKS{Keys: []jwtkeys.JWK{}}
+}
+
+func TestNewSigningKeyHandler(t *testing.T) {
+	mockService := &services.SigningKeyService{}
+	signingKeyHandler := NewSigningKeyHandler(mockService)
+
+	assert.NotNil(t, signingKeyHandler)
+	assert.Equal(t, mockService, signingKeyHandler.signingKeyService)
+}
+
+func TestSigningKeyHandler_JWKS(t *testing.T) {
+	tests := []struct {
+		name         string
+		jwksFunc     func() jwtkeys.JWKS
+		expectedBody string
+	}{
+		{
+			name: "success - public keys",
+			jwksFunc: func() jwtkeys.JWKS {
+				return jwtkeys.JWKS{Keys: []jwtkeys.JWK{{Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: "kid-1", Crv: "Ed25519", X: "x"}}}
+			},
+			expectedBody: `{"keys":[{"kty":"OKP","use":"sig","alg":"EdDSA","kid":"kid-1","crv":"Ed25519","x":"x"}]}`,
+		},
+		{
+			name:         "success - no asymmetric keys",
+			expectedBody: `{"keys":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signingKeyHandler := &SigningKeyHandler{&MockSigningKeyService{JWKSFunc: tt.jwksFunc}}
+			router := setupRouter()
+			router.GET("/.well-known/jwks.json", signingKeyHandler.JWKS)
+
+			req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
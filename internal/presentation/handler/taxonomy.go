@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	tagService domainService.TagServiceInterface
+}
+
+func NewTagHandler(tagService *services.TagService) *TagHandler {
+	return &TagHandler{tagService}
+}
+
+// GetCloud devuelve la nube de etiquetas con el número de publicaciones de cada una
+func (h *TagHandler) GetCloud(c *gin.Context) {
+	cloud, err := h.tagService.GetCloud()
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTagCountResponses(cloud))
+}
+
+func (h *TagHandler) Rename(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	tag, err := h.tagService.Rename(id, req.Name)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTagResponse(tag))
+}
+
+// Merge fusiona la etiqueta de la URL en la etiqueta de destino
+func (h *TagHandler) Merge(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	if err := h.tagService.Merge(id, req.TargetID); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	utils.SendNoContent(c)
+}
+
+func (h *TagHandler) AddAlias(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.TagAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	alias, err := h.tagService.AddAlias(id, req.Alias)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.NewTagAliasResponse(alias))
+}
+
+type CategoryHandler struct {
+	categoryService domainService.CategoryServiceInterface
+}
+
+func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService}
+}
+
+// GetTree devuelve el árbol completo de categorías
+func (h *CategoryHandler) GetTree(c *gin.Context) {
+	tree, err := h.categoryService.GetTree()
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tree)
+}
+
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req dto.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	category, err := h.categoryService.Create(req.ToCategory())
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.NewCategoryResponse(category))
+}
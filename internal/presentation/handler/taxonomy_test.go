@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockTagService mocks the TagService for handler testing
+type MockTagService struct {
+	GetCloudFunc func() ([]model.TagCount, error)
+	RenameFunc   func(id uint, name string) (model.Tag, error)
+	MergeFunc    func(sourceID uint, targetID uint) error
+	AddAliasFunc func(id uint, alias string) (model.TagAlias, error)
+}
+
+func (m *MockTagService) GetCloud() ([]model.TagCount, error) {
+	if m.GetCloudFunc != nil {
+		return m.GetCloudFunc()
+	}
+	return []model.TagCount{}, nil
+}
+
+func (m *MockTagService) Rename(id uint, name string) (model.Tag, error) {
+	if m.RenameFunc != nil {
+		return m.RenameFunc(id, name)
+	}
+	return model.Tag{ID: id, Name: name}, nil
+}
+
+func (m *MockTagService) Merge(sourceID uint, targetID uint) error {
+	if m.MergeFunc != nil {
+		return m.MergeFunc(sourceID, targetID)
+	}
+	return nil
+}
+
+func (m *MockTagService) AddAlias(id uint, alias string) (model.TagAlias, error) {
+	if m.AddAliasFunc != nil {
+		return m.AddAliasFunc(id, alias)
+	}
+	return model.TagAlias{TagID: id, Slug: alias}, nil
+}
+
+// MockCategoryService mocks the CategoryService for handler testing
+type MockCategoryService struct {
+	GetTreeFunc func() ([]dto.CategoryNode, error)
+	CreateFunc  func(category model.Category) (model.Category, error)
+}
+
+func (m *MockCategoryService) GetTree() ([]dto.CategoryNode, error) {
+	if m.GetTreeFunc != nil {
+		return m.GetTreeFunc()
+	}
+	return []dto.CategoryNode{}, nil
+}
+
+func (m *MockCategoryService) Create(category model.Category) (model.Category, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(category)
+	}
+	return category, nil
+}
+
+func TestNewTagHandler(t *testing.T) {
+	mockService := &services.TagService{}
+	tagHandler := NewTagHandler(mockService)
+
+	assert.NotNil(t, tagHandler)
+	assert.Equal(t, mockService, tagHandler.tagService)
+}
+
+func TestTagHandler_GetCloud(t *testing.T) {
+	mockService := &MockTagService{
+		GetCloudFunc: func() ([]model.TagCount, error) {
+			return []model.TagCount{{ID: 1, Name: "Go", Slug: "go", Count: 4}}, nil
+		},
+	}
+	tagHandler := &TagHandler{tagService: mockService}
+
+	router := setupRouter()
+	router.GET("/tags", tagHandler.GetCloud)
+
+	req, _ := http.NewRequest("GET", "/tags", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"id":1,"name":"Go","slug":"go","count":4}]`, w.Body.String())
+}
+
+func TestTagHandler_Rename(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		renameErr      error
+		expectedStatus int
+	}{
+		{name: "success - tag renamed", requestBody: dto.RenameTagRequest{Name: "Golang"}, expectedStatus: http.StatusOK},
+		{name: "error - empty name", requestBody: dto.RenameTagRequest{}, expectedStatus: http.StatusBadRequest},
+		{name: "error - slug taken", requestBody: dto.RenameTagRequest{Name: "SQL"}, renameErr: appErrors.ErrTagExists, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockTagService{
+				RenameFunc: func(id uint, name string) (model.Tag, error) {
+					if tt.renameErr != nil {
+						return model.Tag{}, tt.renameErr
+					}
+					return model.Tag{ID: id, Name: name}, nil
+				},
+			}
+			tagHandler := &TagHandler{tagService: mockService}
+
+			router := setupRouter()
+			router.PUT("/tags/:id", tagHandler.Rename)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestTagHandler_Merge(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+	}{
+		{name: "success - tags merged", requestBody: dto.MergeTagRequest{TargetID: 1}, expectedStatus: http.StatusNoContent},
+		{name: "error - merge into itself", requestBody: dto.MergeTagRequest{TargetID: 2}, expectedStatus: http.StatusBadRequest},
+		{name: "error - missing target", requestBody: dto.MergeTagRequest{}, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockTagService{
+				MergeFunc: func(sourceID uint, targetID uint) error {
+					if sourceID == targetID {
+						return appErrors.ErrInvalidTagMerge
+					}
+					return nil
+				},
+			}
+			tagHandler := &TagHandler{tagService: mockService}
+
+			router := setupRouter()
+			router.POST("/tags/:id/merge", tagHandler.Merge)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/tags/2/merge", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestTagHandler_AddAlias(t *testing.T) {
+	tagHandler := &TagHandler{tagService: &MockTagService{}}
+
+	router := setupRouter()
+	router.POST("/tags/:id/aliases", tagHandler.AddAlias)
+
+	body, _ := json.Marshal(dto.TagAliasRequest{Alias: "golang"})
+	req, _ := http.NewRequest("POST", "/tags/1/aliases", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestNewCategoryHandler(t *testing.T) {
+	mockService := &services.CategoryService{}
+	categoryHandler := NewCategoryHandler(mockService)
+
+	assert.NotNil(t, categoryHandler)
+	assert.Equal(t, mockService, categoryHandler.categoryService)
+}
+
+func TestCategoryHandler_GetTree(t *testing.T) {
+	mockService := &MockCategoryService{
+		GetTreeFunc: func() ([]dto.CategoryNode, error) {
+			return []dto.CategoryNode{{
+				CategoryResponse: dto.CategoryResponse{ID: 1, Name: "Backend", Slug: "backend"},
+				Children:         []dto.CategoryNode{{CategoryResponse: dto.CategoryResponse{ID: 2, Name: "Go", Slug: "go"}, Children: []dto.CategoryNode{}}},
+			}}, nil
+		},
+	}
+	categoryHandler := &CategoryHandler{categoryService: mockService}
+
+	router := setupRouter()
+	router.GET("/categories", categoryHandler.GetTree)
+
+	req, _ := http.NewRequest("GET", "/categories", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var tree []dto.CategoryNode
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &tree))
+	assert.Equal(t, "go", tree[0].Children[0].Slug)
+}
+
+func TestCategoryHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		createErr      error
+		expectedStatus int
+	}{
+		{name: "success - category created", requestBody: dto.CreateCategoryRequest{Name: "Go"}, expectedStatus: http.StatusCreated},
+		{name: "error - missing name", requestBody: dto.CreateCategoryRequest{}, expectedStatus: http.StatusBadRequest},
+		{name: "error - already exists", requestBody: dto.CreateCategoryRequest{Name: "Go"}, createErr: appErrors.ErrCategoryExists, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockCategoryService{
+				CreateFunc: func(category model.Category) (model.Category, error) {
+					return category, tt.createErr
+				},
+			}
+			categoryHandler := &CategoryHandler{categoryService: mockService}
+
+			router := setupRouter()
+			router.POST("/categories", categoryHandler.Create)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest("POST", "/categories", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPostHandler_GetByTag(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockPostService)
+		query          string
+		expectedStatus int
+	}{
+		{
+			name: "success - posts with the tag",
+			mockSetup: func(m *MockPostService) {
+				m.GetPublishedByTagFunc = func(slug string, spec query.Spec) (query.Page[model.Post], error) {
+					assert.Equal(t, "go", slug)
+					return query.Page[model.Post]{Data: []model.Post{{ID: 1}}}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "error - invalid cursor",
+			mockSetup:      func(m *MockPostService) {},
+			query:          "?cursor=nope",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - unknown tag",
+			mockSetup: func(m *MockPostService) {
+				m.GetPublishedByTagFunc = func(slug string, spec query.Spec) (query.Page[model.Post], error) {
+					return query.Page[model.Post]{}, appErrors.ErrTagNotFound
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postHandler, mockService := NewPostHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.GET("/tags/:slug/posts", postHandler.GetByTag)
+
+			req, _ := http.NewRequest("GET", "/tags/go/posts"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPostHandler_GetByCategory(t *testing.T) {
+	postHandler, mockService := NewPostHandlerWithMock()
+	mockService.GetPublishedByCategoryFunc = func(slug string, spec query.Spec) (query.Page[model.Post], error) {
+		assert.Equal(t, "backend", slug)
+		assert.Equal(t, 5, spec.Limit)
+		return query.Page[model.Post]{Data: []model.Post{{ID: 1}, {ID: 2}}, NextCursor: "next"}, nil
+	}
+
+	router := setupRouter()
+	router.GET("/categories/:slug/posts", postHandler.GetByCategory)
+
+	req, _ := http.NewRequest("GET", "/categories/backend/posts?limit=5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var page query.Page[dto.PostResponse]
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Data, 2)
+	assert.Contains(t, w.Header().Get("Link"), `rel="next"`)
+}
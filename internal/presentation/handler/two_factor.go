@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	domainService "github.com/UliVargas/blog-go/internal/domain/service"
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService domainService.TwoFactorServiceInterface
+}
+
+func NewTwoFactorHandler(twoFactorService *services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService}
+}
+
+// Setup genera el secreto TOTP del usuario autenticado, que queda pendiente
+// hasta confirmarlo con un código
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	setup, err := h.twoFactorService.Setup(userID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+// Confirm activa la verificación en dos pasos y devuelve los códigos de recuperación
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.ConfirmTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(userID, req.Code)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecoveryCodesResponse{
+		Message:       "Verificación en dos pasos activada. Guarda los códigos de recuperación: no se volverán a mostrar",
+		RecoveryCodes: codes,
+	})
+}
+
+// Disable desactiva la verificación en dos pasos del usuario autenticado
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos")
+		return
+	}
+
+	if err := utils.GetValidator().Struct(req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	if err := h.twoFactorService.Disable(userID, req.Password, req.Code); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verificación en dos pasos desactivada"})
+}
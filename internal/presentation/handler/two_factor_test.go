@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockTwoFactorService mocks the TwoFactorService for handler testing
+type MockTwoFactorService struct {
+	SetupFunc   func(userID uint) (dto.TwoFactorSetup, error)
+	ConfirmFunc func(userID uint, code string) ([]string, error)
+	DisableFunc func(userID uint, password, code string) error
+}
+
+func (m *MockTwoFactorService) Setup(userID uint) (dto.TwoFactorSetup, error) {
+	if m.SetupFunc != nil {
+		return m.SetupFunc(userID)
+	}
+	return dto.TwoFactorSetup{}, nil
+}
+
+func (m *MockTwoFactorService) Confirm(userID uint, code string) ([]string, error) {
+	if m.ConfirmFunc != nil {
+		return m.ConfirmFunc(userID, code)
+	}
+	return nil, nil
+}
+
+func (m *MockTwoFactorService) Disable(userID uint, password, code string) error {
+	if m.DisableFunc != nil {
+		return m.DisableFunc(userID, password, code)
+	}
+	return nil
+}
+
+func (m *MockTwoFactorService) Authenticate(user model.User, code string) error {
+	return nil
+}
+
+func TestNewTwoFactorHandler(t *testing.T) {
+	mockService := &services.TwoFactorService{}
+	twoFactorHandler := NewTwoFactorHandler(mockService)
+
+	assert.NotNil(t, twoFactorHandler)
+	assert.Equal(t, mockService, twoFactorHandler.twoFactorService)
+}
+
+func TestTwoFactorHandler_Setup(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticated  bool
+		setupFunc      func(userID uint) (dto.TwoFactorSetup, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:          "success - secret generated",
+			authenticated: true,
+			setupFunc: func(userID uint) (dto.TwoFactorSetup, error) {
+				assert.Equal(t, uint(1), userID)
+				return dto.TwoFactorSetup{Secret: "SECRET", URI: "otpauth://totp/Blog:john@example.com?secret=SECRET"}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"secret":"SECRET","otpauth_uri":"otpauth://totp/Blog:john@example.com?secret=SECRET"}`,
+		},
+		{
+			name:          "error - already enabled",
+			authenticated: true,
+			setupFunc: func(userID uint) (dto.TwoFactorSetup, error) {
+				return dto.TwoFactorSetup{}, appErrors.ErrTwoFactorAlreadyEnabled
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"La verificación en dos pasos ya está activada"}`,
+		},
+		{
+			name:           "error - unauthenticated",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"No autorizado"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twoFactorHandler := &TwoFactorHandler{&MockTwoFactorService{SetupFunc: tt.setupFunc}}
+			router := setupRouter()
+			if tt.authenticated {
+				router.Use(withUserID(1))
+			}
+			router.POST("/me/2fa", twoFactorHandler.Setup)
+
+			req, _ := http.NewRequest(http.MethodPost, "/me/2fa", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestTwoFactorHandler_Confirm(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		confirmFunc    func(userID uint, code string) ([]string, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "success - recovery codes returned",
+			requestBody: `{"code":"123456"}`,
+			confirmFunc: func(userID uint, code string) ([]string, error) {
+				assert.Equal(t, "123456", code)
+				return []string{"abcd-efgh", "ijkl-mnop"}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Verificación en dos pasos activada. Guarda los códigos de recuperación: no se volverán a mostrar","recovery_codes":["abcd-efgh","ijkl-mnop"]}`,
+		},
+		{
+			name:        "error - invalid code",
+			requestBody: `{"code":"000000"}`,
+			confirmFunc: func(userID uint, code string) ([]string, error) {
+				return nil, appErrors.ErrInvalidTwoFactorCode
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Código de verificación inválido"}`,
+		},
+		{
+			name:           "error - missing code",
+			requestBody:    `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"code":"Este campo es obligatorio"}}`,
+		},
+		{
+			name:           "error - invalid JSON",
+			requestBody:    `{"code":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos inválidos"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twoFactorHandler := &TwoFactorHandler{&MockTwoFactorService{ConfirmFunc: tt.confirmFunc}}
+			router := setupRouter()
+			router.POST("/me/2fa/confirm", withUserID(1), twoFactorHandler.Confirm)
+
+			req, _ := http.NewRequest(http.MethodPost, "/me/2fa/confirm", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestTwoFactorHandler_Disable(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		disableFunc    func(userID uint, password, code string) error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "success - disabled",
+			requestBody: `{"password":"password123","code":"123456"}`,
+			disableFunc: func(userID uint, password, code string) error {
+				assert.Equal(t, uint(1), userID)
+				assert.Equal(t, "password123", password)
+				assert.Equal(t, "123456", code)
+				return nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Verificación en dos pasos desactivada"}`,
+		},
+		{
+			name:        "error - not enabled",
+			requestBody: `{"password":"password123","code":"123456"}`,
+			disableFunc: func(userID uint, password, code string) error {
+				return appErrors.ErrTwoFactorNotEnabled
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"La verificación en dos pasos no está activada"}`,
+		},
+		{
+			name:           "error - missing password",
+			requestBody:    `{"code":"123456"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"password":"Este campo es obligatorio"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twoFactorHandler := &TwoFactorHandler{&MockTwoFactorService{DisableFunc: tt.disableFunc}}
+			router := setupRouter()
+			router.DELETE("/me/2fa", withUserID(1), twoFactorHandler.Disable)
+
+			req, _ := http.NewRequest(http.MethodDelete, "/me/2fa", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -2,11 +2,11 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
 	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
+	"github.com/UliVargas/blog-go/internal/domain/model"
 	domainService "github.com/UliVargas/blog-go/internal/domain/service"
-	appErrors "github.com/UliVargas/blog-go/pkg/errors"
 	"github.com/UliVargas/blog-go/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -19,26 +19,135 @@ func NewUserHandler(userService *services.UserService) *UserHandler {
 	return &UserHandler{userService}
 }
 
+// GetAll lista los usuarios paginados por cursor. Los administradores reciben la
+// vista de administración
 func (h *UserHandler) GetAll(c *gin.Context) {
-	users, err := h.userService.GetAll()
+	spec, err := dto.UserListSchema.Parse(c.Request.URL.Query())
 	if err != nil {
 		utils.HandleError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, users)
+	page, err := h.userService.GetAll(spec)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	utils.SetPaginationLinks(c, page.NextCursor, page.PrevCursor)
+	if getRole(c).Can(model.PermUsersManage) {
+		c.JSON(http.StatusOK, dto.NewAdminUserPage(page))
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPublicUserPage(page))
 }
 
+// GetByID devuelve el perfil público del usuario, o la vista completa si el
+// usuario consulta su propio perfil
 func (h *UserHandler) GetByID(c *gin.Context) {
-	id := c.Param("id")
-	idUint, err := strconv.ParseUint(id, 10, 64)
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	user, err := h.userService.GetByID(id)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	if viewerID, _ := getUserID(c); viewerID == user.ID {
+		c.JSON(http.StatusOK, dto.NewSelfUserResponse(user))
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPublicUserResponse(user))
+}
+
+// GetMe devuelve el perfil del usuario autenticado
+func (h *UserHandler) GetMe(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	user, err := h.userService.GetByID(userID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewSelfUserResponse(user))
+}
+
+// UpdateMe actualiza parcialmente el perfil del usuario autenticado
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(userID, req)
 	if err != nil {
-		utils.HandleError(c, appErrors.ErrInvalidID)
+		utils.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewSelfUserResponse(user))
+}
+
+// DeleteMe elimina la cuenta del usuario autenticado tras confirmar su contraseña
+func (h *UserHandler) DeleteMe(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
 		return
 	}
-	user, err := h.userService.GetByID(uint(idUint))
+
+	if err := h.userService.DeleteAccount(userID, req.Password); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	utils.SendNoContent(c)
+}
+
+// UpdateRole asigna un rol a un usuario. Requiere el permiso users:manage
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var req dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBadRequest(c, "Datos inválidos proporcionados")
+		return
+	}
+	if err := utils.GetValidator().Struct(&req); err != nil {
+		utils.HandleValidationError(c, err)
+		return
+	}
+
+	user, err := h.userService.UpdateRole(id, model.Role(req.Role))
 	if err != nil {
 		utils.HandleError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, dto.NewAdminUserResponse(user))
 }
@@ -4,28 +4,35 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	services "github.com/UliVargas/blog-go/internal/application/service"
+	"github.com/UliVargas/blog-go/internal/domain/dto"
 	"github.com/UliVargas/blog-go/internal/domain/model"
 	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/query"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
 // MockUserService mocks the UserService for handler testing
 type MockUserService struct {
-	GetAllFunc  func() ([]model.User, error)
+	GetAllFunc  func(spec query.Spec) (query.Page[model.User], error)
 	GetByIDFunc func(id uint) (model.User, error)
 	UpdateFunc  func(user model.User) (model.User, error)
 	DeleteFunc  func(id uint) error
+
+	UpdateProfileFunc func(id uint, req dto.UpdateProfileRequest) (model.User, error)
+	DeleteAccountFunc func(id uint, password string) error
+	UpdateRoleFunc    func(id uint, role model.Role) (model.User, error)
 }
 
-func (m *MockUserService) GetAll() ([]model.User, error) {
+func (m *MockUserService) GetAll(spec query.Spec) (query.Page[model.User], error) {
 	if m.GetAllFunc != nil {
-		return m.GetAllFunc()
+		return m.GetAllFunc(spec)
 	}
-	return []model.User{}, nil
+	return query.Page[model.User]{Data: []model.User{}}, nil
 }
 
 func (m *MockUserService) GetByID(id uint) (model.User, error) {
@@ -49,6 +56,27 @@ func (m *MockUserService) Delete(id uint) error {
 	return nil
 }
 
+func (m *MockUserService) UpdateProfile(id uint, req dto.UpdateProfileRequest) (model.User, error) {
+	if m.UpdateProfileFunc != nil {
+		return m.UpdateProfileFunc(id, req)
+	}
+	return model.User{}, nil
+}
+
+func (m *MockUserService) DeleteAccount(id uint, password string) error {
+	if m.DeleteAccountFunc != nil {
+		return m.DeleteAccountFunc(id, password)
+	}
+	return nil
+}
+
+func (m *MockUserService) UpdateRole(id uint, role model.Role) (model.User, error) {
+	if m.UpdateRoleFunc != nil {
+		return m.UpdateRoleFunc(id, role)
+	}
+	return model.User{}, nil
+}
+
 // NewUserHandlerWithMock creates a UserHandler with a mock service for testing
 func NewUserHandlerWithMock() (*UserHandler, *MockUserService) {
 	mockService := &MockUserService{}
@@ -72,38 +100,63 @@ func TestNewUserHandler(t *testing.T) {
 func TestUserHandler_GetAll(t *testing.T) {
 	tests := []struct {
 		name           string
+		url            string
 		mockSetup      func(*MockUserService)
 		expectedStatus int
 		expectedBody   string
+		expectedLink   string
 	}{
 		{
 			name: "success - users found",
+			url:  "/users?limit=2&name=j",
 			mockSetup: func(m *MockUserService) {
-				m.GetAllFunc = func() ([]model.User, error) {
-					return []model.User{
-						{ID: 1, Name: "John Doe", Email: "john@example.com"},
-						{ID: 2, Name: "Jane Smith", Email: "jane@example.com"},
+				m.GetAllFunc = func(spec query.Spec) (query.Page[model.User], error) {
+					assert.Equal(t, 2, spec.Limit)
+					assert.Equal(t, []query.Condition{{Column: "name", Operator: query.OpContains, Value: "j"}}, spec.Conditions)
+					return query.Page[model.User]{
+						Data: []model.User{
+							{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "hash1", Bio: "Gopher"},
+							{ID: 2, Name: "Jane Smith", Email: "jane@example.com", Password: "hash2"},
+						},
+						NextCursor: "abc",
 					}, nil
 				}
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `[{"id":1,"name":"John Doe","email":"john@example.com"},{"id":2,"name":"Jane Smith","email":"jane@example.com"}]`,
+			expectedBody:   `{"data":[{"id":1,"name":"John Doe","avatar_url":"","bio":"Gopher","website":""},{"id":2,"name":"Jane Smith","avatar_url":"","bio":"","website":""}],"next_cursor":"abc"}`,
+			expectedLink:   `</users?cursor=abc&limit=2&name=j>; rel="next"`,
 		},
 		{
 			name: "success - empty list",
+			url:  "/users",
 			mockSetup: func(m *MockUserService) {
-				m.GetAllFunc = func() ([]model.User, error) {
-					return []model.User{}, nil
+				m.GetAllFunc = func(spec query.Spec) (query.Page[model.User], error) {
+					return query.Page[model.User]{Data: []model.User{}}, nil
 				}
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `[]`,
+			expectedBody:   `{"data":[]}`,
+		},
+		{
+			name:           "error - sort not allowed",
+			url:            "/users?sort=password",
+			mockSetup:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"No se permite ordenar por 'password'"}`,
+		},
+		{
+			name:           "error - invalid cursor",
+			url:            "/users?cursor=invalid",
+			mockSetup:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Cursor de paginación inválido"}`,
 		},
 		{
 			name: "error - database error",
+			url:  "/users",
 			mockSetup: func(m *MockUserService) {
-				m.GetAllFunc = func() ([]model.User, error) {
-					return nil, appErrors.NewInternalServerError(appErrors.ErrDatabaseOperation, "Database connection failed")
+				m.GetAllFunc = func(spec query.Spec) (query.Page[model.User], error) {
+					return query.Page[model.User]{}, appErrors.NewInternalServerError(appErrors.ErrDatabaseOperation, "Database connection failed")
 				}
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -122,7 +175,7 @@ func TestUserHandler_GetAll(t *testing.T) {
 			router.GET("/users", userHandler.GetAll)
 
 			// Create request
-			req, _ := http.NewRequest("GET", "/users", nil)
+			req, _ := http.NewRequest("GET", tt.url, nil)
 			w := httptest.NewRecorder()
 
 			// Execute request
@@ -130,19 +183,10 @@ func TestUserHandler_GetAll(t *testing.T) {
 
 			// Assertions
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedLink, w.Header().Get("Link"))
 
 			// Parse and compare JSON response
-			if tt.expectedStatus == http.StatusOK {
-				var actualUsers []model.User
-				var expectedUsers []model.User
-				err := json.Unmarshal(w.Body.Bytes(), &actualUsers)
-				assert.NoError(t, err)
-				err = json.Unmarshal([]byte(tt.expectedBody), &expectedUsers)
-				assert.NoError(t, err)
-				assert.Equal(t, expectedUsers, actualUsers)
-			} else {
-				assert.JSONEq(t, tt.expectedBody, w.Body.String())
-			}
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
 		})
 	}
 }
@@ -151,6 +195,7 @@ func TestUserHandler_GetByID(t *testing.T) {
 	tests := []struct {
 		name           string
 		urlParam       string
+		viewerID       uint
 		mockSetup      func(*MockUserService)
 		expectedStatus int
 		expectedBody   string
@@ -161,13 +206,25 @@ func TestUserHandler_GetByID(t *testing.T) {
 			mockSetup: func(m *MockUserService) {
 				m.GetByIDFunc = func(id uint) (model.User, error) {
 					if id == 1 {
-						return model.User{ID: 1, Name: "John Doe", Email: "john@example.com"}, nil
+						return model.User{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "hash"}, nil
 					}
 					return model.User{}, appErrors.NewNotFoundError(appErrors.ErrUserNotFound, "User not found")
 				}
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"id":1,"name":"John Doe","email":"john@example.com"}`,
+			expectedBody:   `{"id":1,"name":"John Doe","avatar_url":"","bio":"","website":""}`,
+		},
+		{
+			name:     "success - own profile includes private fields",
+			urlParam: "1",
+			viewerID: 1,
+			mockSetup: func(m *MockUserService) {
+				m.GetByIDFunc = func(id uint) (model.User, error) {
+					return model.User{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "hash", Locale: "en"}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":1,"name":"John Doe","avatar_url":"","bio":"","website":"","email":"john@example.com","email_verified_at":null,"role":"","settings":{"locale":"en"},"created_at":"0001-01-01T00:00:00Z"}`,
 		},
 		{
 			name:           "error - invalid ID format",
@@ -208,7 +265,7 @@ func TestUserHandler_GetByID(t *testing.T) {
 
 			// Setup router and request
 			router := setupRouter()
-			router.GET("/users/:id", userHandler.GetByID)
+			router.GET("/users/:id", withUserID(tt.viewerID), userHandler.GetByID)
 
 			// Create request
 			req, _ := http.NewRequest("GET", "/users/"+tt.urlParam, nil)
@@ -220,17 +277,359 @@ func TestUserHandler_GetByID(t *testing.T) {
 			// Assertions
 			assert.Equal(t, tt.expectedStatus, w.Code)
 
-			if tt.expectedStatus == http.StatusOK {
-				var actualUser model.User
-				var expectedUser model.User
-				err := json.Unmarshal(w.Body.Bytes(), &actualUser)
-				assert.NoError(t, err)
-				err = json.Unmarshal([]byte(tt.expectedBody), &expectedUser)
-				assert.NoError(t, err)
-				assert.Equal(t, expectedUser, actualUser)
-			} else {
-				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+// assertNoPasswordField falla si el JSON contiene un campo password en cualquier nivel
+func assertNoPasswordField(t *testing.T, body []byte) {
+	t.Helper()
+	var value any
+	if !assert.NoError(t, json.Unmarshal(body, &value)) {
+		return
+	}
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, child := range v {
+				assert.NotEqual(t, "password", strings.ToLower(key), "la respuesta expone la contraseña: %s", body)
+				walk(child)
 			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(value)
+}
+
+func TestUserResponses_NeverContainPassword(t *testing.T) {
+	user := model.User{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "$2a$10$hash", Locale: "es"}
+
+	userHandler, mockService := NewUserHandlerWithMock()
+	mockService.GetAllFunc = func(spec query.Spec) (query.Page[model.User], error) {
+		return query.Page[model.User]{Data: []model.User{user}}, nil
+	}
+	mockService.GetByIDFunc = func(id uint) (model.User, error) {
+		return user, nil
+	}
+
+	router := setupRouter()
+	router.GET("/users", withUserID(1), userHandler.GetAll)
+	router.GET("/users/:id", withUserID(1), userHandler.GetByID)
+	router.GET("/users/me", withUserID(1), userHandler.GetMe)
+	router.GET("/public/users/:id", userHandler.GetByID)
+
+	for _, path := range []string{"/users", "/users/1", "/users/me", "/public/users/1"} {
+		t.Run(path, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NotContains(t, w.Body.String(), user.Password)
+			assertNoPasswordField(t, w.Body.Bytes())
+		})
+	}
+
+	// Las proyecciones tampoco deben exponer la contraseña al serializarse por separado
+	for _, projection := range []any{
+		dto.NewPublicUserResponse(user),
+		dto.NewSelfUserResponse(user),
+		dto.NewAdminUserResponse(user),
+		user,
+	} {
+		body, err := json.Marshal(projection)
+		assert.NoError(t, err)
+		assertNoPasswordField(t, body)
+	}
+}
+
+func TestContentResponses_NeverContainPassword(t *testing.T) {
+	// Las relaciones cargadas incluyen al usuario con su contraseña
+	author := model.User{ID: 3, Name: "John Doe", Email: "john@example.com", Password: "$2a$10$hash"}
+	post := model.Post{ID: 1, Title: "Title", Slug: "title", AuthorID: 3, Author: author, Tags: []model.Tag{{ID: 2, Name: "Go", Slug: "go"}}}
+	revision := model.PostRevision{ID: 1, PostID: 1, Number: 1, Post: post, EditorID: 3, Editor: author}
+	comment := model.Comment{ID: 1, PostID: 1, Post: post, AuthorID: 3, Author: author, Body: "Hola"}
+
+	postHandler, postService := NewPostHandlerWithMock()
+	postService.GetPublishedFunc = func(spec query.Spec) (query.Page[model.Post], error) {
+		return query.Page[model.Post]{Data: []model.Post{post}}, nil
+	}
+	postService.GetPublishedByTagFunc = func(slug string, spec query.Spec) (query.Page[model.Post], error) {
+		return query.Page[model.Post]{Data: []model.Post{post}}, nil
+	}
+	postService.GetByAuthorFunc = func(authorID uint) ([]model.Post, error) {
+		return []model.Post{post}, nil
+	}
+	postService.GetByIDFunc = func(id uint, viewerID uint) (model.Post, error) {
+		return post, nil
+	}
+	postService.GetRevisionsFunc = func(postID uint, principal model.Principal) ([]model.PostRevision, error) {
+		return []model.PostRevision{revision}, nil
+	}
+	commentHandler, commentService := NewCommentHandlerWithMock()
+	commentService.GetPendingFunc = func() ([]model.Comment, error) {
+		return []model.Comment{comment}, nil
+	}
+
+	router := setupRouter()
+	router.GET("/posts", postHandler.GetAll)
+	router.GET("/posts/tag/:slug", postHandler.GetByTag)
+	router.GET("/posts/mine", withUserID(3), postHandler.GetMine)
+	router.GET("/posts/:id", postHandler.GetByID)
+	router.GET("/posts/:id/revisions", withUserID(3), postHandler.GetRevisions)
+	router.GET("/comments/pending", withUserID(3), commentHandler.GetPending)
+
+	for _, path := range []string{"/posts", "/posts/tag/go", "/posts/mine", "/posts/1", "/posts/1/revisions", "/comments/pending"} {
+		t.Run(path, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NotContains(t, w.Body.String(), author.Password)
+			assert.NotContains(t, w.Body.String(), author.Email)
+			assertNoPasswordField(t, w.Body.Bytes())
+		})
+	}
+}
+
+func TestUserHandler_GetMe(t *testing.T) {
+	userHandler, mockService := NewUserHandlerWithMock()
+	mockService.GetByIDFunc = func(id uint) (model.User, error) {
+		assert.Equal(t, uint(5), id)
+		return model.User{ID: 5, Name: "John Doe", Email: "john@example.com", Locale: "es"}, nil
+	}
+
+	// /me convive con /:id en el mismo grupo
+	router := setupRouter()
+	router.GET("/users/me", withUserID(5), userHandler.GetMe)
+	router.GET("/users/:id", withUserID(5), userHandler.GetByID)
+
+	req, _ := http.NewRequest("GET", "/users/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":5,"name":"John Doe","avatar_url":"","bio":"","website":"","email":"john@example.com","email_verified_at":null,"role":"","settings":{"locale":"es"},"created_at":"0001-01-01T00:00:00Z"}`, w.Body.String())
+}
+
+func TestUserHandler_UpdateMe(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(*MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success - partial update",
+			body: `{"bio":"Gopher","website":""}`,
+			mockSetup: func(m *MockUserService) {
+				m.UpdateProfileFunc = func(id uint, req dto.UpdateProfileRequest) (model.User, error) {
+					assert.Equal(t, uint(5), id)
+					assert.Nil(t, req.Name)
+					assert.Nil(t, req.AvatarURL)
+					assert.Equal(t, "Gopher", *req.Bio)
+					assert.Equal(t, "", *req.Website)
+					return model.User{ID: 5, Name: "John Doe", Email: "john@example.com", Bio: "Gopher"}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":5,"name":"John Doe","avatar_url":"","bio":"Gopher","website":"","email":"john@example.com","email_verified_at":null,"role":"","settings":{"locale":""},"created_at":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:           "error - invalid website",
+			body:           `{"website":"not-a-url"}`,
+			mockSetup:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"website":"Debe ser una URL válida"}}`,
+		},
+		{
+			name: "error - email already registered",
+			body: `{"email":"jane@example.com"}`,
+			mockSetup: func(m *MockUserService) {
+				m.UpdateProfileFunc = func(id uint, req dto.UpdateProfileRequest) (model.User, error) {
+					return model.User{}, appErrors.ErrEmailExists
+				}
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"El email ya está registrado"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userHandler, mockService := NewUserHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.PATCH("/users/me", withUserID(5), userHandler.UpdateMe)
+
+			req, _ := http.NewRequest("PATCH", "/users/me", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestUserHandler_DeleteMe(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(*MockUserService)
+		expectedStatus int
+	}{
+		{
+			name: "success - account deleted",
+			body: `{"password":"password123"}`,
+			mockSetup: func(m *MockUserService) {
+				m.DeleteAccountFunc = func(id uint, password string) error {
+					assert.Equal(t, uint(5), id)
+					assert.Equal(t, "password123", password)
+					return nil
+				}
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "error - password missing",
+			body:           `{}`,
+			mockSetup:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - incorrect password",
+			body: `{"password":"wrong"}`,
+			mockSetup: func(m *MockUserService) {
+				m.DeleteAccountFunc = func(id uint, password string) error {
+					return appErrors.ErrIncorrectPassword
+				}
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userHandler, mockService := NewUserHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.DELETE("/users/me", withUserID(5), userHandler.DeleteMe)
+
+			req, _ := http.NewRequest("DELETE", "/users/me", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+// withRole simulates the role that AuthMiddleware stores in the context
+func withRole(role model.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("role", role)
+		c.Next()
+	}
+}
+
+func TestUserHandler_GetAll_AdminView(t *testing.T) {
+	userHandler, mockService := NewUserHandlerWithMock()
+	mockService.GetAllFunc = func(spec query.Spec) (query.Page[model.User], error) {
+		return query.Page[model.User]{Data: []model.User{{ID: 1, Name: "John Doe", Email: "john@example.com", Role: model.RoleEditor, Password: "hash"}}}, nil
+	}
+
+	router := setupRouter()
+	router.GET("/users", withUserID(9), withRole(model.RoleAdmin), userHandler.GetAll)
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[{"id":1,"name":"John Doe","avatar_url":"","bio":"","website":"","email":"john@example.com","email_verified_at":null,"role":"editor","settings":{"locale":""},"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}]}`, w.Body.String())
+	assertNoPasswordField(t, w.Body.Bytes())
+}
+
+func TestUserHandler_UpdateRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		body           string
+		mockSetup      func(*MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success - role assigned",
+			url:  "/users/3/role",
+			body: `{"role":"editor"}`,
+			mockSetup: func(m *MockUserService) {
+				m.UpdateRoleFunc = func(id uint, role model.Role) (model.User, error) {
+					assert.Equal(t, uint(3), id)
+					assert.Equal(t, model.RoleEditor, role)
+					return model.User{ID: 3, Name: "John Doe", Email: "john@example.com", Role: role}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":3,"name":"John Doe","avatar_url":"","bio":"","website":"","email":"john@example.com","email_verified_at":null,"role":"editor","settings":{"locale":""},"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:           "error - unknown role",
+			url:            "/users/3/role",
+			body:           `{"role":"superuser"}`,
+			mockSetup:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Datos de validación incorrectos","errors":{"role":"Debe ser uno de: admin, editor, author, reader"}}`,
+		},
+		{
+			name:           "error - invalid ID",
+			url:            "/users/abc/role",
+			body:           `{"role":"editor"}`,
+			mockSetup:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"ID inválido"}`,
+		},
+		{
+			name: "error - user not found",
+			url:  "/users/3/role",
+			body: `{"role":"reader"}`,
+			mockSetup: func(m *MockUserService) {
+				m.UpdateRoleFunc = func(id uint, role model.Role) (model.User, error) {
+					return model.User{}, appErrors.ErrUserNotFound
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Usuario no encontrado"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userHandler, mockService := NewUserHandlerWithMock()
+			tt.mockSetup(mockService)
+
+			router := setupRouter()
+			router.PUT("/users/:id/role", userHandler.UpdateRole)
+
+			req, _ := http.NewRequest("PUT", tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
 		})
 	}
 }
@@ -1,15 +1,63 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
-	"github.com/UliVargas/blog-go/internal/infrastructure/config"
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// RevocationChecker consulta si un token de acceso fue revocado antes de expirar
+type RevocationChecker interface {
+	IsRevoked(jti string) (bool, error)
+}
+
+// CredentialChecker devuelve cuándo cambió el usuario su contraseña por última
+// vez, o el instante cero si nunca la cambió
+type CredentialChecker interface {
+	PasswordChangedAt(userID uint) (time.Time, error)
+}
+
+// AccessTokenAuthenticator valida un token de acceso personal y lo devuelve con
+// su usuario. Los tokens inválidos, expirados o revocados producen
+// ErrInvalidAccessToken
+type AccessTokenAuthenticator interface {
+	Authenticate(token string) (model.PersonalAccessToken, error)
+}
+
+// SessionChecker consulta si la sesión de un token de acceso fue revocada, ya
+// sea al cerrarla desde otro dispositivo o al cerrar sesión
+type SessionChecker interface {
+	IsRevoked(id uint) (bool, error)
+}
+
+// TokenVerifier verifica la firma y la expiración de un JWT con las claves de
+// firma vigentes
+type TokenVerifier interface {
+	Parse(token string) (*jwt.Token, error)
+}
+
+// TokenChecks agrupa las comprobaciones que invalidan un token de acceso antes
+// de su expiración: la revocación del propio token o de su sesión y el cambio de
+// contraseña del usuario después de emitirlo. Si Sessions es nil no se comprueba
+// la sesión. Keys verifica la firma de los JWT; si es nil
+// no se acepta ningún JWT. AccessTokens valida los
+// tokens de acceso personal; si es nil solo se aceptan JWT
+type TokenChecks struct {
+	Keys         TokenVerifier
+	Revocations  RevocationChecker
+	Sessions     SessionChecker
+	Credentials  CredentialChecker
+	AccessTokens AccessTokenAuthenticator
+}
+
+func AuthMiddleware(checks TokenChecks) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -26,32 +74,222 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		cfg := config.Load()
-		if cfg.JWTSECRET == "" {
+		if strings.HasPrefix(bearerToken[1], model.AccessTokenPrefix) {
+			accessToken, err := checks.authenticateAccessToken(bearerToken[1])
+			if errors.Is(err, appErrors.ErrInvalidAccessToken) {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+				ctx.Abort()
+				return
+			}
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo verificar el token"})
+				ctx.Abort()
+				return
+			}
+
+			setAccessToken(ctx, accessToken)
+			ctx.Next()
+			return
+		}
+
+		token, err := checks.parseToken(bearerToken[1])
+		if errors.Is(err, errMissingKeys) {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo iniciar sesión"})
 			ctx.Abort()
 			return
 		}
-
-		token, err := jwt.Parse(bearerToken[1], func(token *jwt.Token) (any, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(cfg.JWTSECRET), nil
-		})
-
 		if err != nil || !token.Valid {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
 			ctx.Abort()
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			if userID, ok := claims["user_id"].(float64); ok {
-				ctx.Set("user_id", uint(userID))
+		revoked, err := checks.isRevoked(token)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo verificar el token"})
+			ctx.Abort()
+			return
+		}
+		if revoked {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token revocado"})
+			ctx.Abort()
+			return
+		}
+
+		setClaims(ctx, token)
+
+		ctx.Next()
+	}
+}
+
+// OptionalAuthMiddleware identifica al usuario cuando envía un token válido,
+// pero permite continuar a las peticiones anónimas o con token inválido o revocado
+func OptionalAuthMiddleware(checks TokenChecks) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		bearerToken := strings.SplitN(ctx.GetHeader("Authorization"), " ", 2)
+
+		if len(bearerToken) == 2 && bearerToken[0] == "Bearer" && strings.HasPrefix(bearerToken[1], model.AccessTokenPrefix) {
+			if accessToken, err := checks.authenticateAccessToken(bearerToken[1]); err == nil {
+				setAccessToken(ctx, accessToken)
+			}
+		} else if len(bearerToken) == 2 && bearerToken[0] == "Bearer" {
+			token, err := checks.parseToken(bearerToken[1])
+			if err == nil && token.Valid {
+				if revoked, err := checks.isRevoked(token); err == nil && !revoked {
+					setClaims(ctx, token)
+				}
 			}
 		}
 
 		ctx.Next()
 	}
 }
+
+// errMissingKeys indica que no hay claves de firma con las que verificar los
+// tokens
+var errMissingKeys = errors.New("claves de firma no configuradas")
+
+// parseToken verifica la firma del token con Keys
+func (c TokenChecks) parseToken(tokenString string) (*jwt.Token, error) {
+	if c.Keys == nil {
+		return nil, errMissingKeys
+	}
+	return c.Keys.Parse(tokenString)
+}
+
+// isRevoked indica si el token fue revocado por su jti o su sesión, o si se
+// emitió antes del último cambio de contraseña de su usuario. Los tokens sin jti
+// no se pueden revocar individualmente y solo dejan de valer al expirar
+func (c TokenChecks) isRevoked(token *jwt.Token) (bool, error) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return false, nil
+	}
+
+	if jti, ok := claims["jti"].(string); ok && jti != "" {
+		revoked, err := c.Revocations.IsRevoked(jti)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	if sessionID, ok := claims["sid"].(float64); ok && sessionID > 0 && c.Sessions != nil {
+		revoked, err := c.Sessions.IsRevoked(uint(sessionID))
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return false, nil
+	}
+	changedAt, err := c.Credentials.PasswordChangedAt(uint(userID))
+	if err != nil {
+		// Los tokens de un usuario eliminado ya no son válidos
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	if changedAt.IsZero() {
+		return false, nil
+	}
+
+	// iat tiene precisión de segundos: los tokens emitidos en el mismo segundo
+	// que el cambio, como los que se entregan al cambiar la contraseña, siguen
+	// siendo válidos
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return true, nil
+	}
+	return issuedAt.Unix() < changedAt.Unix(), nil
+}
+
+// authenticateAccessToken valida un token de acceso personal. Sin autenticador
+// configurado ningún token de acceso personal es válido
+func (c TokenChecks) authenticateAccessToken(token string) (model.PersonalAccessToken, error) {
+	if c.AccessTokens == nil {
+		return model.PersonalAccessToken{}, appErrors.ErrInvalidAccessToken
+	}
+	return c.AccessTokens.Authenticate(token)
+}
+
+// setClaims guarda en el contexto los datos del usuario contenidos en el token,
+// junto con su sesión, su jti y su expiración para poder revocarlo al cerrar sesión
+func setClaims(ctx *gin.Context, token *jwt.Token) {
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		if userID, ok := claims["user_id"].(float64); ok {
+			ctx.Set("user_id", uint(userID))
+		}
+		if role, ok := claims["role"].(string); ok {
+			ctx.Set("role", model.Role(role))
+		}
+		if sessionID, ok := claims["sid"].(float64); ok {
+			ctx.Set("session_id", uint(sessionID))
+		}
+		if jti, ok := claims["jti"].(string); ok {
+			ctx.Set("token_id", jti)
+		}
+		if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+			ctx.Set("token_expires_at", exp.Time)
+		}
+	}
+}
+
+// setAccessToken guarda en el contexto el usuario de un token de acceso
+// personal, con su rol actual, y los scopes del token. A diferencia de los JWT
+// no guarda token_id: estos tokens se revocan desde /users/me/tokens
+func setAccessToken(ctx *gin.Context, token model.PersonalAccessToken) {
+	ctx.Set("user_id", token.UserID)
+	ctx.Set("role", token.User.Role)
+	ctx.Set("token_scopes", token.Scopes)
+}
+
+// RequirePermission rechaza con 403 las peticiones cuyo rol no tiene el permiso
+// según la tabla de políticas del dominio. Debe usarse después de AuthMiddleware.
+// El rol proviene del token, por lo que un cambio de rol se aplica cuando el
+// usuario renueva su token de acceso
+func RequirePermission(permission model.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role, _ := ctx.Value("role").(model.Role)
+		if !role.Can(permission) {
+			utils.HandleError(ctx, appErrors.ErrForbidden)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequireScope rechaza con 403 las peticiones autenticadas con un token de
+// acceso personal que no tiene el scope. Las peticiones con JWT, que
+// representan una sesión del usuario, y las anónimas no se restringen
+func RequireScope(scope model.Scope) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		scopes, ok := ctx.Value("token_scopes").(model.Scopes)
+		if ok && !scopes.Has(scope) {
+			utils.HandleError(ctx, appErrors.ErrInsufficientScope)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequireSession rechaza con 403 las peticiones autenticadas con un token de
+// acceso personal. Se usa en las operaciones sobre la propia cuenta, como
+// cambiar la contraseña o crear otros tokens, que requieren una sesión
+func RequireSession() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := ctx.Value("token_scopes").(model.Scopes); ok {
+			utils.HandleError(ctx, appErrors.ErrSessionRequired)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
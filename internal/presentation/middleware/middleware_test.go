@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 	"time"
 
+	"github.com/UliVargas/blog-go/internal/domain/model"
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/UliVargas/blog-go/pkg/jwtkeys"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
@@ -16,19 +19,8 @@ func TestAuthMiddleware(t *testing.T) {
 	// Configurar Gin en modo test
 	gin.SetMode(gin.TestMode)
 
-	// Guardar valor original de JWT_SECRET
-	originalJWTSecret := os.Getenv("JWTSECRET")
-	defer func() {
-		if originalJWTSecret != "" {
-			os.Setenv("JWTSECRET", originalJWTSecret)
-		} else {
-			os.Unsetenv("JWTSECRET")
-		}
-	}()
-
-	// Configurar JWT_SECRET para los tests
-	testSecret := "test-jwt-secret-key"
-	os.Setenv("JWTSECRET", testSecret)
+	// Secreto con el que checks verifica los tokens
+	testSecret := testJWTSecret
 
 	// Helper function para crear un token JWT válido
 	createValidToken := func(userID float64) string {
@@ -64,7 +56,7 @@ func TestAuthMiddleware(t *testing.T) {
 	tests := []struct {
 		name           string
 		setupRequest   func(*http.Request)
-		setupEnv       func()
+		setupChecks    func(*TokenChecks)
 		expectedStatus int
 		expectedBody   string
 		checkUserID    bool
@@ -76,7 +68,7 @@ func TestAuthMiddleware(t *testing.T) {
 				token := createValidToken(123)
 				req.Header.Set("Authorization", "Bearer "+token)
 			},
-			setupEnv:       func() {},
+			setupChecks:    func(*TokenChecks) {},
 			expectedStatus: http.StatusOK,
 			expectedBody:   `{"message":"success"}`,
 			checkUserID:    true,
@@ -87,7 +79,7 @@ func TestAuthMiddleware(t *testing.T) {
 			setupRequest: func(req *http.Request) {
 				// No configurar Authorization header
 			},
-			setupEnv:       func() {},
+			setupChecks:    func(*TokenChecks) {},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   `{"error":"Token requerido"}`,
 			checkUserID:    false,
@@ -98,7 +90,7 @@ func TestAuthMiddleware(t *testing.T) {
 				token := createValidToken(123)
 				req.Header.Set("Authorization", token) // Sin "Bearer "
 			},
-			setupEnv:       func() {},
+			setupChecks:    func(*TokenChecks) {},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   `{"error":"Formato de token invalido"}`,
 			checkUserID:    false,
@@ -109,19 +101,19 @@ func TestAuthMiddleware(t *testing.T) {
 				token := createValidToken(123)
 				req.Header.Set("Authorization", "Basic "+token)
 			},
-			setupEnv:       func() {},
+			setupChecks:    func(*TokenChecks) {},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   `{"error":"Formato de token invalido"}`,
 			checkUserID:    false,
 		},
 		{
-			name: "missing JWT secret",
+			name: "missing signing keys",
 			setupRequest: func(req *http.Request) {
 				token := createValidToken(123)
 				req.Header.Set("Authorization", "Bearer "+token)
 			},
-			setupEnv: func() {
-				os.Unsetenv("JWTSECRET")
+			setupChecks: func(c *TokenChecks) {
+				c.Keys = nil
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   `{"error":"No se pudo iniciar sesión"}`,
@@ -132,7 +124,7 @@ func TestAuthMiddleware(t *testing.T) {
 			setupRequest: func(req *http.Request) {
 				req.Header.Set("Authorization", "Bearer invalid.token.here")
 			},
-			setupEnv:       func() {},
+			setupChecks:    func(*TokenChecks) {},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   `{"error":"Token inválido"}`,
 			checkUserID:    false,
@@ -143,7 +135,7 @@ func TestAuthMiddleware(t *testing.T) {
 				token := createExpiredToken(123)
 				req.Header.Set("Authorization", "Bearer "+token)
 			},
-			setupEnv:       func() {},
+			setupChecks:    func(*TokenChecks) {},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   `{"error":"Token inválido"}`,
 			checkUserID:    false,
@@ -154,7 +146,7 @@ func TestAuthMiddleware(t *testing.T) {
 				token := createInvalidAlgorithmToken(123)
 				req.Header.Set("Authorization", "Bearer "+token)
 			},
-			setupEnv:       func() {},
+			setupChecks:    func(*TokenChecks) {},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   `{"error":"Token inválido"}`,
 			checkUserID:    false,
@@ -163,13 +155,12 @@ func TestAuthMiddleware(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Restaurar el entorno después de cada test
-			os.Setenv("JWTSECRET", testSecret)
-			tt.setupEnv()
+			tokenChecks := checks(revokedSet{})
+			tt.setupChecks(&tokenChecks)
 
 			// Crear router y middleware
 			router := gin.New()
-			router.Use(AuthMiddleware())
+			router.Use(AuthMiddleware(tokenChecks))
 
 			// Endpoint de prueba
 			router.GET("/test", func(c *gin.Context) {
@@ -203,10 +194,7 @@ func TestAuthMiddleware(t *testing.T) {
 func TestAuthMiddleware_TokenWithoutUserID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	// Configurar JWT_SECRET
-	testSecret := "test-jwt-secret-key"
-	os.Setenv("JWTSECRET", testSecret)
-	defer os.Unsetenv("JWTSECRET")
+	testSecret := testJWTSecret
 
 	// Crear token sin user_id claim
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
@@ -217,7 +205,7 @@ func TestAuthMiddleware_TokenWithoutUserID(t *testing.T) {
 
 	// Crear router y middleware
 	router := gin.New()
-	router.Use(AuthMiddleware())
+	router.Use(AuthMiddleware(checks(revokedSet{})))
 	router.GET("/test", func(c *gin.Context) {
 		// Verificar que user_id no está en el contexto
 		userID, exists := c.Get("user_id")
@@ -244,10 +232,7 @@ func TestAuthMiddleware_TokenWithNonMapClaims(t *testing.T) {
 	// Configurar Gin en modo test
 	gin.SetMode(gin.TestMode)
 
-	// Configurar JWT_SECRET para el test
-	testSecret := "test-jwt-secret-key"
-	os.Setenv("JWTSECRET", testSecret)
-	defer os.Unsetenv("JWTSECRET")
+	testSecret := testJWTSecret
 
 	// Crear un token con claims personalizados (no MapClaims)
 	type CustomClaims struct {
@@ -267,7 +252,7 @@ func TestAuthMiddleware_TokenWithNonMapClaims(t *testing.T) {
 
 	// Crear router y middleware
 	router := gin.New()
-	router.Use(AuthMiddleware())
+	router.Use(AuthMiddleware(checks(revokedSet{})))
 	router.GET("/test", func(c *gin.Context) {
 		// El middleware debería funcionar normalmente con CustomClaims
 		// porque jwt.Parse convierte automáticamente a MapClaims
@@ -305,10 +290,7 @@ func TestAuthMiddleware_TokenWithNonMapClaims(t *testing.T) {
 func TestAuthMiddleware_TokenWithInvalidUserIDType(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	// Configurar JWT_SECRET
-	testSecret := "test-jwt-secret-key"
-	os.Setenv("JWTSECRET", testSecret)
-	defer os.Unsetenv("JWTSECRET")
+	testSecret := testJWTSecret
 
 	// Crear token con user_id como string en lugar de float64
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
@@ -319,7 +301,7 @@ func TestAuthMiddleware_TokenWithInvalidUserIDType(t *testing.T) {
 
 	// Crear router y middleware
 	router := gin.New()
-	router.Use(AuthMiddleware())
+	router.Use(AuthMiddleware(checks(revokedSet{})))
 	router.GET("/test", func(c *gin.Context) {
 		// Verificar que user_id no está en el contexto porque no se pudo convertir
 		userID, exists := c.Get("user_id")
@@ -340,4 +322,508 @@ func TestAuthMiddleware_TokenWithInvalidUserIDType(t *testing.T) {
 
 	// Verificar que la request pasa pero sin user_id
 	assert.Equal(t, http.StatusOK, w.Code)
-}
\ No newline at end of file
+}
+func TestOptionalAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testSecret := testJWTSecret
+
+	validToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": float64(42),
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	validTokenString, _ := validToken.SignedString([]byte(testSecret))
+
+	tests := []struct {
+		name          string
+		authHeader    string
+		expectUserID  bool
+		expectedValue uint
+	}{
+		{name: "valid token sets user_id", authHeader: "Bearer " + validTokenString, expectUserID: true, expectedValue: 42},
+		{name: "anonymous request", authHeader: "", expectUserID: false},
+		{name: "invalid token is ignored", authHeader: "Bearer invalid.token.here", expectUserID: false},
+		{name: "wrong scheme is ignored", authHeader: "Basic " + validTokenString, expectUserID: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(OptionalAuthMiddleware(checks(revokedSet{})))
+			router.GET("/test", func(c *gin.Context) {
+				userID, exists := c.Get("user_id")
+				assert.Equal(t, tt.expectUserID, exists)
+				if tt.expectUserID {
+					assert.Equal(t, tt.expectedValue, userID)
+				}
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Las peticiones nunca se rechazan
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
+// revokedSet mocks the revocation store for middleware testing
+type revokedSet map[string]bool
+
+func (s revokedSet) IsRevoked(jti string) (bool, error) {
+	return s[jti], nil
+}
+
+// passwordChanges mocks the credential store for middleware testing
+type passwordChanges map[uint]time.Time
+
+func (p passwordChanges) PasswordChangedAt(userID uint) (time.Time, error) {
+	return p[userID], nil
+}
+
+// testJWTSecret signs the HS256 tokens accepted by checks
+const testJWTSecret = "test-jwt-secret-key"
+
+// checks builds the middleware dependencies for users that never changed their password
+func checks(revocations RevocationChecker) TokenChecks {
+	return TokenChecks{Keys: jwtkeys.NewKeySet(testJWTSecret), Revocations: revocations, Credentials: passwordChanges{}}
+}
+
+// failingRevocations mocks a revocation store that cannot be queried
+type failingRevocations struct{}
+
+func (failingRevocations) IsRevoked(jti string) (bool, error) {
+	return false, errors.New("store unavailable")
+}
+
+func TestAuthMiddleware_Revocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testSecret := testJWTSecret
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	createToken := func(jti string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id": float64(7),
+			"jti":     jti,
+			"exp":     expiresAt.Unix(),
+		})
+		tokenString, _ := token.SignedString([]byte(testSecret))
+		return tokenString
+	}
+
+	tests := []struct {
+		name           string
+		revocations    RevocationChecker
+		jti            string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "active token sets jti and expiration", revocations: revokedSet{"other": true}, jti: "jti-1", expectedStatus: http.StatusOK},
+		{name: "revoked token is rejected", revocations: revokedSet{"jti-1": true}, jti: "jti-1", expectedStatus: http.StatusUnauthorized, expectedBody: `{"error":"Token revocado"}`},
+		{name: "store failure", revocations: failingRevocations{}, jti: "jti-1", expectedStatus: http.StatusInternalServerError, expectedBody: `{"error":"No se pudo verificar el token"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AuthMiddleware(checks(tt.revocations)))
+			router.GET("/test", func(c *gin.Context) {
+				assert.Equal(t, tt.jti, c.GetString("token_id"))
+				assert.True(t, expiresAt.Equal(c.GetTime("token_expires_at")))
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+createToken(tt.jti))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+// deletedUsers mocks a credential store where no user exists
+type deletedUsers struct{}
+
+func (deletedUsers) PasswordChangedAt(userID uint) (time.Time, error) {
+	return time.Time{}, appErrors.ErrUserNotFound
+}
+
+func TestAuthMiddleware_PasswordChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testSecret := testJWTSecret
+
+	changedAt := time.Now().Add(-time.Minute)
+	createToken := func(claims jwt.MapClaims) string {
+		claims["user_id"] = float64(7)
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+		return tokenString
+	}
+
+	tests := []struct {
+		name           string
+		credentials    CredentialChecker
+		token          string
+		expectedStatus int
+	}{
+		{"token issued before the change is rejected", passwordChanges{7: changedAt}, createToken(jwt.MapClaims{"iat": changedAt.Add(-time.Hour).Unix()}), http.StatusUnauthorized},
+		{"token issued in the same second is accepted", passwordChanges{7: changedAt}, createToken(jwt.MapClaims{"iat": changedAt.Unix()}), http.StatusOK},
+		{"token issued after the change is accepted", passwordChanges{7: changedAt}, createToken(jwt.MapClaims{"iat": time.Now().Unix()}), http.StatusOK},
+		{"token without iat is rejected after a change", passwordChanges{7: changedAt}, createToken(jwt.MapClaims{}), http.StatusUnauthorized},
+		{"password never changed", passwordChanges{}, createToken(jwt.MapClaims{"iat": changedAt.Add(-time.Hour).Unix()}), http.StatusOK},
+		{"deleted user is rejected", deletedUsers{}, createToken(jwt.MapClaims{"iat": time.Now().Unix()}), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AuthMiddleware(TokenChecks{Keys: jwtkeys.NewKeySet(testJWTSecret), Revocations: revokedSet{}, Credentials: tt.credentials}))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.JSONEq(t, `{"error":"Token revocado"}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOptionalAuthMiddleware_RevokedTokenIsAnonymous(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testSecret := testJWTSecret
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": float64(7),
+		"jti":     "jti-1",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	tokenString, _ := token.SignedString([]byte(testSecret))
+
+	router := gin.New()
+	router.Use(OptionalAuthMiddleware(checks(revokedSet{"jti-1": true})))
+	router.GET("/test", func(c *gin.Context) {
+		_, exists := c.Get("user_id")
+		assert.False(t, exists)
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testSecret := testJWTSecret
+
+	createToken := func(role string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id": float64(7),
+			"role":    role,
+			"exp":     time.Now().Add(time.Hour).Unix(),
+		})
+		tokenString, _ := token.SignedString([]byte(testSecret))
+		return tokenString
+	}
+
+	tests := []struct {
+		name           string
+		role           string
+		permission     model.Permission
+		expectedStatus int
+	}{
+		{name: "author can publish posts", role: "author", permission: model.PermPostsPublish, expectedStatus: http.StatusOK},
+		{name: "reader cannot publish posts", role: "reader", permission: model.PermPostsPublish, expectedStatus: http.StatusForbidden},
+		{name: "reader can comment", role: "reader", permission: model.PermCommentsCreate, expectedStatus: http.StatusOK},
+		{name: "author cannot moderate comments", role: "author", permission: model.PermCommentsModerate, expectedStatus: http.StatusForbidden},
+		{name: "editor can moderate comments", role: "editor", permission: model.PermCommentsModerate, expectedStatus: http.StatusOK},
+		{name: "author cannot manage taxonomy", role: "author", permission: model.PermTaxonomyManage, expectedStatus: http.StatusForbidden},
+		{name: "editor can manage taxonomy", role: "editor", permission: model.PermTaxonomyManage, expectedStatus: http.StatusOK},
+		{name: "editor cannot manage users", role: "editor", permission: model.PermUsersManage, expectedStatus: http.StatusForbidden},
+		{name: "admin can manage users", role: "admin", permission: model.PermUsersManage, expectedStatus: http.StatusOK},
+		{name: "unknown role has no permissions", role: "superuser", permission: model.PermCommentsCreate, expectedStatus: http.StatusForbidden},
+		{name: "token without role has no permissions", role: "", permission: model.PermCommentsCreate, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", AuthMiddleware(checks(revokedSet{})), RequirePermission(tt.permission), func(c *gin.Context) {
+				assert.Equal(t, model.Role(tt.role), c.Value("role"))
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+createToken(tt.role))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.JSONEq(t, `{"error":"No tienes permiso para realizar esta acción"}`, w.Body.String())
+			}
+		})
+	}
+}
+
+// accessTokens mocks the personal access token authenticator
+type accessTokens map[string]model.PersonalAccessToken
+
+func (a accessTokens) Authenticate(token string) (model.PersonalAccessToken, error) {
+	if token == model.AccessTokenPrefix+"broken" {
+		return model.PersonalAccessToken{}, errors.New("database down")
+	}
+	accessToken, ok := a[token]
+	if !ok {
+		return model.PersonalAccessToken{}, appErrors.ErrInvalidAccessToken
+	}
+	return accessToken, nil
+}
+
+func TestAuthMiddleware_PersonalAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	valid := model.AccessTokenPrefix + "valid"
+	tokenChecks := checks(revokedSet{})
+	tokenChecks.Keys = nil
+	tokenChecks.AccessTokens = accessTokens{valid: {
+		ID:     3,
+		UserID: 7,
+		User:   model.User{ID: 7, Role: model.RoleEditor},
+		Scopes: model.Scopes{model.ScopePostsRead},
+	}}
+
+	tests := []struct {
+		name           string
+		checks         TokenChecks
+		token          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "valid token without signing keys", checks: tokenChecks, token: valid, expectedStatus: http.StatusOK, expectedBody: `{"message":"success"}`},
+		{name: "unknown token", checks: tokenChecks, token: model.AccessTokenPrefix + "unknown", expectedStatus: http.StatusUnauthorized, expectedBody: `{"error":"Token inválido"}`},
+		{name: "authenticator failure", checks: tokenChecks, token: model.AccessTokenPrefix + "broken", expectedStatus: http.StatusInternalServerError, expectedBody: `{"error":"No se pudo verificar el token"}`},
+		{name: "no authenticator configured", checks: checks(revokedSet{}), token: valid, expectedStatus: http.StatusUnauthorized, expectedBody: `{"error":"Token inválido"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", AuthMiddleware(tt.checks), func(c *gin.Context) {
+				assert.Equal(t, uint(7), c.Value("user_id"))
+				assert.Equal(t, model.RoleEditor, c.Value("role"))
+				assert.Equal(t, model.Scopes{model.ScopePostsRead}, c.Value("token_scopes"))
+				_, hasTokenID := c.Get("token_id")
+				assert.False(t, hasTokenID)
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestOptionalAuthMiddleware_PersonalAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	valid := model.AccessTokenPrefix + "valid"
+	tokenChecks := checks(revokedSet{})
+	tokenChecks.AccessTokens = accessTokens{valid: {UserID: 7, User: model.User{ID: 7, Role: model.RoleAuthor}}}
+
+	tests := []struct {
+		name         string
+		token        string
+		expectUserID bool
+	}{
+		{name: "valid token identifies the user", token: valid, expectUserID: true},
+		{name: "invalid token is anonymous", token: model.AccessTokenPrefix + "unknown", expectUserID: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", OptionalAuthMiddleware(tokenChecks), func(c *gin.Context) {
+				_, exists := c.Get("user_id")
+				assert.Equal(t, tt.expectUserID, exists)
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
+func TestRequireScopeAndSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		scopes         any
+		middleware     gin.HandlerFunc
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "token with the scope", scopes: model.Scopes{model.ScopePostsWrite}, middleware: RequireScope(model.ScopePostsWrite), expectedStatus: http.StatusOK, expectedBody: `{"message":"success"}`},
+		{name: "token without the scope", scopes: model.Scopes{model.ScopePostsRead}, middleware: RequireScope(model.ScopePostsWrite), expectedStatus: http.StatusForbidden, expectedBody: `{"error":"El token no tiene el scope necesario para esta operación"}`},
+		{name: "session is not restricted by scopes", middleware: RequireScope(model.ScopePostsWrite), expectedStatus: http.StatusOK, expectedBody: `{"message":"success"}`},
+		{name: "session passes", middleware: RequireSession(), expectedStatus: http.StatusOK, expectedBody: `{"message":"success"}`},
+		{name: "token is rejected where a session is required", scopes: model.Scopes{model.ScopeUsersWrite}, middleware: RequireSession(), expectedStatus: http.StatusForbidden, expectedBody: `{"error":"Esta operación no se puede realizar con un token de acceso personal"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", func(c *gin.Context) {
+				if tt.scopes != nil {
+					c.Set("token_scopes", tt.scopes)
+				}
+			}, tt.middleware, func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuthMiddleware_SigningKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	key, err := jwtkeys.Generate(jwtkeys.EdDSA)
+	assert.NoError(t, err)
+	keys := jwtkeys.NewKeySet("")
+	keys.Replace(&key, []jwtkeys.Key{key})
+	tokenChecks := checks(revokedSet{})
+	tokenChecks.Keys = keys
+
+	claims := map[string]any{"user_id": 7, "role": "author", "exp": time.Now().Add(time.Hour).Unix()}
+	signed, err := keys.Sign(claims)
+	assert.NoError(t, err)
+	legacy, err := jwtkeys.NewKeySet(testJWTSecret).Sign(claims)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{name: "token signed with the active key", token: signed, expectedStatus: http.StatusOK},
+		{name: "HS256 token is rejected when the keys do not accept it", token: legacy, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", AuthMiddleware(tokenChecks), func(c *gin.Context) {
+				assert.Equal(t, uint(7), c.Value("user_id"))
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+// revokedSessions mocks the session store for middleware testing
+type revokedSessions map[uint]bool
+
+func (s revokedSessions) IsRevoked(id uint) (bool, error) {
+	return s[id], nil
+}
+
+func TestAuthMiddleware_Sessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testSecret := testJWTSecret
+
+	createToken := func(claims jwt.MapClaims) string {
+		claims["user_id"] = float64(7)
+		claims["jti"] = "jti-1"
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+		return tokenString
+	}
+
+	tests := []struct {
+		name           string
+		sessions       SessionChecker
+		claims         jwt.MapClaims
+		expectedStatus int
+		expectedBody   string
+		expectedID     uint
+	}{
+		{name: "active session is stored in the context", sessions: revokedSessions{3: true}, claims: jwt.MapClaims{"sid": float64(2)}, expectedStatus: http.StatusOK, expectedID: 2},
+		{name: "revoked session is rejected", sessions: revokedSessions{2: true}, claims: jwt.MapClaims{"sid": float64(2)}, expectedStatus: http.StatusUnauthorized, expectedBody: `{"error":"Token revocado"}`},
+		{name: "token without session is accepted", sessions: revokedSessions{2: true}, claims: jwt.MapClaims{}, expectedStatus: http.StatusOK},
+		{name: "no session checker configured", claims: jwt.MapClaims{"sid": float64(2)}, expectedStatus: http.StatusOK, expectedID: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenChecks := checks(revokedSet{})
+			tokenChecks.Sessions = tt.sessions
+
+			router := gin.New()
+			router.Use(AuthMiddleware(tokenChecks))
+			router.GET("/test", func(c *gin.Context) {
+				assert.Equal(t, tt.expectedID, c.GetUint("session_id"))
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+createToken(tt.claims))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op identifica el tipo de cambio de un fragmento
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Change representa un fragmento de texto sin cambios, insertado o eliminado
+type Change struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// contextLines es la cantidad de líneas de contexto alrededor de cada bloque del diff unificado
+const contextLines = 3
+
+// Lines compara dos textos línea por línea
+func Lines(a, b string) []Change {
+	return compute(splitLines(a), splitLines(b))
+}
+
+// Words compara dos textos palabra por palabra, agrupando las palabras
+// consecutivas con la misma operación
+func Words(a, b string) []Change {
+	changes := compute(strings.Fields(a), strings.Fields(b))
+
+	var merged []Change
+	for start := 0; start < len(changes); {
+		end := start + 1
+		for end < len(changes) && changes[end].Op == changes[start].Op {
+			end++
+		}
+		words := make([]string, end-start)
+		for i, change := range changes[start:end] {
+			words[i] = change.Text
+		}
+		merged = append(merged, Change{Op: changes[start].Op, Text: strings.Join(words, " ")})
+		start = end
+	}
+	return merged
+}
+
+// Unified genera un diff en formato unificado, como el de `diff -u`
+func Unified(a, b, fromName, toName string) string {
+	changes := Lines(a, b)
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromName, toName)
+
+	for _, h := range hunks(changes) {
+		fmt.Fprintf(&builder, "@@ -%s +%s @@\n", hunkRange(h.fromLine, h.fromCount), hunkRange(h.toLine, h.toCount))
+		for _, change := range changes[h.start:h.end] {
+			switch change.Op {
+			case OpEqual:
+				builder.WriteString(" ")
+			case OpInsert:
+				builder.WriteString("+")
+			case OpDelete:
+				builder.WriteString("-")
+			}
+			builder.WriteString(change.Text)
+			builder.WriteString("\n")
+		}
+	}
+	return builder.String()
+}
+
+type hunk struct {
+	start, end          int
+	fromLine, fromCount int
+	toLine, toCount     int
+}
+
+// hunks agrupa los cambios en bloques con contextLines líneas de contexto,
+// uniendo los bloques cuyo contexto se solapa
+func hunks(changes []Change) []hunk {
+	var result []hunk
+	var current *hunk
+	fromLine, toLine := 1, 1
+
+	for i, change := range changes {
+		if change.Op != OpEqual {
+			start := max(i-contextLines, 0)
+			if current == nil || start > current.end {
+				if current != nil {
+					result = append(result, *current)
+				}
+				// Retroceder las líneas de contexto previas
+				back := i - start
+				current = &hunk{start: start, end: start, fromLine: fromLine - back, toLine: toLine - back}
+			}
+			current.end = min(i+contextLines+1, len(changes))
+		}
+
+		switch change.Op {
+		case OpEqual:
+			fromLine++
+			toLine++
+		case OpDelete:
+			fromLine++
+		case OpInsert:
+			toLine++
+		}
+	}
+	if current != nil {
+		result = append(result, *current)
+	}
+
+	for i := range result {
+		for _, change := range changes[result[i].start:result[i].end] {
+			if change.Op != OpInsert {
+				result[i].fromCount++
+			}
+			if change.Op != OpDelete {
+				result[i].toCount++
+			}
+		}
+		// Convención de diff: un rango vacío apunta a la línea anterior
+		if result[i].fromCount == 0 {
+			result[i].fromLine--
+		}
+		if result[i].toCount == 0 {
+			result[i].toLine--
+		}
+	}
+	return result
+}
+
+func hunkRange(line, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// maxCost limita cuántas iteraciones hace cada búsqueda de la serpiente central.
+// Los cuerpos de las publicaciones admiten hasta 100000 caracteres, es decir,
+// hasta 100000 líneas o 50000 palabras por texto. Con el límite cada búsqueda
+// cuesta O(maxCost²) y avanza al menos maxCost posiciones, así que comparar dos
+// cuerpos del tamaño máximo sin nada en común no pasa de unos 10⁸ pasos. Los
+// tramos de hasta unas 2·maxCost diferencias se siguen resolviendo de forma exacta
+const maxCost = 500
+
+// compute calcula los cambios entre a y b con la variante de espacio lineal del
+// algoritmo de Myers: busca la serpiente central del camino de edición y resuelve
+// por separado las dos mitades, con memoria O(N+M) y tiempo O((N+M)·D). Si una
+// búsqueda supera maxCost, como hace GNU diff, se divide por el punto que más ha
+// avanzado: el diff sigue siendo correcto, aunque puede dejar de ser mínimo
+func compute(a, b []string) []Change {
+	var changes []Change
+	appendChanges(&changes, a, b)
+	return changes
+}
+
+func appendChanges(changes *[]Change, a, b []string) {
+	// El prefijo y el sufijo comunes no necesitan búsqueda
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	appendOp(changes, OpEqual, a[:prefix])
+	a, b = a[prefix:], b[prefix:]
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	if len(a) > 0 && len(b) > 0 {
+		x, y := middleSnake(a, b)
+		appendChanges(changes, a[:x], b[:y])
+		appendChanges(changes, a[x:], b[y:])
+	} else {
+		appendOp(changes, OpDelete, a)
+		appendOp(changes, OpInsert, b)
+	}
+	appendOp(changes, OpEqual, common)
+}
+
+func appendOp(changes *[]Change, op Op, texts []string) {
+	for _, text := range texts {
+		*changes = append(*changes, Change{Op: op, Text: text})
+	}
+}
+
+// middleSnake avanza a la vez desde el principio y desde el final de a y b hasta
+// que los dos caminos se solapan, y devuelve el punto en el que dividir el
+// problema. a y b no pueden estar vacíos ni empezar o terminar igual
+func middleSnake(a, b []string) (x, y int) {
+	n, m := len(a), len(b)
+	maxD := min((n+m+1)/2, maxCost)
+	offset := maxD + 1
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	delta := n - m
+	// Con delta impar el solapamiento se detecta al avanzar hacia delante y con
+	// delta par al avanzar hacia atrás
+	odd := delta%2 != 0
+	// Las diagonales que se salen de la cuadrícula dejan de explorarse
+	var forwardStart, forwardEnd, backwardStart, backwardEnd int
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d + forwardStart; k <= d-forwardEnd; k += 2 {
+			i := offset + k
+			var x1 int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x1 = forward[i+1]
+			} else {
+				x1 = forward[i-1] + 1
+			}
+			y1 := x1 - k
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			forward[i] = x1
+
+			switch {
+			case x1 > n:
+				forwardEnd += 2
+			case y1 > m:
+				forwardStart += 2
+			case odd:
+				j := offset + delta - k
+				if j >= 0 && j < len(backward) && backward[j] != -1 && x1 >= n-backward[j] {
+					return x1, y1
+				}
+			}
+		}
+
+		for k := -d + backwardStart; k <= d-backwardEnd; k += 2 {
+			i := offset + k
+			var x2 int
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x2 = backward[i+1]
+			} else {
+				x2 = backward[i-1] + 1
+			}
+			y2 := x2 - k
+			for x2 < n && y2 < m && a[n-x2-1] == b[m-y2-1] {
+				x2++
+				y2++
+			}
+			backward[i] = x2
+
+			switch {
+			case x2 > n:
+				backwardEnd += 2
+			case y2 > m:
+				backwardStart += 2
+			case !odd:
+				j := offset + delta - k
+				if j >= 0 && j < len(forward) && forward[j] != -1 {
+					x1 := forward[j]
+					if x1 >= n-x2 {
+						return x1, x1 - (j - offset)
+					}
+				}
+			}
+		}
+	}
+
+	// Sin solapamiento dentro de maxCost se divide por el punto más avanzado del
+	// camino hacia delante, que nunca es el inicio ni el final
+	for k := -maxD; k <= maxD; k++ {
+		x1 := forward[offset+k]
+		if y1 := x1 - k; x1 >= 0 && x1 <= n && y1 >= 0 && y1 <= m && x1+y1 > x+y {
+			x, y = x1, y1
+		}
+	}
+	return x, y
+}
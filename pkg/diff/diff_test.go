@@ -0,0 +1,184 @@
+package diff
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected []Change
+	}{
+		{
+			name:     "identical texts",
+			a:        "uno\ndos",
+			b:        "uno\ndos",
+			expected: []Change{{OpEqual, "uno"}, {OpEqual, "dos"}},
+		},
+		{
+			name:     "both empty",
+			a:        "",
+			b:        "",
+			expected: nil,
+		},
+		{
+			name:     "insertion at the end",
+			a:        "uno",
+			b:        "uno\ndos",
+			expected: []Change{{OpEqual, "uno"}, {OpInsert, "dos"}},
+		},
+		{
+			name:     "deletion in the middle",
+			a:        "uno\ndos\ntres",
+			b:        "uno\ntres",
+			expected: []Change{{OpEqual, "uno"}, {OpDelete, "dos"}, {OpEqual, "tres"}},
+		},
+		{
+			name:     "replacement",
+			a:        "uno\ndos\ntres",
+			b:        "uno\nDOS\ntres",
+			expected: []Change{{OpEqual, "uno"}, {OpDelete, "dos"}, {OpInsert, "DOS"}, {OpEqual, "tres"}},
+		},
+		{
+			name:     "from empty",
+			a:        "",
+			b:        "uno\ndos",
+			expected: []Change{{OpInsert, "uno"}, {OpInsert, "dos"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Lines(tt.a, tt.b))
+		})
+	}
+}
+
+func TestWords(t *testing.T) {
+	changes := Words("el gato negro duerme", "el perro negro duerme mucho")
+
+	assert.Equal(t, []Change{
+		{OpEqual, "el"},
+		{OpDelete, "gato"},
+		{OpInsert, "perro"},
+		{OpEqual, "negro duerme"},
+		{OpInsert, "mucho"},
+	}, changes)
+}
+
+func TestUnified(t *testing.T) {
+	t.Run("single hunk with context", func(t *testing.T) {
+		a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10"
+		b := "1\n2\n3\n4\n5\ncinco\n7\n8\n9\n10"
+
+		expected := "--- rev 1\n+++ rev 2\n" +
+			"@@ -3,7 +3,7 @@\n" +
+			" 3\n 4\n 5\n-6\n+cinco\n 7\n 8\n 9\n"
+
+		assert.Equal(t, expected, Unified(a, b, "rev 1", "rev 2"))
+	})
+
+	t.Run("distant changes produce separate hunks", func(t *testing.T) {
+		a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl"
+		b := "A\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nL"
+
+		expected := "--- a\n+++ b\n" +
+			"@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n" +
+			"@@ -9,4 +9,4 @@\n i\n j\n k\n-l\n+L\n"
+
+		assert.Equal(t, expected, Unified(a, b, "a", "b"))
+	})
+
+	t.Run("insertion into empty text", func(t *testing.T) {
+		expected := "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+uno\n+dos\n"
+		assert.Equal(t, expected, Unified("", "uno\ndos", "a", "b"))
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		assert.Equal(t, "--- a\n+++ b\n", Unified("igual", "igual", "a", "b"))
+	})
+}
+
+func TestLines_ReconstructsBothTexts(t *testing.T) {
+	var a, b []string
+	for i := range 300 {
+		line := strconv.Itoa(i)
+		if i%7 != 0 {
+			a = append(a, line)
+		}
+		if i%5 != 0 {
+			b = append(b, line)
+		} else {
+			b = append(b, "nueva "+line)
+		}
+	}
+
+	var gotA, gotB []string
+	for _, change := range Lines(strings.Join(a, "\n"), strings.Join(b, "\n")) {
+		if change.Op != OpInsert {
+			gotA = append(gotA, change.Text)
+		}
+		if change.Op != OpDelete {
+			gotB = append(gotB, change.Text)
+		}
+	}
+
+	assert.Equal(t, a, gotA)
+	assert.Equal(t, b, gotB)
+}
+
+func TestCompute_LongTexts(t *testing.T) {
+	// Texts as long as the largest body, with more changes than maxCost covers
+	base := make([]string, 40000)
+	for i := range base {
+		base[i] = strconv.Itoa(i)
+	}
+	edited := append([]string(nil), base...)
+	for i := 0; i < len(edited); i += 40 {
+		edited[i] = "cambio"
+	}
+
+	changes := compute(base, edited)
+
+	var gotA, gotB []string
+	edits := 0
+	for _, change := range changes {
+		if change.Op != OpInsert {
+			gotA = append(gotA, change.Text)
+		}
+		if change.Op != OpDelete {
+			gotB = append(gotB, change.Text)
+		}
+		if change.Op != OpEqual {
+			edits++
+		}
+	}
+	assert.Equal(t, base, gotA)
+	assert.Equal(t, edited, gotB)
+	assert.Equal(t, 2000, edits, "scattered edits are still found exactly")
+
+	t.Run("nothing in common", func(t *testing.T) {
+		a := strings.Fields(strings.Repeat("a ", 50000))
+		b := strings.Fields(strings.Repeat("b ", 50000))
+
+		changes := compute(a, b)
+
+		deleted, inserted := 0, 0
+		for _, change := range changes {
+			switch change.Op {
+			case OpDelete:
+				deleted++
+			case OpInsert:
+				inserted++
+			}
+		}
+		assert.Equal(t, len(a), deleted)
+		assert.Equal(t, len(b), inserted)
+		assert.Len(t, changes, len(a)+len(b))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Errores de dominio personalizados
@@ -13,14 +14,63 @@ var (
 	ErrUserExists      = errors.New("el usuario ya existe")
 	ErrEmailExists     = errors.New("el email ya está registrado")
 	ErrUsernameExists  = errors.New("el nombre de usuario ya está en uso")
+
+	// Errores de publicaciones
+	ErrPostNotFound     = errors.New("publicación no encontrada")
+	ErrNotPostAuthor    = errors.New("solo el autor puede modificar la publicación")
+	ErrInvalidPublishAt = errors.New("la fecha de publicación programada debe ser futura")
+	ErrRevisionNotFound = errors.New("revisión no encontrada")
+	ErrSlugExists       = errors.New("el slug ya está en uso")
+
+	// Errores de comentarios
+	ErrCommentNotFound      = errors.New("comentario no encontrado")
+	ErrNotCommentAuthor     = errors.New("solo el autor puede modificar el comentario")
+	ErrCommentEditExpired   = errors.New("el plazo para editar el comentario ha expirado")
+	ErrInvalidParentComment = errors.New("el comentario al que se responde no es válido")
+
+	// Errores de etiquetas y categorías
+	ErrTagNotFound      = errors.New("etiqueta no encontrada")
+	ErrTagExists        = errors.New("la etiqueta ya existe")
+	ErrInvalidTagMerge  = errors.New("una etiqueta no se puede fusionar consigo misma")
+	ErrCategoryNotFound = errors.New("categoría no encontrada")
+	ErrCategoryExists   = errors.New("la categoría ya existe")
 	
 	// Errores de autenticación
-	ErrInvalidCredentials = errors.New("credenciales inválidas")
-	ErrUnauthorized      = errors.New("no autorizado")
+	ErrInvalidCredentials        = errors.New("credenciales inválidas")
+	ErrUnauthorized              = errors.New("no autorizado")
+	ErrForbidden                 = errors.New("permiso denegado")
+	ErrIncorrectPassword         = errors.New("la contraseña actual es incorrecta")
+	ErrPasswordTooLong           = errors.New("la contraseña es demasiado larga")
+	ErrInvalidRefreshToken       = errors.New("token de renovación inválido")
+	ErrRefreshTokenReused        = errors.New("token de renovación reutilizado")
+	ErrInvalidVerificationToken  = errors.New("token de verificación inválido")
+	ErrEmailNotVerified          = errors.New("el email no está verificado")
+	ErrInvalidResetToken         = errors.New("token de restablecimiento inválido")
+	ErrTooManyLoginAttempts      = errors.New("demasiados intentos de inicio de sesión")
+	ErrInvalidTwoFactorCode      = errors.New("código de verificación en dos pasos inválido")
+	ErrInvalidTwoFactorChallenge = errors.New("desafío de verificación en dos pasos inválido")
+	ErrTwoFactorAlreadyEnabled   = errors.New("la verificación en dos pasos ya está activada")
+	ErrTwoFactorNotEnabled       = errors.New("la verificación en dos pasos no está activada")
+	ErrIdentityNotFound          = errors.New("identidad externa no encontrada")
+	ErrOIDCProviderNotFound      = errors.New("proveedor de inicio de sesión desconocido")
+	ErrOIDCProviderUnavailable   = errors.New("el proveedor de inicio de sesión no está disponible")
+	ErrInvalidOIDCState          = errors.New("estado de inicio de sesión externo inválido")
+	ErrOIDCAuthenticationFailed  = errors.New("no se pudo autenticar con el proveedor")
+	ErrOIDCEmailNotVerified      = errors.New("el proveedor no ha verificado el email")
+	ErrOIDCAccountNotLinkable    = errors.New("la cuenta con el mismo email no se puede vincular")
+	ErrInvalidAccessToken        = errors.New("token de acceso personal inválido")
+	ErrAccessTokenNotFound       = errors.New("token de acceso personal no encontrado")
+	ErrInvalidScope              = errors.New("scope desconocido")
+	ErrInvalidTokenExpiry        = errors.New("la expiración del token debe ser futura")
+	ErrInsufficientScope         = errors.New("el token no tiene el scope necesario")
+	ErrSessionRequired           = errors.New("la operación no admite tokens de acceso personal")
+	ErrSessionNotFound           = errors.New("sesión no encontrada")
 	
 	// Errores de validación
-	ErrInvalidInput = errors.New("datos de entrada inválidos")
-	ErrInvalidID    = errors.New("ID inválido")
+	ErrInvalidInput  = errors.New("datos de entrada inválidos")
+	ErrInvalidID     = errors.New("ID inválido")
+	ErrInvalidQuery  = errors.New("parámetros de consulta inválidos")
+	ErrInvalidCursor = errors.New("cursor de paginación inválido")
 	
 	// Errores de base de datos
 	ErrDatabaseConnection = errors.New("error de conexión con la base de datos")
@@ -46,6 +96,27 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
+// RetryAfterError indica que la operación se puede reintentar pasado RetryAfter
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+func NewRetryAfterError(err error, retryAfter time.Duration) *RetryAfterError {
+	return &RetryAfterError{
+		Err:        err,
+		RetryAfter: retryAfter,
+	}
+}
+
 // Constructores de errores con códigos HTTP
 func NewBadRequestError(err error, message string) *AppError {
 	return &AppError{
@@ -123,4 +194,26 @@ func WrapDatabaseError(err error) error {
 	
 	// Para otros errores, envolver como error de operación de base de datos
 	return fmt.Errorf("%w: %v", ErrDatabaseOperation, err)
-}
\ No newline at end of file
+}
+
+// WrapDatabaseErrorWith funciona como WrapDatabaseError para entidades distintas
+// al usuario: devuelve notFound cuando el registro no existe y conflict cuando se
+// viola una restricción de unicidad. Sin conflict, la violación se trata como
+// cualquier otro error de la operación
+func WrapDatabaseErrorWith(err error, notFound error, conflict error) error {
+	if err == nil {
+		return nil
+	}
+
+	errorMsg := err.Error()
+	if errorMsg == "record not found" {
+		return notFound
+	}
+	if strings.Contains(errorMsg, "duplicate key") || strings.Contains(errorMsg, "UNIQUE constraint failed") {
+		if conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("%w: %v", ErrDatabaseOperation, err)
+	}
+	return WrapDatabaseError(err)
+}
@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -121,6 +122,14 @@ func TestNewUnauthorizedError(t *testing.T) {
 	assert.Equal(t, message, appErr.Error())
 }
 
+func TestNewRetryAfterError(t *testing.T) {
+	retryErr := NewRetryAfterError(ErrTooManyLoginAttempts, 30*time.Second)
+
+	assert.Equal(t, 30*time.Second, retryErr.RetryAfter)
+	assert.Equal(t, ErrTooManyLoginAttempts.Error(), retryErr.Error())
+	assert.ErrorIs(t, retryErr, ErrTooManyLoginAttempts)
+}
+
 func TestWrapDatabaseError(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -205,6 +214,34 @@ func TestWrapDatabaseError(t *testing.T) {
 	}
 }
 
+func TestWrapDatabaseErrorWith(t *testing.T) {
+	t.Run("record not found uses the given error", func(t *testing.T) {
+		result := WrapDatabaseErrorWith(errors.New("record not found"), ErrPostNotFound, ErrSlugExists)
+		assert.Equal(t, ErrPostNotFound, result)
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		assert.Nil(t, WrapDatabaseErrorWith(nil, ErrPostNotFound, ErrSlugExists))
+	})
+
+	t.Run("duplicate key uses the given conflict", func(t *testing.T) {
+		err := errors.New(`ERROR: duplicate key value violates unique constraint "idx_tag_aliases_slug"`)
+		assert.Equal(t, ErrTagExists, WrapDatabaseErrorWith(err, ErrTagNotFound, ErrTagExists))
+	})
+
+	t.Run("duplicate key without a conflict is not a user conflict", func(t *testing.T) {
+		err := errors.New(`ERROR: duplicate key value violates unique constraint "idx_comments_id"`)
+		result := WrapDatabaseErrorWith(err, ErrCommentNotFound, nil)
+		assert.ErrorIs(t, result, ErrDatabaseOperation)
+		assert.NotErrorIs(t, result, ErrUserExists)
+	})
+
+	t.Run("other errors are delegated to WrapDatabaseError", func(t *testing.T) {
+		result := WrapDatabaseErrorWith(errors.New("foreign key constraint failed"), ErrPostNotFound, ErrSlugExists)
+		assert.Equal(t, ErrForeignKeyViolation, result)
+	})
+}
+
 func TestDomainErrors(t *testing.T) {
 	// Test que los errores de dominio tienen los mensajes correctos
 	tests := []struct {
@@ -223,6 +260,49 @@ func TestDomainErrors(t *testing.T) {
 		{"ErrDatabaseConnection", ErrDatabaseConnection, "error de conexión con la base de datos"},
 		{"ErrDatabaseOperation", ErrDatabaseOperation, "error en operación de base de datos"},
 		{"ErrForeignKeyViolation", ErrForeignKeyViolation, "no se puede completar la operación debido a dependencias"},
+		{"ErrPostNotFound", ErrPostNotFound, "publicación no encontrada"},
+		{"ErrNotPostAuthor", ErrNotPostAuthor, "solo el autor puede modificar la publicación"},
+		{"ErrInvalidPublishAt", ErrInvalidPublishAt, "la fecha de publicación programada debe ser futura"},
+		{"ErrRevisionNotFound", ErrRevisionNotFound, "revisión no encontrada"},
+		{"ErrSlugExists", ErrSlugExists, "el slug ya está en uso"},
+		{"ErrCommentNotFound", ErrCommentNotFound, "comentario no encontrado"},
+		{"ErrNotCommentAuthor", ErrNotCommentAuthor, "solo el autor puede modificar el comentario"},
+		{"ErrCommentEditExpired", ErrCommentEditExpired, "el plazo para editar el comentario ha expirado"},
+		{"ErrInvalidParentComment", ErrInvalidParentComment, "el comentario al que se responde no es válido"},
+		{"ErrTagNotFound", ErrTagNotFound, "etiqueta no encontrada"},
+		{"ErrTagExists", ErrTagExists, "la etiqueta ya existe"},
+		{"ErrInvalidTagMerge", ErrInvalidTagMerge, "una etiqueta no se puede fusionar consigo misma"},
+		{"ErrCategoryNotFound", ErrCategoryNotFound, "categoría no encontrada"},
+		{"ErrCategoryExists", ErrCategoryExists, "la categoría ya existe"},
+		{"ErrInvalidQuery", ErrInvalidQuery, "parámetros de consulta inválidos"},
+		{"ErrInvalidCursor", ErrInvalidCursor, "cursor de paginación inválido"},
+		{"ErrIncorrectPassword", ErrIncorrectPassword, "la contraseña actual es incorrecta"},
+		{"ErrPasswordTooLong", ErrPasswordTooLong, "la contraseña es demasiado larga"},
+		{"ErrInvalidRefreshToken", ErrInvalidRefreshToken, "token de renovación inválido"},
+		{"ErrRefreshTokenReused", ErrRefreshTokenReused, "token de renovación reutilizado"},
+		{"ErrForbidden", ErrForbidden, "permiso denegado"},
+		{"ErrInvalidVerificationToken", ErrInvalidVerificationToken, "token de verificación inválido"},
+		{"ErrEmailNotVerified", ErrEmailNotVerified, "el email no está verificado"},
+		{"ErrInvalidResetToken", ErrInvalidResetToken, "token de restablecimiento inválido"},
+		{"ErrTooManyLoginAttempts", ErrTooManyLoginAttempts, "demasiados intentos de inicio de sesión"},
+		{"ErrInvalidTwoFactorCode", ErrInvalidTwoFactorCode, "código de verificación en dos pasos inválido"},
+		{"ErrInvalidTwoFactorChallenge", ErrInvalidTwoFactorChallenge, "desafío de verificación en dos pasos inválido"},
+		{"ErrTwoFactorAlreadyEnabled", ErrTwoFactorAlreadyEnabled, "la verificación en dos pasos ya está activada"},
+		{"ErrTwoFactorNotEnabled", ErrTwoFactorNotEnabled, "la verificación en dos pasos no está activada"},
+		{"ErrIdentityNotFound", ErrIdentityNotFound, "identidad externa no encontrada"},
+		{"ErrOIDCProviderNotFound", ErrOIDCProviderNotFound, "proveedor de inicio de sesión desconocido"},
+		{"ErrOIDCProviderUnavailable", ErrOIDCProviderUnavailable, "el proveedor de inicio de sesión no está disponible"},
+		{"ErrInvalidOIDCState", ErrInvalidOIDCState, "estado de inicio de sesión externo inválido"},
+		{"ErrOIDCAuthenticationFailed", ErrOIDCAuthenticationFailed, "no se pudo autenticar con el proveedor"},
+		{"ErrOIDCEmailNotVerified", ErrOIDCEmailNotVerified, "el proveedor no ha verificado el email"},
+		{"ErrOIDCAccountNotLinkable", ErrOIDCAccountNotLinkable, "la cuenta con el mismo email no se puede vincular"},
+		{"ErrInvalidAccessToken", ErrInvalidAccessToken, "token de acceso personal inválido"},
+		{"ErrAccessTokenNotFound", ErrAccessTokenNotFound, "token de acceso personal no encontrado"},
+		{"ErrInvalidScope", ErrInvalidScope, "scope desconocido"},
+		{"ErrInvalidTokenExpiry", ErrInvalidTokenExpiry, "la expiración del token debe ser futura"},
+		{"ErrInsufficientScope", ErrInsufficientScope, "el token no tiene el scope necesario"},
+		{"ErrSessionRequired", ErrSessionRequired, "la operación no admite tokens de acceso personal"},
+		{"ErrSessionNotFound", ErrSessionNotFound, "sesión no encontrada"},
 	}
 
 	for _, tt := range tests {
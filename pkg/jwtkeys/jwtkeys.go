@@ -0,0 +1,233 @@
+// Package jwtkeys gestiona las claves con las que se firman y verifican los
+// tokens de acceso. Las claves asimétricas (RS256 y EdDSA) se identifican por su
+// kid y su parte pública se publica como JWKS, de modo que otros servicios
+// pueden verificar los tokens sin compartir un secreto. Los tokens sin kid se
+// firman y verifican con un secreto HS256
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algoritmos de firma admitidos
+const (
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+	HS256 = "HS256"
+)
+
+const (
+	// rsaBits es el tamaño de las claves RSA generadas
+	rsaBits = 2048
+	// kidBytes es la longitud en bytes de los kid aleatorios
+	kidBytes = 12
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("algoritmo de firma no soportado")
+	ErrUnknownKey           = errors.New("clave de firma desconocida")
+	ErrMissingSecret        = errors.New("no hay secreto para firmar tokens HS256")
+)
+
+// Key es una clave asimétrica de firma identificada por su kid
+type Key struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer
+}
+
+// Generate genera una clave nueva del algoritmo con un kid aleatorio
+func Generate(algorithm string) (Key, error) {
+	var private crypto.Signer
+	var err error
+	switch algorithm {
+	case RS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaBits)
+	case EdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return Key{}, ErrUnsupportedAlgorithm
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	kid := make([]byte, kidBytes)
+	if _, err := rand.Read(kid); err != nil {
+		return Key{}, err
+	}
+	return Key{ID: base64.RawURLEncoding.EncodeToString(kid), Algorithm: algorithm, Private: private}, nil
+}
+
+// ParseKey reconstruye una clave a partir de su clave privada en PKCS #8 DER
+func ParseKey(id, algorithm string, der []byte) (Key, error) {
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		if algorithm == RS256 {
+			return Key{ID: id, Algorithm: algorithm, Private: private}, nil
+		}
+	case ed25519.PrivateKey:
+		if algorithm == EdDSA {
+			return Key{ID: id, Algorithm: algorithm, Private: private}, nil
+		}
+	}
+	return Key{}, ErrUnsupportedAlgorithm
+}
+
+// MarshalPrivateKey devuelve la clave privada en PKCS #8 DER
+func (k Key) MarshalPrivateKey() ([]byte, error) {
+	return x509.MarshalPKCS8PrivateKey(k.Private)
+}
+
+// JWK es la parte pública de una clave en formato JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS es el documento que publica las claves públicas vigentes
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK devuelve la parte pública de la clave
+func (k Key) JWK() JWK {
+	jwk := JWK{Use: "sig", Alg: k.Algorithm, Kid: k.ID}
+	switch public := k.Private.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+func (k Key) method() jwt.SigningMethod {
+	if k.Algorithm == EdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// KeySet es el conjunto de claves vigente: la clave con la que se firman los
+// tokens nuevos y las que todavía verifican tokens. Es seguro para uso
+// concurrente, de modo que las claves se pueden rotar mientras se atienden
+// peticiones
+type KeySet struct {
+	secret []byte
+
+	mu      sync.RWMutex
+	signing *Key
+	keys    map[string]Key
+	order   []string
+}
+
+// NewKeySet crea un conjunto sin claves asimétricas que firma con HS256 usando
+// secret. Sin secreto solo se pueden usar claves asimétricas
+func NewKeySet(secret string) *KeySet {
+	return &KeySet{secret: []byte(secret), keys: make(map[string]Key)}
+}
+
+// Replace reemplaza las claves asimétricas. Los tokens nuevos se firman con
+// signing o, si es nil, con el secreto HS256. keys son las claves que verifican
+// tokens y se publican, y debe incluir a signing
+func (s *KeySet) Replace(signing *Key, keys []Key) {
+	byID := make(map[string]Key, len(keys))
+	order := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := byID[key.ID]; !ok {
+			order = append(order, key.ID)
+		}
+		byID[key.ID] = key
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signing = signing
+	s.keys = byID
+	s.order = order
+}
+
+// Sign firma las claims con la clave activa e incluye su kid en la cabecera
+func (s *KeySet) Sign(claims map[string]any) (string, error) {
+	s.mu.RLock()
+	signing := s.signing
+	s.mu.RUnlock()
+
+	if signing == nil {
+		if len(s.secret) == 0 {
+			return "", ErrMissingSecret
+		}
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims(claims)).SignedString(s.secret)
+	}
+
+	token := jwt.NewWithClaims(signing.method(), jwt.MapClaims(claims))
+	token.Header["kid"] = signing.ID
+	return token.SignedString(signing.Private)
+}
+
+// Parse verifica la firma y la expiración de un token. Los tokens con kid se
+// verifican con esa clave y solo con su algoritmo; los tokens sin kid solo se
+// aceptan firmados con el secreto HS256. Si el kid no está en el conjunto el
+// error incluye ErrUnknownKey
+func (s *KeySet) Parse(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, s.verificationKey)
+}
+
+func (s *KeySet) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || len(s.secret) == 0 {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return s.secret, nil
+	}
+
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, jwt.ErrSignatureInvalid
+	}
+	return key.Private.Public(), nil
+}
+
+// JWKS devuelve la parte pública de las claves que verifican tokens
+func (s *KeySet) JWKS() JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(s.order))}
+	for _, id := range s.order {
+		jwks.Keys = append(jwks.Keys, s.keys[id].JWK())
+	}
+	return jwks
+}
@@ -0,0 +1,170 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func claims() map[string]any {
+	return map[string]any{"user_id": 1, "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+func TestGenerate_MarshalAndParse(t *testing.T) {
+	for _, algorithm := range []string{RS256, EdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			key, err := Generate(algorithm)
+			require.NoError(t, err)
+			assert.NotEmpty(t, key.ID)
+			assert.Equal(t, algorithm, key.Algorithm)
+
+			der, err := key.MarshalPrivateKey()
+			require.NoError(t, err)
+			parsed, err := ParseKey(key.ID, algorithm, der)
+			require.NoError(t, err)
+			assert.Equal(t, key.JWK(), parsed.JWK())
+		})
+	}
+
+	_, err := Generate(HS256)
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestParseKey_AlgorithmMismatch(t *testing.T) {
+	key, err := Generate(EdDSA)
+	require.NoError(t, err)
+	der, err := key.MarshalPrivateKey()
+	require.NoError(t, err)
+
+	_, err = ParseKey(key.ID, RS256, der)
+
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestKeySet_SignAndParse(t *testing.T) {
+	for _, algorithm := range []string{RS256, EdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			key, err := Generate(algorithm)
+			require.NoError(t, err)
+			keys := NewKeySet("secret")
+			keys.Replace(&key, []Key{key})
+
+			signed, err := keys.Sign(claims())
+			require.NoError(t, err)
+
+			token, err := keys.Parse(signed)
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, token.Header["kid"])
+			assert.Equal(t, algorithm, token.Method.Alg())
+		})
+	}
+}
+
+func TestKeySet_HS256WithoutKeys(t *testing.T) {
+	keys := NewKeySet("secret")
+
+	signed, err := keys.Sign(claims())
+	require.NoError(t, err)
+	token, err := keys.Parse(signed)
+	require.NoError(t, err)
+	assert.Nil(t, token.Header["kid"])
+
+	_, err = NewKeySet("").Sign(claims())
+	assert.ErrorIs(t, err, ErrMissingSecret)
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	old, err := Generate(RS256)
+	require.NoError(t, err)
+	current, err := Generate(EdDSA)
+	require.NoError(t, err)
+
+	keys := NewKeySet("secret")
+	keys.Replace(&old, []Key{old})
+	legacy, err := NewKeySet("secret").Sign(claims())
+	require.NoError(t, err)
+	signedWithOld, err := keys.Sign(claims())
+	require.NoError(t, err)
+
+	// The previous key keeps verifying until it is retired
+	keys.Replace(&current, []Key{old, current})
+	signedWithCurrent, err := keys.Sign(claims())
+	require.NoError(t, err)
+	for _, signed := range []string{legacy, signedWithOld, signedWithCurrent} {
+		_, err := keys.Parse(signed)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, keys.JWKS().Keys, 2)
+
+	keys.Replace(&current, []Key{current})
+	_, err = keys.Parse(signedWithOld)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	_, err = keys.Parse(signedWithCurrent)
+	assert.NoError(t, err)
+}
+
+func TestKeySet_Parse_Rejects(t *testing.T) {
+	key, err := Generate(RS256)
+	require.NoError(t, err)
+	keys := NewKeySet("secret")
+	keys.Replace(&key, []Key{key})
+
+	// An HS256 token carrying the kid of the RSA key
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims(claims()))
+	confused.Header["kid"] = key.ID
+	confusedToken, err := confused.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	otherSecret, err := NewKeySet("other").Sign(claims())
+	require.NoError(t, err)
+
+	expired := claims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	expiredToken, err := keys.Sign(expired)
+	require.NoError(t, err)
+
+	for name, token := range map[string]string{
+		"algorithm does not match the key": confusedToken,
+		"other HS256 secret":               otherSecret,
+		"expired token":                    expiredToken,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := keys.Parse(token)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err = NewKeySet("").Parse(otherSecret)
+	assert.Error(t, err, "tokens without kid need the HS256 secret")
+}
+
+func TestKey_JWK(t *testing.T) {
+	rsaKey, err := Generate(RS256)
+	require.NoError(t, err)
+	jwk := rsaKey.JWK()
+	public := rsaKey.Private.Public().(*rsa.PublicKey)
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	require.NoError(t, err)
+	assert.Equal(t, "RSA", jwk.Kty)
+	assert.Equal(t, "sig", jwk.Use)
+	assert.Equal(t, RS256, jwk.Alg)
+	assert.Equal(t, rsaKey.ID, jwk.Kid)
+	assert.Equal(t, "AQAB", jwk.E)
+	assert.Equal(t, 0, public.N.Cmp(new(big.Int).SetBytes(n)))
+
+	edKey, err := Generate(EdDSA)
+	require.NoError(t, err)
+	jwk = edKey.JWK()
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	require.NoError(t, err)
+	assert.Equal(t, "OKP", jwk.Kty)
+	assert.Equal(t, "Ed25519", jwk.Crv)
+	assert.Equal(t, []byte(edKey.Private.Public().(ed25519.PublicKey)), x)
+}
@@ -0,0 +1,152 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/UliVargas/blog-go/pkg/utils"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// WordsPerMinute es la velocidad de lectura usada para estimar el tiempo de lectura
+const WordsPerMinute = 200
+
+// Heading es una entrada de la tabla de contenidos
+type Heading struct {
+	Level int
+	Text  string
+	ID    string
+}
+
+// Document es el resultado de renderizar un texto Markdown
+type Document struct {
+	HTML        string
+	TOC         []Heading
+	WordCount   int
+	ReadingTime int // minutos
+}
+
+var (
+	engine = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+	policy = newPolicy()
+)
+
+// newPolicy permite el HTML habitual de un artículo y los IDs de los encabezados,
+// eliminando scripts, manejadores de eventos y URLs javascript:
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[a-z0-9-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	return p
+}
+
+// Render convierte Markdown en HTML sanitizado y calcula la tabla de contenidos,
+// el número de palabras y el tiempo estimado de lectura
+func Render(source string) (Document, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	root := engine.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := engine.Renderer().Render(&buf, src, root); err != nil {
+		return Document{}, fmt.Errorf("renderizar markdown: %w", err)
+	}
+
+	var toc []Heading
+	var words int
+	err := ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.Heading:
+			id, _ := n.AttributeString("id")
+			idBytes, _ := id.([]byte)
+			toc = append(toc, Heading{Level: n.Level, Text: plainText(n, src), ID: string(idBytes)})
+		case *ast.Text:
+			words += len(strings.Fields(string(n.Value(src))))
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				words += len(strings.Fields(string(segment.Value(src))))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return Document{}, fmt.Errorf("analizar markdown: %w", err)
+	}
+
+	return Document{
+		HTML:        policy.Sanitize(buf.String()),
+		TOC:         toc,
+		WordCount:   words,
+		ReadingTime: readingTime(words),
+	}, nil
+}
+
+func readingTime(words int) int {
+	if words == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(words) / WordsPerMinute))
+}
+
+// plainText concatena el texto de los nodos hijos, sin formato
+func plainText(node ast.Node, src []byte) string {
+	var builder strings.Builder
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			switch t := n.(type) {
+			case *ast.Text:
+				builder.Write(t.Value(src))
+				if t.SoftLineBreak() {
+					builder.WriteByte(' ')
+				}
+			case *ast.String:
+				builder.Write(t.Value)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return builder.String()
+}
+
+// headingIDs genera IDs de encabezados con el mismo formato que los slugs de las
+// publicaciones, añadiendo un sufijo cuando un encabezado se repite
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: map[string]bool{}}
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := utils.Slugify(string(value))
+	if base == "" {
+		base = "heading"
+	}
+
+	id := base
+	for i := 1; h.used[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	h.used[id] = true
+	return []byte(id)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
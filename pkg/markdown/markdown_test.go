@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_HTML(t *testing.T) {
+	doc, err := Render("# Título\n\nUn párrafo con **negrita** y [un enlace](https://example.com).")
+
+	require.NoError(t, err)
+	assert.Contains(t, doc.HTML, `<h1 id="titulo">Título</h1>`)
+	assert.Contains(t, doc.HTML, "<strong>negrita</strong>")
+	assert.Contains(t, doc.HTML, `href="https://example.com"`)
+}
+
+func TestRender_Sanitization(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		forbidden []string
+	}{
+		{
+			name:      "script tags",
+			source:    "Hola <script>alert('xss')</script>",
+			forbidden: []string{"<script"},
+		},
+		{
+			name:      "event handlers",
+			source:    `<img src="x.png" onerror="alert(1)">` + "\n\n" + `<a href="#" onclick="alert(1)">clic</a>`,
+			forbidden: []string{"onerror", "onclick"},
+		},
+		{
+			name:      "javascript links",
+			source:    "[clic](javascript:alert(1))",
+			forbidden: []string{"javascript:"},
+		},
+		{
+			name:      "javascript links in raw html",
+			source:    `<a href="javascript:alert(1)">clic</a>`,
+			forbidden: []string{"javascript:"},
+		},
+		{
+			name:      "iframes",
+			source:    `<iframe src="https://evil.example"></iframe>`,
+			forbidden: []string{"<iframe"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Render(tt.source)
+
+			require.NoError(t, err)
+			for _, value := range tt.forbidden {
+				assert.NotContains(t, strings.ToLower(doc.HTML), value)
+			}
+		})
+	}
+}
+
+func TestRender_TOC(t *testing.T) {
+	source := "# Introducción\n\n## Instalación en **Linux**\n\n## Instalación en **Linux**\n\n### ¿Qué sigue?\n"
+
+	doc, err := Render(source)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Heading{
+		{Level: 1, Text: "Introducción", ID: "introduccion"},
+		{Level: 2, Text: "Instalación en Linux", ID: "instalacion-en-linux"},
+		{Level: 2, Text: "Instalación en Linux", ID: "instalacion-en-linux-1"},
+		{Level: 3, Text: "¿Qué sigue?", ID: "que-sigue"},
+	}, doc.TOC)
+	assert.Contains(t, doc.HTML, `id="instalacion-en-linux-1"`)
+}
+
+func TestRender_WordCountAndReadingTime(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		words       int
+		readingTime int
+	}{
+		{name: "empty", source: "", words: 0, readingTime: 0},
+		{name: "short text", source: "# Hola\n\nuno *dos* tres", words: 4, readingTime: 1},
+		{name: "code blocks count", source: "texto\n\n```\nfmt.Println(1)\n```", words: 2, readingTime: 1},
+		{name: "long text", source: strings.Repeat("palabra ", 401), words: 401, readingTime: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Render(tt.source)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.words, doc.WordCount)
+			assert.Equal(t, tt.readingTime, doc.ReadingTime)
+		})
+	}
+}
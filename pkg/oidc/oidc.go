@@ -0,0 +1,374 @@
+// Package oidc implementa la parte cliente del flujo de código de autorización de
+// OpenID Connect con PKCE: descubrimiento del proveedor, URL de autorización,
+// canje del código y verificación del ID token con las claves publicadas por el
+// proveedor (JWKS). Solo se aceptan ID tokens firmados con RS256 o ES256
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrDiscovery indica que no se pudo obtener o validar la configuración del proveedor
+	ErrDiscovery = errors.New("no se pudo obtener la configuración del proveedor")
+	// ErrExchange indica que el proveedor rechazó el canje del código de autorización
+	ErrExchange = errors.New("el proveedor rechazó el código de autorización")
+	// ErrInvalidIDToken indica que el ID token falta, no es válido o no es para este cliente
+	ErrInvalidIDToken = errors.New("ID token inválido")
+)
+
+// verifierBytes es la longitud en bytes de los code verifier de PKCE, que
+// codificados en base64 quedan en 43 caracteres, el mínimo del RFC 7636
+const verifierBytes = 32
+
+// clockSkew es la diferencia de reloj con el proveedor que se tolera al
+// comprobar la expiración del ID token
+const clockSkew = time.Minute
+
+// Config es el registro de la aplicación en un proveedor. ClientSecret puede
+// estar vacío para clientes públicos, que se autentican solo con PKCE
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims son los datos del usuario que se leen del ID token
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// metadata es el subconjunto del documento de descubrimiento que usa el cliente
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider es un proveedor OpenID Connect. La configuración del proveedor se
+// descubre en el primer uso y las claves se vuelven a descargar cuando un ID
+// token está firmado con una clave desconocida, lo que cubre las rotaciones
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     map[string]any
+}
+
+// NewProvider crea el cliente de un proveedor. Si client es nil se usa uno con
+// un tiempo de espera de 10 segundos
+func NewProvider(config Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{config: config, client: client}
+}
+
+// GenerateVerifier genera un code verifier aleatorio para PKCE
+func GenerateVerifier() (string, error) {
+	buf := make([]byte, verifierBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Challenge devuelve el code challenge S256 de verifier
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL devuelve la URL del proveedor a la que se redirige al usuario para
+// iniciar sesión. state y nonce se comprueban al volver y verifier se envía al
+// canjear el código
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) (string, error) {
+	md, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(md.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", Challenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+// Authenticate canjea el código de autorización y devuelve los datos del ID
+// token, tras comprobar su firma, emisor, audiencia, expiración y nonce
+func (p *Provider) Authenticate(code, verifier, nonce string) (Claims, error) {
+	md, err := p.discover()
+	if err != nil {
+		return Claims{}, err
+	}
+	rawIDToken, err := p.exchange(md, code, verifier)
+	if err != nil {
+		return Claims{}, err
+	}
+	return p.verify(md, rawIDToken, nonce)
+}
+
+// discover descarga el documento de descubrimiento del proveedor y lo guarda. Un
+// fallo no se guarda, para volver a intentarlo en la siguiente petición
+func (p *Provider) discover() (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var md metadata
+	wellKnown := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(wellKnown, &md); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	// El emisor debe coincidir exactamente para que no se pueda suplantar
+	if md.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("%w: el emisor %q no coincide con %q", ErrDiscovery, md.Issuer, p.config.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: faltan endpoints", ErrDiscovery)
+	}
+	p.metadata = &md
+	return p.metadata, nil
+}
+
+// exchange canjea el código en el endpoint de tokens y devuelve el ID token
+func (p *Provider) exchange(md *metadata, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+	req, err := http.NewRequest(http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: respuesta %d", ErrExchange, resp.StatusCode)
+	}
+
+	var body struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	if body.IDToken == "" {
+		return "", fmt.Errorf("%w: la respuesta no incluye ID token", ErrInvalidIDToken)
+	}
+	return body.IDToken, nil
+}
+
+// verify comprueba el ID token y extrae sus datos
+func (p *Provider) verify(md *metadata, rawIDToken, nonce string) (Claims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(md, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithIssuer(md.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	tokenNonce, _ := claims["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return Claims{}, fmt.Errorf("%w: nonce incorrecto", ErrInvalidIDToken)
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return Claims{}, fmt.Errorf("%w: falta el sujeto", ErrInvalidIDToken)
+	}
+
+	email, _ := claims["email"].(string)
+	name, _ := claims["name"].(string)
+	// Algunos proveedores envían email_verified como texto
+	var verified bool
+	switch value := claims["email_verified"].(type) {
+	case bool:
+		verified = value
+	case string:
+		verified = value == "true"
+	}
+	return Claims{Subject: subject, Email: email, EmailVerified: verified, Name: name}, nil
+}
+
+// key devuelve la clave pública con el identificador kid. Si no se conoce se
+// vuelven a descargar las claves del proveedor. Un token sin kid solo se acepta
+// si el proveedor publica una única clave
+func (p *Provider) key(md *metadata, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+	keys, err := p.fetchKeys(md.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("clave desconocida: %q", kid)
+}
+
+func (p *Provider) lookup(kid string) (any, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// jwk es una clave pública RSA o EC en formato JSON Web Key (RFC 7517)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys descarga las claves de firma del proveedor. Las claves de cifrado y
+// los tipos no admitidos se ignoran
+func (p *Provider) fetchKeys(jwksURI string) (map[string]any, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.getJSON(jwksURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("exponente RSA inválido")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, nil
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeInt(value string) (*big.Int, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(buf), nil
+}
+
+// getJSON descarga url y decodifica la respuesta en dst
+func (p *Provider) getJSON(url string, dst any) error {
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("respuesta %d de %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
@@ -0,0 +1,170 @@
+package oidc_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/UliVargas/blog-go/pkg/oidc"
+	"github.com/UliVargas/blog-go/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProvider(server *oidctest.Server) *oidc.Provider {
+	return oidc.NewProvider(oidc.Config{
+		Issuer:       server.URL,
+		ClientID:     server.ClientID,
+		ClientSecret: server.ClientSecret,
+		RedirectURL:  "http://localhost:8080/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	}, nil)
+}
+
+// login runs the authorization step against the stand-in and returns the code
+func login(t *testing.T, server *oidctest.Server, provider *oidc.Provider, verifier string, claims map[string]any) string {
+	authURL, err := provider.AuthCodeURL("state-123", "nonce-123", verifier)
+	require.NoError(t, err)
+	code, state, err := server.Authorize(authURL, claims)
+	require.NoError(t, err)
+	require.Equal(t, "state-123", state)
+	return code
+}
+
+func TestChallenge(t *testing.T) {
+	// RFC 7636, apéndice B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", oidc.Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestGenerateVerifier(t *testing.T) {
+	first, err := oidc.GenerateVerifier()
+	require.NoError(t, err)
+	second, err := oidc.GenerateVerifier()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 43)
+	assert.NotEqual(t, first, second)
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	server := oidctest.NewServer("blog", "secret")
+	defer server.Close()
+
+	authURL, err := newProvider(server).AuthCodeURL("state-123", "nonce-123", "verifier")
+
+	require.NoError(t, err)
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "blog", query.Get("client_id"))
+	assert.Equal(t, "http://localhost:8080/callback", query.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, "state-123", query.Get("state"))
+	assert.Equal(t, "nonce-123", query.Get("nonce"))
+	assert.Equal(t, oidc.Challenge("verifier"), query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestProvider_Authenticate(t *testing.T) {
+	for _, secret := range []string{"secret", ""} {
+		t.Run("client secret "+secret, func(t *testing.T) {
+			server := oidctest.NewServer("blog", secret)
+			defer server.Close()
+			provider := newProvider(server)
+			code := login(t, server, provider, "verifier-123", map[string]any{
+				"sub":            "user-1",
+				"email":          "john@example.com",
+				"email_verified": "true",
+				"name":           "John",
+			})
+
+			claims, err := provider.Authenticate(code, "verifier-123", "nonce-123")
+
+			require.NoError(t, err)
+			assert.Equal(t, oidc.Claims{Subject: "user-1", Email: "john@example.com", EmailVerified: true, Name: "John"}, claims)
+		})
+	}
+}
+
+func TestProvider_Authenticate_Rejected(t *testing.T) {
+	server := oidctest.NewServer("blog", "secret")
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		claims    map[string]any
+		verifier  string
+		nonce     string
+		wantError error
+	}{
+		{"wrong verifier", map[string]any{"sub": "user-1"}, "other-verifier", "nonce-123", oidc.ErrExchange},
+		{"wrong nonce", map[string]any{"sub": "user-1"}, "verifier-123", "other-nonce", oidc.ErrInvalidIDToken},
+		{"expired token", map[string]any{"sub": "user-1", "exp": time.Now().Add(-time.Hour).Unix()}, "verifier-123", "nonce-123", oidc.ErrInvalidIDToken},
+		{"other audience", map[string]any{"sub": "user-1", "aud": "other-client"}, "verifier-123", "nonce-123", oidc.ErrInvalidIDToken},
+		{"other issuer", map[string]any{"sub": "user-1", "iss": "https://evil.example.com"}, "verifier-123", "nonce-123", oidc.ErrInvalidIDToken},
+		{"missing subject", map[string]any{"email": "john@example.com"}, "verifier-123", "nonce-123", oidc.ErrInvalidIDToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newProvider(server)
+			code := login(t, server, provider, "verifier-123", tt.claims)
+
+			_, err := provider.Authenticate(code, tt.verifier, tt.nonce)
+
+			assert.ErrorIs(t, err, tt.wantError)
+		})
+	}
+}
+
+func TestProvider_Authenticate_CodeIsSingleUse(t *testing.T) {
+	server := oidctest.NewServer("blog", "secret")
+	defer server.Close()
+	provider := newProvider(server)
+	code := login(t, server, provider, "verifier-123", map[string]any{"sub": "user-1"})
+
+	_, err := provider.Authenticate(code, "verifier-123", "nonce-123")
+	require.NoError(t, err)
+	_, err = provider.Authenticate(code, "verifier-123", "nonce-123")
+
+	assert.ErrorIs(t, err, oidc.ErrExchange)
+}
+
+func TestProvider_Authenticate_KeyRotation(t *testing.T) {
+	server := oidctest.NewServer("blog", "secret")
+	defer server.Close()
+	provider := newProvider(server)
+
+	code := login(t, server, provider, "verifier-123", map[string]any{"sub": "user-1"})
+	_, err := provider.Authenticate(code, "verifier-123", "nonce-123")
+	require.NoError(t, err)
+
+	server.RotateKey()
+	code = login(t, server, provider, "verifier-123", map[string]any{"sub": "user-1"})
+	_, err = provider.Authenticate(code, "verifier-123", "nonce-123")
+
+	assert.NoError(t, err)
+}
+
+func TestProvider_Discovery(t *testing.T) {
+	server := oidctest.NewServer("blog", "secret")
+	defer server.Close()
+
+	t.Run("issuer mismatch", func(t *testing.T) {
+		provider := oidc.NewProvider(oidc.Config{Issuer: server.URL + "/", ClientID: "blog"}, nil)
+
+		_, err := provider.AuthCodeURL("state", "nonce", "verifier")
+
+		assert.ErrorIs(t, err, oidc.ErrDiscovery)
+	})
+
+	t.Run("provider unavailable", func(t *testing.T) {
+		provider := oidc.NewProvider(oidc.Config{Issuer: server.URL + "/missing", ClientID: "blog"}, nil)
+
+		_, err := provider.Authenticate("code", "verifier", "nonce")
+
+		assert.ErrorIs(t, err, oidc.ErrDiscovery)
+	})
+}
@@ -0,0 +1,234 @@
+// Package password genera y verifica los hashes de las contraseñas. Argon2id
+// usa el formato PHC ($argon2id$v=19$m=19456,t=2,p=1$<sal>$<hash>) y bcrypt su
+// formato modular ($2a$10$...), de modo que cada hash indica el algoritmo y los
+// parámetros con los que se generó y se puede verificar aunque la configuración
+// cambie
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algoritmos de hash admitidos
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// MaxLength es la longitud máxima en bytes de una contraseña. bcrypt ignora lo
+// que sigue a los primeros 72 bytes, así que con un límite mayor migrar de
+// bcrypt a Argon2id no cambiaría qué contraseñas se aceptan, y limitarla evita
+// calcular el hash de entradas arbitrariamente grandes
+const MaxLength = 72
+
+const (
+	// saltBytes es la longitud en bytes de la sal de Argon2id
+	saltBytes = 16
+	// keyBytes es la longitud en bytes del hash de Argon2id
+	keyBytes = 32
+)
+
+var (
+	ErrMismatch             = errors.New("la contraseña no coincide")
+	ErrUnsupportedAlgorithm = errors.New("algoritmo de hash no soportado")
+	ErrInvalidHash          = errors.New("formato de hash no válido")
+	ErrInvalidParams        = errors.New("parámetros de hash no válidos")
+	ErrTooLong              = errors.New("la contraseña es demasiado larga")
+)
+
+var encoding = base64.RawStdEncoding
+
+// Algorithm es un algoritmo de hash con sus parámetros actuales
+type Algorithm interface {
+	Hash(password string) (string, error)
+	// Verify devuelve ErrMismatch si la contraseña no corresponde al hash
+	Verify(encoded, password string) error
+	// Recognizes indica si el hash se generó con este algoritmo
+	Recognizes(encoded string) bool
+	// Outdated indica si el hash se generó con otros parámetros
+	Outdated(encoded string) bool
+}
+
+// Argon2id son los parámetros de Argon2id: la memoria en KiB, el número de
+// pasadas y el de hilos
+type Argon2id struct {
+	Memory      int
+	Iterations  int
+	Parallelism int
+}
+
+// validate comprueba que los parámetros se puedan usar con argon2.IDKey
+func (a Argon2id) validate() error {
+	if a.Iterations < 1 || a.Iterations > math.MaxUint32 ||
+		a.Parallelism < 1 || a.Parallelism > math.MaxUint8 ||
+		a.Memory < 8*a.Parallelism || a.Memory > math.MaxUint32 {
+		return ErrInvalidParams
+	}
+	return nil
+}
+
+func (a Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, saltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, uint32(a.Iterations), uint32(a.Memory), uint8(a.Parallelism), keyBytes)
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", AlgorithmArgon2id, argon2.Version,
+		a.Memory, a.Iterations, a.Parallelism, encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+func (a Argon2id) Verify(encoded, password string) error {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return err
+	}
+	candidate := argon2.IDKey([]byte(password), salt, uint32(params.Iterations), uint32(params.Memory), uint8(params.Parallelism), uint32(len(key)))
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+func (a Argon2id) Recognizes(encoded string) bool {
+	return strings.HasPrefix(encoded, "$"+AlgorithmArgon2id+"$")
+}
+
+func (a Argon2id) Outdated(encoded string) bool {
+	params, salt, key, err := decodeArgon2id(encoded)
+	return err != nil || params != a || len(salt) != saltBytes || len(key) != keyBytes
+}
+
+// decodeArgon2id extrae los parámetros, la sal y el hash de un hash PHC de Argon2id
+func decodeArgon2id(encoded string) (Argon2id, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return Argon2id{}, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2id{}, nil, nil, ErrInvalidHash
+	}
+	var params Argon2id
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2id{}, nil, nil, ErrInvalidHash
+	}
+	if params.validate() != nil {
+		return Argon2id{}, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := encoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return Argon2id{}, nil, nil, ErrInvalidHash
+	}
+	key, err := encoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2id{}, nil, nil, ErrInvalidHash
+	}
+	return params, salt, key, nil
+}
+
+// Bcrypt es el coste de bcrypt
+type Bcrypt struct {
+	Cost int
+}
+
+func (b Bcrypt) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (b Bcrypt) Verify(encoded, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatch
+	}
+	if err != nil {
+		return ErrInvalidHash
+	}
+	return nil
+}
+
+func (b Bcrypt) Recognizes(encoded string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(encoded, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b Bcrypt) Outdated(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != b.Cost
+}
+
+// Hasher genera los hashes con el algoritmo preferido y verifica los de todos
+// los algoritmos admitidos, lo que permite migrar de uno a otro regenerando el
+// hash cuando el usuario inicia sesión
+type Hasher struct {
+	preferred  Algorithm
+	algorithms []Algorithm
+}
+
+// New crea un Hasher que genera los hashes con algorithm, AlgorithmArgon2id o
+// AlgorithmBcrypt, y verifica los de ambos
+func New(algorithm string, argon Argon2id, bcryptParams Bcrypt) (*Hasher, error) {
+	if err := argon.validate(); err != nil {
+		return nil, err
+	}
+	if bcryptParams.Cost < bcrypt.MinCost || bcryptParams.Cost > bcrypt.MaxCost {
+		return nil, ErrInvalidParams
+	}
+
+	switch algorithm {
+	case AlgorithmArgon2id:
+		return &Hasher{preferred: argon, algorithms: []Algorithm{argon, bcryptParams}}, nil
+	case AlgorithmBcrypt:
+		return &Hasher{preferred: bcryptParams, algorithms: []Algorithm{bcryptParams, argon}}, nil
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+// Hash devuelve ErrTooLong si la contraseña supera MaxLength
+func (h *Hasher) Hash(password string) (string, error) {
+	if len(password) > MaxLength {
+		return "", ErrTooLong
+	}
+	return h.preferred.Hash(password)
+}
+
+// Verify comprueba la contraseña con el algoritmo con el que se generó el hash.
+// Devuelve ErrMismatch si no coincide e ErrInvalidHash si el hash no corresponde
+// a ningún algoritmo admitido. Una contraseña que supera MaxLength no puede
+// corresponder a ningún hash y se rechaza sin calcularlo
+func (h *Hasher) Verify(encoded, password string) error {
+	if len(password) > MaxLength {
+		return ErrMismatch
+	}
+	for _, algorithm := range h.algorithms {
+		if algorithm.Recognizes(encoded) {
+			return algorithm.Verify(encoded, password)
+		}
+	}
+	return ErrInvalidHash
+}
+
+// NeedsRehash indica si el hash se generó con otro algoritmo o con parámetros
+// distintos de los actuales
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	return !h.preferred.Recognizes(encoded) || h.preferred.Outdated(encoded)
+}
@@ -0,0 +1,133 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2id uses cheap parameters to keep the tests fast
+var testArgon2id = Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+var testBcrypt = Bcrypt{Cost: bcrypt.MinCost}
+
+func TestArgon2id_HashAndVerify(t *testing.T) {
+	encoded, err := testArgon2id.Hash("secret-password")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.True(t, testArgon2id.Recognizes(encoded))
+	assert.False(t, testBcrypt.Recognizes(encoded))
+	assert.NoError(t, testArgon2id.Verify(encoded, "secret-password"))
+	assert.ErrorIs(t, testArgon2id.Verify(encoded, "wrong-password"), ErrMismatch)
+
+	other, err := testArgon2id.Hash("secret-password")
+	require.NoError(t, err)
+	assert.NotEqual(t, encoded, other, "each hash uses a random salt")
+}
+
+func TestArgon2id_VerifyUsesEncodedParams(t *testing.T) {
+	encoded, err := testArgon2id.Hash("secret-password")
+	require.NoError(t, err)
+
+	current := Argon2id{Memory: 2048, Iterations: 2, Parallelism: 1}
+
+	assert.NoError(t, current.Verify(encoded, "secret-password"))
+	assert.True(t, current.Outdated(encoded))
+	assert.False(t, testArgon2id.Outdated(encoded))
+}
+
+func TestArgon2id_InvalidHash(t *testing.T) {
+	for _, encoded := range []string{
+		"",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!$aGFzaA",
+	} {
+		assert.ErrorIs(t, testArgon2id.Verify(encoded, "secret-password"), ErrInvalidHash, encoded)
+		assert.True(t, testArgon2id.Outdated(encoded), encoded)
+	}
+}
+
+func TestBcrypt_HashAndVerify(t *testing.T) {
+	encoded, err := testBcrypt.Hash("secret-password")
+	require.NoError(t, err)
+
+	assert.True(t, testBcrypt.Recognizes(encoded))
+	assert.False(t, testArgon2id.Recognizes(encoded))
+	assert.NoError(t, testBcrypt.Verify(encoded, "secret-password"))
+	assert.ErrorIs(t, testBcrypt.Verify(encoded, "wrong-password"), ErrMismatch)
+	assert.ErrorIs(t, testBcrypt.Verify("$2a$invalid", "secret-password"), ErrInvalidHash)
+	assert.False(t, testBcrypt.Outdated(encoded))
+	assert.True(t, Bcrypt{Cost: bcrypt.MinCost + 1}.Outdated(encoded))
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		argon     Argon2id
+		bcrypt    Bcrypt
+		wantError error
+	}{
+		{"argon2id", AlgorithmArgon2id, testArgon2id, testBcrypt, nil},
+		{"bcrypt", AlgorithmBcrypt, testArgon2id, testBcrypt, nil},
+		{"unknown algorithm", "scrypt", testArgon2id, testBcrypt, ErrUnsupportedAlgorithm},
+		{"memory below the minimum", AlgorithmArgon2id, Argon2id{Memory: 8, Iterations: 1, Parallelism: 2}, testBcrypt, ErrInvalidParams},
+		{"parallelism out of range", AlgorithmArgon2id, Argon2id{Memory: 65536, Iterations: 1, Parallelism: 256}, testBcrypt, ErrInvalidParams},
+		{"bcrypt cost out of range", AlgorithmBcrypt, testArgon2id, Bcrypt{Cost: bcrypt.MaxCost + 1}, ErrInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher, err := New(tt.algorithm, tt.argon, tt.bcrypt)
+
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+				assert.Nil(t, hasher)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, hasher)
+			}
+		})
+	}
+}
+
+func TestHasher_MigratesFromBcrypt(t *testing.T) {
+	hasher, err := New(AlgorithmArgon2id, testArgon2id, testBcrypt)
+	require.NoError(t, err)
+	legacy, err := testBcrypt.Hash("secret-password")
+	require.NoError(t, err)
+
+	// Existing bcrypt hashes still verify but must be regenerated
+	assert.NoError(t, hasher.Verify(legacy, "secret-password"))
+	assert.ErrorIs(t, hasher.Verify(legacy, "wrong-password"), ErrMismatch)
+	assert.True(t, hasher.NeedsRehash(legacy))
+
+	encoded, err := hasher.Hash("secret-password")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$"))
+	assert.NoError(t, hasher.Verify(encoded, "secret-password"))
+	assert.False(t, hasher.NeedsRehash(encoded))
+
+	assert.ErrorIs(t, hasher.Verify("plain-text", "plain-text"), ErrInvalidHash)
+	assert.True(t, hasher.NeedsRehash("plain-text"))
+}
+
+func TestHasher_MaxLength(t *testing.T) {
+	hasher, err := New(AlgorithmArgon2id, testArgon2id, testBcrypt)
+	require.NoError(t, err)
+	longest := strings.Repeat("a", MaxLength)
+	encoded, err := hasher.Hash(longest)
+	require.NoError(t, err)
+	assert.NoError(t, hasher.Verify(encoded, longest))
+
+	// Multi-byte characters count by their encoded length
+	_, err = hasher.Hash(strings.Repeat("ñ", MaxLength/2+1))
+	assert.ErrorIs(t, err, ErrTooLong)
+	assert.ErrorIs(t, hasher.Verify(encoded, longest+"a"), ErrMismatch)
+}
@@ -0,0 +1,277 @@
+// Package query implementa especificaciones de consulta reutilizables para los
+// listados: paginación por cursor (keyset), ordenamiento sobre una lista blanca
+// de campos y filtros simples. Los cursores son opacos para el cliente y
+// codifican el valor del campo de ordenamiento junto con el ID del último registro
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+)
+
+const (
+	// DefaultLimit es el tamaño de página cuando el esquema no define otro
+	DefaultLimit = 20
+	// MaxLimit es el tamaño máximo de página cuando el esquema no define otro
+	MaxLimit = 100
+
+	// Parámetros reservados de la consulta
+	ParamCursor = "cursor"
+	ParamLimit  = "limit"
+	ParamSort   = "sort"
+)
+
+// Kind indica cómo se interpreta el valor de un campo en filtros y cursores
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindInt
+	KindTime
+)
+
+// Operator es la comparación que aplica un filtro
+type Operator string
+
+const (
+	OpEqual    Operator = "eq"
+	OpContains Operator = "contains"
+	OpAfter    Operator = "after"
+	OpBefore   Operator = "before"
+)
+
+// Field describe una columna por la que se permite ordenar
+type Field struct {
+	Column string
+	Kind   Kind
+}
+
+// Filter describe un parámetro de filtrado permitido y la columna que afecta
+type Filter struct {
+	Column   string
+	Kind     Kind
+	Operator Operator
+}
+
+// Schema es la lista blanca de ordenamientos y filtros de un listado. DefaultSort
+// usa el mismo formato que el parámetro sort: el nombre del campo, con el
+// prefijo "-" para orden descendente
+type Schema struct {
+	Sorts        map[string]Field
+	Filters      map[string]Filter
+	DefaultSort  string
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// Condition es un filtro ya validado, listo para aplicarse a la consulta
+type Condition struct {
+	Column   string
+	Operator Operator
+	Value    any
+}
+
+// Cursor es la posición desde la que continúa la página. Backward indica que se
+// solicitó la página anterior a esa posición
+type Cursor struct {
+	Value    any
+	ID       uint
+	Backward bool
+}
+
+// Spec es una consulta de listado validada contra su esquema
+type Spec struct {
+	Limit      int
+	SortKey    string
+	Sort       Field
+	Desc       bool
+	Cursor     *Cursor
+	Conditions []Condition
+}
+
+// Page es una página de resultados con los cursores para navegar
+type Page[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// cursorPayload es la representación serializada del cursor. Incluye la clave
+// de ordenamiento para rechazar cursores generados con otro orden
+type cursorPayload struct {
+	Sort     string `json:"s"`
+	Value    string `json:"v"`
+	ID       uint   `json:"i"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// Parse valida los parámetros de la consulta contra el esquema y construye la
+// especificación. Los parámetros que no son filtros conocidos se ignoran
+func (s Schema) Parse(values url.Values) (Spec, error) {
+	spec := Spec{Limit: s.defaultLimit()}
+
+	if raw := values.Get(ParamLimit); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return Spec{}, appErrors.NewBadRequestError(appErrors.ErrInvalidQuery, "El límite debe ser un número positivo")
+		}
+		spec.Limit = min(limit, s.maxLimit())
+	}
+
+	spec.SortKey = values.Get(ParamSort)
+	if spec.SortKey == "" {
+		spec.SortKey = s.DefaultSort
+	}
+	name, desc := strings.CutPrefix(spec.SortKey, "-")
+	field, ok := s.Sorts[name]
+	if !ok {
+		return Spec{}, appErrors.NewBadRequestError(appErrors.ErrInvalidQuery, fmt.Sprintf("No se permite ordenar por '%s'", name))
+	}
+	spec.Sort = field
+	spec.Desc = desc
+
+	for param, filter := range s.Filters {
+		raw := strings.TrimSpace(values.Get(param))
+		if raw == "" {
+			continue
+		}
+		value, err := parseValue(filter.Kind, raw)
+		if err != nil {
+			return Spec{}, appErrors.NewBadRequestError(appErrors.ErrInvalidQuery, fmt.Sprintf("Valor inválido para el filtro '%s'", param))
+		}
+		spec.Conditions = append(spec.Conditions, Condition{filter.Column, filter.Operator, value})
+	}
+
+	if raw := values.Get(ParamCursor); raw != "" {
+		cursor, err := decodeCursor(raw, spec.SortKey, spec.Sort.Kind)
+		if err != nil {
+			return Spec{}, err
+		}
+		spec.Cursor = &cursor
+	}
+
+	return spec, nil
+}
+
+func (s Schema) defaultLimit() int {
+	if s.DefaultLimit > 0 {
+		return min(s.DefaultLimit, s.maxLimit())
+	}
+	return min(DefaultLimit, s.maxLimit())
+}
+
+func (s Schema) maxLimit() int {
+	if s.MaxLimit > 0 {
+		return s.MaxLimit
+	}
+	return MaxLimit
+}
+
+// NewPage arma la página a partir de los registros obtenidos con un límite de
+// Limit+1, en el orden en que los devolvió la consulta. key devuelve el valor del
+// campo de ordenamiento y el ID de cada registro para generar los cursores
+func NewPage[T any](items []T, spec Spec, key func(T) (any, uint)) Page[T] {
+	hasMore := len(items) > spec.Limit
+	if hasMore {
+		items = items[:spec.Limit]
+	}
+
+	// La página anterior se consulta en orden inverso, por lo que hay que
+	// devolver los registros a su orden natural
+	backward := spec.Cursor != nil && spec.Cursor.Backward
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page := Page[T]{Data: items}
+	if page.Data == nil {
+		page.Data = []T{}
+	}
+	if len(items) == 0 {
+		return page
+	}
+
+	if hasMore || backward {
+		value, id := key(items[len(items)-1])
+		page.NextCursor = encodeCursor(spec, value, id, false)
+	}
+	if (backward && hasMore) || (!backward && spec.Cursor != nil) {
+		value, id := key(items[0])
+		page.PrevCursor = encodeCursor(spec, value, id, true)
+	}
+	return page
+}
+
+func encodeCursor(spec Spec, value any, id uint, backward bool) string {
+	payload, _ := json.Marshal(cursorPayload{
+		Sort:     spec.SortKey,
+		Value:    formatValue(value),
+		ID:       id,
+		Backward: backward,
+	})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodeCursor(raw string, sortKey string, kind Kind) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return Cursor{}, appErrors.ErrInvalidCursor
+	}
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil || payload.Sort != sortKey || payload.ID == 0 {
+		return Cursor{}, appErrors.ErrInvalidCursor
+	}
+	value, err := parseValue(kind, payload.Value)
+	if err != nil {
+		return Cursor{}, appErrors.ErrInvalidCursor
+	}
+	return Cursor{Value: value, ID: payload.ID, Backward: payload.Backward}, nil
+}
+
+// parseValue convierte el texto recibido al tipo del campo. Las fechas aceptan
+// RFC 3339 o únicamente el día (YYYY-MM-DD)
+func parseValue(kind Kind, raw string) (any, error) {
+	switch kind {
+	case KindInt:
+		return strconv.ParseInt(raw, 10, 64)
+	case KindTime:
+		if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, raw)
+	default:
+		return raw, nil
+	}
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// MapPage convierte los elementos de la página conservando sus cursores
+func MapPage[T, R any](page Page[T], fn func(T) R) Page[R] {
+	data := make([]R, 0, len(page.Data))
+	for _, item := range page.Data {
+		data = append(data, fn(item))
+	}
+	return Page[R]{Data: data, NextCursor: page.NextCursor, PrevCursor: page.PrevCursor}
+}
@@ -0,0 +1,176 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	appErrors "github.com/UliVargas/blog-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = Schema{
+	Sorts: map[string]Field{
+		"id":         {Column: "id", Kind: KindInt},
+		"name":       {Column: "name", Kind: KindString},
+		"created_at": {Column: "created_at", Kind: KindTime},
+	},
+	Filters: map[string]Filter{
+		"name":          {Column: "name", Kind: KindString, Operator: OpContains},
+		"created_after": {Column: "created_at", Kind: KindTime, Operator: OpAfter},
+	},
+	DefaultSort: "-created_at",
+}
+
+type item struct {
+	ID        uint
+	CreatedAt time.Time
+}
+
+func itemKey(i item) (any, uint) {
+	return i.CreatedAt, i.ID
+}
+
+func TestSchema_Parse_Defaults(t *testing.T) {
+	spec, err := testSchema.Parse(url.Values{})
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLimit, spec.Limit)
+	assert.Equal(t, "-created_at", spec.SortKey)
+	assert.Equal(t, "created_at", spec.Sort.Column)
+	assert.True(t, spec.Desc)
+	assert.Nil(t, spec.Cursor)
+	assert.Empty(t, spec.Conditions)
+}
+
+func TestSchema_Parse_LimitIsCapped(t *testing.T) {
+	spec, err := testSchema.Parse(url.Values{"limit": {"500"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, MaxLimit, spec.Limit)
+}
+
+func TestSchema_Parse_Filters(t *testing.T) {
+	spec, err := testSchema.Parse(url.Values{
+		"name":          {" john "},
+		"created_after": {"2024-01-15"},
+		"sort":          {"name"},
+	})
+
+	require.NoError(t, err)
+	assert.False(t, spec.Desc)
+	assert.Equal(t, "name", spec.Sort.Column)
+	assert.ElementsMatch(t, []Condition{
+		{Column: "name", Operator: OpContains, Value: "john"},
+		{Column: "created_at", Operator: OpAfter, Value: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+	}, spec.Conditions)
+}
+
+func TestSchema_Parse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values url.Values
+		err    error
+	}{
+		{"invalid limit", url.Values{"limit": {"abc"}}, appErrors.ErrInvalidQuery},
+		{"zero limit", url.Values{"limit": {"0"}}, appErrors.ErrInvalidQuery},
+		{"sort not allowed", url.Values{"sort": {"password"}}, appErrors.ErrInvalidQuery},
+		{"invalid filter value", url.Values{"created_after": {"yesterday"}}, appErrors.ErrInvalidQuery},
+		{"malformed cursor", url.Values{"cursor": {"%%%"}}, appErrors.ErrInvalidCursor},
+		{"cursor not json", url.Values{"cursor": {"bm90LWpzb24"}}, appErrors.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testSchema.Parse(tt.values)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestSchema_Parse_CursorFromAnotherSort(t *testing.T) {
+	spec, err := testSchema.Parse(url.Values{"limit": {"1"}})
+	require.NoError(t, err)
+	page := NewPage([]item{{ID: 1, CreatedAt: time.Now()}, {ID: 2, CreatedAt: time.Now()}}, spec, itemKey)
+	require.NotEmpty(t, page.NextCursor)
+
+	_, err = testSchema.Parse(url.Values{"cursor": {page.NextCursor}, "sort": {"created_at"}})
+
+	assert.ErrorIs(t, err, appErrors.ErrInvalidCursor)
+}
+
+func TestNewPage_FirstPage(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	spec, err := testSchema.Parse(url.Values{"limit": {"2"}})
+	require.NoError(t, err)
+
+	page := NewPage([]item{
+		{ID: 3, CreatedAt: now},
+		{ID: 2, CreatedAt: now.Add(-time.Hour)},
+		{ID: 1, CreatedAt: now.Add(-2 * time.Hour)},
+	}, spec, itemKey)
+
+	assert.Len(t, page.Data, 2)
+	assert.Empty(t, page.PrevCursor)
+	require.NotEmpty(t, page.NextCursor)
+
+	next, err := testSchema.Parse(url.Values{"limit": {"2"}, "cursor": {page.NextCursor}})
+	require.NoError(t, err)
+	require.NotNil(t, next.Cursor)
+	assert.Equal(t, uint(2), next.Cursor.ID)
+	assert.Equal(t, now.Add(-time.Hour), next.Cursor.Value)
+	assert.False(t, next.Cursor.Backward)
+}
+
+func TestNewPage_LastPage(t *testing.T) {
+	spec, err := testSchema.Parse(url.Values{"limit": {"2"}})
+	require.NoError(t, err)
+	spec.Cursor = &Cursor{Value: time.Now(), ID: 3}
+
+	page := NewPage([]item{{ID: 2}, {ID: 1}}, spec, itemKey)
+
+	assert.Len(t, page.Data, 2)
+	assert.Empty(t, page.NextCursor)
+	assert.NotEmpty(t, page.PrevCursor)
+}
+
+func TestNewPage_Backward(t *testing.T) {
+	spec, err := testSchema.Parse(url.Values{"limit": {"2"}})
+	require.NoError(t, err)
+	spec.Cursor = &Cursor{Value: time.Now(), ID: 1, Backward: true}
+
+	// La consulta hacia atrás devuelve los registros en orden inverso
+	page := NewPage([]item{{ID: 2}, {ID: 3}, {ID: 4}}, spec, itemKey)
+
+	require.Len(t, page.Data, 2)
+	assert.Equal(t, uint(3), page.Data[0].ID)
+	assert.Equal(t, uint(2), page.Data[1].ID)
+	assert.NotEmpty(t, page.NextCursor)
+	assert.NotEmpty(t, page.PrevCursor)
+
+	prev, err := testSchema.Parse(url.Values{"cursor": {page.PrevCursor}})
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), prev.Cursor.ID)
+	assert.True(t, prev.Cursor.Backward)
+}
+
+func TestNewPage_Empty(t *testing.T) {
+	spec, err := testSchema.Parse(url.Values{})
+	require.NoError(t, err)
+
+	page := NewPage[item](nil, spec, itemKey)
+
+	assert.NotNil(t, page.Data)
+	assert.Empty(t, page.Data)
+	assert.Empty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
+}
+
+func TestMapPage(t *testing.T) {
+	page := Page[item]{Data: []item{{ID: 1}, {ID: 2}}, NextCursor: "next", PrevCursor: "prev"}
+
+	mapped := MapPage(page, func(i item) uint { return i.ID })
+
+	assert.Equal(t, Page[uint]{Data: []uint{1, 2}, NextCursor: "next", PrevCursor: "prev"}, mapped)
+}
@@ -0,0 +1,99 @@
+// Package totp implementa contraseñas de un solo uso basadas en el tiempo
+// (RFC 6238) con los parámetros que admiten las aplicaciones de autenticación
+// habituales: HMAC-SHA1, códigos de 6 dígitos y pasos de 30 segundos
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Digits es la longitud de los códigos
+	Digits = 6
+	// Period es la duración en segundos de cada paso
+	Period = 30
+
+	// secretBytes es la longitud del secreto recomendada para HMAC-SHA1
+	secretBytes = 20
+	// modulus reduce el valor truncado a Digits dígitos
+	modulus = 1_000_000
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret genera un secreto aleatorio codificado en base32, el formato
+// que esperan las aplicaciones de autenticación
+func GenerateSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(buf), nil
+}
+
+// URI devuelve el enlace otpauth:// que las aplicaciones de autenticación
+// importan, normalmente a partir de un código QR
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	params := url.Values{
+		"secret":    {secret},
+		"issuer":    {issuer},
+		"algorithm": {"SHA1"},
+		"digits":    {fmt.Sprint(Digits)},
+		"period":    {fmt.Sprint(Period)},
+	}
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// Step devuelve el paso de tiempo que corresponde a t
+func Step(t time.Time) int64 {
+	return t.Unix() / Period
+}
+
+// Code calcula el código del secreto para el paso indicado
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Truncamiento dinámico (RFC 4226, sección 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%modulus), nil
+}
+
+// Validate comprueba el código en el paso de now y en los skew pasos anteriores
+// y posteriores, para tolerar el desfase de reloj del dispositivo. Devuelve el
+// paso en el que el código es válido, que permite rechazar su reutilización
+func Validate(secret, code string, now time.Time, skew int64) (int64, bool) {
+	if len(code) != Digits {
+		return 0, false
+	}
+	current := Step(now)
+	for step := current - skew; step <= current+skew; step++ {
+		expected, err := Code(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
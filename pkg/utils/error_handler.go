@@ -2,7 +2,9 @@ package utils
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	appErrors "github.com/UliVargas/blog-go/pkg/errors"
 	"github.com/gin-gonic/gin"
@@ -29,6 +31,13 @@ func HandleError(c *gin.Context, err error) {
 		return
 	}
 
+	// Indicar al cliente cuándo puede reintentar
+	var retryErr *appErrors.RetryAfterError
+	if errors.As(err, &retryErr) {
+		seconds := int(math.Ceil(retryErr.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(max(seconds, 1)))
+	}
+
 	// Manejar errores de dominio específicos
 	switch {
 	case errors.Is(err, appErrors.ErrUserNotFound):
@@ -47,6 +56,170 @@ func HandleError(c *gin.Context, err error) {
 		c.JSON(http.StatusConflict, ErrorResponse{
 			Error: "El usuario ya existe",
 		})
+	case errors.Is(err, appErrors.ErrPostNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Publicación no encontrada",
+		})
+	case errors.Is(err, appErrors.ErrNotPostAuthor):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Solo el autor puede modificar la publicación",
+		})
+	case errors.Is(err, appErrors.ErrInvalidPublishAt):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "La fecha de publicación programada debe ser futura",
+		})
+	case errors.Is(err, appErrors.ErrSlugExists):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "El slug ya está en uso",
+		})
+	case errors.Is(err, appErrors.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Revisión no encontrada",
+		})
+	case errors.Is(err, appErrors.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Comentario no encontrado",
+		})
+	case errors.Is(err, appErrors.ErrNotCommentAuthor):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Solo el autor puede modificar el comentario",
+		})
+	case errors.Is(err, appErrors.ErrCommentEditExpired):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "El plazo para editar el comentario ha expirado",
+		})
+	case errors.Is(err, appErrors.ErrInvalidParentComment):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "El comentario al que se responde no es válido",
+		})
+	case errors.Is(err, appErrors.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Etiqueta no encontrada",
+		})
+	case errors.Is(err, appErrors.ErrTagExists):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "La etiqueta ya existe",
+		})
+	case errors.Is(err, appErrors.ErrInvalidTagMerge):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Una etiqueta no se puede fusionar consigo misma",
+		})
+	case errors.Is(err, appErrors.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Categoría no encontrada",
+		})
+	case errors.Is(err, appErrors.ErrCategoryExists):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "La categoría ya existe",
+		})
+	case errors.Is(err, appErrors.ErrInvalidQuery):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Parámetros de consulta inválidos",
+		})
+	case errors.Is(err, appErrors.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Cursor de paginación inválido",
+		})
+	case errors.Is(err, appErrors.ErrIncorrectPassword):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "La contraseña actual es incorrecta",
+		})
+	case errors.Is(err, appErrors.ErrPasswordTooLong):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "La contraseña no puede superar los 72 bytes",
+		})
+	case errors.Is(err, appErrors.ErrInvalidRefreshToken):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "Token de renovación inválido o expirado",
+		})
+	case errors.Is(err, appErrors.ErrRefreshTokenReused):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "Token de renovación reutilizado; se cerraron las sesiones asociadas",
+		})
+	case errors.Is(err, appErrors.ErrForbidden):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "No tienes permiso para realizar esta acción",
+		})
+	case errors.Is(err, appErrors.ErrInvalidVerificationToken):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Token de verificación inválido o expirado",
+		})
+	case errors.Is(err, appErrors.ErrInvalidResetToken):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Token de restablecimiento inválido o expirado",
+		})
+	case errors.Is(err, appErrors.ErrEmailNotVerified):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Debes verificar tu email antes de iniciar sesión",
+		})
+	case errors.Is(err, appErrors.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "Código de verificación inválido",
+		})
+	case errors.Is(err, appErrors.ErrInvalidTwoFactorChallenge):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "La verificación en dos pasos expiró; inicia sesión de nuevo",
+		})
+	case errors.Is(err, appErrors.ErrTwoFactorAlreadyEnabled):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "La verificación en dos pasos ya está activada",
+		})
+	case errors.Is(err, appErrors.ErrTwoFactorNotEnabled):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "La verificación en dos pasos no está activada",
+		})
+	case errors.Is(err, appErrors.ErrOIDCProviderNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Proveedor de inicio de sesión desconocido",
+		})
+	case errors.Is(err, appErrors.ErrOIDCProviderUnavailable):
+		c.JSON(http.StatusBadGateway, ErrorResponse{
+			Error: "El proveedor de inicio de sesión no está disponible",
+		})
+	case errors.Is(err, appErrors.ErrInvalidOIDCState):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "La solicitud de inicio de sesión expiró o no es válida; inténtalo de nuevo",
+		})
+	case errors.Is(err, appErrors.ErrOIDCAuthenticationFailed):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "No se pudo iniciar sesión con el proveedor",
+		})
+	case errors.Is(err, appErrors.ErrOIDCEmailNotVerified):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "El proveedor no ha verificado tu email",
+		})
+	case errors.Is(err, appErrors.ErrOIDCAccountNotLinkable):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Ya existe una cuenta con este email; verifica tu email antes de vincularla",
+		})
+	case errors.Is(err, appErrors.ErrAccessTokenNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Token de acceso no encontrado",
+		})
+	case errors.Is(err, appErrors.ErrInvalidScope):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Scope desconocido",
+		})
+	case errors.Is(err, appErrors.ErrInvalidTokenExpiry):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "La fecha de expiración del token debe ser futura",
+		})
+	case errors.Is(err, appErrors.ErrInsufficientScope):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "El token no tiene el scope necesario para esta operación",
+		})
+	case errors.Is(err, appErrors.ErrSessionRequired):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Esta operación no se puede realizar con un token de acceso personal",
+		})
+	case errors.Is(err, appErrors.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Sesión no encontrada",
+		})
+	case errors.Is(err, appErrors.ErrTooManyLoginAttempts):
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error: "Demasiados intentos de inicio de sesión; inténtalo más tarde",
+		})
 	case errors.Is(err, appErrors.ErrInvalidCredentials):
 		c.JSON(http.StatusUnauthorized, ErrorResponse{
 			Error: "Credenciales inválidas",
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	appErrors "github.com/UliVargas/blog-go/pkg/errors"
 	"github.com/gin-gonic/gin"
@@ -60,6 +61,252 @@ func TestHandleError(t *testing.T) {
 			expectedStatus: http.StatusConflict,
 			expectedError:  "El usuario ya existe",
 		},
+		{
+			name:           "ErrPostNotFound",
+			err:            appErrors.ErrPostNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Publicación no encontrada",
+		},
+		{
+			name:           "ErrNotPostAuthor",
+			err:            appErrors.ErrNotPostAuthor,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "Solo el autor puede modificar la publicación",
+		},
+		{
+			name:           "ErrInvalidPublishAt",
+			err:            appErrors.ErrInvalidPublishAt,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "La fecha de publicación programada debe ser futura",
+		},
+		{
+			name:           "ErrSlugExists",
+			err:            appErrors.ErrSlugExists,
+			expectedStatus: http.StatusConflict,
+			expectedError:  "El slug ya está en uso",
+		},
+		{
+			name:           "ErrRevisionNotFound",
+			err:            appErrors.ErrRevisionNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Revisión no encontrada",
+		},
+		{
+			name:           "ErrCommentNotFound",
+			err:            appErrors.ErrCommentNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Comentario no encontrado",
+		},
+		{
+			name:           "ErrNotCommentAuthor",
+			err:            appErrors.ErrNotCommentAuthor,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "Solo el autor puede modificar el comentario",
+		},
+		{
+			name:           "ErrCommentEditExpired",
+			err:            appErrors.ErrCommentEditExpired,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "El plazo para editar el comentario ha expirado",
+		},
+		{
+			name:           "ErrInvalidParentComment",
+			err:            appErrors.ErrInvalidParentComment,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "El comentario al que se responde no es válido",
+		},
+		{
+			name:           "ErrTagNotFound",
+			err:            appErrors.ErrTagNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Etiqueta no encontrada",
+		},
+		{
+			name:           "ErrTagExists",
+			err:            appErrors.ErrTagExists,
+			expectedStatus: http.StatusConflict,
+			expectedError:  "La etiqueta ya existe",
+		},
+		{
+			name:           "ErrInvalidTagMerge",
+			err:            appErrors.ErrInvalidTagMerge,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Una etiqueta no se puede fusionar consigo misma",
+		},
+		{
+			name:           "ErrCategoryNotFound",
+			err:            appErrors.ErrCategoryNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Categoría no encontrada",
+		},
+		{
+			name:           "ErrCategoryExists",
+			err:            appErrors.ErrCategoryExists,
+			expectedStatus: http.StatusConflict,
+			expectedError:  "La categoría ya existe",
+		},
+		{
+			name:           "ErrInvalidQuery",
+			err:            appErrors.ErrInvalidQuery,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Parámetros de consulta inválidos",
+		},
+		{
+			name:           "ErrInvalidCursor",
+			err:            appErrors.ErrInvalidCursor,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Cursor de paginación inválido",
+		},
+		{
+			name:           "ErrIncorrectPassword",
+			err:            appErrors.ErrIncorrectPassword,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "La contraseña actual es incorrecta",
+		},
+		{
+			name:           "ErrPasswordTooLong",
+			err:            appErrors.ErrPasswordTooLong,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "La contraseña no puede superar los 72 bytes",
+		},
+		{
+			name:           "ErrInvalidRefreshToken",
+			err:            appErrors.ErrInvalidRefreshToken,
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "Token de renovación inválido o expirado",
+		},
+		{
+			name:           "ErrRefreshTokenReused",
+			err:            appErrors.ErrRefreshTokenReused,
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "Token de renovación reutilizado; se cerraron las sesiones asociadas",
+		},
+		{
+			name:           "ErrForbidden",
+			err:            appErrors.ErrForbidden,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "No tienes permiso para realizar esta acción",
+		},
+		{
+			name:           "ErrInvalidVerificationToken",
+			err:            appErrors.ErrInvalidVerificationToken,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Token de verificación inválido o expirado",
+		},
+		{
+			name:           "ErrInvalidResetToken",
+			err:            appErrors.ErrInvalidResetToken,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Token de restablecimiento inválido o expirado",
+		},
+		{
+			name:           "ErrInvalidTwoFactorCode",
+			err:            appErrors.ErrInvalidTwoFactorCode,
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "Código de verificación inválido",
+		},
+		{
+			name:           "ErrInvalidTwoFactorChallenge",
+			err:            appErrors.ErrInvalidTwoFactorChallenge,
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "La verificación en dos pasos expiró; inicia sesión de nuevo",
+		},
+		{
+			name:           "ErrTwoFactorAlreadyEnabled",
+			err:            appErrors.ErrTwoFactorAlreadyEnabled,
+			expectedStatus: http.StatusConflict,
+			expectedError:  "La verificación en dos pasos ya está activada",
+		},
+		{
+			name:           "ErrTwoFactorNotEnabled",
+			err:            appErrors.ErrTwoFactorNotEnabled,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "La verificación en dos pasos no está activada",
+		},
+		{
+			name:           "ErrOIDCProviderNotFound",
+			err:            appErrors.ErrOIDCProviderNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Proveedor de inicio de sesión desconocido",
+		},
+		{
+			name:           "ErrOIDCProviderUnavailable",
+			err:            appErrors.ErrOIDCProviderUnavailable,
+			expectedStatus: http.StatusBadGateway,
+			expectedError:  "El proveedor de inicio de sesión no está disponible",
+		},
+		{
+			name:           "ErrInvalidOIDCState",
+			err:            appErrors.ErrInvalidOIDCState,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "La solicitud de inicio de sesión expiró o no es válida; inténtalo de nuevo",
+		},
+		{
+			name:           "ErrOIDCAuthenticationFailed",
+			err:            appErrors.ErrOIDCAuthenticationFailed,
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "No se pudo iniciar sesión con el proveedor",
+		},
+		{
+			name:           "ErrOIDCEmailNotVerified",
+			err:            appErrors.ErrOIDCEmailNotVerified,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "El proveedor no ha verificado tu email",
+		},
+		{
+			name:           "ErrOIDCAccountNotLinkable",
+			err:            appErrors.ErrOIDCAccountNotLinkable,
+			expectedStatus: http.StatusConflict,
+			expectedError:  "Ya existe una cuenta con este email; verifica tu email antes de vincularla",
+		},
+		{
+			name:           "ErrAccessTokenNotFound",
+			err:            appErrors.ErrAccessTokenNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Token de acceso no encontrado",
+		},
+		{
+			name:           "ErrInvalidScope",
+			err:            appErrors.ErrInvalidScope,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Scope desconocido",
+		},
+		{
+			name:           "ErrInvalidTokenExpiry",
+			err:            appErrors.ErrInvalidTokenExpiry,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "La fecha de expiración del token debe ser futura",
+		},
+		{
+			name:           "ErrInsufficientScope",
+			err:            appErrors.ErrInsufficientScope,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "El token no tiene el scope necesario para esta operación",
+		},
+		{
+			name:           "ErrSessionRequired",
+			err:            appErrors.ErrSessionRequired,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "Esta operación no se puede realizar con un token de acceso personal",
+		},
+		{
+			name:           "ErrSessionNotFound",
+			err:            appErrors.ErrSessionNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Sesión no encontrada",
+		},
+		{
+			name:           "ErrTooManyLoginAttempts",
+			err:            appErrors.NewRetryAfterError(appErrors.ErrTooManyLoginAttempts, time.Minute),
+			expectedStatus: http.StatusTooManyRequests,
+			expectedError:  "Demasiados intentos de inicio de sesión; inténtalo más tarde",
+		},
+		{
+			name:           "ErrEmailNotVerified",
+			err:            appErrors.ErrEmailNotVerified,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "Debes verificar tu email antes de iniciar sesión",
+		},
 		{
 			name:           "ErrInvalidCredentials",
 			err:            appErrors.ErrInvalidCredentials,
@@ -142,6 +389,32 @@ func TestHandleError(t *testing.T) {
 	}
 }
 
+func TestHandleError_RetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		retryAfter time.Duration
+		expected   string
+	}{
+		{"whole seconds", time.Minute, "60"},
+		{"rounds up fractions", 1500 * time.Millisecond, "2"},
+		{"never below one second", time.Millisecond, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			HandleError(c, appErrors.NewRetryAfterError(appErrors.ErrTooManyLoginAttempts, tt.retryAfter))
+
+			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+			assert.Equal(t, tt.expected, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
 func TestHandleValidationError(t *testing.T) {
 	// Usar el validador real para generar errores de validación
 	validator := GetValidator()
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -32,3 +34,25 @@ func SendCreated(c *gin.Context, message string, data interface{}) {
 func SendNoContent(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
+
+// SetPaginationLinks agrega la cabecera Link (RFC 8288) con los enlaces a la
+// página siguiente y anterior, conservando el resto de parámetros de la consulta
+func SetPaginationLinks(c *gin.Context, nextCursor, prevCursor string) {
+	var links []string
+	for _, link := range []struct{ cursor, rel string }{
+		{nextCursor, "next"},
+		{prevCursor, "prev"},
+	} {
+		if link.cursor == "" {
+			continue
+		}
+		u := *c.Request.URL
+		query := u.Query()
+		query.Set("cursor", link.cursor)
+		u.RawQuery = query.Encode()
+		links = append(links, fmt.Sprintf("<%s>; rel=\"%s\"", u.RequestURI(), link.rel))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+}
@@ -135,3 +135,44 @@ func TestSendNoContent(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	assert.Empty(t, w.Body.String())
 }
+
+func TestSetPaginationLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		next     string
+		prev     string
+		expected string
+	}{
+		{
+			name:     "next and prev",
+			next:     "abc",
+			prev:     "xyz",
+			expected: `</test?cursor=abc&limit=10&sort=-created_at>; rel="next", </test?cursor=xyz&limit=10&sort=-created_at>; rel="prev"`,
+		},
+		{
+			name:     "only next",
+			next:     "abc",
+			expected: `</test?cursor=abc&limit=10&sort=-created_at>; rel="next"`,
+		},
+		{
+			name:     "no cursors",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupTestRouter()
+			router.GET("/test", func(c *gin.Context) {
+				SetPaginationLinks(c, tt.next, tt.prev)
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test?limit=10&sort=-created_at&cursor=old", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Header().Get("Link"))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSlugLength limita la longitud de los slugs generados
+const maxSlugLength = 100
+
+// Slugify convierte un texto en un identificador apto para URLs.
+// Los caracteres acentuados se transliteran (á → a, ñ → n, ü → u) y
+// cualquier otro carácter se reemplaza por un guion
+func Slugify(text string) string {
+	decomposed := norm.NFD.String(strings.ToLower(text))
+
+	var builder strings.Builder
+	lastDash := true // evita guiones al inicio
+	for _, r := range decomposed {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Marcas diacríticas separadas por la normalización NFD
+			continue
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			builder.WriteRune(r)
+			lastDash = false
+		case !lastDash:
+			builder.WriteByte('-')
+			lastDash = true
+		}
+	}
+
+	slug := builder.String()
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+	}
+	return strings.Trim(slug, "-")
+}
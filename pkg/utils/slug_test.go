@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"simple title", "Hello World", "hello-world"},
+		{"spanish accents", "Árbol de la canción", "arbol-de-la-cancion"},
+		{"eñe", "El año del niño", "el-ano-del-nino"},
+		{"diaeresis", "Pingüino Güero", "pinguino-guero"},
+		{"uppercase eñe", "ESPAÑA", "espana"},
+		{"inverted punctuation", "¿Qué es Go? ¡Todo!", "que-es-go-todo"},
+		{"repeated separators", "Go  --  rápido", "go-rapido"},
+		{"numbers", "Top 10 tips 2024", "top-10-tips-2024"},
+		{"only symbols", "¡¿!?", ""},
+		{"leading and trailing spaces", "  hola  ", "hola"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Slugify(tt.input))
+		})
+	}
+}
+
+func TestSlugify_MaxLength(t *testing.T) {
+	slug := Slugify(strings.Repeat("palabra ", 30))
+
+	assert.LessOrEqual(t, len(slug), maxSlugLength)
+	assert.False(t, strings.HasSuffix(slug, "-"))
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidSignature indica que un token firmado está mal formado o que su
+// firma no corresponde al contenido
+var ErrInvalidSignature = errors.New("firma de token inválida")
+
+// ErrInvalidCiphertext indica que un contenido cifrado está mal formado, se
+// modificó o se cifró con otro secreto
+var ErrInvalidCiphertext = errors.New("contenido cifrado inválido")
+
+// GenerateToken genera un token aleatorio de size bytes codificado en base64
+// apto para URLs
+func GenerateToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken devuelve el hash SHA-256 en hexadecimal del token. Los tokens se
+// guardan hasheados para que una filtración de la base de datos no permita usarlos
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignToken firma data con HMAC-SHA256 y devuelve "contenido.firma" en base64
+// apto para URLs. La clave se deriva de secret y purpose, por lo que un token
+// firmado para un propósito no es válido para otro
+func SignToken(data []byte, secret, purpose string) string {
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sign(payload, secret, purpose))
+}
+
+// VerifySignedToken comprueba la firma de un token generado con SignToken y
+// devuelve su contenido
+func VerifySignedToken(token, secret, purpose string) ([]byte, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, sign(payload, secret, purpose)) {
+		return nil, ErrInvalidSignature
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return data, nil
+}
+
+// Encrypt cifra data con AES-256-GCM usando una clave derivada de secret y
+// purpose, como en SignToken. El resultado empieza por el nonce
+func Encrypt(data []byte, secret, purpose string) ([]byte, error) {
+	aead, err := newAEAD(secret, purpose)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// Decrypt descifra un contenido generado con Encrypt
+func Decrypt(data []byte, secret, purpose string) ([]byte, error) {
+	aead, err := newAEAD(secret, purpose)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plain, nil
+}
+
+func newAEAD(secret, purpose string) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveKey(secret, purpose))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sign(payload, secret, purpose string) []byte {
+	mac := hmac.New(sha256.New, deriveKey(secret, purpose))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// deriveKey deriva de secret una clave distinta para cada uso
+func deriveKey(secret, purpose string) []byte {
+	key := hmac.New(sha256.New, []byte(secret))
+	key.Write([]byte(purpose))
+	return key.Sum(nil)
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateToken(t *testing.T) {
+	first, err := GenerateToken(32)
+	assert.NoError(t, err)
+	second, err := GenerateToken(32)
+	assert.NoError(t, err)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(first)
+	assert.NoError(t, err)
+	assert.Len(t, decoded, 32)
+	assert.NotEqual(t, first, second)
+}
+
+func TestHashToken(t *testing.T) {
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", HashToken("test"))
+	assert.Equal(t, HashToken("token"), HashToken("token"))
+	assert.NotEqual(t, HashToken("token"), HashToken("other"))
+}
+
+func TestSignToken(t *testing.T) {
+	token := SignToken([]byte(`{"uid":1}`), "secret", "verify")
+
+	data, err := VerifySignedToken(token, "secret", "verify")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"uid":1}`, string(data))
+
+	tests := []struct {
+		name    string
+		token   string
+		secret  string
+		purpose string
+	}{
+		{"other secret", token, "other", "verify"},
+		{"other purpose", token, "secret", "reset"},
+		{"tampered payload", "eyJ1aWQiOjJ9" + token[strings.Index(token, "."):], "secret", "verify"},
+		{"missing signature", "eyJ1aWQiOjF9", "secret", "verify"},
+		{"malformed signature", "eyJ1aWQiOjF9.%%%", "secret", "verify"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifySignedToken(tt.token, tt.secret, tt.purpose)
+			assert.ErrorIs(t, err, ErrInvalidSignature)
+		})
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	encrypted, err := Encrypt([]byte("private key"), "secret", "signing-key")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "private key")
+
+	plain, err := Decrypt(encrypted, "secret", "signing-key")
+	assert.NoError(t, err)
+	assert.Equal(t, "private key", string(plain))
+
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name    string
+		data    []byte
+		secret  string
+		purpose string
+	}{
+		{"other secret", encrypted, "other", "signing-key"},
+		{"other purpose", encrypted, "secret", "verify"},
+		{"tampered content", tampered, "secret", "signing-key"},
+		{"truncated", encrypted[:4], "secret", "signing-key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decrypt(tt.data, tt.secret, tt.purpose)
+			assert.ErrorIs(t, err, ErrInvalidCiphertext)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"reflect"
 	"strings"
 	"sync"
 
@@ -38,9 +39,23 @@ func FormatValidationErrors(err error) map[string]string {
 			case "required":
 				errors[fieldName] = "Este campo es obligatorio"
 			case "min":
-				errors[fieldName] = "Debe tener al menos " + fieldError.Param() + " caracteres"
+				switch {
+				case isNumber(fieldError.Kind()):
+					errors[fieldName] = "Debe ser al menos " + fieldError.Param()
+				case isList(fieldError.Kind()):
+					errors[fieldName] = "Debe tener al menos " + fieldError.Param() + " elementos"
+				default:
+					errors[fieldName] = "Debe tener al menos " + fieldError.Param() + " caracteres"
+				}
 			case "max":
-				errors[fieldName] = "No puede tener más de " + fieldError.Param() + " caracteres"
+				switch {
+				case isNumber(fieldError.Kind()):
+					errors[fieldName] = "No puede ser mayor que " + fieldError.Param()
+				case isList(fieldError.Kind()):
+					errors[fieldName] = "No puede tener más de " + fieldError.Param() + " elementos"
+				default:
+					errors[fieldName] = "No puede tener más de " + fieldError.Param() + " caracteres"
+				}
 			case "email":
 				errors[fieldName] = "Debe ser un email válido"
 			case "len":
@@ -51,8 +66,12 @@ func FormatValidationErrors(err error) map[string]string {
 				errors[fieldName] = "Solo se permiten letras"
 			case "alphanum":
 				errors[fieldName] = "Solo se permiten letras y números"
-			case "url":
+			case "url", "eq=|url":
 				errors[fieldName] = "Debe ser una URL válida"
+			case "nefield":
+				errors[fieldName] = "Debe ser distinto de " + strings.ToLower(fieldError.Param())
+			case "oneof":
+				errors[fieldName] = "Debe ser uno de: " + strings.ReplaceAll(fieldError.Param(), " ", ", ")
 			default:
 				errors[fieldName] = "Valor inválido"
 			}
@@ -62,6 +81,24 @@ func FormatValidationErrors(err error) map[string]string {
 	return errors
 }
 
+// isNumber indica si el campo validado es numérico, en cuyo caso min y max
+// limitan su valor en lugar de su longitud
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isList indica si el campo validado es una lista, en cuyo caso min y max
+// limitan su número de elementos
+func isList(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+}
+
 // CreateValidationErrorResponse crea una respuesta estándar para errores de validación
 func CreateValidationErrorResponse(err error) ValidationErrorResponse {
 	return ValidationErrorResponse{
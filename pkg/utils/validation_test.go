@@ -36,6 +36,12 @@ func TestFormatValidationErrors(t *testing.T) {
 		Name     string `validate:"alpha"`
 		Username string `validate:"alphanum"`
 		LongText string `validate:"max=10"`
+		Status   string `validate:"omitempty,oneof=draft published"`
+		Depth    int    `validate:"omitempty,min=1,max=10"`
+		IDs      []uint `validate:"omitempty,min=2,max=3"`
+		Homepage string `validate:"eq=|url"`
+		Current  string
+		Next     string `validate:"nefield=Current"`
 	}
 
 	tests := []struct {
@@ -106,6 +112,55 @@ func TestFormatValidationErrors(t *testing.T) {
 				"longtext": "No puede tener más de 10 caracteres",
 			},
 		},
+		{
+			name: "numeric max error",
+			data: ExtendedTestStruct{Depth: 50},
+			expected: map[string]string{
+				"depth": "No puede ser mayor que 10",
+			},
+		},
+		{
+			name: "numeric min error",
+			data: ExtendedTestStruct{Depth: -1},
+			expected: map[string]string{
+				"depth": "Debe ser al menos 1",
+			},
+		},
+		{
+			name: "list max error",
+			data: ExtendedTestStruct{IDs: []uint{1, 2, 3, 4}},
+			expected: map[string]string{
+				"ids": "No puede tener más de 3 elementos",
+			},
+		},
+		{
+			name: "optional url error",
+			data: ExtendedTestStruct{Homepage: "not-a-url"},
+			expected: map[string]string{
+				"homepage": "Debe ser una URL válida",
+			},
+		},
+		{
+			name: "list min error",
+			data: ExtendedTestStruct{IDs: []uint{1}},
+			expected: map[string]string{
+				"ids": "Debe tener al menos 2 elementos",
+			},
+		},
+		{
+			name: "oneof validation error",
+			data: ExtendedTestStruct{Status: "deleted"},
+			expected: map[string]string{
+				"status": "Debe ser uno de: draft, published",
+			},
+		},
+		{
+			name: "nefield error",
+			data: ExtendedTestStruct{Current: "secret", Next: "secret"},
+			expected: map[string]string{
+				"next": "Debe ser distinto de current",
+			},
+		},
 	}
 
 	// Test adicional para el caso default con una etiqueta personalizada